@@ -2,13 +2,22 @@
 package main
 
 import (
+	"clean-architecture-api/internal/config"
 	"clean-architecture-api/internal/delivery/http"
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/infrastructure/database"
 	"clean-architecture-api/pkg/logger"
 	"clean-architecture-api/pkg/newrelic"
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	newrelicagent "github.com/newrelic/go-agent/v3/newrelic"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -17,6 +26,11 @@ func main() {
 	if err := loadEnv(); err != nil {
 		logger.Fatal("Failed to load environment variables", err)
 	}
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("Invalid configuration", err)
+	}
+	constants.SetSystemUserID(uuid.MustParse(cfg.SystemUserID))
 
 	nrConfig := newrelic.NewConfig()
 	nrApp, err := newrelic.NewApplication(nrConfig)
@@ -25,26 +39,80 @@ func main() {
 	} else if nrApp != nil {
 		logger.Info("New Relic application initialized successfully")
 	}
-	db, err := database.NewDatabaseWithNewRelic(nrApp)
+	db, err := openDatabase(cfg, nrApp, logger)
 	if err != nil {
-		logger.Fatal("Failed to connect to database", err)
-	}
-	if err := database.InitializeDefaultPolicies(db, logger); err != nil {
-		logger.Fatal("Failed to initialize default policies", err)
+		logger.Fatal("Failed to set up database", err)
 	}
-	server, err := http.NewServerWithNewRelic(db, logger, nrApp)
+	server, err := http.NewServerWithNewRelic(cfg, db, logger, nrApp)
 	if err != nil {
 		logger.Fatal("Failed to create HTTP server", err)
 	}
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+
+	go func() {
+		if err := startServer(server, ":"+cfg.Port, cfg.TLS, logger); err != nil {
+			logger.Fatal("Failed to start server", err)
+		}
+	}()
+
+	go func() {
+		logger.Info("gRPC server starting on port " + cfg.GRPCPort)
+		if err := server.RunGRPC(":" + cfg.GRPCPort); err != nil {
+			logger.Fatal("Failed to start gRPC server", err)
+		}
+	}()
+
+	waitForShutdown(server, logger)
+}
+
+// openDatabase connects to Postgres, or with DB_DRIVER=memory opens an in-memory SQLite database
+// for the tables the memory repositories do not cover, then seeds the default policies and the
+// system user.
+func openDatabase(cfg *config.Config, nrApp *newrelicagent.Application, logger logger.Logger) (*gorm.DB, error) {
+	if cfg.DBDriver == constants.DBDriverMemory {
+		db, err := database.NewInMemoryDatabase()
+		if err != nil {
+			return nil, err
+		}
+		if err := database.InitializeSQLiteDefaultPolicies(db, logger); err != nil {
+			return nil, err
+		}
+		return db, database.SeedSQLiteSystemUser(db, logger)
 	}
 
-	logger.Info("Server starting on port " + port)
-	if err := server.Run(":" + port); err != nil {
-		logger.Fatal("Failed to start server", err)
+	db, err := database.NewDatabaseFromConfig(cfg.Database, nrApp)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.InitializeDefaultPolicies(db, logger); err != nil {
+		return nil, err
+	}
+	return db, database.SeedSystemUser(db, logger)
+}
+
+// startServer serves HTTPS when TLS_CERT_FILE and TLS_KEY_FILE are both set, plain HTTP otherwise.
+func startServer(server *http.Server, addr string, tls config.TLSConfig, logger logger.Logger) error {
+	if tls.Enabled() {
+		logger.Info("Server starting with TLS on " + addr)
+		return server.RunTLS(addr, tls.CertFile, tls.KeyFile)
+	}
+
+	logger.Info("Server starting on " + addr)
+	return server.Run(addr)
+}
+
+func waitForShutdown(server *http.Server, logger logger.Logger) {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("Shutting down server...")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Error("Server forced to shutdown", err)
 	}
+	logger.Info("Server stopped")
 }
 
 func loadEnv() error {
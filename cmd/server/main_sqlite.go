@@ -4,11 +4,19 @@
 package main
 
 import (
+	"clean-architecture-api/internal/config"
 	"clean-architecture-api/internal/delivery/http"
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/infrastructure/database"
 	"clean-architecture-api/pkg/logger"
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 func main() {
@@ -17,8 +25,13 @@ func main() {
 	if err := loadEnv(); err != nil {
 		logger.Fatal("Failed to load environment variables", err)
 	}
+	cfg, err := config.LoadSQLite()
+	if err != nil {
+		logger.Fatal("Invalid configuration", err)
+	}
+	constants.SetSystemUserID(uuid.MustParse(cfg.SystemUserID))
 
-	db, err := database.NewSQLiteDatabase()
+	db, err := database.NewSQLiteDatabaseFromConfig(cfg.SQLite)
 	if err != nil {
 		logger.Fatal("Failed to connect to SQLite database", err)
 	}
@@ -26,20 +39,48 @@ func main() {
 	if err := database.InitializeSQLiteDefaultPolicies(db, logger); err != nil {
 		logger.Fatal("Failed to initialize default policies", err)
 	}
+	if err := database.SeedSQLiteSystemUser(db, logger); err != nil {
+		logger.Fatal("Failed to seed system user", err)
+	}
 
-	server, err := http.NewServer(db, logger)
+	server, err := http.NewServer(cfg, db, logger)
 	if err != nil {
 		logger.Fatal("Failed to create server", err)
 	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	port := cfg.Port
+
+	go func() {
+		var err error
+		if cfg.TLS.Enabled() {
+			logger.Info("Server starting with TLS on port " + port + " with SQLite database")
+			err = server.RunTLS(":"+port, cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		} else {
+			logger.Info("Server starting on port " + port + " with SQLite database")
+			err = server.Run(":" + port)
+		}
+		if err != nil {
+			log.Fatal("Failed to start server", err)
+		}
+	}()
+
+	grpcPort := cfg.GRPCPort
+
+	go func() {
+		logger.Info("gRPC server starting on port " + grpcPort)
+		if err := server.RunGRPC(":" + grpcPort); err != nil {
+			logger.Fatal("Failed to start gRPC server", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
 
-	logger.Info("Server starting on port " + port + " with SQLite database")
-	if err := server.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server", err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Error("Server forced to shutdown", err)
 	}
 }
 
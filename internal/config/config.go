@@ -0,0 +1,294 @@
+// Package config loads the settings the server needs at startup and validates them together, so a
+// misconfigured deployment fails before anything starts and reports every problem at once.
+package config
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/internal/infrastructure/database"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Config struct {
+	Env      string
+	Port     string
+	GRPCPort string
+	TLS      TLSConfig
+	JWT      JWTConfig
+	// SystemUserID is the identity recorded for internal operations; see constants.SystemUserID
+	SystemUserID string
+	// DBDriver is DB_DRIVER for Load and empty for LoadSQLite
+	DBDriver string
+	// Database is set by Load unless DBDriver is memory, and SQLite by LoadSQLite
+	Database *database.DatabaseConfig
+	SQLite   *database.SQLiteConfig
+	// PaginationHeaders adds X-Total-Count and Link headers to product and user lists
+	PaginationHeaders bool
+	// RegistrationRequiresApproval creates new registrations inactive until an admin approves them
+	RegistrationRequiresApproval bool
+	// EmailVerificationEnabled holds email changes until the new address is confirmed
+	EmailVerificationEnabled bool
+	// ProductCategoryCase is the casing product categories are stored and looked up in
+	ProductCategoryCase string
+	// AuditReads names the resources whose reads and lists are audited as well as their
+	// mutations; "*" names all of them
+	AuditReads []string
+}
+
+// Production reports whether ENV is production, which turns off development conveniences
+func (c *Config) Production() bool {
+	return c.Env == "production"
+}
+
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+}
+
+// Enabled reports whether the HTTP server should serve HTTPS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+type JWTConfig struct {
+	Algorithm    string
+	SecretKey    string
+	SigningKeys  string
+	SigningKeyID string
+	// ClockSkewSeconds is JWT_CLOCK_SKEW_SECONDS as given; empty means the default
+	ClockSkewSeconds string
+}
+
+// KeySet returns the signing key settings auth.LoadKeySet builds the keyset from
+func (c JWTConfig) KeySet() auth.KeySetConfig {
+	return auth.KeySetConfig{
+		Algorithm:    c.Algorithm,
+		SecretKey:    c.SecretKey,
+		SigningKeys:  c.SigningKeys,
+		SigningKeyID: c.SigningKeyID,
+	}
+}
+
+// ClockSkew is the leeway for token exp and nbf checks, the default when ClockSkewSeconds is
+// empty. Validate rejects values it cannot parse.
+func (c JWTConfig) ClockSkew() time.Duration {
+	seconds, err := strconv.Atoi(c.ClockSkewSeconds)
+	if err != nil {
+		seconds = constants.DefaultJWTClockSkewSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Load reads the configuration for the Postgres-backed server
+func Load() (*Config, error) {
+	return load(false)
+}
+
+// LoadSQLite reads the configuration for the SQLite-backed server, which needs no database credentials
+func LoadSQLite() (*Config, error) {
+	return load(true)
+}
+
+func load(sqlite bool) (*Config, error) {
+	// problems collects values that cannot be parsed at all; Validate checks the rest
+	var problems []error
+	getBool := func(key string) bool {
+		value, err := getBoolOrDefault(key, false)
+		if err != nil {
+			problems = append(problems, err)
+		}
+		return value
+	}
+
+	cfg := &Config{
+		Env:      getEnvOrDefault("ENV", constants.DefaultEnv),
+		Port:     getEnvOrDefault("PORT", constants.DefaultPort),
+		GRPCPort: getEnvOrDefault("GRPC_PORT", constants.DefaultGRPCPort),
+		TLS: TLSConfig{
+			CertFile: os.Getenv("TLS_CERT_FILE"),
+			KeyFile:  os.Getenv("TLS_KEY_FILE"),
+		},
+		JWT: JWTConfig{
+			Algorithm:        getEnvOrDefault("JWT_SIGNING_ALGORITHM", "HS256"),
+			SecretKey:        os.Getenv("JWT_SECRET_KEY"),
+			SigningKeys:      os.Getenv("JWT_SIGNING_KEYS"),
+			SigningKeyID:     os.Getenv("JWT_SIGNING_KEY_ID"),
+			ClockSkewSeconds: os.Getenv("JWT_CLOCK_SKEW_SECONDS"),
+		},
+		SystemUserID:                 getEnvOrDefault("SYSTEM_USER_ID", constants.DefaultSystemUserID),
+		PaginationHeaders:            getBool("PAGINATION_HEADERS"),
+		RegistrationRequiresApproval: getBool("REGISTRATION_REQUIRES_APPROVAL"),
+		EmailVerificationEnabled:     getBool("EMAIL_VERIFICATION_ENABLED"),
+		ProductCategoryCase:          strings.ToLower(getEnvOrDefault("PRODUCT_CATEGORY_CASE", constants.DefaultCategoryCase)),
+		AuditReads:                   splitList(os.Getenv("AUDIT_READS")),
+	}
+
+	if !sqlite {
+		cfg.DBDriver = getEnvOrDefault("DB_DRIVER", constants.DBDriverPostgres)
+	}
+	if sqlite {
+		cfg.SQLite = database.NewSQLiteConfig()
+	} else if cfg.DBDriver == constants.DBDriverPostgres {
+		cfg.Database = &database.DatabaseConfig{
+			Host:         getEnvOrDefault("DB_HOST", constants.DefaultDBHost),
+			Port:         getEnvOrDefault("DB_PORT", constants.DefaultDBPort),
+			User:         getEnvOrDefault("DB_USER", constants.DefaultDBUser),
+			Password:     os.Getenv("DB_PASSWORD"),
+			Name:         getEnvOrDefault("DB_NAME", constants.DefaultDBName),
+			ReplicaHosts: database.ParseReplicaHosts(os.Getenv("DB_REPLICA_HOSTS")),
+		}
+	}
+
+	if err := joinProblems(append(problems, cfg.problems()...)); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks every setting and returns one error listing all the problems found
+func (c *Config) Validate() error {
+	return joinProblems(c.problems())
+}
+
+func (c *Config) problems() []error {
+	var problems []error
+	check := func(err error) {
+		if err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	check(validatePort("PORT", c.Port))
+	check(validatePort("GRPC_PORT", c.GRPCPort))
+	if c.Port == c.GRPCPort {
+		check(fmt.Errorf("PORT and GRPC_PORT must differ, both are %s", c.Port))
+	}
+	check(c.TLS.validate())
+	check(c.JWT.validate())
+	if id, err := uuid.Parse(c.SystemUserID); err != nil || id == uuid.Nil {
+		check(fmt.Errorf("SYSTEM_USER_ID must be a non-nil UUID, got %q", c.SystemUserID))
+	}
+
+	if c.DBDriver != "" && c.DBDriver != constants.DBDriverPostgres && c.DBDriver != constants.DBDriverMemory {
+		check(fmt.Errorf("DB_DRIVER must be %s or %s, got %q", constants.DBDriverPostgres, constants.DBDriverMemory, c.DBDriver))
+	}
+	if c.ProductCategoryCase != constants.CategoryCaseLower && c.ProductCategoryCase != constants.CategoryCaseTitle {
+		check(fmt.Errorf("PRODUCT_CATEGORY_CASE must be %s or %s, got %q",
+			constants.CategoryCaseLower, constants.CategoryCaseTitle, c.ProductCategoryCase))
+	}
+	if c.Database != nil {
+		if c.Database.Password == "" {
+			check(errors.New("DB_PASSWORD is required"))
+		}
+		check(validatePort("DB_PORT", c.Database.Port))
+	}
+	if c.SQLite != nil && c.SQLite.DBPath == "" {
+		check(errors.New("SQLITE_DB_PATH must not be empty"))
+	}
+	return problems
+}
+
+func joinProblems(problems []error) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(problems...))
+}
+
+func (c TLSConfig) validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	var problems []error
+	for key, path := range map[string]string{"TLS_CERT_FILE": c.CertFile, "TLS_KEY_FILE": c.KeyFile} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			problems = append(problems, fmt.Errorf("%s %q is not readable: %w", key, path, err))
+		}
+	}
+	return errors.Join(problems...)
+}
+
+func (c JWTConfig) validate() error {
+	if c.ClockSkewSeconds != "" {
+		if seconds, err := strconv.Atoi(c.ClockSkewSeconds); err != nil || seconds < 0 {
+			return fmt.Errorf("JWT_CLOCK_SKEW_SECONDS must be a non-negative number of seconds, got %q", c.ClockSkewSeconds)
+		}
+	}
+
+	switch c.Algorithm {
+	case "HS256":
+		if c.SigningKeys == "" && c.SecretKey == "" {
+			return errors.New("JWT_SECRET_KEY or JWT_SIGNING_KEYS is required")
+		}
+	case "RS256":
+		if c.SigningKeys == "" {
+			return errors.New("JWT_SIGNING_KEYS is required for RS256")
+		}
+	default:
+		return fmt.Errorf("JWT_SIGNING_ALGORITHM must be HS256 or RS256, got %q", c.Algorithm)
+	}
+
+	if c.SigningKeys == "" {
+		return nil
+	}
+	ids := make(map[string]bool)
+	for _, entry := range strings.Split(c.SigningKeys, ",") {
+		id, value, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found || id == "" || value == "" {
+			return errors.New("JWT_SIGNING_KEYS entries must look like kid:value")
+		}
+		ids[id] = true
+	}
+	if c.SigningKeyID != "" && !ids[c.SigningKeyID] {
+		return fmt.Errorf("JWT_SIGNING_KEY_ID %q is not in JWT_SIGNING_KEYS", c.SigningKeyID)
+	}
+	return nil
+}
+
+func validatePort(key, value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("%s must be a port number between 1 and 65535, got %q", key, value)
+	}
+	return nil
+}
+
+func getBoolOrDefault(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false, got %q", key, value)
+	}
+	return parsed, nil
+}
+
+// splitList splits a comma-separated value, dropping blank entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
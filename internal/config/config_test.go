@@ -0,0 +1,162 @@
+package config
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearEnv blanks every variable Load reads, so the host environment cannot leak into a test
+func clearEnv(t *testing.T) {
+	for _, key := range []string{
+		"ENV", "PORT", "GRPC_PORT", "TLS_CERT_FILE", "TLS_KEY_FILE",
+		"JWT_SIGNING_ALGORITHM", "JWT_SECRET_KEY", "JWT_SIGNING_KEYS", "JWT_SIGNING_KEY_ID", "JWT_CLOCK_SKEW_SECONDS",
+		"DB_DRIVER", "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_REPLICA_HOSTS", "SQLITE_DB_PATH",
+		"SYSTEM_USER_ID", "PAGINATION_HEADERS", "REGISTRATION_REQUIRES_APPROVAL", "AUDIT_READS",
+		"PRODUCT_CATEGORY_CASE", "EMAIL_VERIFICATION_ENABLED",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET_KEY", "secret")
+	t.Setenv("DB_PASSWORD", "pw")
+	t.Setenv("DB_REPLICA_HOSTS", "replica-1, replica-2:5433")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "development", cfg.Env)
+	assert.Equal(t, "8080", cfg.Port)
+	assert.Equal(t, "9090", cfg.GRPCPort)
+	assert.False(t, cfg.TLS.Enabled())
+	assert.Equal(t, "HS256", cfg.JWT.Algorithm)
+	assert.Equal(t, constants.DefaultSystemUserID, cfg.SystemUserID)
+	assert.Equal(t, constants.DefaultJWTClockSkewSeconds*time.Second, cfg.JWT.ClockSkew())
+	assert.Equal(t, constants.DBDriverPostgres, cfg.DBDriver)
+	require.NotNil(t, cfg.Database)
+	assert.Equal(t, "pw", cfg.Database.Password)
+	assert.Equal(t, []string{"replica-1", "replica-2:5433"}, cfg.Database.ReplicaHosts)
+	assert.Nil(t, cfg.SQLite)
+	assert.False(t, cfg.PaginationHeaders)
+	assert.False(t, cfg.RegistrationRequiresApproval)
+	assert.Empty(t, cfg.AuditReads)
+	assert.Equal(t, constants.CategoryCaseLower, cfg.ProductCategoryCase)
+}
+
+func TestLoad_FeatureSettings(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET_KEY", "secret")
+	t.Setenv("PAGINATION_HEADERS", "true")
+	t.Setenv("REGISTRATION_REQUIRES_APPROVAL", "1")
+	t.Setenv("AUDIT_READS", "user, product")
+	t.Setenv("PRODUCT_CATEGORY_CASE", "Title")
+	t.Setenv("EMAIL_VERIFICATION_ENABLED", "true")
+	t.Setenv("JWT_CLOCK_SKEW_SECONDS", "0")
+
+	cfg, err := LoadSQLite()
+	require.NoError(t, err)
+	assert.True(t, cfg.PaginationHeaders)
+	assert.True(t, cfg.RegistrationRequiresApproval)
+	assert.Equal(t, []string{"user", "product"}, cfg.AuditReads)
+	assert.Equal(t, constants.CategoryCaseTitle, cfg.ProductCategoryCase)
+	assert.True(t, cfg.EmailVerificationEnabled)
+	assert.Zero(t, cfg.JWT.ClockSkew())
+}
+
+func TestLoad_ReportsEveryProblem(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("PORT", "http")
+	t.Setenv("GRPC_PORT", "70000")
+	t.Setenv("TLS_CERT_FILE", "/tmp/cert.pem")
+	t.Setenv("JWT_SIGNING_ALGORITHM", "ES256")
+	t.Setenv("PAGINATION_HEADERS", "yes please")
+	t.Setenv("PRODUCT_CATEGORY_CASE", "upper")
+
+	_, err := Load()
+	require.Error(t, err)
+	for _, problem := range []string{"PORT must be", "GRPC_PORT must be", "TLS_CERT_FILE and TLS_KEY_FILE", "JWT_SIGNING_ALGORITHM", "DB_PASSWORD", "PAGINATION_HEADERS must be true or false", "PRODUCT_CATEGORY_CASE must be"} {
+		assert.Contains(t, err.Error(), problem)
+	}
+}
+
+func TestLoadSQLite_NeedsNoDatabaseCredentials(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET_KEY", "secret")
+
+	cfg, err := LoadSQLite()
+	require.NoError(t, err)
+	assert.Nil(t, cfg.Database)
+	require.NotNil(t, cfg.SQLite)
+	assert.NotEmpty(t, cfg.SQLite.DBPath)
+}
+
+func TestLoad_MemoryDriverNeedsNoDatabaseCredentials(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET_KEY", "secret")
+	t.Setenv("DB_DRIVER", "memory")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, constants.DBDriverMemory, cfg.DBDriver)
+	assert.Nil(t, cfg.Database)
+	assert.Nil(t, cfg.SQLite)
+
+	t.Setenv("DB_DRIVER", "mysql")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DB_DRIVER")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	certFile := filepath.Join(t.TempDir(), "cert.pem")
+	require.NoError(t, os.WriteFile(certFile, []byte("cert"), 0o600))
+
+	valid := func() *Config {
+		return &Config{
+			Port: "8080", GRPCPort: "9090", JWT: JWTConfig{Algorithm: "HS256", SecretKey: "secret"},
+			SystemUserID: constants.DefaultSystemUserID, ProductCategoryCase: constants.DefaultCategoryCase,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		problem string
+	}{
+		{"valid", func(*Config) {}, ""},
+		{"ports clash", func(c *Config) { c.GRPCPort = "8080" }, "must differ"},
+		{"missing TLS file", func(c *Config) { c.TLS = TLSConfig{CertFile: certFile, KeyFile: "/nonexistent/key.pem"} }, "TLS_KEY_FILE"},
+		{"HS256 without secret", func(c *Config) { c.JWT.SecretKey = "" }, "JWT_SECRET_KEY or JWT_SIGNING_KEYS"},
+		{"RS256 without keys", func(c *Config) { c.JWT.Algorithm = "RS256" }, "JWT_SIGNING_KEYS is required"},
+		{"unknown category case", func(c *Config) { c.ProductCategoryCase = "upper" }, "PRODUCT_CATEGORY_CASE"},
+		{"malformed signing keys", func(c *Config) { c.JWT.SigningKeys = "no-separator" }, "kid:value"},
+		{"unknown signing key ID", func(c *Config) {
+			c.JWT.SigningKeys, c.JWT.SigningKeyID = "k1:secret", "k2"
+		}, "JWT_SIGNING_KEY_ID"},
+		{"negative clock skew", func(c *Config) { c.JWT.ClockSkewSeconds = "-5" }, "JWT_CLOCK_SKEW_SECONDS"},
+		{"non-numeric clock skew", func(c *Config) { c.JWT.ClockSkewSeconds = "30s" }, "JWT_CLOCK_SKEW_SECONDS"},
+		{"malformed system user ID", func(c *Config) { c.SystemUserID = "system" }, "SYSTEM_USER_ID"},
+		{"nil system user ID", func(c *Config) { c.SystemUserID = "00000000-0000-0000-0000-000000000000" }, "SYSTEM_USER_ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			if tt.problem == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.problem)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package graphql
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/usecase"
+	"context"
+
+	"github.com/google/uuid"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+// Resolver is the root Query resolver. Each query runs the same permission check as its HTTP route.
+type Resolver struct {
+	userUseCase    usecase.UserUseCase
+	productUseCase usecase.ProductUseCase
+	authService    repositories.AuthorizationService
+}
+
+func NewResolver(
+	userUseCase usecase.UserUseCase,
+	productUseCase usecase.ProductUseCase,
+	authService repositories.AuthorizationService,
+) *Resolver {
+	return &Resolver{
+		userUseCase:    userUseCase,
+		productUseCase: productUseCase,
+		authService:    authService,
+	}
+}
+
+type pageArgs struct {
+	Limit  *int32
+	Offset *int32
+}
+
+func (r *Resolver) User(ctx context.Context, args struct{ ID graphqlgo.ID }) (*userResolver, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, errors.ErrInvalidUserID
+	}
+
+	if err := r.authService.CheckResourcePermission(ctx, userID, constants.PermissionUserRead, constants.ActionRead, id.String()); err != nil {
+		return nil, errors.ErrInsufficientPermissions
+	}
+
+	user, err := r.userUseCase.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &userResolver{user: user}, nil
+}
+
+func (r *Resolver) Users(ctx context.Context, args pageArgs) ([]*userResolver, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.authService.CheckPermission(ctx, userID, constants.PermissionUserList, constants.ActionList); err != nil {
+		return nil, errors.ErrInsufficientPermissions
+	}
+
+	limit, offset := clampPagination(args.Limit, args.Offset)
+	users, err := r.userUseCase.List(ctx, limit, offset, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*userResolver, len(users))
+	for i, user := range users {
+		resolvers[i] = &userResolver{user: user}
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) Product(ctx context.Context, args struct{ ID graphqlgo.ID }) (*productResolver, error) {
+	if _, err := currentUserID(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, errors.ErrInvalidProductID
+	}
+
+	product, err := r.productUseCase.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &productResolver{product: product}, nil
+}
+
+func (r *Resolver) Products(ctx context.Context, args struct {
+	Category *string
+	pageArgs
+}) ([]*productResolver, error) {
+	if _, err := currentUserID(ctx); err != nil {
+		return nil, err
+	}
+
+	limit, offset := clampPagination(args.Limit, args.Offset)
+
+	var products []*entities.Product
+	var err error
+	if args.Category != nil && *args.Category != "" {
+		products, err = r.productUseCase.GetByCategory(ctx, *args.Category, limit, offset)
+	} else {
+		products, err = r.productUseCase.List(ctx, limit, offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*productResolver, len(products))
+	for i, product := range products {
+		resolvers[i] = &productResolver{product: product}
+	}
+	return resolvers, nil
+}
+
+// ProductsByIds resolves a list of product IDs, such as those a client holds from order lines, in
+// one lookup. Unknown IDs are left out of the result.
+func (r *Resolver) ProductsByIds(ctx context.Context, args struct{ IDs []graphqlgo.ID }) ([]*productResolver, error) {
+	if _, err := currentUserID(ctx); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(args.IDs))
+	for i, raw := range args.IDs {
+		id, err := uuid.Parse(string(raw))
+		if err != nil {
+			return nil, errors.ErrInvalidProductID
+		}
+		ids[i] = id
+	}
+
+	products, err := r.productUseCase.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*productResolver, len(products))
+	for i, product := range products {
+		resolvers[i] = &productResolver{product: product}
+	}
+	return resolvers, nil
+}
+
+// currentUserID fails closed when the handler was mounted without authentication
+func currentUserID(ctx context.Context) (uuid.UUID, error) {
+	userID, exists := constants.UserIDFromContext(ctx)
+	if !exists {
+		return uuid.Nil, errors.ErrUserIDNotFound
+	}
+	return userID, nil
+}
+
+func clampPagination(limit, offset *int32) (int, int) {
+	resolvedLimit, resolvedOffset := constants.DefaultLimit, constants.DefaultOffset
+	if limit != nil && *limit > 0 {
+		resolvedLimit = int(*limit)
+	}
+	if resolvedLimit > constants.MaxLimit {
+		resolvedLimit = constants.MaxLimit
+	}
+	if offset != nil && *offset > 0 {
+		resolvedOffset = int(*offset)
+	}
+	return resolvedLimit, resolvedOffset
+}
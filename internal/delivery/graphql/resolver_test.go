@@ -0,0 +1,159 @@
+package graphql
+
+import (
+	"bytes"
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/usecase"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuthorizationService struct {
+	repositories.AuthorizationService
+	denied map[string]bool
+}
+
+func (f *fakeAuthorizationService) CheckPermission(ctx context.Context, userID uuid.UUID, resource, action string) error {
+	if f.denied[resource] {
+		return errors.New("denied")
+	}
+	return nil
+}
+
+func (f *fakeAuthorizationService) CheckResourcePermission(ctx context.Context, userID uuid.UUID, resource, action, resourceID string) error {
+	return f.CheckPermission(ctx, userID, resource, action)
+}
+
+type fakeUserUseCase struct {
+	usecase.UserUseCase
+	users []*entities.User
+}
+
+func (f *fakeUserUseCase) List(ctx context.Context, limit, offset int, userID uuid.UUID) ([]*entities.User, error) {
+	return f.users, nil
+}
+
+type fakeProductUseCase struct {
+	usecase.ProductUseCase
+	products []*entities.Product
+	limit    int
+	offset   int
+}
+
+func (f *fakeProductUseCase) GetByCategory(ctx context.Context, category string, limit, offset int) ([]*entities.Product, error) {
+	f.limit, f.offset = limit, offset
+	var matched []*entities.Product
+	for _, product := range f.products {
+		if product.Category == category {
+			matched = append(matched, product)
+		}
+	}
+	return matched, nil
+}
+
+func (f *fakeProductUseCase) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error) {
+	var matched []*entities.Product
+	for _, product := range f.products {
+		for _, id := range ids {
+			if product.ID == id {
+				matched = append(matched, product)
+			}
+		}
+	}
+	return matched, nil
+}
+
+type graphqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, handler http.Handler, ctx context.Context, query string) graphqlResponse {
+	body, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp graphqlResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func authenticated() context.Context {
+	return constants.WithUserID(context.Background(), uuid.New())
+}
+
+func newTestHandler(t *testing.T, authService *fakeAuthorizationService) (http.Handler, *fakeProductUseCase) {
+	users := &fakeUserUseCase{users: []*entities.User{{Email: "jane@example.com", FirstName: "Jane", Role: constants.RoleUser}}}
+	products := &fakeProductUseCase{products: []*entities.Product{
+		{Name: "Laptop", Price: 999.5, Stock: 2, Category: "electronics"},
+		{Name: "Novel", Price: 12, Stock: 10, Category: "books"},
+	}}
+
+	handler, err := NewHandler(users, products, authService)
+	require.NoError(t, err)
+	return handler, products
+}
+
+func TestGraphQL_ProductsByCategory(t *testing.T) {
+	handler, products := newTestHandler(t, &fakeAuthorizationService{})
+
+	resp := execute(t, handler, authenticated(), `{ products(category: "books", limit: 500, offset: 3) { name price stock } }`)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `[{"name":"Novel","price":12,"stock":10}]`, string(resp.Data["products"]))
+	assert.Equal(t, constants.MaxLimit, products.limit)
+	assert.Equal(t, 3, products.offset)
+}
+
+func TestGraphQL_EnforcesAccess(t *testing.T) {
+	handler, _ := newTestHandler(t, &fakeAuthorizationService{
+		denied: map[string]bool{constants.PermissionUserList: true},
+	})
+
+	resp := execute(t, handler, authenticated(), `{ users { email } }`)
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "insufficient permissions")
+
+	resp = execute(t, handler, context.Background(), `{ products { name } }`)
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "user ID not found")
+}
+
+func TestGraphQL_Users(t *testing.T) {
+	handler, _ := newTestHandler(t, &fakeAuthorizationService{})
+
+	resp := execute(t, handler, authenticated(), `{ users(limit: 5) { email firstName role } }`)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `[{"email":"jane@example.com","firstName":"Jane","role":"user"}]`, string(resp.Data["users"]))
+}
+
+func TestGraphQL_ProductsByIds(t *testing.T) {
+	handler, products := newTestHandler(t, &fakeAuthorizationService{})
+	for _, product := range products.products {
+		product.ID = uuid.New()
+	}
+
+	query := `{ productsByIds(ids: ["` + products.products[1].ID.String() + `", "` + uuid.NewString() + `"]) { name } }`
+	resp := execute(t, handler, authenticated(), query)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `[{"name":"Novel"}]`, string(resp.Data["productsByIds"]), "unknown IDs are skipped")
+
+	resp = execute(t, handler, authenticated(), `{ productsByIds(ids: ["not-a-uuid"]) { name } }`)
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "invalid product ID")
+}
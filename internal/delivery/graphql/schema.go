@@ -0,0 +1,72 @@
+// Package graphql exposes read-only user and product queries over GraphQL.
+// The schema is declared in Go next to its resolvers and checked against them at startup.
+package graphql
+
+import (
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/usecase"
+	"net/http"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+// maxQueryDepth rejects deeply nested queries before any resolver runs
+const maxQueryDepth = 5
+
+const schema = `
+	schema {
+		query: Query
+	}
+
+	scalar Time
+
+	type Query {
+		user(id: ID!): User
+		users(limit: Int, offset: Int): [User!]!
+		product(id: ID!): Product
+		products(category: String, limit: Int, offset: Int): [Product!]!
+		productsByIds(ids: [ID!]!): [Product!]!
+	}
+
+	type User {
+		id: ID!
+		email: String!
+		firstName: String!
+		lastName: String!
+		role: String!
+		isActive: Boolean!
+		createdAt: Time!
+		updatedAt: Time!
+	}
+
+	type Product {
+		id: ID!
+		name: String!
+		description: String!
+		price: Float!
+		stock: Int!
+		category: String!
+		createdAt: Time!
+		updatedAt: Time!
+	}
+`
+
+// NewHandler parses the schema against the resolvers and serves it over HTTP. It expects the
+// request context to carry the caller identity, so mount it behind AuthMiddleware.AuthRequired.
+func NewHandler(
+	userUseCase usecase.UserUseCase,
+	productUseCase usecase.ProductUseCase,
+	authService repositories.AuthorizationService,
+) (http.Handler, error) {
+	parsed, err := graphqlgo.ParseSchema(
+		schema,
+		NewResolver(userUseCase, productUseCase, authService),
+		graphqlgo.MaxDepth(maxQueryDepth),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &relay.Handler{Schema: parsed}, nil
+}
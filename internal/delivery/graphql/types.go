@@ -0,0 +1,37 @@
+package graphql
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+type userResolver struct {
+	user *entities.User
+}
+
+func (r *userResolver) ID() graphqlgo.ID          { return graphqlgo.ID(r.user.ID.String()) }
+func (r *userResolver) Email() string             { return r.user.Email }
+func (r *userResolver) FirstName() string         { return r.user.FirstName }
+func (r *userResolver) LastName() string          { return r.user.LastName }
+func (r *userResolver) Role() string              { return r.user.Role }
+func (r *userResolver) IsActive() bool            { return r.user.IsActive }
+func (r *userResolver) CreatedAt() graphqlgo.Time { return graphqlgo.Time{Time: r.user.CreatedAt} }
+func (r *userResolver) UpdatedAt() graphqlgo.Time { return graphqlgo.Time{Time: r.user.UpdatedAt} }
+
+type productResolver struct {
+	product *entities.Product
+}
+
+func (r *productResolver) ID() graphqlgo.ID    { return graphqlgo.ID(r.product.ID.String()) }
+func (r *productResolver) Name() string        { return r.product.Name }
+func (r *productResolver) Description() string { return r.product.Description }
+func (r *productResolver) Price() float64      { return r.product.Price }
+func (r *productResolver) Stock() int32        { return int32(r.product.Stock) }
+func (r *productResolver) Category() string    { return r.product.Category }
+func (r *productResolver) CreatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.product.CreatedAt}
+}
+func (r *productResolver) UpdatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.product.UpdatedAt}
+}
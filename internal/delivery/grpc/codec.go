@@ -0,0 +1,37 @@
+// Package grpc exposes the user and product use cases over gRPC.
+// Messages are plain Go structs encoded as JSON, so no protobuf code generation is needed;
+// clients select the codec with the content subtype returned by ClientDialOption.
+package grpc
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// CodecName is the gRPC content subtype ("application/grpc+json") used by this package
+const CodecName = "json"
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return CodecName
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// ClientDialOption makes every call on the connection use the JSON codec
+func ClientDialOption() grpc.DialOption {
+	return grpc.WithDefaultCallOptions(grpc.CallContentSubtype(CodecName))
+}
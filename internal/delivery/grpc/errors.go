@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"errors"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps domain errors onto gRPC codes the same way BaseHandler maps them onto HTTP statuses
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var appErr *domainerrors.AppError
+	if !errors.As(err, &appErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return status.Error(codeFromCategory(appErr.Category), appErr.Message)
+}
+
+func codeFromCategory(category domainerrors.ErrorCategory) codes.Code {
+	switch category {
+	case domainerrors.CategoryValidation:
+		return codes.InvalidArgument
+	case domainerrors.CategoryNotFound:
+		return codes.NotFound
+	case domainerrors.CategoryUnauthorized:
+		return codes.Unauthenticated
+	case domainerrors.CategoryForbidden:
+		return codes.PermissionDenied
+	case domainerrors.CategoryConflict:
+		return codes.AlreadyExists
+	case domainerrors.CategoryRateLimited:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
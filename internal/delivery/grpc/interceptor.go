@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/internal/usecase"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// methodPermission is the resource check a method needs on top of authentication
+type methodPermission struct {
+	resource string
+	action   string
+	withID   bool
+}
+
+// methodPermissions mirrors the access middlewares on the matching HTTP routes
+var methodPermissions = map[string]methodPermission{
+	userGetMethod:       {constants.PermissionUserRead, constants.ActionRead, true},
+	userListMethod:      {constants.PermissionUserList, constants.ActionList, false},
+	productCreateMethod: {constants.PermissionProductCreate, constants.ActionCreate, false},
+	productUpdateMethod: {constants.PermissionProductUpdate, constants.ActionUpdate, true},
+	productDeleteMethod: {constants.PermissionProductDelete, constants.ActionDelete, true},
+}
+
+// AuthInterceptor is the gRPC counterpart of AuthMiddleware.AuthRequired: every call must
+// carry a bearer token in the "authorization" metadata
+type AuthInterceptor struct {
+	authUseCase usecase.AuthUseCase
+	authService repositories.AuthorizationService
+	logger      logger.Logger
+}
+
+func NewAuthInterceptor(
+	authUseCase usecase.AuthUseCase,
+	authService repositories.AuthorizationService,
+	logger logger.Logger,
+) *AuthInterceptor {
+	return &AuthInterceptor{
+		authUseCase: authUseCase,
+		authService: authService,
+		logger:      logger,
+	}
+}
+
+func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := i.authenticate(ctx)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+
+		if err := i.authorize(ctx, info.FullMethod, req); err != nil {
+			return nil, toStatus(err)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// authenticate validates the bearer token and returns a context enriched with the caller identity
+func (i *AuthInterceptor) authenticate(ctx context.Context) (context.Context, error) {
+	token := extractToken(ctx)
+	if token == "" {
+		return nil, errors.ErrAuthorizationHeaderRequired
+	}
+
+	claims, err := i.authUseCase.ValidateToken(ctx, token)
+	if err != nil {
+		i.logger.Error(errors.ErrFailedToValidateToken.Error(), err)
+		return nil, errors.ErrInvalidOrExpiredToken
+	}
+
+	enrichedCtx := i.authService.CreateEnrichedContext(ctx, claims.UserID, claims.Role, claims.Email)
+	if p, ok := peer.FromContext(ctx); ok {
+		enrichedCtx = constants.WithClientIP(enrichedCtx, clientIP(p.Addr))
+	}
+	if claims.ImpersonatedBy != nil {
+		enrichedCtx = constants.WithImpersonator(enrichedCtx, *claims.ImpersonatedBy)
+	}
+	return auth.WithPermissionCache(enrichedCtx), nil
+}
+
+func (i *AuthInterceptor) authorize(ctx context.Context, fullMethod string, req interface{}) error {
+	permission, ok := methodPermissions[fullMethod]
+	if !ok {
+		return nil
+	}
+
+	userID, exists := constants.UserIDFromContext(ctx)
+	if !exists {
+		return errors.ErrUserIDNotFound
+	}
+
+	var err error
+	if target, hasID := req.(idRequest); permission.withID && hasID {
+		err = i.authService.CheckResourcePermission(ctx, userID, permission.resource, permission.action, target.GetID())
+	} else {
+		err = i.authService.CheckPermission(ctx, userID, permission.resource, permission.action)
+	}
+	if err != nil {
+		return errors.ErrInsufficientPermissions
+	}
+	return nil
+}
+
+func extractToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 || !strings.HasPrefix(values[0], "Bearer ") {
+		return ""
+	}
+
+	return strings.TrimPrefix(values[0], "Bearer ")
+}
+
+func clientIP(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
@@ -0,0 +1,76 @@
+package grpc
+
+import "clean-architecture-api/internal/domain/entities"
+
+type GetUserRequest struct {
+	ID string `json:"id"`
+}
+
+func (r *GetUserRequest) GetID() string { return r.ID }
+
+type ListUsersRequest struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+type UserResponse struct {
+	User *entities.User `json:"user"`
+}
+
+type ListUsersResponse struct {
+	Users []*entities.User `json:"users"`
+}
+
+type ProductInput struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+	Stock       int     `json:"stock"`
+	Category    string  `json:"category"`
+}
+
+type CreateProductRequest struct {
+	ProductInput
+}
+
+type GetProductRequest struct {
+	ID string `json:"id"`
+}
+
+func (r *GetProductRequest) GetID() string { return r.ID }
+
+type UpdateProductRequest struct {
+	ID string `json:"id"`
+	ProductInput
+}
+
+func (r *UpdateProductRequest) GetID() string { return r.ID }
+
+type DeleteProductRequest struct {
+	ID string `json:"id"`
+}
+
+func (r *DeleteProductRequest) GetID() string { return r.ID }
+
+// ListProductsRequest lists all products, or only one category when Category is set
+type ListProductsRequest struct {
+	Category string `json:"category,omitempty"`
+	Limit    int    `json:"limit"`
+	Offset   int    `json:"offset"`
+}
+
+type ProductResponse struct {
+	Product *entities.Product `json:"product"`
+}
+
+type ListProductsResponse struct {
+	Products []*entities.Product `json:"products"`
+}
+
+type DeleteProductResponse struct{}
+
+// idRequest is implemented by requests that target a single resource, so the
+// interceptor can run the same per-resource check as ResourceAccessWithID
+type idRequest interface {
+	GetID() string
+}
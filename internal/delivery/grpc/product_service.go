@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/usecase"
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+const (
+	productServiceName = "api.v1.ProductService"
+
+	productCreateMethod = "/" + productServiceName + "/CreateProduct"
+	productGetMethod    = "/" + productServiceName + "/GetProduct"
+	productUpdateMethod = "/" + productServiceName + "/UpdateProduct"
+	productDeleteMethod = "/" + productServiceName + "/DeleteProduct"
+	productListMethod   = "/" + productServiceName + "/ListProducts"
+)
+
+type ProductServiceServer interface {
+	CreateProduct(ctx context.Context, req *CreateProductRequest) (*ProductResponse, error)
+	GetProduct(ctx context.Context, req *GetProductRequest) (*ProductResponse, error)
+	UpdateProduct(ctx context.Context, req *UpdateProductRequest) (*ProductResponse, error)
+	DeleteProduct(ctx context.Context, req *DeleteProductRequest) (*DeleteProductResponse, error)
+	ListProducts(ctx context.Context, req *ListProductsRequest) (*ListProductsResponse, error)
+}
+
+type productService struct {
+	productUseCase usecase.ProductUseCase
+}
+
+func NewProductService(productUseCase usecase.ProductUseCase) ProductServiceServer {
+	return &productService{productUseCase: productUseCase}
+}
+
+func (s *productService) CreateProduct(ctx context.Context, req *CreateProductRequest) (*ProductResponse, error) {
+	product := req.toEntity()
+	if err := s.productUseCase.Create(ctx, product, currentUserID(ctx)); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ProductResponse{Product: product}, nil
+}
+
+func (s *productService) GetProduct(ctx context.Context, req *GetProductRequest) (*ProductResponse, error) {
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, toStatus(errors.ErrInvalidProductID)
+	}
+
+	product, err := s.productUseCase.GetByID(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ProductResponse{Product: product}, nil
+}
+
+func (s *productService) UpdateProduct(ctx context.Context, req *UpdateProductRequest) (*ProductResponse, error) {
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, toStatus(errors.ErrInvalidProductID)
+	}
+
+	product := req.toEntity()
+	product.ID = id
+	if err := s.productUseCase.Update(ctx, product); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ProductResponse{Product: product}, nil
+}
+
+func (s *productService) DeleteProduct(ctx context.Context, req *DeleteProductRequest) (*DeleteProductResponse, error) {
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, toStatus(errors.ErrInvalidProductID)
+	}
+
+	if err := s.productUseCase.Delete(ctx, id); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &DeleteProductResponse{}, nil
+}
+
+func (s *productService) ListProducts(ctx context.Context, req *ListProductsRequest) (*ListProductsResponse, error) {
+	limit, offset := usecase.ClampPagination(req.Limit, req.Offset)
+
+	var products []*entities.Product
+	var err error
+	if req.Category != "" {
+		products, err = s.productUseCase.GetByCategory(ctx, req.Category, limit, offset)
+	} else {
+		products, err = s.productUseCase.List(ctx, limit, offset)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ListProductsResponse{Products: products}, nil
+}
+
+func (in ProductInput) toEntity() *entities.Product {
+	return &entities.Product{
+		Name:        in.Name,
+		Description: in.Description,
+		Price:       in.Price,
+		Stock:       in.Stock,
+		Category:    in.Category,
+	}
+}
+
+var productServiceDesc = grpc.ServiceDesc{
+	ServiceName: productServiceName,
+	HandlerType: (*ProductServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "CreateProduct", Handler: unaryHandler(productCreateMethod, ProductServiceServer.CreateProduct)},
+		{MethodName: "GetProduct", Handler: unaryHandler(productGetMethod, ProductServiceServer.GetProduct)},
+		{MethodName: "UpdateProduct", Handler: unaryHandler(productUpdateMethod, ProductServiceServer.UpdateProduct)},
+		{MethodName: "DeleteProduct", Handler: unaryHandler(productDeleteMethod, ProductServiceServer.DeleteProduct)},
+		{MethodName: "ListProducts", Handler: unaryHandler(productListMethod, ProductServiceServer.ListProducts)},
+	},
+}
+
+func RegisterProductServiceServer(registrar grpc.ServiceRegistrar, srv ProductServiceServer) {
+	registrar.RegisterService(&productServiceDesc, srv)
+}
+
+// ProductServiceClient calls ProductService on a connection dialed with ClientDialOption
+type ProductServiceClient struct {
+	conn grpc.ClientConnInterface
+}
+
+func NewProductServiceClient(conn grpc.ClientConnInterface) *ProductServiceClient {
+	return &ProductServiceClient{conn: conn}
+}
+
+func (c *ProductServiceClient) CreateProduct(ctx context.Context, req *CreateProductRequest, opts ...grpc.CallOption) (*ProductResponse, error) {
+	return invoke[ProductResponse](ctx, c.conn, productCreateMethod, req, opts)
+}
+
+func (c *ProductServiceClient) GetProduct(ctx context.Context, req *GetProductRequest, opts ...grpc.CallOption) (*ProductResponse, error) {
+	return invoke[ProductResponse](ctx, c.conn, productGetMethod, req, opts)
+}
+
+func (c *ProductServiceClient) UpdateProduct(ctx context.Context, req *UpdateProductRequest, opts ...grpc.CallOption) (*ProductResponse, error) {
+	return invoke[ProductResponse](ctx, c.conn, productUpdateMethod, req, opts)
+}
+
+func (c *ProductServiceClient) DeleteProduct(ctx context.Context, req *DeleteProductRequest, opts ...grpc.CallOption) (*DeleteProductResponse, error) {
+	return invoke[DeleteProductResponse](ctx, c.conn, productDeleteMethod, req, opts)
+}
+
+func (c *ProductServiceClient) ListProducts(ctx context.Context, req *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error) {
+	return invoke[ListProductsResponse](ctx, c.conn, productListMethod, req, opts)
+}
@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/usecase"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"net"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+// Server serves the gRPC API on its own listener, next to the HTTP server
+type Server struct {
+	server *grpc.Server
+	logger logger.Logger
+}
+
+func NewServer(
+	authUseCase usecase.AuthUseCase,
+	userUseCase usecase.UserUseCase,
+	productUseCase usecase.ProductUseCase,
+	authService repositories.AuthorizationService,
+	logger logger.Logger,
+	opts ...grpc.ServerOption,
+) *Server {
+	interceptor := NewAuthInterceptor(authUseCase, authService, logger)
+	opts = append(opts, grpc.ChainUnaryInterceptor(interceptor.Unary()))
+
+	server := grpc.NewServer(opts...)
+	RegisterUserServiceServer(server, NewUserService(userUseCase))
+	RegisterProductServiceServer(server, NewProductService(productUseCase))
+
+	return &Server{server: server, logger: logger}
+}
+
+// Run listens on addr and serves until Shutdown is called
+func (s *Server) Run(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+func (s *Server) Serve(listener net.Listener) error {
+	err := s.server.Serve(listener)
+	if err == grpc.ErrServerStopped {
+		return nil
+	}
+	return err
+}
+
+// Shutdown waits for in-flight calls to finish, and cancels them once ctx expires
+func (s *Server) Shutdown(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.server.Stop()
+		<-done
+	}
+}
+
+// unaryHandler adapts a typed service method to grpc.MethodDesc, running the interceptor chain
+func unaryHandler[S any, Req any, Resp any](
+	fullMethod string,
+	call func(S, context.Context, *Req) (*Resp, error),
+) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		req := new(Req)
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(srv.(S), ctx, req.(*Req))
+		}
+		if interceptor == nil {
+			return handler(ctx, req)
+		}
+
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}
+		return interceptor(ctx, req, info, handler)
+	}
+}
+
+func invoke[Resp any](ctx context.Context, conn grpc.ClientConnInterface, method string, req interface{}, opts []grpc.CallOption) (*Resp, error) {
+	resp := new(Resp)
+	if err := conn.Invoke(ctx, method, req, resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func currentUserID(ctx context.Context) uuid.UUID {
+	if userID, exists := constants.UserIDFromContext(ctx); exists {
+		return userID
+	}
+	return constants.SystemUserID()
+}
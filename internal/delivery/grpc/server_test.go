@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	domainerrors "clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/internal/usecase"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testToken = "valid-token"
+
+type fakeAuthUseCase struct {
+	usecase.AuthUseCase
+	claims *auth.Claims
+}
+
+func (f *fakeAuthUseCase) ValidateToken(ctx context.Context, token string) (*auth.Claims, error) {
+	if token != testToken {
+		return nil, errors.New("invalid token")
+	}
+	return f.claims, nil
+}
+
+type fakeAuthorizationService struct {
+	repositories.AuthorizationService
+	denied map[string]bool
+}
+
+func (f *fakeAuthorizationService) CheckPermission(ctx context.Context, userID uuid.UUID, resource, action string) error {
+	if f.denied[resource] {
+		return errors.New("denied")
+	}
+	return nil
+}
+
+func (f *fakeAuthorizationService) CheckResourcePermission(ctx context.Context, userID uuid.UUID, resource, action, resourceID string) error {
+	return f.CheckPermission(ctx, userID, resource, action)
+}
+
+func (f *fakeAuthorizationService) CreateEnrichedContext(ctx context.Context, userID uuid.UUID, role, email string) context.Context {
+	ctx = constants.WithUserID(ctx, userID)
+	return constants.WithUserRole(ctx, role)
+}
+
+type fakeProductUseCase struct {
+	usecase.ProductUseCase
+	products  map[uuid.UUID]*entities.Product
+	createdBy uuid.UUID
+}
+
+func (f *fakeProductUseCase) Create(ctx context.Context, product *entities.Product, userID uuid.UUID) error {
+	if err := product.Validate(); err != nil {
+		return err
+	}
+	product.ID = uuid.New()
+	f.products[product.ID] = product
+	f.createdBy = userID
+	return nil
+}
+
+func (f *fakeProductUseCase) GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
+	product, ok := f.products[id]
+	if !ok {
+		return nil, domainerrors.ErrProductNotFound
+	}
+	return product, nil
+}
+
+func newTestClient(t *testing.T, authService *fakeAuthorizationService) (*ProductServiceClient, *fakeProductUseCase, uuid.UUID) {
+	userID := uuid.New()
+	authUseCase := &fakeAuthUseCase{claims: &auth.Claims{UserID: userID, Role: constants.RoleUser}}
+	productUseCase := &fakeProductUseCase{products: make(map[uuid.UUID]*entities.Product)}
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(authUseCase, nil, productUseCase, authService, logger.NewLogger())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		ClientDialOption(),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return NewProductServiceClient(conn), productUseCase, userID
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestProductService_CreateAndGet(t *testing.T) {
+	client, productUseCase, userID := newTestClient(t, &fakeAuthorizationService{})
+	ctx := withToken(testToken)
+
+	created, err := client.CreateProduct(ctx, &CreateProductRequest{ProductInput{Name: "Widget", Price: 9.5, Stock: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, "Widget", created.Product.Name)
+	assert.Equal(t, userID, productUseCase.createdBy)
+
+	fetched, err := client.GetProduct(ctx, &GetProductRequest{ID: created.Product.ID.String()})
+	require.NoError(t, err)
+	assert.Equal(t, created.Product.ID, fetched.Product.ID)
+	assert.Equal(t, 9.5, fetched.Product.Price)
+}
+
+func TestProductService_MapsDomainErrors(t *testing.T) {
+	client, _, _ := newTestClient(t, &fakeAuthorizationService{})
+	ctx := withToken(testToken)
+
+	_, err := client.GetProduct(ctx, &GetProductRequest{ID: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.GetProduct(ctx, &GetProductRequest{ID: uuid.New().String()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	client, _, _ := newTestClient(t, &fakeAuthorizationService{
+		denied: map[string]bool{constants.PermissionProductCreate: true},
+	})
+
+	_, err := client.GetProduct(context.Background(), &GetProductRequest{ID: uuid.New().String()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.GetProduct(withToken("wrong"), &GetProductRequest{ID: uuid.New().String()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.CreateProduct(withToken(testToken), &CreateProductRequest{ProductInput{Name: "Widget", Price: 1}})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/usecase"
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+const (
+	userServiceName = "api.v1.UserService"
+
+	userGetMethod  = "/" + userServiceName + "/GetUser"
+	userListMethod = "/" + userServiceName + "/ListUsers"
+)
+
+// UserServiceServer is the read-only user API
+type UserServiceServer interface {
+	GetUser(ctx context.Context, req *GetUserRequest) (*UserResponse, error)
+	ListUsers(ctx context.Context, req *ListUsersRequest) (*ListUsersResponse, error)
+}
+
+type userService struct {
+	userUseCase usecase.UserUseCase
+}
+
+func NewUserService(userUseCase usecase.UserUseCase) UserServiceServer {
+	return &userService{userUseCase: userUseCase}
+}
+
+func (s *userService) GetUser(ctx context.Context, req *GetUserRequest) (*UserResponse, error) {
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, toStatus(errors.ErrInvalidUserID)
+	}
+
+	user, err := s.userUseCase.GetByID(ctx, id, currentUserID(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &UserResponse{User: user}, nil
+}
+
+func (s *userService) ListUsers(ctx context.Context, req *ListUsersRequest) (*ListUsersResponse, error) {
+	limit, offset := usecase.ClampPagination(req.Limit, req.Offset)
+
+	users, err := s.userUseCase.List(ctx, limit, offset, currentUserID(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &ListUsersResponse{Users: users}, nil
+}
+
+var userServiceDesc = grpc.ServiceDesc{
+	ServiceName: userServiceName,
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "GetUser", Handler: unaryHandler(userGetMethod, UserServiceServer.GetUser)},
+		{MethodName: "ListUsers", Handler: unaryHandler(userListMethod, UserServiceServer.ListUsers)},
+	},
+}
+
+func RegisterUserServiceServer(registrar grpc.ServiceRegistrar, srv UserServiceServer) {
+	registrar.RegisterService(&userServiceDesc, srv)
+}
+
+// UserServiceClient calls UserService on a connection dialed with ClientDialOption
+type UserServiceClient struct {
+	conn grpc.ClientConnInterface
+}
+
+func NewUserServiceClient(conn grpc.ClientConnInterface) *UserServiceClient {
+	return &UserServiceClient{conn: conn}
+}
+
+func (c *UserServiceClient) GetUser(ctx context.Context, req *GetUserRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	return invoke[UserResponse](ctx, c.conn, userGetMethod, req, opts)
+}
+
+func (c *UserServiceClient) ListUsers(ctx context.Context, req *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	return invoke[ListUsersResponse](ctx, c.conn, userListMethod, req, opts)
+}
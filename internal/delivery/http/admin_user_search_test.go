@@ -0,0 +1,79 @@
+package http
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/infrastructure/database"
+	"clean-architecture-api/pkg/logger"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminUserSearch(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	db, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+	server, err := NewServer(testConfig(t), db, logger.NewLogger())
+	require.NoError(t, err)
+
+	for _, person := range []map[string]string{
+		{"email": "root@example.com", "first_name": "Root", "last_name": "Admin"},
+		{"email": "ada@example.com", "first_name": "Ada", "last_name": "Lovelace"},
+		{"email": "adam@example.com", "first_name": "Adam", "last_name": "Smith"},
+		{"email": "lovelace.fan@example.com", "first_name": "Fan", "last_name": "Club"},
+	} {
+		person["password"] = "password123"
+		rec := doJSON(t, server, http.MethodPost, "/api/v1/auth/register", "", person)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	require.NoError(t, db.Model(&entities.UserSQLite{}).Where("email IN ?", []string{"root@example.com", "ada@example.com"}).
+		Update("role", constants.RoleAdmin).Error)
+	require.NoError(t, db.Model(&entities.UserSQLite{}).Where("email = ?", "adam@example.com").
+		Update("is_active", false).Error)
+	adminToken := loginAs(t, server, map[string]string{"email": "root@example.com", "password": "password123"})
+
+	search := func(query string) ([]string, int64) {
+		rec := doJSON(t, server, http.MethodGet, "/api/v1/admin/users/search?"+query, adminToken, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp struct {
+			Data struct {
+				Users []entities.User `json:"users"`
+				Total int64           `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		emails := make([]string, len(resp.Data.Users))
+		for i, user := range resp.Data.Users {
+			emails[i] = user.Email
+		}
+		return emails, resp.Data.Total
+	}
+
+	emails, total := search("q=LOVELACE&sort=email")
+	assert.Equal(t, []string{"ada@example.com", "lovelace.fan@example.com"}, emails, "last name and email match")
+	assert.Equal(t, int64(2), total)
+
+	emails, _ = search("q=ada&role=admin")
+	assert.Equal(t, []string{"ada@example.com"}, emails)
+	emails, _ = search("q=ada&active=false")
+	assert.Equal(t, []string{"adam@example.com"}, emails)
+	emails, _ = search("q=ada&role=user&active=true")
+	assert.Empty(t, emails)
+
+	emails, total = search("q=example.com&active=true&limit=1&sort=-email")
+	assert.Equal(t, []string{"root@example.com"}, emails)
+	assert.Equal(t, int64(3), total, "the total counts every page")
+
+	for _, query := range []string{"role=owner", "active=maybe", "status=pending", "sort=password"} {
+		rec := doJSON(t, server, http.MethodGet, "/api/v1/admin/users/search?"+query, adminToken, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query+": "+rec.Body.String())
+	}
+
+	_, token := registerAndLogin(t, server, "curious@example.com")
+	rec := doJSON(t, server, http.MethodGet, "/api/v1/admin/users/search?q=ada", token, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+}
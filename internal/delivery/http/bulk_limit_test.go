@@ -0,0 +1,59 @@
+package http
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/infrastructure/database"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxBulkItems_RejectsOversizedImport(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	t.Setenv("MAX_BULK_ITEMS", "2")
+	db, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+	server, err := NewServer(testConfig(t), db, logger.NewLogger())
+	require.NoError(t, err)
+
+	credentials := map[string]string{
+		"email": "root@example.com", "password": "password123", "first_name": "Root", "last_name": "Admin",
+	}
+	rec := doJSON(t, server, http.MethodPost, "/api/v1/auth/register", "", credentials)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NoError(t, db.Model(&entities.UserSQLite{}).Where("email = ?", "root@example.com").
+		Update("role", constants.RoleAdmin).Error)
+	adminToken := loginAs(t, server, credentials)
+
+	named := func(name string) *entities.PolicyDocument {
+		policy := productReadPolicy()
+		policy.Name = name
+		return policy
+	}
+	before, err := server.policyEngine.GetPoliciesForRole(context.Background(), constants.RoleUser)
+	require.NoError(t, err)
+
+	// An invalid policy would fail validation, so a 400 for the size shows the batch was turned
+	// away before any item was looked at
+	broken := named("bulk-broken")
+	broken.Statements[0].Effect = "maybe"
+	set := entities.PolicySet{Policies: []*entities.PolicyDocument{named("bulk-a"), named("bulk-b"), broken}}
+	rec = doJSON(t, server, http.MethodPost, "/api/v1/admin/policies/import", adminToken, set)
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"error":{"category":"validation","code":"TOO_MANY_BULK_ITEMS",
+		"message":"batch has more items than allowed","max_items":2}}`, rec.Body.String())
+
+	after, err := server.policyEngine.GetPoliciesForRole(context.Background(), constants.RoleUser)
+	require.NoError(t, err)
+	assert.Len(t, after, len(before))
+
+	// A batch at the limit goes through
+	set.Policies = set.Policies[:2]
+	rec = doJSON(t, server, http.MethodPost, "/api/v1/admin/policies/import", adminToken, set)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"clean-architecture-api/internal/infrastructure/database"
+	"clean-architecture-api/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resolvedClientIP(server *Server) string {
+	server.router.GET("/test/client-ip", func(c *gin.Context) {
+		c.String(http.StatusOK, c.ClientIP())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test/client-ip", nil)
+	req.RemoteAddr = "10.0.0.5:41234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+	return rec.Body.String()
+}
+
+func TestClientIP_TrustedProxies(t *testing.T) {
+	assert.Equal(t, "10.0.0.5", resolvedClientIP(newTestServer(t)), "X-Forwarded-For must be ignored by default")
+
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1")
+	assert.Equal(t, "203.0.113.7", resolvedClientIP(newTestServer(t)))
+
+	t.Setenv("TRUSTED_PROXIES", "192.168.1.1")
+	assert.Equal(t, "10.0.0.5", resolvedClientIP(newTestServer(t)), "untrusted hops must not set the client IP")
+
+	t.Setenv("TRUSTED_PROXIES", "not-an-ip")
+	db, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+	_, err = NewServer(testConfig(t), db, logger.NewLogger())
+	assert.Error(t, err)
+}
@@ -0,0 +1,231 @@
+package http
+
+import (
+	"clean-architecture-api/internal/delivery/middleware"
+	"clean-architecture-api/internal/domain/constants"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ServerConfig struct {
+	TLSMinVersion     uint16
+	TLSCipherSuites   []uint16
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// SlowRequestThreshold is the latency above which a request is logged and counted as slow
+	SlowRequestThreshold time.Duration
+	// CORS is nil when CORS_ALLOWED_ORIGINS is unset
+	CORS        *middleware.CORSConfig
+	Compression middleware.CompressionConfig
+	BodyLimit   middleware.BodyLimitConfig
+	RateLimit   middleware.RateLimitConfig
+	// RequestTimeout bounds how long a request's context lives
+	RequestTimeout middleware.RequestTimeoutConfig
+	// TrustedProxies lists the IPs and CIDRs whose X-Forwarded-For is honoured; empty trusts none
+	TrustedProxies []string
+	// ProductsPublic serves product reads without a token; otherwise they need product list/read permissions
+	ProductsPublic bool
+	// PublicProductRoutes narrows ProductsPublic to some of the product read routes, keyed by
+	// constants.ProductRoute*
+	PublicProductRoutes map[string]bool
+	// ProductsPublicView serves anonymous callers of the public product routes the catalog view,
+	// while callers that send a token are authenticated and get full products
+	ProductsPublicView bool
+}
+
+// TLSConfig returns the protocol and cipher suite policy shared by the HTTPS and gRPC listeners
+func (c *ServerConfig) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:   c.TLSMinVersion,
+		CipherSuites: append([]uint16(nil), c.TLSCipherSuites...),
+	}
+}
+
+// ProductRoutePublic reports whether the named product read route is served without a token
+func (c *ServerConfig) ProductRoutePublic(route string) bool {
+	return c.ProductsPublic && c.PublicProductRoutes[route]
+}
+
+func NewServerConfig() (*ServerConfig, error) {
+	minVersion, err := parseTLSVersion(getEnvOrDefault("TLS_MIN_VERSION", "1.2"))
+	if err != nil {
+		return nil, err
+	}
+
+	cipherSuites, err := parseTLSCipherSuites(getEnvOrDefault("TLS_CIPHER_SUITES", constants.DefaultTLSCipherSuites))
+	if err != nil {
+		return nil, err
+	}
+
+	productsPublic, err := getBoolOrDefault("PRODUCTS_PUBLIC", constants.DefaultProductsPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	publicProductRoutes, err := parsePublicProductRoutes(getEnvOrDefault("PRODUCTS_PUBLIC_ROUTES", constants.DefaultPublicProductRoutes))
+	if err != nil {
+		return nil, err
+	}
+
+	productsPublicView, err := getBoolOrDefault("PRODUCTS_PUBLIC_VIEW", constants.DefaultProductsPublicView)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ServerConfig{
+		TLSMinVersion:       minVersion,
+		TLSCipherSuites:     cipherSuites,
+		ProductsPublic:      productsPublic,
+		PublicProductRoutes: publicProductRoutes,
+		ProductsPublicView:  productsPublicView,
+	}
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			config.TrustedProxies = append(config.TrustedProxies, proxy)
+		}
+	}
+
+	timeouts := []struct {
+		key          string
+		defaultValue time.Duration
+		target       *time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", constants.DefaultHTTPReadTimeout, &config.ReadTimeout},
+		{"HTTP_READ_HEADER_TIMEOUT", constants.DefaultHTTPReadHeaderTimeout, &config.ReadHeaderTimeout},
+		{"HTTP_WRITE_TIMEOUT", constants.DefaultHTTPWriteTimeout, &config.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", constants.DefaultHTTPIdleTimeout, &config.IdleTimeout},
+		{"SLOW_REQUEST_THRESHOLD", constants.DefaultSlowRequestThreshold, &config.SlowRequestThreshold},
+	}
+	for _, timeout := range timeouts {
+		value, err := getDurationOrDefault(timeout.key, timeout.defaultValue)
+		if err != nil {
+			return nil, err
+		}
+		*timeout.target = value
+	}
+
+	if config.CORS, err = middleware.NewCORSConfigFromEnv(); err != nil {
+		return nil, err
+	}
+	if config.Compression, err = middleware.NewCompressionConfigFromEnv(); err != nil {
+		return nil, err
+	}
+	if config.BodyLimit, err = middleware.NewBodyLimitConfigFromEnv(); err != nil {
+		return nil, err
+	}
+	if config.RateLimit, err = middleware.NewRateLimitConfigFromEnv(); err != nil {
+		return nil, err
+	}
+	if config.RequestTimeout, err = middleware.NewRequestTimeoutConfigFromEnv(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func getDurationOrDefault(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive duration such as 15s", key, value)
+	}
+	return duration, nil
+}
+
+func getBoolOrDefault(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: must be true or false", key, value)
+	}
+	return parsed, nil
+}
+
+func parsePublicProductRoutes(value string) (map[string]bool, error) {
+	routes := make(map[string]bool)
+	for _, route := range strings.Split(value, ",") {
+		switch route = strings.ToLower(strings.TrimSpace(route)); route {
+		case "":
+		case constants.ProductRouteList, constants.ProductRouteCategory, constants.ProductRouteDetail:
+			routes[route] = true
+		default:
+			return nil, fmt.Errorf("invalid PRODUCTS_PUBLIC_ROUTES entry %q: must be list, category or detail", route)
+		}
+	}
+	return routes, nil
+}
+
+func parseTLSVersion(value string) (uint16, error) {
+	switch value {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS_MIN_VERSION %q: must be 1.2 or 1.3", value)
+	}
+}
+
+// parseTLSCipherSuites resolves a comma-separated list of Go cipher suite names such as
+// TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. Suites Go considers insecure are refused, as are TLS 1.3
+// suites, which cannot be restricted.
+func parseTLSCipherSuites(value string) ([]uint16, error) {
+	known := make(map[string]*tls.CipherSuite)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite
+	}
+
+	var ids []uint16
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		suite, ok := known[name]
+		if !ok {
+			for _, insecure := range tls.InsecureCipherSuites() {
+				if insecure.Name == name {
+					return nil, fmt.Errorf("invalid TLS_CIPHER_SUITES entry %q: cipher suite is insecure", name)
+				}
+			}
+			return nil, fmt.Errorf("invalid TLS_CIPHER_SUITES entry %q: unknown cipher suite", name)
+		}
+		if !supportsTLS12(suite) {
+			return nil, fmt.Errorf("invalid TLS_CIPHER_SUITES entry %q: TLS 1.3 suites cannot be configured", name)
+		}
+		ids = append(ids, suite.ID)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("invalid TLS_CIPHER_SUITES %q: must name at least one cipher suite", value)
+	}
+	return ids, nil
+}
+
+func supportsTLS12(suite *tls.CipherSuite) bool {
+	for _, version := range suite.SupportedVersions {
+		if version == tls.VersionTLS12 {
+			return true
+		}
+	}
+	return false
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServerConfig_TLS(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		config, err := NewServerConfig()
+		require.NoError(t, err)
+
+		tlsConfig := config.TLSConfig()
+		assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+		assert.Contains(t, tlsConfig.CipherSuites, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+		for _, insecure := range tls.InsecureCipherSuites() {
+			assert.NotContains(t, tlsConfig.CipherSuites, insecure.ID, insecure.Name)
+		}
+	})
+
+	t.Run("from env", func(t *testing.T) {
+		t.Setenv("TLS_MIN_VERSION", "1.3")
+		t.Setenv("TLS_CIPHER_SUITES", "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256")
+		config, err := NewServerConfig()
+		require.NoError(t, err)
+
+		tlsConfig := config.TLSConfig()
+		assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+		assert.Equal(t, []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		}, tlsConfig.CipherSuites)
+	})
+
+	invalid := []struct {
+		name  string
+		key   string
+		value string
+		want  string
+	}{
+		{name: "old protocol", key: "TLS_MIN_VERSION", value: "1.0", want: "TLS_MIN_VERSION"},
+		{name: "unknown suite", key: "TLS_CIPHER_SUITES", value: "TLS_MADE_UP", want: "unknown cipher suite"},
+		{name: "insecure suite", key: "TLS_CIPHER_SUITES", value: "TLS_RSA_WITH_RC4_128_SHA", want: "insecure"},
+		{name: "TLS 1.3 suite", key: "TLS_CIPHER_SUITES", value: "TLS_AES_128_GCM_SHA256", want: "TLS 1.3"},
+		{name: "empty list", key: "TLS_CIPHER_SUITES", value: " , ", want: "at least one"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			_, err := NewServerConfig()
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"bytes"
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/infrastructure/database"
+	"clean-architecture-api/pkg/logger"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireJSON_Routes(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	db, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+	server, err := NewServer(testConfig(t), db, logger.NewLogger())
+	require.NoError(t, err)
+
+	credentials := map[string]string{
+		"email": "root@example.com", "password": "password123", "first_name": "Root", "last_name": "Admin",
+	}
+	rec := doJSON(t, server, http.MethodPost, "/api/v1/auth/register", "", credentials)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader("email=root%40example.com&password=password123"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.JSONEq(t, `{"error":"`+domainerrors.ErrUnsupportedMediaType.Error()+`"}`, rec.Body.String())
+
+	// The policy import also takes the document as a multipart file upload
+	require.NoError(t, db.Model(&entities.UserSQLite{}).Where("email = ?", "root@example.com").
+		Update("role", constants.RoleAdmin).Error)
+	adminToken := loginAs(t, server, credentials)
+
+	document, err := json.Marshal(entities.PolicySet{Policies: []*entities.PolicyDocument{productReadPolicy()}})
+	require.NoError(t, err)
+	var upload bytes.Buffer
+	form := multipart.NewWriter(&upload)
+	part, err := form.CreateFormFile("file", "policies.json")
+	require.NoError(t, err)
+	_, err = part.Write(document)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/admin/policies/import", &upload)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rec = httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
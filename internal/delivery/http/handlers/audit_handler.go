@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditPurger deletes audit entries that fell out of the retention window
+type AuditPurger interface {
+	RunOnce(ctx context.Context) (int, error)
+}
+
+type AuditHandler struct {
+	*BaseHandler
+	purger AuditPurger
+}
+
+func NewAuditHandler(purger AuditPurger, logger logger.Logger) *AuditHandler {
+	return &AuditHandler{
+		BaseHandler: NewBaseHandler(logger),
+		purger:      purger,
+	}
+}
+
+// PurgeAuditLogs runs the retention sweep on demand instead of waiting for the next interval
+func (h *AuditHandler) PurgeAuditLogs(c *gin.Context) {
+	deleted, err := h.purger.RunOnce(c.Request.Context())
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to purge audit logs", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"deleted": deleted})
+}
@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/errors"
 	"clean-architecture-api/internal/usecase"
 	"clean-architecture-api/pkg/logger"
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
@@ -27,17 +30,49 @@ type RegisterRequest struct {
 	Password  string `json:"password" binding:"required"`
 	FirstName string `json:"first_name" binding:"required"`
 	LastName  string `json:"last_name" binding:"required"`
+	// ChallengeToken is the CAPTCHA response; only required when CHALLENGE_ENABLED=true
+	ChallengeToken string `json:"challenge_token,omitempty"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Email          string `json:"email" binding:"required"`
+	Password       string `json:"password" binding:"required"`
+	ChallengeToken string `json:"challenge_token,omitempty"`
+	// RememberMe requests a longer-lived refresh token
+	RememberMe bool `json:"remember_me"`
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+type IntrospectRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// TokenIntrospection describes a token in the style of OAuth2 introspection (RFC 7662). An
+// inactive token carries nothing but active=false.
+type TokenIntrospection struct {
+	Active bool       `json:"active"`
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	Email  string     `json:"email,omitempty"`
+	Role   string     `json:"role,omitempty"`
+	Exp    int64      `json:"exp,omitempty"`
+}
+
+type ChangeEmailRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -45,7 +80,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := h.authUseCase.Register(c.Request.Context(), req.Email, req.Password, req.FirstName, req.LastName)
+	user, err := h.authUseCase.Register(h.withChallenge(c, req.ChallengeToken), req.Email, req.Password, req.FirstName, req.LastName)
 	if err != nil {
 		h.SendErrorResponse(c, http.StatusBadRequest, "Registration failed", err)
 		return
@@ -64,7 +99,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	tokenPair, err := h.authUseCase.Login(c.Request.Context(), req.Email, req.Password)
+	ctx := h.withClientInfo(c, h.withChallenge(c, req.ChallengeToken))
+	tokenPair, err := h.authUseCase.Login(ctx, req.Email, req.Password, req.RememberMe)
 	if err != nil {
 		h.SendErrorResponse(c, http.StatusUnauthorized, "Login failed", err)
 		return
@@ -76,6 +112,15 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	})
 }
 
+func (h *AuthHandler) withChallenge(c *gin.Context, response string) context.Context {
+	return constants.WithChallenge(c.Request.Context(), constants.Challenge{Response: response, RemoteIP: c.ClientIP()})
+}
+
+// withClientInfo records where a request that starts a session came from
+func (h *AuthHandler) withClientInfo(c *gin.Context, ctx context.Context) context.Context {
+	return constants.WithClientInfo(ctx, constants.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()})
+}
+
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -94,3 +139,273 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		"tokens":  tokenPair,
 	})
 }
+
+// Introspect reports whether the token in the body would be accepted on a protected route, and
+// for whom. It changes nothing; a token that does not validate is answered with active=false
+// rather than an error.
+func (h *AuthHandler) Introspect(c *gin.Context) {
+	var req IntrospectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendBadRequest(c, errors.ErrInvalidRequest.Error())
+		return
+	}
+
+	claims, err := h.authUseCase.ValidateToken(c.Request.Context(), req.Token)
+	if err != nil {
+		h.SendSuccessResponse(c, http.StatusOK, TokenIntrospection{Active: false})
+		return
+	}
+
+	introspection := TokenIntrospection{
+		Active: true,
+		UserID: &claims.UserID,
+		Email:  claims.Email,
+		Role:   claims.Role,
+	}
+	if claims.ExpiresAt != nil {
+		introspection.Exp = claims.ExpiresAt.Unix()
+	}
+	h.SendSuccessResponse(c, http.StatusOK, introspection)
+}
+
+// Logout ends the session of the refresh token in the body
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendBadRequest(c, errors.ErrInvalidRequest.Error())
+		return
+	}
+
+	if err := h.authUseCase.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		h.SendErrorResponse(c, http.StatusUnauthorized, "Logout failed", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// ListSessions lists the caller's signed-in sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to list sessions", err)
+		return
+	}
+
+	sessions, err := h.authUseCase.ListSessions(c.Request.Context(), userID)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to list sessions", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSession signs the caller out of the :id session
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	sessionID, err := h.ParseUUID(c, "id")
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Invalid session ID", err)
+		return
+	}
+
+	userID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Session revocation failed", err)
+		return
+	}
+
+	if err := h.authUseCase.RevokeSession(c.Request.Context(), userID, sessionID); err != nil {
+		h.SendErrorResponse(c, 0, "Session revocation failed", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// Impersonate issues the calling admin a short-lived token acting as the :id user
+func (h *AuthHandler) Impersonate(c *gin.Context) {
+	targetID, err := h.ParseUUID(c, "id")
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Invalid user ID", err)
+		return
+	}
+
+	adminID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Impersonation failed", err)
+		return
+	}
+
+	tokenPair, err := h.authUseCase.Impersonate(c.Request.Context(), targetID, adminID)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Impersonation failed", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{
+		"message": "Impersonation token issued",
+		"tokens":  tokenPair,
+	})
+}
+
+// RevokeUserTokens signs the :id user out of every session
+func (h *AuthHandler) RevokeUserTokens(c *gin.Context) {
+	targetID, err := h.ParseUUID(c, "id")
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Invalid user ID", err)
+		return
+	}
+
+	adminID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Token revocation failed", err)
+		return
+	}
+
+	if err := h.authUseCase.RevokeUserTokens(c.Request.Context(), targetID, adminID); err != nil {
+		h.SendErrorResponse(c, 0, "Token revocation failed", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"message": "All tokens of the user were revoked"})
+}
+
+// adminUserListParams selects the users an admin lists; pending is the only status so far
+type adminUserListParams struct {
+	Status string `form:"status" binding:"required,oneof=pending"`
+}
+
+// ListUsersByStatus lists the registrations waiting for approval, oldest first
+func (h *AuthHandler) ListUsersByStatus(c *gin.Context) {
+	var params adminUserListParams
+	if err := h.BindQuery(c, &params); err != nil {
+		h.SendErrorResponse(c, 0, "Invalid list query", err)
+		return
+	}
+	query, err := h.BindListQuery(c, constants.ResourceUser)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Invalid list query", err)
+		return
+	}
+
+	users, err := h.authUseCase.ListPendingUsers(c.Request.Context(), query.Limit, query.Offset)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to list users", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"users": users, "page": query.Page()})
+}
+
+// ApproveUser activates the :id user's pending registration
+func (h *AuthHandler) ApproveUser(c *gin.Context) {
+	targetID, err := h.ParseUUID(c, "id")
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Invalid user ID", err)
+		return
+	}
+
+	adminID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "User approval failed", err)
+		return
+	}
+
+	user, err := h.authUseCase.ApproveUser(c.Request.Context(), targetID, adminID)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "User approval failed", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"message": "User approved", "user": user})
+}
+
+// ChangeEmail starts changing the caller's email. When verification is enabled the change waits
+// for ConfirmEmailChange and the response is 202; otherwise it applies at once.
+func (h *AuthHandler) ChangeEmail(c *gin.Context) {
+	var req ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendBadRequest(c, errors.ErrInvalidRequest.Error())
+		return
+	}
+
+	userID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Email change failed", err)
+		return
+	}
+
+	user, err := h.authUseCase.RequestEmailChange(c.Request.Context(), userID, req.Email)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Email change failed", err)
+		return
+	}
+
+	if user.PendingEmail != nil {
+		h.SendSuccessResponse(c, http.StatusAccepted, gin.H{
+			"message": "Confirmation sent to the new email address",
+			"user":    user,
+		})
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{
+		"message": "Email updated successfully",
+		"user":    user,
+	})
+}
+
+// ConfirmEmailChange applies the caller's pending email using the token mailed to it
+func (h *AuthHandler) ConfirmEmailChange(c *gin.Context) {
+	var req ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendBadRequest(c, errors.ErrInvalidRequest.Error())
+		return
+	}
+
+	userID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Email confirmation failed", err)
+		return
+	}
+
+	user, err := h.authUseCase.ConfirmEmailChange(c.Request.Context(), userID, req.Token)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Email confirmation failed", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{
+		"message": "Email updated successfully",
+		"user":    user,
+	})
+}
+
+// ChangePassword replaces the caller's password. Tokens issued before the change stop working, so
+// the response carries a new pair.
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendBadRequest(c, errors.ErrInvalidRequest.Error())
+		return
+	}
+
+	userID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Password change failed", err)
+		return
+	}
+
+	ctx := h.withClientInfo(c, c.Request.Context())
+	tokenPair, err := h.authUseCase.ChangePassword(ctx, userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Password change failed", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{
+		"message": "Password changed successfully",
+		"tokens":  tokenPair,
+	})
+}
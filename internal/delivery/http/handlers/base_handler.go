@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"clean-architecture-api/internal/delivery/http/response"
 	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/pkg/logger"
 	"errors"
+	"fmt"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
 
 	domainerrors "clean-architecture-api/internal/domain/errors"
 
@@ -14,11 +18,58 @@ import (
 )
 
 type BaseHandler struct {
-	logger logger.Logger
+	logger            logger.Logger
+	pagination        PaginationConfig
+	paginationHeaders bool
+	// maxBulkItems caps how many items a single bulk request may carry
+	maxBulkItems int
 }
 
 func NewBaseHandler(logger logger.Logger) *BaseHandler {
-	return &BaseHandler{logger: logger}
+	return &BaseHandler{
+		logger:       logger,
+		pagination:   NewPaginationConfigFromEnv(),
+		maxBulkItems: loadMaxBulkItems(),
+	}
+}
+
+func loadMaxBulkItems() int {
+	if value, err := strconv.Atoi(os.Getenv("MAX_BULK_ITEMS")); err == nil && value > 0 {
+		return value
+	}
+	return constants.DefaultMaxBulkItems
+}
+
+// WithPaginationConfig overrides the per-resource page limits
+func (h *BaseHandler) WithPaginationConfig(config PaginationConfig) *BaseHandler {
+	h.pagination = config
+	return h
+}
+
+// WithPaginationHeaders turns the X-Total-Count and Link headers on list responses on or off; they
+// are off by default
+func (h *BaseHandler) WithPaginationHeaders(enabled bool) *BaseHandler {
+	h.paginationHeaders = enabled
+	return h
+}
+
+// WithMaxBulkItems overrides the MAX_BULK_ITEMS cap on bulk requests
+func (h *BaseHandler) WithMaxBulkItems(maxItems int) *BaseHandler {
+	h.maxBulkItems = maxItems
+	return h
+}
+
+// CheckBulkSize answers 400 and returns false when a bulk request carries more than the configured
+// number of items. Bulk handlers call it right after binding, before any item is validated or stored.
+func (h *BaseHandler) CheckBulkSize(c *gin.Context, count int) bool {
+	if count <= h.maxBulkItems {
+		return true
+	}
+	h.logger.Warn(fmt.Sprintf("Rejected bulk request with %d items, above the maximum of %d", count, h.maxBulkItems))
+	body := response.ErrorBody(c, domainerrors.ErrTooManyBulkItems)
+	body["max_items"] = h.maxBulkItems
+	h.respond(c, http.StatusBadRequest, gin.H{"error": body})
+	return false
 }
 
 func (h *BaseHandler) ParseUUID(c *gin.Context, paramName string) (uuid.UUID, error) {
@@ -30,39 +81,67 @@ func (h *BaseHandler) ParseUUID(c *gin.Context, paramName string) (uuid.UUID, er
 	return id, nil
 }
 
-func (h *BaseHandler) ParsePagination(c *gin.Context) (limit, offset int) {
-	limitStr := c.DefaultQuery("limit", strconv.Itoa(constants.DefaultLimit))
-	offsetStr := c.DefaultQuery("offset", strconv.Itoa(constants.DefaultOffset))
+// GetUserID returns the caller the auth middleware identified. On a route the middleware did not
+// guard it returns ErrUserIDNotFound, which SendErrorResponse answers with 401.
+func (h *BaseHandler) GetUserID(c *gin.Context) (uuid.UUID, error) {
+	userID, exists := constants.UserIDFromContext(c.Request.Context())
+	if !exists {
+		return uuid.Nil, domainerrors.ErrUserIDNotFound
+	}
+	return userID, nil
+}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = constants.DefaultLimit
+// GetUserRole returns the caller's role like GetUserID, or ErrUserRoleNotFound
+func (h *BaseHandler) GetUserRole(c *gin.Context) (string, error) {
+	role, exists := constants.UserRoleFromContext(c.Request.Context())
+	if !exists {
+		return "", domainerrors.ErrUserRoleNotFound
 	}
+	return role, nil
+}
 
-	offset, err = strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = constants.DefaultOffset
+// BindListQuery parses list parameters from the query string using the page limits configured
+// for resource, accepting only the given sort fields. A limit above the maximum is clamped; one
+// that is not a number, or is negative, is rejected like any other invalid query parameter.
+func (h *BaseHandler) BindListQuery(c *gin.Context, resource string, sortFields ...string) (*ListQuery, error) {
+	var params listParams
+	if err := h.BindQuery(c, &params); err != nil {
+		return nil, err
 	}
 
-	return limit, offset
+	query := &ListQuery{
+		Limit:      params.Limit,
+		Offset:     params.Offset,
+		Sort:       strings.TrimSpace(params.Sort),
+		Cursor:     params.Cursor,
+		Filters:    listFilters(c),
+		sortFields: sortFields,
+		limits:     h.pagination.For(resource),
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	return query, nil
 }
 
 func (h *BaseHandler) SendErrorResponse(c *gin.Context, statusCode int, message string, err error) {
 	h.logger.Error(message, err)
 
+	var queryErr *QueryError
+	if errors.As(err, &queryErr) {
+		body := response.ErrorBody(c, domainerrors.ErrInvalidQuery)
+		body["fields"] = queryErr.Fields
+		h.respond(c, http.StatusBadRequest, gin.H{"error": body})
+		return
+	}
+
 	var appErr *domainerrors.AppError
 	if errors.As(err, &appErr) {
-		c.JSON(h.getStatusCodeFromCategory(appErr.Category), gin.H{
-			"error": gin.H{
-				"category": appErr.Category,
-				"code":     appErr.Code,
-				"message":  appErr.Message,
-			},
-		})
+		h.respond(c, h.getStatusCodeFromCategory(appErr.Category), gin.H{"error": response.ErrorBody(c, appErr)})
 		return
 	}
 
-	c.JSON(statusCode, gin.H{"error": err.Error()})
+	h.respond(c, statusCode, gin.H{"error": err.Error()})
 }
 
 func (h *BaseHandler) getStatusCodeFromCategory(category domainerrors.ErrorCategory) int {
@@ -77,6 +156,8 @@ func (h *BaseHandler) getStatusCodeFromCategory(category domainerrors.ErrorCateg
 		return http.StatusForbidden
 	case domainerrors.CategoryConflict:
 		return http.StatusConflict
+	case domainerrors.CategoryRateLimited:
+		return http.StatusTooManyRequests
 	case domainerrors.CategoryInternal, domainerrors.CategoryDatabase:
 		return http.StatusInternalServerError
 	default:
@@ -85,21 +166,21 @@ func (h *BaseHandler) getStatusCodeFromCategory(category domainerrors.ErrorCateg
 }
 
 func (h *BaseHandler) SendSuccessResponse(c *gin.Context, statusCode int, data interface{}) {
-	c.JSON(statusCode, gin.H{
+	h.respond(c, statusCode, gin.H{
 		"success": true,
 		"data":    data,
 	})
 }
 
 func (h *BaseHandler) SendBadRequest(c *gin.Context, message string) {
-	c.JSON(http.StatusBadRequest, gin.H{"error": message})
+	h.respond(c, http.StatusBadRequest, gin.H{"error": message})
 }
 
 func (h *BaseHandler) SendNotFound(c *gin.Context, message string) {
-	c.JSON(http.StatusNotFound, gin.H{"error": message})
+	h.respond(c, http.StatusNotFound, gin.H{"error": message})
 }
 
 func (h *BaseHandler) SendInternalServerError(c *gin.Context, message string, err error) {
 	h.logger.Error(message, err)
-	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	h.respond(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
 }
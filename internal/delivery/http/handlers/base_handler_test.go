@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseHandler_BindListQuery_PageLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewBaseHandler(logger.NewLogger()).WithPaginationConfig(PaginationConfig{
+		constants.ResourceProduct: {Default: 20, Max: 50},
+	})
+
+	tests := []struct {
+		name           string
+		resource       string
+		query          string
+		expectedLimit  int
+		expectedOffset int
+	}{
+		{
+			name:           "defaults when query is empty",
+			resource:       "policy",
+			query:          "",
+			expectedLimit:  constants.DefaultLimit,
+			expectedOffset: constants.DefaultOffset,
+		},
+		{
+			name:           "limit above max is clamped",
+			resource:       "policy",
+			query:          "?limit=500&offset=20",
+			expectedLimit:  constants.MaxLimit,
+			expectedOffset: 20,
+		},
+		{
+			name:           "resource default applies when query is empty",
+			resource:       constants.ResourceProduct,
+			query:          "",
+			expectedLimit:  20,
+			expectedOffset: constants.DefaultOffset,
+		},
+		{
+			name:           "limit above resource max is clamped",
+			resource:       constants.ResourceProduct,
+			query:          "?limit=80",
+			expectedLimit:  50,
+			expectedOffset: constants.DefaultOffset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/"+tt.query, nil)
+
+			query, err := handler.BindListQuery(c, tt.resource)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedLimit, query.Limit)
+			assert.Equal(t, tt.expectedOffset, query.Offset)
+		})
+	}
+
+	for _, rawQuery := range []string{"?limit=-5", "?offset=-1", "?limit=ten"} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/"+rawQuery, nil)
+		_, err := handler.BindListQuery(c, "policy")
+		assert.Error(t, err, rawQuery)
+	}
+}
+
+func TestNewPaginationConfigFromEnv(t *testing.T) {
+	t.Setenv("USER_LIST_DEFAULT_LIMIT", "25")
+	t.Setenv("USER_LIST_MAX_LIMIT", "500")
+	t.Setenv("PRODUCT_LIST_MAX_LIMIT", "15")
+
+	config := NewPaginationConfigFromEnv()
+	assert.Equal(t, PageLimits{Default: 25, Max: constants.MaxLimit}, config.For(constants.ResourceUser),
+		"a max above the use case cap is lowered to it")
+	assert.Equal(t, PageLimits{Default: 15, Max: 15}, config.For(constants.ResourceProduct))
+	assert.Equal(t, PageLimits{Default: constants.DefaultLimit, Max: constants.MaxLimit}, config.For("policy"))
+}
+
+func TestBaseHandler_BindListQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewBaseHandler(logger.NewLogger())
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?limit=500&sort=-price&category=books", nil)
+
+	query, err := handler.BindListQuery(c, constants.ResourceProduct, "name", "price")
+	assert.NoError(t, err)
+	assert.Equal(t, constants.MaxLimit, query.Limit)
+	assert.Equal(t, "price", query.SortField())
+	assert.True(t, query.SortDescending())
+	assert.Equal(t, map[string]string{"category": "books"}, query.Filters)
+
+	sort, ok := constants.SortOrderFromContext(query.WithSort(context.Background()))
+	assert.True(t, ok)
+	assert.Equal(t, constants.SortOrder{Field: "price", Descending: true}, sort)
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?sort=password", nil)
+	_, err = handler.BindListQuery(c, constants.ResourceProduct, "name", "price")
+	assert.Equal(t, domainerrors.ErrInvalidSortField, err)
+}
+
+func TestBaseHandler_BindListQuery_Cursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewBaseHandler(logger.NewLogger())
+	bind := func(rawQuery string) (*ListQuery, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/?"+rawQuery, nil)
+		return handler.BindListQuery(c, constants.ResourceProduct)
+	}
+
+	// No cursor, or an empty one, is the first page
+	for _, rawQuery := range []string{"", "cursor="} {
+		query, err := bind(rawQuery)
+		assert.NoError(t, err)
+		_, ok := query.CursorID()
+		assert.False(t, ok)
+	}
+
+	id := uuid.New()
+	query, err := bind("cursor=" + EncodeCursor(id))
+	assert.NoError(t, err)
+	got, ok := query.CursorID()
+	assert.True(t, ok)
+	assert.Equal(t, id, got)
+
+	garbage := []string{
+		"not-base64!",
+		"%00%ff",
+		EncodeCursor(id)[:10],
+		base64.RawURLEncoding.EncodeToString([]byte(id.String())),
+		EncodeCursor(uuid.Nil),
+	}
+	for _, cursor := range garbage {
+		_, err := bind("cursor=" + cursor)
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidRequest, cursor)
+	}
+}
+
+func TestBaseHandler_SetPaginationHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewBaseHandler(logger.NewLogger())
+	count := func() (int64, error) { return 45, nil }
+	listPage := func(rawQuery string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/v1/products?"+rawQuery, nil)
+		query, err := handler.BindListQuery(c, constants.ResourceProduct)
+		assert.NoError(t, err)
+		handler.SetPaginationHeaders(c, query, count)
+		return w
+	}
+
+	// Off unless enabled, and the count is never run
+	handler.WithPaginationHeaders(false)
+	w := listPage("limit=10&offset=20")
+	assert.Empty(t, w.Header().Get(TotalCountHeader))
+	assert.Empty(t, w.Header().Get(LinkHeader))
+
+	handler.WithPaginationHeaders(true)
+	w = listPage("category=books&limit=10&offset=20")
+	assert.Equal(t, "45", w.Header().Get(TotalCountHeader))
+	assert.Equal(t, `</api/v1/products?category=books&limit=10&offset=0>; rel="first", `+
+		`</api/v1/products?category=books&limit=10&offset=10>; rel="prev", `+
+		`</api/v1/products?category=books&limit=10&offset=30>; rel="next", `+
+		`</api/v1/products?category=books&limit=10&offset=40>; rel="last"`, w.Header().Get(LinkHeader))
+
+	// The first page has no prev and the last has no next
+	assert.NotContains(t, listPage("limit=10").Header().Get(LinkHeader), `rel="prev"`)
+	assert.NotContains(t, listPage("limit=10&offset=40").Header().Get(LinkHeader), `rel="next"`)
+}
+
+func TestBaseHandler_GetUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewBaseHandler(logger.NewLogger())
+	userID := uuid.New()
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	_, err := handler.GetUserID(c)
+	assert.ErrorIs(t, err, domainerrors.ErrUserIDNotFound)
+	_, err = handler.GetUserRole(c)
+	assert.ErrorIs(t, err, domainerrors.ErrUserRoleNotFound)
+
+	ctx := constants.WithUserRole(constants.WithUserID(context.Background(), userID), constants.RoleUser)
+	c.Request = c.Request.WithContext(ctx)
+	got, err := handler.GetUserID(c)
+	require.NoError(t, err)
+	assert.Equal(t, userID, got)
+	role, err := handler.GetUserRole(c)
+	require.NoError(t, err)
+	assert.Equal(t, constants.RoleUser, role)
+}
+
+// A route registered without the auth middleware answers 401 instead of panicking
+func TestHandlers_MissingIdentityIsUnauthorized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/sessions", NewAuthHandler(nil, logger.NewLogger()).ListSessions)
+	router.GET("/actions/:resource", NewPermissionHandler(nil, logger.NewLogger()).GetAllowedActions)
+
+	for path, code := range map[string]string{
+		"/sessions":         domainerrors.ErrUserIDNotFound.Code,
+		"/actions/products": domainerrors.ErrUserRoleNotFound.Code,
+	} {
+		rec := httptest.NewRecorder()
+		require.NotPanics(t, func() {
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		})
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+		var body struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, code, body.Error.Code, path)
+	}
+}
+
+func TestBaseHandler_SendErrorResponseLocalizesMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewBaseHandler(logger.NewLogger())
+	router := gin.New()
+	router.GET("/users/:id", func(c *gin.Context) {
+		handler.SendErrorResponse(c, http.StatusInternalServerError, "lookup failed", domainerrors.ErrUserNotFound)
+	})
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantLanguage   string
+		wantMessage    string
+	}{
+		{name: "no header", wantLanguage: "en", wantMessage: "user not found"},
+		{name: "example locale", acceptLanguage: "vi-VN,vi;q=0.9", wantLanguage: "vi", wantMessage: "không tìm thấy người dùng"},
+		{name: "unsupported locale falls back to English", acceptLanguage: "fr-FR,fr;q=0.9", wantLanguage: "en", wantMessage: "user not found"},
+		{name: "first supported preference wins", acceptLanguage: "de;q=0.9,en;q=0.8,vi;q=0.7", wantLanguage: "en", wantMessage: "user not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users/"+uuid.NewString(), nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			assert.Equal(t, tt.wantLanguage, rec.Header().Get("Content-Language"))
+			assert.JSONEq(t, `{"error":{"category":"not_found","code":"USER_NOT_FOUND","message":"`+tt.wantMessage+`"}}`, rec.Body.String())
+		})
+	}
+}
+
+func TestBaseHandler_SendErrorResponseRateLimited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewBaseHandler(logger.NewLogger())
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	handler.SendErrorResponse(c, http.StatusInternalServerError, "rate limited", domainerrors.ErrRateLimited)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"category":"rate_limited"`)
+}
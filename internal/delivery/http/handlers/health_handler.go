@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthChecker is implemented by dependencies that can verify their own readiness
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthReporter is implemented by checkers that add details to their readiness result
+type HealthReporter interface {
+	HealthDetails() map[string]interface{}
+}
+
+type HealthHandler struct {
+	*BaseHandler
+	checks     map[string]HealthChecker
+	degradable map[string]HealthChecker
+}
+
+type checkResult struct {
+	Status    string                 `json:"status"`
+	LatencyMs float64                `json:"latency_ms"`
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// NewHealthHandler creates a new health handler running the given named readiness checks
+func NewHealthHandler(checks map[string]HealthChecker, logger logger.Logger) *HealthHandler {
+	return &HealthHandler{
+		BaseHandler: NewBaseHandler(logger),
+		checks:      checks,
+		degradable:  make(map[string]HealthChecker),
+	}
+}
+
+// AddDegradableCheck registers a readiness check the instance can keep serving without. When it
+// fails readiness reports "degraded" but still answers 200, so the instance stays in rotation.
+func (h *HealthHandler) AddDegradableCheck(name string, checker HealthChecker) *HealthHandler {
+	h.degradable[name] = checker
+	return h
+}
+
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), constants.ReadinessCheckTimeout)
+	defer cancel()
+
+	ready, degraded := true, false
+	results := make(map[string]checkResult, len(h.checks)+len(h.degradable))
+	for name, checker := range h.checks {
+		result := h.runCheck(ctx, name, checker, "failed")
+		ready = ready && result.Error == ""
+		results[name] = result
+	}
+	for name, checker := range h.degradable {
+		result := h.runCheck(ctx, name, checker, "degraded")
+		degraded = degraded || result.Error != ""
+		results[name] = result
+	}
+
+	status, code := "ready", http.StatusOK
+	switch {
+	case !ready:
+		status, code = "not_ready", http.StatusServiceUnavailable
+	case degraded:
+		status = "degraded"
+	}
+
+	c.JSON(code, gin.H{
+		"status": status,
+		"checks": results,
+	})
+}
+
+func (h *HealthHandler) runCheck(ctx context.Context, name string, checker HealthChecker, failedStatus string) checkResult {
+	start := time.Now()
+	err := checker.HealthCheck(ctx)
+	result := checkResult{
+		Status:    "ok",
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		h.logger.Error("Readiness check failed: "+name, err)
+		result.Status = failedStatus
+		result.Error = err.Error()
+	}
+	if reporter, ok := checker.(HealthReporter); ok {
+		result.Details = reporter.HealthDetails()
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"bytes"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/pkg/logger"
+	"clean-architecture-api/pkg/metrics"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyPolicyRepository fails GetActive until its database comes back
+type flakyPolicyRepository struct {
+	repositories.PolicyRepository
+	down bool
+}
+
+func (r *flakyPolicyRepository) GetActive(_ context.Context) ([]*entities.PolicyDocument, error) {
+	if r.down {
+		return nil, errors.New("connection refused")
+	}
+	return []*entities.PolicyDocument{{Name: "admin-all"}}, nil
+}
+
+type readinessResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+func TestHealthHandler_ReadyDegradedUntilPoliciesLoad(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &flakyPolicyRepository{down: true}
+	engine := auth.NewPolicyEngine(repo, logger.NewLogger())
+	checker, ok := engine.(HealthChecker)
+	require.True(t, ok, "policy engine should implement HealthChecker")
+
+	handler := NewHealthHandler(map[string]HealthChecker{}, logger.NewLogger()).
+		AddDegradableCheck("policy_cache", checker)
+	router := gin.New()
+	router.GET("/health/ready", handler.Ready)
+
+	ready := func() (int, readinessResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+		var body readinessResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body
+	}
+
+	code, body := ready()
+	assert.Equal(t, http.StatusOK, code, "a degraded instance stays in rotation")
+	assert.Equal(t, "degraded", body.Status)
+	policyCache := body.Checks["policy_cache"]
+	assert.Equal(t, "degraded", policyCache.Status)
+	assert.Contains(t, policyCache.Error, "connection refused")
+	assert.Nil(t, policyCache.Details["last_load_at"])
+	assert.EqualValues(t, 0, policyCache.Details["policies"])
+
+	repo.down = false
+	_, err := engine.ReloadPolicies(context.Background())
+	require.NoError(t, err)
+
+	code, body = ready()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", body.Status)
+	policyCache = body.Checks["policy_cache"]
+	assert.Equal(t, "ok", policyCache.Status)
+	assert.NotNil(t, policyCache.Details["last_load_at"])
+	assert.EqualValues(t, 1, policyCache.Details["policies"])
+
+	var scrape bytes.Buffer
+	require.NoError(t, metrics.Default.WriteText(&scrape))
+	assert.Contains(t, scrape.String(), "# TYPE policy_cache_last_load_timestamp gauge")
+}
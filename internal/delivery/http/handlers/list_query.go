@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/usecase"
+	"context"
+	"encoding/base64"
+	"sort"
+	"strings"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var listQueryReservedParams = map[string]bool{
+	"limit":  true,
+	"offset": true,
+	"sort":   true,
+	"cursor": true,
+}
+
+// ListQuery holds the pagination, sorting and filtering parameters shared by list endpoints.
+// Sort is a field name, optionally prefixed with "-" for descending order. Cursor is the opaque
+// value from EncodeCursor; an empty cursor asks for the first page.
+type ListQuery struct {
+	Limit   int               `json:"limit"`
+	Offset  int               `json:"offset"`
+	Sort    string            `json:"sort,omitempty"`
+	Cursor  string            `json:"cursor,omitempty"`
+	Filters map[string]string `json:"filters,omitempty"`
+
+	sortFields []string
+	limits     PageLimits
+	cursorID   uuid.UUID
+}
+
+// listParams are the query parameters every list endpoint accepts; a zero limit means the default
+type listParams struct {
+	Limit  int    `form:"limit" binding:"min=0"`
+	Offset int    `form:"offset" binding:"min=0"`
+	Sort   string `form:"sort"`
+	Cursor string `form:"cursor"`
+}
+
+// listFilters collects the query parameters that are not pagination or sorting
+func listFilters(c *gin.Context) map[string]string {
+	filters := make(map[string]string)
+	for key, values := range c.Request.URL.Query() {
+		if listQueryReservedParams[key] || len(values) == 0 {
+			continue
+		}
+		filters[key] = values[0]
+	}
+	return filters
+}
+
+// Validate clamps pagination into the allowed range and rejects sort fields
+// that are not whitelisted for the endpoint, and cursors that do not decode
+func (q *ListQuery) Validate() error {
+	q.clampPagination()
+
+	if q.Sort != "" && !q.isSortable(q.SortField()) {
+		return domainerrors.ErrInvalidSortField
+	}
+
+	if q.Cursor != "" {
+		id, err := DecodeCursor(q.Cursor)
+		if err != nil {
+			return err
+		}
+		q.cursorID = id
+	}
+
+	return nil
+}
+
+// RejectUnknownFilters fails with a QueryError naming every filter parameter that is not in
+// allowed, so a misspelled filter is reported instead of silently matching everything
+func (q *ListQuery) RejectUnknownFilters(allowed ...string) error {
+	var fields []FieldError
+	for key := range q.Filters {
+		if !containsString(allowed, key) {
+			fields = append(fields, FieldError{Field: key, Rule: queryRuleUnknown})
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	return &QueryError{Fields: fields}
+}
+
+// CursorID returns the ID decoded from the cursor, and false on the first page
+func (q *ListQuery) CursorID() (uuid.UUID, bool) {
+	return q.cursorID, q.cursorID != uuid.Nil
+}
+
+// EncodeCursor turns the ID of the last item on a page into the cursor for the next one
+func EncodeCursor(id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(id[:])
+}
+
+// DecodeCursor reverses EncodeCursor. Padding is tolerated; anything that is not the base64 of a
+// non-nil UUID is rejected with ErrInvalidRequest.
+func DecodeCursor(cursor string) (uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(cursor, "="))
+	if err != nil {
+		return uuid.Nil, domainerrors.ErrInvalidRequest
+	}
+	id, err := uuid.FromBytes(raw)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, domainerrors.ErrInvalidRequest
+	}
+	return id, nil
+}
+
+// clampPagination applies the resource's page limits and then the use case bound, so Limit is the
+// page size the use case actually serves
+func (q *ListQuery) clampPagination() {
+	if q.Limit <= 0 {
+		q.Limit = q.limits.Default
+	}
+	if q.Limit > q.limits.Max {
+		q.Limit = q.limits.Max
+	}
+	q.Limit, q.Offset = usecase.ClampPagination(q.Limit, q.Offset)
+}
+
+// PageInfo reports the page a list response holds
+type PageInfo struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// Page returns the limit and offset that were applied, which may be lower than requested
+func (q *ListQuery) Page() PageInfo {
+	return PageInfo{Limit: q.Limit, Offset: q.Offset}
+}
+
+// SortField returns the sort field without its direction prefix
+func (q *ListQuery) SortField() string {
+	return strings.TrimPrefix(q.Sort, "-")
+}
+
+// SortDescending reports whether the sort field was prefixed with "-"
+func (q *ListQuery) SortDescending() bool {
+	return strings.HasPrefix(q.Sort, "-")
+}
+
+// WithSort passes the requested sort order on to the repositories; without one they fall back
+// to their default stable order
+func (q *ListQuery) WithSort(ctx context.Context) context.Context {
+	if q.Sort == "" {
+		return ctx
+	}
+	return constants.WithSortOrder(ctx, constants.SortOrder{Field: q.SortField(), Descending: q.SortDescending()})
+}
+
+func (q *ListQuery) isSortable(field string) bool {
+	return containsString(q.sortFields, field)
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+const xmlContentType = "application/xml; charset=utf-8"
+
+var xmlNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// respond writes body as XML when the Accept header prefers application/xml and as JSON otherwise.
+// XML is produced from the JSON encoding, so both formats expose the same field names and
+// json:"-" fields stay hidden.
+func (h *BaseHandler) respond(c *gin.Context, statusCode int, body gin.H) {
+	switch c.NegotiateFormat(gin.MIMEJSON, gin.MIMEXML, gin.MIMEXML2) {
+	case gin.MIMEXML, gin.MIMEXML2:
+	default:
+		c.JSON(statusCode, body)
+		return
+	}
+
+	data, err := marshalXMLResponse(body)
+	if err != nil {
+		h.logger.Error("Failed to encode XML response", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode response"})
+		return
+	}
+	c.Data(statusCode, xmlContentType, data)
+}
+
+// marshalXMLResponse renders body under a <response> root. Objects become nested elements,
+// array items become <item> elements, and keys that are not valid XML names are written as
+// <entry key="...">.
+func marshalXMLResponse(body gin.H) ([]byte, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	if err := encodeXMLValue(encoder, xmlElement("response"), tree); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeXMLValue(encoder *xml.Encoder, start xml.StartElement, value interface{}) error {
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := encodeXMLValue(encoder, xmlElement(key), v[key]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := encodeXMLValue(encoder, xmlElement("item"), item); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		text, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if s, ok := v.(string); ok {
+			text = []byte(s)
+		}
+		if err := encoder.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+func xmlElement(name string) xml.StartElement {
+	if xmlNamePattern.MatchString(name) {
+		return xml.StartElement{Name: xml.Name{Local: name}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}},
+	}
+}
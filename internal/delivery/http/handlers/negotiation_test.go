@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"clean-architecture-api/pkg/logger"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type negotiatedUser struct {
+	Name     string `json:"name"`
+	Password string `json:"-"`
+}
+
+func negotiationRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewBaseHandler(logger.NewLogger())
+	router := gin.New()
+	router.GET("/success", func(c *gin.Context) {
+		handler.SendSuccessResponse(c, http.StatusOK, gin.H{
+			"users": []negotiatedUser{{Name: "alice", Password: "secret"}, {Name: "bob"}},
+			"total": 2,
+		})
+	})
+	router.GET("/error", func(c *gin.Context) {
+		handler.SendErrorResponse(c, http.StatusInternalServerError, "lookup failed", domainerrors.ErrUserNotFound)
+	})
+	return router
+}
+
+func negotiate(router *gin.Engine, path, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBaseHandler_RespondsWithJSONByDefault(t *testing.T) {
+	router := negotiationRouter()
+
+	for _, accept := range []string{"", "*/*", "application/json", "text/html"} {
+		rec := negotiate(router, "/success", accept)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("Content-Type"), "application/json", "Accept: %q", accept)
+
+		var body struct {
+			Success bool `json:"success"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.True(t, body.Success)
+	}
+}
+
+func TestBaseHandler_RespondsWithXMLWhenRequested(t *testing.T) {
+	router := negotiationRouter()
+
+	rec := negotiate(router, "/success", "application/xml")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.NotContains(t, rec.Body.String(), "secret")
+
+	var success struct {
+		XMLName xml.Name `xml:"response"`
+		Success bool     `xml:"success"`
+		Data    struct {
+			Total int      `xml:"total"`
+			Users []string `xml:"users>item>name"`
+		} `xml:"data"`
+	}
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &success))
+	assert.True(t, success.Success)
+	assert.Equal(t, 2, success.Data.Total)
+	assert.Equal(t, []string{"alice", "bob"}, success.Data.Users)
+
+	rec = negotiate(router, "/error", "text/xml")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	var failure struct {
+		XMLName xml.Name `xml:"response"`
+		Error   struct {
+			Category string `xml:"category"`
+			Code     string `xml:"code"`
+			Message  string `xml:"message"`
+		} `xml:"error"`
+	}
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &failure))
+	assert.Equal(t, string(domainerrors.CategoryNotFound), failure.Error.Category)
+	assert.NotEmpty(t, failure.Error.Message)
+}
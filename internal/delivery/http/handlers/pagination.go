@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	TotalCountHeader = "X-Total-Count"
+	LinkHeader       = "Link"
+)
+
+// PageLimits bounds the page size of a list endpoint
+type PageLimits struct {
+	Default int
+	Max     int
+}
+
+// PaginationConfig holds page limits keyed by resource name. Resources without an entry use
+// the global constants.DefaultLimit and constants.MaxLimit.
+type PaginationConfig map[string]PageLimits
+
+func DefaultPaginationConfig() PaginationConfig {
+	return PaginationConfig{
+		constants.ResourceProduct: {Default: constants.DefaultProductLimit, Max: constants.MaxLimit},
+		constants.ResourceUser:    {Default: constants.DefaultUserLimit, Max: constants.MaxLimit},
+	}
+}
+
+// NewPaginationConfigFromEnv applies <RESOURCE>_LIST_DEFAULT_LIMIT and <RESOURCE>_LIST_MAX_LIMIT
+// overrides, e.g. PRODUCT_LIST_DEFAULT_LIMIT, on top of DefaultPaginationConfig. A max above
+// constants.MaxLimit is capped there, since the use cases never return larger pages.
+func NewPaginationConfigFromEnv() PaginationConfig {
+	config := DefaultPaginationConfig()
+	for resource, limits := range config {
+		prefix := strings.ToUpper(resource) + "_LIST_"
+		if value, err := strconv.Atoi(os.Getenv(prefix + "DEFAULT_LIMIT")); err == nil && value > 0 {
+			limits.Default = value
+		}
+		if value, err := strconv.Atoi(os.Getenv(prefix + "MAX_LIMIT")); err == nil && value > 0 {
+			limits.Max = min(value, constants.MaxLimit)
+		}
+		if limits.Default > limits.Max {
+			limits.Default = limits.Max
+		}
+		config[resource] = limits
+	}
+	return config
+}
+
+// For returns the limits for resource, falling back to the global ones
+func (c PaginationConfig) For(resource string) PageLimits {
+	if limits, ok := c[resource]; ok {
+		return limits
+	}
+	return PageLimits{Default: constants.DefaultLimit, Max: constants.MaxLimit}
+}
+
+// SetPaginationHeaders adds X-Total-Count and an RFC 8288 Link header with first, prev, next and
+// last pages when PAGINATION_HEADERS is enabled. count runs only then, so lists skip the extra
+// query by default. A failed count is logged and the headers are left out; the body is unaffected.
+func (h *BaseHandler) SetPaginationHeaders(c *gin.Context, query *ListQuery, count func() (int64, error)) {
+	if !h.paginationHeaders {
+		return
+	}
+
+	total, err := count()
+	if err != nil {
+		h.logger.Error("Failed to count list total for pagination headers", err)
+		return
+	}
+
+	c.Header(TotalCountHeader, strconv.FormatInt(total, 10))
+	c.Header(LinkHeader, paginationLinks(c, query.Limit, query.Offset, int(total)))
+}
+
+func paginationLinks(c *gin.Context, limit, offset, total int) string {
+	link := func(rel string, offset int) string {
+		values := c.Request.URL.Query()
+		values.Set("limit", strconv.Itoa(limit))
+		values.Set("offset", strconv.Itoa(offset))
+		return fmt.Sprintf(`<%s?%s>; rel="%s"`, c.Request.URL.Path, values.Encode(), rel)
+	}
+
+	last := 0
+	if total > 0 {
+		last = (total - 1) / limit * limit
+	}
+
+	links := []string{link("first", 0)}
+	if offset > 0 {
+		links = append(links, link("prev", max(offset-limit, 0)))
+	}
+	if offset+limit < total {
+		links = append(links, link("next", offset+limit))
+	}
+	links = append(links, link("last", last))
+	return strings.Join(links, ", ")
+}
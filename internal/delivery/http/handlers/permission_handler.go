@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PermissionCheckRequest struct {
+	Resource   string `json:"resource" binding:"required"`
+	Action     string `json:"action" binding:"required"`
+	ResourceID string `json:"resource_id,omitempty"`
+}
+
+type CheckPermissionsRequest struct {
+	Checks []PermissionCheckRequest `json:"checks" binding:"required,min=1,dive"`
+}
+
+type PermissionCheckResult struct {
+	Resource   string `json:"resource"`
+	Action     string `json:"action"`
+	ResourceID string `json:"resource_id,omitempty"`
+	Allowed    bool   `json:"allowed"`
+}
+
+type PermissionHandler struct {
+	*BaseHandler
+	authzService repositories.AuthorizationService
+}
+
+// NewPermissionHandler creates a new permission introspection handler instance
+func NewPermissionHandler(authzService repositories.AuthorizationService, logger logger.Logger) *PermissionHandler {
+	return &PermissionHandler{
+		BaseHandler:  NewBaseHandler(logger),
+		authzService: authzService,
+	}
+}
+
+func (h *PermissionHandler) GetEffectivePermissions(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusUnauthorized, "Failed to get user ID", err)
+		return
+	}
+
+	permissions, err := h.authzService.GetEffectivePermissions(ctx, userID)
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get effective permissions", err)
+		return
+	}
+
+	if permissions == nil {
+		permissions = []entities.Permission{}
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"permissions": permissions})
+}
+
+func (h *PermissionHandler) GetAllowedActions(c *gin.Context) {
+	role, err := h.GetUserRole(c)
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusUnauthorized, "Failed to get user role", err)
+		return
+	}
+
+	resource := c.Param("resource")
+	actions, err := h.authzService.GetAllowedActionsForRole(role, resource)
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get allowed actions", err)
+		return
+	}
+
+	if actions == nil {
+		actions = []string{}
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{
+		"resource": resource,
+		"actions":  actions,
+	})
+}
+
+// CheckPermissions evaluates each check against the caller and reports allowed or denied, in request
+// order. Repeated checks are answered from the request-scoped permission cache.
+func (h *PermissionHandler) CheckPermissions(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusUnauthorized, "Failed to get user ID", err)
+		return
+	}
+
+	var req CheckPermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid permission checks", errors.ErrInvalidRequest)
+		return
+	}
+	if !h.CheckBulkSize(c, len(req.Checks)) {
+		return
+	}
+
+	results := make([]PermissionCheckResult, len(req.Checks))
+	for i, check := range req.Checks {
+		err := h.authzService.CheckResourcePermission(ctx, userID, check.Resource, check.Action, check.ResourceID)
+		var permissionErr *errors.PermissionError
+		if err != nil && !stderrors.As(err, &permissionErr) {
+			h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to check permissions", err)
+			return
+		}
+		results[i] = PermissionCheckResult{
+			Resource:   check.Resource,
+			Action:     check.Action,
+			ResourceID: check.ResourceID,
+			Allowed:    err == nil,
+		}
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"results": results})
+}
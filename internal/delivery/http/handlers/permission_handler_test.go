@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPolicyEngine allows reading products and updating product p-1 only, counting evaluations
+type countingPolicyEngine struct {
+	repositories.PolicyEngine
+	evaluations int
+}
+
+func (e *countingPolicyEngine) Evaluate(_ context.Context, req *entities.PermissionRequest) (*entities.PermissionResponse, error) {
+	e.evaluations++
+	allowed := req.Resource == constants.ResourceProduct &&
+		(req.Action == constants.ActionRead || (req.Action == constants.ActionUpdate && req.ResourceID == "p-1"))
+	return &entities.PermissionResponse{Allowed: allowed}, nil
+}
+
+func TestPermissionHandler_CheckPermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := &countingPolicyEngine{}
+	authzService := auth.NewAuthorizationService(engine)
+	handler := NewPermissionHandler(authzService, logger.NewLogger())
+
+	router := gin.New()
+	router.POST("/auth/permissions/check", func(c *gin.Context) {
+		ctx := auth.WithPermissionCache(c.Request.Context())
+		ctx = authzService.CreateEnrichedContext(ctx, uuid.New(), constants.RoleUser, "")
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}, handler.CheckPermissions)
+
+	body, err := json.Marshal(CheckPermissionsRequest{Checks: []PermissionCheckRequest{
+		{Resource: constants.ResourceProduct, Action: constants.ActionRead},
+		{Resource: constants.ResourceProduct, Action: constants.ActionUpdate, ResourceID: "p-1"},
+		{Resource: constants.ResourceProduct, Action: constants.ActionUpdate, ResourceID: "p-2"},
+		{Resource: constants.ResourceUser, Action: constants.ActionDelete},
+		{Resource: constants.ResourceProduct, Action: constants.ActionRead},
+	}})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/permissions/check", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data struct {
+			Results []PermissionCheckResult `json:"results"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+	allowed := make([]bool, len(response.Data.Results))
+	for i, result := range response.Data.Results {
+		allowed[i] = result.Allowed
+	}
+	assert.Equal(t, []bool{true, true, false, false, true}, allowed)
+	assert.Equal(t, "p-2", response.Data.Results[2].ResourceID)
+	assert.Equal(t, 4, engine.evaluations, "the repeated check must be served from the permission cache")
+}
+
+func TestPermissionHandler_CheckPermissionsRejectsEmptyBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authzService := auth.NewAuthorizationService(&countingPolicyEngine{})
+	handler := NewPermissionHandler(authzService, logger.NewLogger())
+
+	router := gin.New()
+	router.POST("/auth/permissions/check", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(authzService.CreateEnrichedContext(c.Request.Context(), uuid.New(), constants.RoleUser, ""))
+		c.Next()
+	}, handler.CheckPermissions)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/permissions/check", bytes.NewBufferString(`{"checks":[]}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPermissionHandler_CheckPermissionsRejectsOversizedBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := &countingPolicyEngine{}
+	authzService := auth.NewAuthorizationService(engine)
+	handler := NewPermissionHandler(authzService, logger.NewLogger())
+	handler.WithMaxBulkItems(2)
+
+	router := gin.New()
+	router.POST("/auth/permissions/check", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(authzService.CreateEnrichedContext(c.Request.Context(), uuid.New(), constants.RoleUser, ""))
+		c.Next()
+	}, handler.CheckPermissions)
+
+	check := PermissionCheckRequest{Resource: constants.ResourceProduct, Action: constants.ActionRead}
+	body, err := json.Marshal(CheckPermissionsRequest{Checks: []PermissionCheckRequest{check, check, check}})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/permissions/check", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"TOO_MANY_BULK_ITEMS"`)
+	assert.Zero(t, engine.evaluations, "no check runs when the batch is too large")
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"clean-architecture-api/internal/delivery/http/response"
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	domainerrors "clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/usecase"
+	"clean-architecture-api/pkg/logger"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+type SimulatePolicyRequest struct {
+	UserID     string                 `json:"user_id,omitempty"`
+	Role       string                 `json:"role,omitempty"`
+	Resource   string                 `json:"resource" binding:"required"`
+	Action     string                 `json:"action" binding:"required"`
+	ResourceID string                 `json:"resource_id,omitempty"`
+	Context    map[string]interface{} `json:"context,omitempty"`
+}
+
+type PolicyHandler struct {
+	*BaseHandler
+	policyUseCase usecase.PolicyUseCase
+}
+
+// NewPolicyHandler creates a new policy administration handler instance
+func NewPolicyHandler(policyUseCase usecase.PolicyUseCase, logger logger.Logger) *PolicyHandler {
+	return &PolicyHandler{
+		BaseHandler:   NewBaseHandler(logger),
+		policyUseCase: policyUseCase,
+	}
+}
+
+func (h *PolicyHandler) ExportPolicies(c *gin.Context) {
+	set, err := h.policyUseCase.Export(c.Request.Context())
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to export policies", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, set)
+}
+
+func (h *PolicyHandler) ImportPolicies(c *gin.Context) {
+	var set entities.PolicySet
+	if err := bindPolicySet(c, &set); err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid policy document", domainerrors.ErrInvalidRequest)
+		return
+	}
+	if !h.CheckBulkSize(c, len(set.Policies)) {
+		return
+	}
+
+	results, err := h.policyUseCase.Import(c.Request.Context(), &set)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrInvalidPolicyDoc) && results != nil {
+			h.logger.Error("Policy document failed validation", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   response.ErrorBody(c, domainerrors.ErrInvalidPolicyDoc),
+				"results": results,
+			})
+			return
+		}
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to import policies", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"results": results})
+}
+
+// bindPolicySet reads the document from the JSON body, or from the "file" part of a
+// multipart/form-data upload
+func bindPolicySet(c *gin.Context, set *entities.PolicySet) error {
+	if c.ContentType() != binding.MIMEMultipartPOSTForm {
+		return c.ShouldBindJSON(set)
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return err
+	}
+	file, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	body, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	return binding.JSON.BindBody(body, set)
+}
+
+func (h *PolicyHandler) GetPolicyVersions(c *gin.Context) {
+	policyID, err := h.ParseUUID(c, "id")
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid policy ID", err)
+		return
+	}
+
+	versions, err := h.policyUseCase.ListVersions(c.Request.Context(), policyID)
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to list policy versions", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"versions": versions})
+}
+
+// GetUserPolicies returns the policy documents that apply to a user through their role
+func (h *PolicyHandler) GetUserPolicies(c *gin.Context) {
+	userID, err := h.ParseUUID(c, "id")
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	requesterID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to get user policies", err)
+		return
+	}
+	result, err := h.policyUseCase.PoliciesForUser(c.Request.Context(), userID, requesterID)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to get user policies", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{
+		"user_id":  result.UserID,
+		"role":     result.Role,
+		"policies": result.Policies,
+	})
+}
+
+func (h *PolicyHandler) RollbackPolicy(c *gin.Context) {
+	policyID, err := h.ParseUUID(c, "id")
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid policy ID", err)
+		return
+	}
+
+	policy, err := h.policyUseCase.Rollback(c.Request.Context(), policyID, c.Param("version"))
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to roll back policy", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"policy": policy})
+}
+
+// ReloadPolicies refreshes the policy cache without a restart and reports how many policies were loaded
+func (h *PolicyHandler) ReloadPolicies(c *gin.Context) {
+	count, err := h.policyUseCase.Reload(c.Request.Context())
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to reload policies", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"policies_loaded": count})
+}
+
+type simulateParams struct {
+	Explain bool `form:"explain"`
+}
+
+// SimulatePolicy shows how the policy engine would decide a request for a role or user, without
+// acting on it. ?explain=true adds a trace of every statement evaluated.
+func (h *PolicyHandler) SimulatePolicy(c *gin.Context) {
+	var req SimulatePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid simulation request", domainerrors.ErrInvalidRequest)
+		return
+	}
+
+	permissionReq := &entities.PermissionRequest{
+		Role:       req.Role,
+		Resource:   req.Resource,
+		Action:     req.Action,
+		ResourceID: req.ResourceID,
+		Context:    req.Context,
+	}
+	if req.UserID != "" {
+		userID, err := uuid.Parse(req.UserID)
+		if err != nil {
+			h.SendErrorResponse(c, http.StatusBadRequest, "Invalid user ID", domainerrors.ErrInvalidID)
+			return
+		}
+		permissionReq.UserID = userID
+	}
+
+	var params simulateParams
+	if err := h.BindQuery(c, &params); err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid query", err)
+		return
+	}
+	ctx := c.Request.Context()
+	if params.Explain {
+		ctx = constants.WithPolicyExplain(ctx)
+	}
+
+	requesterID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to simulate policy", err)
+		return
+	}
+	response, err := h.policyUseCase.Simulate(ctx, permissionReq, requesterID)
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to simulate policy", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"request": permissionReq, "result": response})
+}
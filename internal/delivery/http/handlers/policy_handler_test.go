@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/usecase"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingPolicyUseCase records the requester each policy lookup runs for
+type recordingPolicyUseCase struct {
+	usecase.PolicyUseCase
+	requesters []uuid.UUID
+}
+
+func (u *recordingPolicyUseCase) PoliciesForUser(_ context.Context, targetID, requesterID uuid.UUID) (*entities.UserPolicies, error) {
+	u.requesters = append(u.requesters, requesterID)
+	return &entities.UserPolicies{UserID: targetID}, nil
+}
+
+func (u *recordingPolicyUseCase) Simulate(_ context.Context, _ *entities.PermissionRequest, requesterID uuid.UUID) (*entities.PermissionResponse, error) {
+	u.requesters = append(u.requesters, requesterID)
+	return &entities.PermissionResponse{}, nil
+}
+
+func TestPolicyHandler_RequiresIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	policies := &recordingPolicyUseCase{}
+	handler := NewPolicyHandler(policies, logger.NewLogger())
+
+	router := gin.New()
+	router.GET("/users/:id/policies", handler.GetUserPolicies)
+	router.POST("/policies/simulate", handler.SimulatePolicy)
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/users/"+uuid.NewString()+"/policies", nil),
+		httptest.NewRequest(http.MethodPost, "/policies/simulate",
+			strings.NewReader(`{"role":"user","resource":"products","action":"read"}`)),
+	}
+	for _, req := range requests {
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, req.URL.Path)
+	}
+	assert.Empty(t, policies.requesters, "a missing caller must not reach the use case as the nil user")
+}
@@ -7,21 +7,74 @@ import (
 	"clean-architecture-api/internal/usecase"
 	"clean-architecture-api/pkg/logger"
 	"net/http"
+	"os"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// productSortFields are the columns clients may pass as ?sort= on product lists
+var productSortFields = []string{"name", "price", "stock", "created_at"}
+
 type ProductHandler struct {
 	*BaseHandler
-	productUseCase usecase.ProductUseCase
+	productUseCase    usecase.ProductUseCase
+	lowStockThreshold int
+	// publicView serves anonymous callers the catalog view of each product
+	publicView bool
 }
 
 func NewProductHandler(productUseCase usecase.ProductUseCase, logger logger.Logger) *ProductHandler {
 	return &ProductHandler{
-		BaseHandler:    NewBaseHandler(logger),
-		productUseCase: productUseCase,
+		BaseHandler:       NewBaseHandler(logger),
+		productUseCase:    productUseCase,
+		lowStockThreshold: loadLowStockThreshold(),
+	}
+}
+
+// WithPublicView makes product reads by anonymous callers return entities.PublicProduct, leaving
+// out internal fields; authenticated callers still get full products
+func (h *ProductHandler) WithPublicView(enabled bool) *ProductHandler {
+	h.publicView = enabled
+	return h
+}
+
+// productView returns product as the caller may see it
+func (h *ProductHandler) productView(c *gin.Context, product *entities.Product) interface{} {
+	if !h.anonymousView(c) {
+		return product
+	}
+	return product.Public()
+}
+
+// productsView returns products as the caller may see them
+func (h *ProductHandler) productsView(c *gin.Context, products []*entities.Product) interface{} {
+	if !h.anonymousView(c) {
+		return products
+	}
+	views := make([]*entities.PublicProduct, len(products))
+	for i, product := range products {
+		views[i] = product.Public()
 	}
+	return views
+}
+
+func (h *ProductHandler) anonymousView(c *gin.Context) bool {
+	if !h.publicView {
+		return false
+	}
+	_, err := h.GetUserID(c)
+	return err != nil
+}
+
+// loadLowStockThreshold reads LOW_STOCK_THRESHOLD, the threshold the low-stock report uses when
+// the request does not pass one
+func loadLowStockThreshold() int {
+	if value, err := strconv.Atoi(os.Getenv("LOW_STOCK_THRESHOLD")); err == nil && value >= 0 {
+		return value
+	}
+	return constants.DefaultLowStockThreshold
 }
 
 type CreateProductRequest struct {
@@ -47,9 +100,9 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	userID, err := h.getUserIDFromContext(c)
+	userID, err := h.GetUserID(c)
 	if err != nil {
-		h.SendInternalServerError(c, "Failed to get user ID", err)
+		h.SendErrorResponse(c, 0, "Failed to get user ID", err)
 		return
 	}
 
@@ -66,14 +119,6 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	})
 }
 
-func (h *ProductHandler) getUserIDFromContext(c *gin.Context) (uuid.UUID, error) {
-	userID, exists := c.Get(string(constants.ContextUserID))
-	if !exists {
-		return uuid.Nil, errors.ErrUserIDNotFound
-	}
-	return userID.(uuid.UUID), nil
-}
-
 func (h *ProductHandler) createProductFromRequest(req CreateProductRequest) *entities.Product {
 	return &entities.Product{
 		Name:        req.Name,
@@ -93,11 +138,11 @@ func (h *ProductHandler) GetProductByID(c *gin.Context) {
 
 	product, err := h.productUseCase.GetByID(c.Request.Context(), productID)
 	if err != nil {
-		h.SendNotFound(c, err.Error())
+		h.SendErrorResponse(c, http.StatusNotFound, "Failed to get product", err)
 		return
 	}
 
-	h.SendSuccessResponse(c, http.StatusOK, gin.H{"product": product})
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"product": h.productView(c, product)})
 }
 
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
@@ -151,16 +196,66 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	h.SendSuccessResponse(c, http.StatusOK, gin.H{"message": "Product deleted successfully"})
 }
 
+// RestoreProduct brings back a soft-deleted product; a live or unknown product is a 404
+func (h *ProductHandler) RestoreProduct(c *gin.Context) {
+	productID, err := h.ParseUUID(c, "id")
+	if err != nil {
+		h.SendBadRequest(c, errors.ErrInvalidProductID.Error())
+		return
+	}
+
+	product, err := h.productUseCase.Restore(c.Request.Context(), productID)
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Failed to restore product", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"message": "Product restored successfully", "product": product})
+}
+
+// GetProductHistory lists who changed the product and when, newest first
+func (h *ProductHandler) GetProductHistory(c *gin.Context) {
+	productID, err := h.ParseUUID(c, "id")
+	if err != nil {
+		h.SendBadRequest(c, errors.ErrInvalidProductID.Error())
+		return
+	}
+
+	query, err := h.BindListQuery(c, constants.ResourceActivity)
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid list query", err)
+		return
+	}
+
+	history, err := h.productUseCase.History(c.Request.Context(), productID, query.Limit, query.Offset)
+	if err != nil {
+		h.SendInternalServerError(c, "Failed to get product history", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"history": history, "page": query.Page()})
+}
+
 func (h *ProductHandler) ListProducts(c *gin.Context) {
-	limit, offset := h.ParsePagination(c)
+	query, err := h.BindListQuery(c, constants.ResourceProduct, productSortFields...)
+	if err == nil {
+		err = query.RejectUnknownFilters()
+	}
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid list query", err)
+		return
+	}
 
-	products, err := h.productUseCase.List(c.Request.Context(), limit, offset)
+	products, err := h.productUseCase.List(query.WithSort(c.Request.Context()), query.Limit, query.Offset)
 	if err != nil {
-		h.SendInternalServerError(c, "Failed to list products", err)
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to list products", err)
 		return
 	}
+	h.SetPaginationHeaders(c, query, func() (int64, error) {
+		return h.productUseCase.Count(c.Request.Context())
+	})
 
-	h.SendSuccessResponse(c, http.StatusOK, gin.H{"products": products})
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"products": h.productsView(c, products), "page": query.Page()})
 }
 
 func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
@@ -170,13 +265,65 @@ func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
 		return
 	}
 
-	limit, offset := h.ParsePagination(c)
+	query, err := h.BindListQuery(c, constants.ResourceProduct, productSortFields...)
+	if err == nil {
+		err = query.RejectUnknownFilters()
+	}
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid list query", err)
+		return
+	}
+
+	products, err := h.productUseCase.GetByCategory(query.WithSort(c.Request.Context()), category, query.Limit, query.Offset)
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to get products by category", err)
+		return
+	}
+	h.SetPaginationHeaders(c, query, func() (int64, error) {
+		return h.productUseCase.CountInCategory(c.Request.Context(), category)
+	})
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"products": h.productsView(c, products), "page": query.Page()})
+}
+
+// GetCategoryStats returns the number of products in each category. Products without a category
+// are counted under the empty key.
+func (h *ProductHandler) GetCategoryStats(c *gin.Context) {
+	counts, err := h.productUseCase.CountByCategory(c.Request.Context())
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusInternalServerError, "Failed to count products per category", err)
+		return
+	}
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"categories": counts})
+}
+
+type lowStockParams struct {
+	Threshold *int `form:"threshold" binding:"omitempty,min=0"`
+}
+
+// LowStockReport lists products with stock at or below ?threshold=, lowest stock first
+func (h *ProductHandler) LowStockReport(c *gin.Context) {
+	var params lowStockParams
+	if err := h.BindQuery(c, &params); err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid threshold", err)
+		return
+	}
+	threshold := h.lowStockThreshold
+	if params.Threshold != nil {
+		threshold = *params.Threshold
+	}
+
+	query, err := h.BindListQuery(c, constants.ResourceProduct)
+	if err != nil {
+		h.SendErrorResponse(c, http.StatusBadRequest, "Invalid list query", err)
+		return
+	}
 
-	products, err := h.productUseCase.GetByCategory(c.Request.Context(), category, limit, offset)
+	products, err := h.productUseCase.LowStock(c.Request.Context(), threshold, query.Limit, query.Offset)
 	if err != nil {
-		h.SendInternalServerError(c, "Failed to get products by category", err)
+		h.SendErrorResponse(c, 0, "Failed to get low stock products", err)
 		return
 	}
 
-	h.SendSuccessResponse(c, http.StatusOK, gin.H{"products": products})
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"threshold": threshold, "products": products, "page": query.Page()})
 }
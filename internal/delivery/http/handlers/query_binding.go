@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// queryRuleType is the rule reported for a value that does not parse as its field's type
+	queryRuleType = "type"
+	// queryRuleUnknown is the rule reported for a parameter the endpoint does not accept
+	queryRuleUnknown = "unknown"
+)
+
+// FieldError describes one query parameter that did not parse or broke a validation rule
+type FieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Param string `json:"param,omitempty"`
+}
+
+// QueryError is returned by BindQuery. SendErrorResponse renders it as an INVALID_QUERY
+// validation error listing every offending parameter.
+type QueryError struct {
+	Fields []FieldError
+}
+
+func (e *QueryError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		parts[i] = field.Field + ": " + field.Rule
+		if field.Param != "" {
+			parts[i] += "=" + field.Param
+		}
+	}
+	return domainerrors.ErrInvalidQuery.Message + ": " + strings.Join(parts, ", ")
+}
+
+func (e *QueryError) Unwrap() error {
+	return domainerrors.ErrInvalidQuery
+}
+
+// BindQuery fills dst, a pointer to a struct with form tags, from the query string and checks
+// its binding tags
+func (h *BaseHandler) BindQuery(c *gin.Context, dst interface{}) error {
+	if fields := queryTypeErrors(c, dst); len(fields) > 0 {
+		return &QueryError{Fields: fields}
+	}
+
+	err := c.ShouldBindQuery(dst)
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, len(validationErrs))
+		for i, fieldErr := range validationErrs {
+			fields[i] = FieldError{
+				Field: queryFieldName(dst, fieldErr.StructField()),
+				Rule:  fieldErr.Tag(),
+				Param: fieldErr.Param(),
+			}
+		}
+		return &QueryError{Fields: fields}
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", domainerrors.ErrInvalidQuery, err)
+	}
+	return nil
+}
+
+// queryTypeErrors reports the parameters that do not parse as the numeric or boolean field they
+// bind to. Gin stops at the first such value and does not say which parameter it was.
+func queryTypeErrors(c *gin.Context, dst interface{}) []FieldError {
+	var fields []FieldError
+	structType := reflect.TypeOf(dst).Elem()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+		value, ok := c.GetQuery(name)
+		if name == "" || name == "-" || !ok || value == "" {
+			continue
+		}
+
+		kind := field.Type.Kind()
+		if kind == reflect.Ptr {
+			kind = field.Type.Elem().Kind()
+		}
+		var err error
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			_, err = strconv.ParseInt(value, 10, 64)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			_, err = strconv.ParseUint(value, 10, 64)
+		case reflect.Float32, reflect.Float64:
+			_, err = strconv.ParseFloat(value, 64)
+		case reflect.Bool:
+			_, err = strconv.ParseBool(value)
+		}
+		if err != nil {
+			fields = append(fields, FieldError{Field: name, Rule: queryRuleType, Param: kind.String()})
+		}
+	}
+	return fields
+}
+
+// queryFieldName maps a struct field back to the query parameter it binds
+func queryFieldName(dst interface{}, structField string) string {
+	field, ok := reflect.TypeOf(dst).Elem().FieldByName(structField)
+	if !ok {
+		return structField
+	}
+	if name := strings.Split(field.Tag.Get("form"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return structField
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/pkg/logger"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type queryErrorResponse struct {
+	Error struct {
+		Category string       `json:"category"`
+		Code     string       `json:"code"`
+		Fields   []FieldError `json:"fields"`
+	} `json:"error"`
+}
+
+func TestBaseHandler_BindQuery_InvalidValues(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewBaseHandler(logger.NewLogger())
+	router := gin.New()
+	router.GET("/list", func(c *gin.Context) {
+		query, err := handler.BindListQuery(c, constants.ResourceProduct)
+		if err != nil {
+			handler.SendErrorResponse(c, http.StatusBadRequest, "Invalid list query", err)
+			return
+		}
+		handler.SendSuccessResponse(c, http.StatusOK, query)
+	})
+	router.GET("/low-stock", func(c *gin.Context) {
+		var params lowStockParams
+		if err := handler.BindQuery(c, &params); err != nil {
+			handler.SendErrorResponse(c, http.StatusBadRequest, "Invalid threshold", err)
+			return
+		}
+		handler.SendSuccessResponse(c, http.StatusOK, params)
+	})
+
+	tests := []struct {
+		path   string
+		fields []FieldError
+	}{
+		{"/list?limit=ten", []FieldError{{Field: "limit", Rule: "type", Param: "int"}}},
+		{"/list?limit=abc&offset=1.5", []FieldError{
+			{Field: "limit", Rule: "type", Param: "int"},
+			{Field: "offset", Rule: "type", Param: "int"},
+		}},
+		{"/list?limit=-5&offset=-1", []FieldError{
+			{Field: "limit", Rule: "min", Param: "0"},
+			{Field: "offset", Rule: "min", Param: "0"},
+		}},
+		{"/low-stock?threshold=-1", []FieldError{{Field: "threshold", Rule: "min", Param: "0"}}},
+		{"/low-stock?threshold=few", []FieldError{{Field: "threshold", Rule: "type", Param: "int"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var body queryErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, string(domainerrors.CategoryValidation), body.Error.Category)
+			assert.Equal(t, domainerrors.ErrInvalidQuery.Code, body.Error.Code)
+			assert.Equal(t, tt.fields, body.Error.Fields)
+		})
+	}
+
+	for _, path := range []string{"/list?limit=500&offset=0", "/list?limit=", "/low-stock?threshold=0", "/low-stock"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path+": "+w.Body.String())
+	}
+}
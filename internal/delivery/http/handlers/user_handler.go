@@ -4,6 +4,7 @@ import (
 	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/entities"
 	domainerrors "clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/internal/usecase"
 	"clean-architecture-api/pkg/logger"
 	"net/http"
@@ -12,6 +13,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// userSortFields are the columns clients may pass as ?sort= on user lists
+var userSortFields = []string{"email", "first_name", "last_name", "created_at"}
+
 type UserHandler struct {
 	*BaseHandler
 	userUseCase usecase.UserUseCase
@@ -38,7 +42,11 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 
-	currentUserID := h.getCurrentUserID(c)
+	currentUserID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to get user", err)
+		return
+	}
 	user, err := h.userUseCase.GetByID(c.Request.Context(), targetUserID, currentUserID)
 	if err != nil {
 		h.SendErrorResponse(c, 0, "Failed to get user", err)
@@ -48,6 +56,16 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	h.SendSuccessResponse(c, http.StatusOK, gin.H{"user": user})
 }
 
+func (h *UserHandler) GetUserByEmail(c *gin.Context) {
+	user, err := h.userUseCase.GetByEmail(c.Request.Context(), c.Query("email"))
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to get user by email", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"user": user})
+}
+
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	targetUserID, err := h.ParseUUID(c, "id")
 	if err != nil {
@@ -62,8 +80,12 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	currentUserID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to update user", err)
+		return
+	}
 	user := h.createUserFromRequest(targetUserID, req)
-	currentUserID := h.getCurrentUserID(c)
 
 	if err := h.userUseCase.Update(c.Request.Context(), user, currentUserID); err != nil {
 		h.SendErrorResponse(c, 0, "Failed to update user", err)
@@ -92,7 +114,11 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	currentUserID := h.getCurrentUserID(c)
+	currentUserID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to delete user", err)
+		return
+	}
 	if err := h.userUseCase.Delete(c.Request.Context(), targetUserID, currentUserID); err != nil {
 		h.SendErrorResponse(c, 0, "Failed to delete user", err)
 		return
@@ -101,24 +127,94 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	h.SendSuccessResponse(c, http.StatusOK, gin.H{"message": "User deleted successfully"})
 }
 
+// ListUsers pages through the users the caller may see. It takes no filters; the admin search
+// narrows by role and status.
 func (h *UserHandler) ListUsers(c *gin.Context) {
-	limit, offset := h.ParsePagination(c)
-	currentUserID := h.getCurrentUserID(c)
+	query, err := h.BindListQuery(c, constants.ResourceUser, userSortFields...)
+	if err == nil {
+		err = query.RejectUnknownFilters()
+	}
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Invalid list query", err)
+		return
+	}
+	currentUserID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to list users", err)
+		return
+	}
 
-	users, err := h.userUseCase.List(c.Request.Context(), limit, offset, currentUserID)
+	users, err := h.userUseCase.List(query.WithSort(c.Request.Context()), query.Limit, query.Offset, currentUserID)
 	if err != nil {
 		h.SendInternalServerError(c, "Failed to list users", err)
 		return
 	}
+	h.SetPaginationHeaders(c, query, func() (int64, error) {
+		return h.userUseCase.Count(c.Request.Context(), currentUserID)
+	})
 
-	h.SendSuccessResponse(c, http.StatusOK, gin.H{"users": users})
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"users": users, "page": query.Page()})
 }
 
-func (h *UserHandler) getCurrentUserID(c *gin.Context) uuid.UUID {
-	if userID, exists := c.Get("user_id"); exists {
-		if id, ok := userID.(uuid.UUID); ok {
-			return id
-		}
+// userSearchParams are the filters of the admin user search; they are the only filter
+// parameters it accepts
+type userSearchParams struct {
+	Query  string `form:"q" binding:"max=100"`
+	Role   string `form:"role" binding:"omitempty,oneof=user admin"`
+	Active *bool  `form:"active"`
+}
+
+var userSearchFilters = []string{"q", "role", "active"}
+
+// SearchUsers finds users whose name or email contains ?q=, optionally narrowed by ?role= and
+// ?active=. The response carries the total number of matches.
+func (h *UserHandler) SearchUsers(c *gin.Context) {
+	var params userSearchParams
+	if err := h.BindQuery(c, &params); err != nil {
+		h.SendErrorResponse(c, 0, "Invalid search query", err)
+		return
+	}
+	query, err := h.BindListQuery(c, constants.ResourceUser, userSortFields...)
+	if err == nil {
+		err = query.RejectUnknownFilters(userSearchFilters...)
+	}
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Invalid search query", err)
+		return
 	}
-	return uuid.MustParse(constants.SystemUserID)
+
+	search := repositories.UserSearch{Query: params.Query, Role: params.Role, Active: params.Active}
+	users, total, err := h.userUseCase.Search(query.WithSort(c.Request.Context()), search, query.Limit, query.Offset)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to search users", err)
+		return
+	}
+	h.SetPaginationHeaders(c, query, func() (int64, error) {
+		return total, nil
+	})
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"users": users, "total": total, "page": query.Page()})
+}
+
+// GetMyActivity lists the caller's own audit entries, newest first
+func (h *UserHandler) GetMyActivity(c *gin.Context) {
+	userID, err := h.GetUserID(c)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Failed to get activity", err)
+		return
+	}
+
+	query, err := h.BindListQuery(c, constants.ResourceActivity)
+	if err != nil {
+		h.SendErrorResponse(c, 0, "Invalid list query", err)
+		return
+	}
+
+	activity, err := h.userUseCase.Activity(c.Request.Context(), userID, query.Limit, query.Offset)
+	if err != nil {
+		h.SendInternalServerError(c, "Failed to get activity", err)
+		return
+	}
+
+	h.SendSuccessResponse(c, http.StatusOK, gin.H{"activity": activity, "page": query.Page()})
 }
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/usecase"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingUserUseCase records the caller each user operation runs as
+type recordingUserUseCase struct {
+	usecase.UserUseCase
+	callers []uuid.UUID
+}
+
+func (u *recordingUserUseCase) GetByID(_ context.Context, id uuid.UUID, userID uuid.UUID) (*entities.User, error) {
+	u.callers = append(u.callers, userID)
+	return &entities.User{BaseEntity: entities.BaseEntity{ID: id}}, nil
+}
+
+func (u *recordingUserUseCase) Update(_ context.Context, _ *entities.User, userID uuid.UUID) error {
+	u.callers = append(u.callers, userID)
+	return nil
+}
+
+func (u *recordingUserUseCase) Delete(_ context.Context, _ uuid.UUID, userID uuid.UUID) error {
+	u.callers = append(u.callers, userID)
+	return nil
+}
+
+func (u *recordingUserUseCase) List(_ context.Context, _, _ int, userID uuid.UUID) ([]*entities.User, error) {
+	u.callers = append(u.callers, userID)
+	return nil, nil
+}
+
+func TestUserHandler_RequiresIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := &recordingUserUseCase{}
+	handler := NewUserHandler(users, logger.NewLogger())
+
+	// No auth middleware runs, so no caller is in the context
+	router := gin.New()
+	router.GET("/users", handler.ListUsers)
+	router.GET("/users/:id", handler.GetUserByID)
+	router.PUT("/users/:id", handler.UpdateUser)
+	router.DELETE("/users/:id", handler.DeleteUser)
+
+	path := "/users/" + uuid.NewString()
+	body := `{"first_name":"Ada","last_name":"Lovelace","role":"user"}`
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/users", nil),
+		httptest.NewRequest(http.MethodGet, path, nil),
+		httptest.NewRequest(http.MethodPut, path, strings.NewReader(body)),
+		httptest.NewRequest(http.MethodDelete, path, nil),
+	}
+	for _, req := range requests {
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, req.Method+" "+req.URL.Path)
+		assert.Contains(t, rec.Body.String(), `"code":"USER_ID_NOT_FOUND"`)
+	}
+	assert.Empty(t, users.callers, "no operation may run without a caller, least of all as the system user")
+}
+
+func TestUserHandler_ListUsersRejectsFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := &recordingUserUseCase{}
+	handler := NewUserHandler(users, logger.NewLogger())
+
+	router := gin.New()
+	router.GET("/users", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(constants.WithUserID(c.Request.Context(), uuid.New()))
+		handler.ListUsers(c)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users?role=admin&limit=5", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"role"`)
+	assert.Empty(t, users.callers, "a filter the list cannot apply must not fall back to listing everyone")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users?limit=5", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, users.callers, 1)
+}
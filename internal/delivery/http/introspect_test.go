@@ -0,0 +1,88 @@
+package http
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospect(t *testing.T) {
+	server, _ := newProductAccessServer(t, "")
+	userID, token := registerAndLogin(t, server, "gateway@example.com")
+
+	introspect := func(token string) map[string]interface{} {
+		rec := doJSON(t, server, http.MethodPost, "/api/v1/auth/introspect", "", map[string]string{"token": token})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		got := introspect(token)
+		assert.Equal(t, true, got["active"])
+		assert.Equal(t, userID, got["user_id"])
+		assert.Equal(t, "gateway@example.com", got["email"])
+		assert.Equal(t, constants.RoleUser, got["role"])
+		assert.Greater(t, got["exp"], float64(time.Now().Unix()))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		signed := func(expiresAt time.Time) string {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{
+				UserID:           uuid.MustParse(userID),
+				Email:            "gateway@example.com",
+				Role:             constants.RoleUser,
+				RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(expiresAt)},
+			}).SignedString([]byte("test-secret"))
+			require.NoError(t, err)
+			return token
+		}
+		require.Equal(t, true, introspect(signed(time.Now().Add(time.Hour)))["active"], "same key, not yet expired")
+		assert.Equal(t, map[string]interface{}{"active": false}, introspect(signed(time.Now().Add(-time.Hour))))
+	})
+
+	t.Run("refresh token", func(t *testing.T) {
+		rec := doJSON(t, server, http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+			"email": "gateway@example.com", "password": "password123",
+		})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var login struct {
+			Data struct {
+				Tokens struct {
+					RefreshToken string `json:"refresh_token"`
+				} `json:"tokens"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &login))
+		refreshToken := login.Data.Tokens.RefreshToken
+		require.NotEmpty(t, refreshToken)
+
+		assert.Equal(t, map[string]interface{}{"active": false}, introspect(refreshToken))
+		rec = doJSON(t, server, http.MethodGet, "/api/v1/auth/sessions", refreshToken, nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, "a refresh token is not accepted as a bearer token")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{"active": false}, introspect("not.a.jwt"))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		rec := doJSON(t, server, http.MethodPost, "/api/v1/auth/introspect", "", map[string]string{})
+		assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	})
+
+	// Introspection has no side effects: the token still works afterwards
+	rec := doJSON(t, server, http.MethodGet, "/api/v1/auth/sessions", token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
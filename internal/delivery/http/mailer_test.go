@@ -0,0 +1,48 @@
+package http
+
+import (
+	"clean-architecture-api/internal/infrastructure/database"
+	"clean-architecture-api/pkg/logger"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServer_RefusesLogMailerWhereCodesMatter(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"production", map[string]string{"ENV": "production"}},
+		{"email verification", map[string]string{"EMAIL_VERIFICATION_ENABLED": "true"}},
+		{"explicit log driver", map[string]string{"EMAIL_VERIFICATION_ENABLED": "true", "MAIL_DRIVER": "log", "SMTP_ADDR": "smtp.example.com:587"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_SECRET_KEY", "test-secret")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			db, err := database.NewInMemoryDatabase()
+			require.NoError(t, err)
+
+			_, err = NewServer(testConfig(t), db, logger.NewLogger())
+			assert.ErrorContains(t, err, "log mail driver")
+		})
+	}
+
+	t.Run("smtp relay", func(t *testing.T) {
+		t.Setenv("JWT_SECRET_KEY", "test-secret")
+		t.Setenv("ENV", "production")
+		t.Setenv("EMAIL_VERIFICATION_ENABLED", "true")
+		t.Setenv("SMTP_ADDR", "smtp.example.com:587")
+		t.Setenv("SMTP_FROM", "noreply@example.com")
+		db, err := database.NewInMemoryDatabase()
+		require.NoError(t, err)
+
+		_, err = NewServer(testConfig(t), db, logger.NewLogger())
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/infrastructure/database"
+	"clean-architecture-api/internal/infrastructure/repository"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newMemoryDriverServer(t *testing.T, policy *entities.PolicyDocument) (*Server, *gorm.DB) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	db, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+	require.NoError(t, repository.NewPolicySQLiteRepository(db, logger.NewLogger()).Create(context.Background(), policy))
+	cfg := testConfig(t)
+	cfg.DBDriver = constants.DBDriverMemory
+	server, err := NewServer(cfg, db, logger.NewLogger())
+	require.NoError(t, err)
+	return server, db
+}
+
+func TestServer_MemoryDriverKeepsUsersOutOfTheDatabase(t *testing.T) {
+	server, db := newMemoryDriverServer(t, selfAccessPolicy())
+
+	userID, token := registerAndLogin(t, server, "memory@example.com")
+	update := map[string]interface{}{"first_name": "Renamed", "last_name": "User", "role": constants.RoleUser, "is_active": true}
+	rec := doJSON(t, server, http.MethodPut, "/api/v1/users/"+userID, token, update)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = doJSON(t, server, http.MethodGet, "/api/v1/users/"+userID, token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"first_name":"Renamed"`)
+
+	var stored int64
+	require.NoError(t, db.Model(&entities.UserSQLite{}).Count(&stored).Error)
+	assert.Zero(t, stored)
+
+	// Another user is still hidden by the same repository access checks
+	otherID, _ := registerAndLogin(t, server, "other@example.com")
+	rec = doJSON(t, server, http.MethodGet, "/api/v1/users/"+otherID, token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+}
+
+func TestServer_MemoryDriverReservesStockOfMemoryProducts(t *testing.T) {
+	server, db := newMemoryDriverServer(t, productManagerPolicy())
+	managerID, token := registerAndLogin(t, server, "stock@example.com")
+
+	rec := doJSON(t, server, http.MethodPost, "/api/v1/products", token,
+		map[string]interface{}{"name": "Lamp", "price": 20, "stock": 3, "category": "home"})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created struct {
+		Data struct {
+			Product struct {
+				ID string `json:"id"`
+			} `json:"product"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	path := "/api/v1/products/" + created.Data.Product.ID
+	stock := func() float64 {
+		rec := doJSON(t, server, http.MethodGet, path, token, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp struct {
+			Data struct {
+				Product map[string]interface{} `json:"product"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp.Data.Product["stock"].(float64)
+	}
+
+	// The reservation takes its units from the product the HTTP routes serve
+	ctx := constants.WithUserRole(constants.WithUserID(context.Background(), uuid.MustParse(managerID)), constants.RoleUser)
+	reservationID, err := server.products.Reserve(ctx, uuid.MustParse(created.Data.Product.ID), 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), stock())
+	require.NoError(t, server.products.Release(ctx, reservationID))
+	assert.Equal(t, float64(3), stock())
+
+	var stored int64
+	require.NoError(t, db.Model(&entities.Reservation{}).Count(&stored).Error)
+	assert.Zero(t, stored)
+}
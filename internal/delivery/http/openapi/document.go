@@ -0,0 +1,180 @@
+// Package openapi builds an OpenAPI 3 document at startup from the request and response
+// types the handlers already use, and serves it together with a Swagger UI page.
+package openapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+const Version = "3.0.3"
+
+const (
+	contentTypeJSON = "application/json"
+	bearerAuth      = "bearerAuth"
+)
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower-case HTTP methods to their operation
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z_]+)`)
+
+func NewDocument(title, version, description string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: title, Version: version, Description: description},
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+}
+
+// Add documents a route given in gin syntax; ":id" segments become required path parameters
+func (d *Document) Add(method, ginPath string, op Operation) {
+	path := ginParam.ReplaceAllString(ginPath, "{$1}")
+	for _, match := range ginParam.FindAllStringSubmatch(ginPath, -1) {
+		op.Parameters = append([]Parameter{{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		}}, op.Parameters...)
+	}
+	if op.Responses == nil {
+		op.Responses = make(map[string]Response)
+	}
+
+	item, exists := d.Paths[path]
+	if !exists {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	(*item)[strings.ToLower(method)] = &op
+}
+
+// Has reports whether a route given in gin syntax is documented
+func (d *Document) Has(method, ginPath string) bool {
+	item, exists := d.Paths[ginParam.ReplaceAllString(ginPath, "{$1}")]
+	if !exists {
+		return false
+	}
+	_, exists = (*item)[strings.ToLower(method)]
+	return exists
+}
+
+// BearerAuth marks an operation as requiring the Authorization: Bearer header
+func BearerAuth() []map[string][]string {
+	return []map[string][]string{{bearerAuth: {}}}
+}
+
+func QueryParam(name, schemaType, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: schemaType}}
+}
+
+// JSONBody describes a required JSON request body shaped like v
+func (d *Document) JSONBody(v interface{}) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content:  map[string]MediaType{contentTypeJSON: {Schema: d.SchemaOf(v)}},
+	}
+}
+
+// Success describes the {"success": true, "data": {...}} envelope written by SendSuccessResponse,
+// with one data property per entry in fields
+func (d *Document) Success(description string, fields map[string]interface{}) Response {
+	data := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for name, value := range fields {
+		data.Properties[name] = d.SchemaOf(value)
+	}
+
+	return Response{
+		Description: description,
+		Content: map[string]MediaType{contentTypeJSON: {Schema: &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"success": {Type: "boolean"},
+				"data":    data,
+			},
+		}}},
+	}
+}
+
+// Error describes the error bodies written by SendErrorResponse and friends
+func (d *Document) Error(description string) Response {
+	return Response{
+		Description: description,
+		Content:     map[string]MediaType{contentTypeJSON: {Schema: d.errorSchema()}},
+	}
+}
+
+func (d *Document) errorSchema() *Schema {
+	const name = "Error"
+	if _, exists := d.Components.Schemas[name]; !exists {
+		d.Components.Schemas[name] = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"error": {Description: "A message, or an object with category, code and message"},
+			},
+		}
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
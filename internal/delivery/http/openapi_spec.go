@@ -0,0 +1,332 @@
+package http
+
+import (
+	"clean-architecture-api/internal/delivery/http/handlers"
+	"clean-architecture-api/internal/delivery/http/openapi"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/pkg/version"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const openAPISpecPath = "/openapi.json"
+
+// newOpenAPIDocument documents the auth, user and product routes. Keep it in step with
+// setupAuthRoutes, setupUserRoutes and setupProductRoutes; the route coverage test fails otherwise.
+func newOpenAPIDocument() *openapi.Document {
+	doc := openapi.NewDocument("Clean Architecture API", version.Get().Version,
+		"Successful responses are wrapped as {\"success\": true, \"data\": {...}}.")
+
+	pagination := []openapi.Parameter{
+		openapi.QueryParam("limit", "integer", "Page size; the default and cap are configured per resource"),
+		openapi.QueryParam("offset", "integer", "Number of items to skip"),
+		openapi.QueryParam("sort", "string", "Field to sort by, prefixed with - for descending; defaults to creation order"),
+	}
+	unauthorized := doc.Error("Missing or invalid bearer token")
+	forbidden := doc.Error("Insufficient permissions")
+	badRequest := doc.Error("Invalid request")
+	notFound := doc.Error("Not found")
+
+	// Auth
+	doc.Add(http.MethodPost, "/api/v1/auth/register", openapi.Operation{
+		Summary:     "Register a new user",
+		Tags:        []string{"auth"},
+		RequestBody: doc.JSONBody(handlers.RegisterRequest{}),
+		Responses: map[string]openapi.Response{
+			"201": doc.Success("User registered", map[string]interface{}{"message": "", "user": entities.User{}}),
+			"400": badRequest,
+		},
+	})
+	doc.Add(http.MethodPost, "/api/v1/auth/login", openapi.Operation{
+		Summary:     "Log in and receive a token pair",
+		Tags:        []string{"auth"},
+		RequestBody: doc.JSONBody(handlers.LoginRequest{}),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Logged in", map[string]interface{}{"message": "", "tokens": auth.TokenPair{}}),
+			"400": badRequest,
+			"401": doc.Error("Invalid credentials"),
+		},
+	})
+	doc.Add(http.MethodPost, "/api/v1/auth/refresh", openapi.Operation{
+		Summary:     "Exchange a refresh token for a new token pair",
+		Tags:        []string{"auth"},
+		RequestBody: doc.JSONBody(handlers.RefreshTokenRequest{}),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Token refreshed", map[string]interface{}{"message": "", "tokens": auth.TokenPair{}}),
+			"400": badRequest,
+			"401": doc.Error("Invalid refresh token"),
+		},
+	})
+	doc.Add(http.MethodPost, "/api/v1/auth/logout", openapi.Operation{
+		Summary:     "End the session a refresh token belongs to",
+		Tags:        []string{"auth"},
+		RequestBody: doc.JSONBody(handlers.RefreshTokenRequest{}),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Logged out", map[string]interface{}{"message": ""}),
+			"400": badRequest,
+			"401": doc.Error("Invalid refresh token"),
+		},
+	})
+	doc.Add(http.MethodPost, "/api/v1/auth/introspect", openapi.Operation{
+		Summary:     "Check a token without side effects; invalid tokens report active=false",
+		Tags:        []string{"auth"},
+		RequestBody: doc.JSONBody(handlers.IntrospectRequest{}),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Token state", map[string]interface{}{
+				"active": false, "user_id": uuid.UUID{}, "email": "", "role": "", "exp": int64(0),
+			}),
+			"400": badRequest,
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/auth/sessions", openapi.Operation{
+		Summary:  "List the caller's signed-in sessions",
+		Tags:     []string{"auth"},
+		Security: openapi.BearerAuth(),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Sessions", map[string]interface{}{"sessions": []entities.Session{}}),
+			"401": unauthorized,
+		},
+	})
+	doc.Add(http.MethodDelete, "/api/v1/auth/sessions/:id", openapi.Operation{
+		Summary:  "Sign the caller out of one session",
+		Tags:     []string{"auth"},
+		Security: openapi.BearerAuth(),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Session revoked", map[string]interface{}{"message": ""}),
+			"401": unauthorized,
+			"404": notFound,
+		},
+	})
+	doc.Add(http.MethodPut, "/api/v1/auth/email", openapi.Operation{
+		Summary:     "Change the caller's email, pending confirmation when verification is enabled",
+		Tags:        []string{"auth"},
+		Security:    openapi.BearerAuth(),
+		RequestBody: doc.JSONBody(handlers.ChangeEmailRequest{}),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Email updated", map[string]interface{}{"message": "", "user": entities.User{}}),
+			"202": doc.Success("Confirmation sent to the new address", map[string]interface{}{"message": "", "user": entities.User{}}),
+			"400": badRequest,
+			"401": unauthorized,
+			"409": doc.Error("Email already in use"),
+		},
+	})
+	doc.Add(http.MethodPost, "/api/v1/auth/email/confirm", openapi.Operation{
+		Summary:     "Confirm a pending email change with the token sent to the new address",
+		Tags:        []string{"auth"},
+		Security:    openapi.BearerAuth(),
+		RequestBody: doc.JSONBody(handlers.ConfirmEmailChangeRequest{}),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Email updated", map[string]interface{}{"message": "", "user": entities.User{}}),
+			"400": badRequest,
+			"401": unauthorized,
+			"409": doc.Error("Email already in use"),
+		},
+	})
+	doc.Add(http.MethodPut, "/api/v1/auth/password", openapi.Operation{
+		Summary:     "Change the caller's password, revoking every token issued before the change",
+		Tags:        []string{"auth"},
+		Security:    openapi.BearerAuth(),
+		RequestBody: doc.JSONBody(handlers.ChangePasswordRequest{}),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Password changed", map[string]interface{}{"message": "", "tokens": auth.TokenPair{}}),
+			"400": doc.Error("Invalid request or wrong current password"),
+			"401": unauthorized,
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/auth/permissions", openapi.Operation{
+		Summary:  "List the caller's effective permissions",
+		Tags:     []string{"auth"},
+		Security: openapi.BearerAuth(),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Effective permissions", map[string]interface{}{"permissions": []entities.Permission{}}),
+			"401": unauthorized,
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/auth/permissions/:resource/actions", openapi.Operation{
+		Summary:  "List the actions the caller's role allows on a resource",
+		Tags:     []string{"auth"},
+		Security: openapi.BearerAuth(),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Allowed actions", map[string]interface{}{"resource": "", "actions": []string{}}),
+			"401": unauthorized,
+		},
+	})
+	doc.Add(http.MethodPost, "/api/v1/auth/permissions/check", openapi.Operation{
+		Summary:     "Check several permissions for the caller at once",
+		Tags:        []string{"auth"},
+		Security:    openapi.BearerAuth(),
+		RequestBody: doc.JSONBody(handlers.CheckPermissionsRequest{}),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Result per check, in request order", map[string]interface{}{"results": []handlers.PermissionCheckResult{}}),
+			"400": badRequest,
+			"401": unauthorized,
+		},
+	})
+
+	// Users
+	doc.Add(http.MethodGet, "/api/v1/users/by-email", openapi.Operation{
+		Summary:    "Get a user by email (admin only)",
+		Tags:       []string{"users"},
+		Security:   openapi.BearerAuth(),
+		Parameters: []openapi.Parameter{openapi.QueryParam("email", "string", "Email address")},
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("User", map[string]interface{}{"user": entities.User{}}),
+			"401": unauthorized,
+			"403": forbidden,
+			"404": notFound,
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/users/me/activity", openapi.Operation{
+		Summary:    "List the caller's own audit trail, newest first",
+		Tags:       []string{"users"},
+		Security:   openapi.BearerAuth(),
+		Parameters: pagination[:2],
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Activity", map[string]interface{}{"activity": []entities.ActivityEntry{}}),
+			"401": unauthorized,
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/users", openapi.Operation{
+		Summary:    "List users",
+		Tags:       []string{"users"},
+		Security:   openapi.BearerAuth(),
+		Parameters: pagination,
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Users", map[string]interface{}{"users": []entities.User{}}),
+			"401": unauthorized,
+			"403": forbidden,
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/users/:id", openapi.Operation{
+		Summary:  "Get a user by ID",
+		Tags:     []string{"users"},
+		Security: openapi.BearerAuth(),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("User", map[string]interface{}{"user": entities.User{}}),
+			"401": unauthorized,
+			"403": forbidden,
+			"404": notFound,
+		},
+	})
+	doc.Add(http.MethodPut, "/api/v1/users/:id", openapi.Operation{
+		Summary:     "Update a user",
+		Tags:        []string{"users"},
+		Security:    openapi.BearerAuth(),
+		RequestBody: doc.JSONBody(handlers.UpdateUserRequest{}),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("User updated", map[string]interface{}{"message": ""}),
+			"400": badRequest,
+			"401": unauthorized,
+			"403": forbidden,
+		},
+	})
+	doc.Add(http.MethodDelete, "/api/v1/users/:id", openapi.Operation{
+		Summary:  "Delete a user",
+		Tags:     []string{"users"},
+		Security: openapi.BearerAuth(),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("User deleted", map[string]interface{}{"message": ""}),
+			"401": unauthorized,
+			"403": forbidden,
+		},
+	})
+
+	// Products
+	doc.Add(http.MethodGet, "/api/v1/products", openapi.Operation{
+		Summary:    "List products",
+		Tags:       []string{"products"},
+		Parameters: pagination,
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Products", map[string]interface{}{"products": []entities.Product{}}),
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/products/:id", openapi.Operation{
+		Summary: "Get a product by ID",
+		Tags:    []string{"products"},
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Product", map[string]interface{}{"product": entities.Product{}}),
+			"400": badRequest,
+			"404": notFound,
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/products/category/:category", openapi.Operation{
+		Summary:    "List products in a category",
+		Tags:       []string{"products"},
+		Parameters: pagination,
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Products", map[string]interface{}{"products": []entities.Product{}}),
+			"400": badRequest,
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/products/stats/categories", openapi.Operation{
+		Summary: "Count products per category",
+		Tags:    []string{"products"},
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Product counts keyed by category; the empty key counts uncategorized products",
+				map[string]interface{}{"categories": map[string]int64{}}),
+		},
+	})
+	doc.Add(http.MethodPost, "/api/v1/products", openapi.Operation{
+		Summary:     "Create a product",
+		Tags:        []string{"products"},
+		Security:    openapi.BearerAuth(),
+		RequestBody: doc.JSONBody(handlers.CreateProductRequest{}),
+		Responses: map[string]openapi.Response{
+			"201": doc.Success("Product created", map[string]interface{}{"message": "", "product": entities.Product{}}),
+			"400": badRequest,
+			"401": unauthorized,
+			"403": forbidden,
+		},
+	})
+	doc.Add(http.MethodPut, "/api/v1/products/:id", openapi.Operation{
+		Summary:     "Update a product",
+		Tags:        []string{"products"},
+		Security:    openapi.BearerAuth(),
+		RequestBody: doc.JSONBody(handlers.UpdateProductRequest{}),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Product updated", map[string]interface{}{"message": ""}),
+			"400": badRequest,
+			"401": unauthorized,
+			"403": forbidden,
+		},
+	})
+	doc.Add(http.MethodPost, "/api/v1/products/:id/restore", openapi.Operation{
+		Summary:  "Restore a soft-deleted product",
+		Tags:     []string{"products"},
+		Security: openapi.BearerAuth(),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Product restored", map[string]interface{}{"message": "", "product": entities.Product{}}),
+			"400": badRequest,
+			"401": unauthorized,
+			"403": forbidden,
+			"404": doc.Error("No soft-deleted product with this ID"),
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/products/:id/history", openapi.Operation{
+		Summary:    "List who changed a product and when, newest first",
+		Tags:       []string{"products"},
+		Security:   openapi.BearerAuth(),
+		Parameters: pagination[:2],
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Change history", map[string]interface{}{"history": []entities.ChangeEntry{}}),
+			"400": badRequest,
+			"401": unauthorized,
+			"404": notFound,
+		},
+	})
+	doc.Add(http.MethodDelete, "/api/v1/products/:id", openapi.Operation{
+		Summary:  "Delete a product",
+		Tags:     []string{"products"},
+		Security: openapi.BearerAuth(),
+		Responses: map[string]openapi.Response{
+			"200": doc.Success("Product deleted", map[string]interface{}{"message": ""}),
+			"400": badRequest,
+			"401": unauthorized,
+			"403": forbidden,
+		},
+	})
+
+	return doc
+}
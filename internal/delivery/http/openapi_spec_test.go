@@ -0,0 +1,99 @@
+package http
+
+import (
+	"clean-architecture-api/internal/config"
+	"clean-architecture-api/internal/infrastructure/database"
+	"clean-architecture-api/pkg/logger"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig loads the server configuration from the environment the test
+// has set up, so t.Setenv calls must come first.
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg, err := config.LoadSQLite()
+	require.NoError(t, err)
+	return cfg
+}
+
+func newTestServer(t *testing.T) *Server {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	db, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+
+	server, err := NewServer(testConfig(t), db, logger.NewLogger())
+	require.NoError(t, err)
+	return server
+}
+
+func TestOpenAPIDocument_CoversRoutes(t *testing.T) {
+	server := newTestServer(t)
+	doc := newOpenAPIDocument()
+
+	documented := []string{"/api/v1/auth", "/api/v1/users", "/api/v1/products"}
+	for _, route := range server.router.Routes() {
+		for _, prefix := range documented {
+			if strings.HasPrefix(route.Path, prefix) {
+				assert.True(t, doc.Has(route.Method, route.Path), "%s %s is not documented", route.Method, route.Path)
+			}
+		}
+	}
+}
+
+func TestOpenAPIDocument_Served(t *testing.T) {
+	server := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, openAPISpecPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var spec struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Schemas map[string]struct {
+				Required   []string `json:"required"`
+				Properties map[string]struct {
+					Type             string   `json:"type"`
+					Minimum          *float64 `json:"minimum"`
+					ExclusiveMinimum bool     `json:"exclusiveMinimum"`
+				} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+
+	createProduct := spec.Components.Schemas["CreateProductRequest"]
+	assert.ElementsMatch(t, []string{"name", "price"}, createProduct.Required)
+	assert.Equal(t, "number", createProduct.Properties["price"].Type)
+	assert.True(t, createProduct.Properties["price"].ExclusiveMinimum)
+
+	_, hasPassword := spec.Components.Schemas["User"].Properties["password"]
+	assert.False(t, hasPassword)
+}
+
+func TestSwaggerUI_DisabledInProduction(t *testing.T) {
+	t.Setenv("ENV", "production")
+	t.Setenv("SMTP_ADDR", "smtp.example.com:587")
+	t.Setenv("SMTP_FROM", "noreply@example.com")
+	server := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	t.Setenv("SWAGGER_UI_ENABLED", "true")
+	server = newTestServer(t)
+
+	rec = httptest.NewRecorder()
+	server.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), openAPISpecPath)
+}
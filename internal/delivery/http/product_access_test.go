@@ -0,0 +1,199 @@
+package http
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/infrastructure/database"
+	"clean-architecture-api/internal/infrastructure/repository"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// productReadPolicy lets users list and read products. The route guards check product:<action>
+// and the use case and repository check the plain product resource.
+func productReadPolicy() *entities.PolicyDocument {
+	statement := func(action, resource string) entities.PolicyStatement {
+		return entities.PolicyStatement{
+			ID:        uuid.New(),
+			Effect:    constants.PolicyEffectAllow,
+			Principal: "role:" + constants.RoleUser,
+			Action:    action,
+			Resource:  resource,
+		}
+	}
+
+	return &entities.PolicyDocument{
+		ID:       uuid.New(),
+		Name:     "product-read",
+		Version:  "1.0",
+		IsActive: true,
+		Statements: []entities.PolicyStatement{
+			statement(constants.ActionList, constants.PermissionProductList),
+			statement(constants.ActionList, constants.ResourceProduct),
+			statement(constants.ActionRead, constants.PermissionProductRead),
+			statement(constants.ActionRead, constants.ResourceProduct),
+		},
+	}
+}
+
+func newProductAccessServer(t *testing.T, public string) (*Server, repositories.PolicyRepository) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	t.Setenv("PRODUCTS_PUBLIC", public)
+	db, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+	server, err := NewServer(testConfig(t), db, logger.NewLogger())
+	require.NoError(t, err)
+	return server, repository.NewPolicySQLiteRepository(db, logger.NewLogger())
+}
+
+var productReadPaths = []string{"/api/v1/products", "/api/v1/products/category/books", "/api/v1/products/stats/categories"}
+
+func TestProductReadAccess_Public(t *testing.T) {
+	server, _ := newProductAccessServer(t, "")
+
+	for _, path := range productReadPaths {
+		rec := doJSON(t, server, http.MethodGet, path, "", nil)
+		assert.Equal(t, http.StatusOK, rec.Code, path+": "+rec.Body.String())
+	}
+	rec := doJSON(t, server, http.MethodGet, "/api/v1/products/"+uuid.NewString(), "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+}
+
+func TestProductReadAccess_Protected(t *testing.T) {
+	server, policies := newProductAccessServer(t, "false")
+	_, token := registerAndLogin(t, server, "reader@example.com")
+	paths := append(productReadPaths, "/api/v1/products/"+uuid.NewString())
+
+	for _, path := range paths {
+		rec := doJSON(t, server, http.MethodGet, path, "", nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+	}
+	for _, path := range productReadPaths {
+		rec := doJSON(t, server, http.MethodGet, path, token, nil)
+		assert.Equal(t, http.StatusForbidden, rec.Code, path)
+	}
+	// A product the caller may not read looks the same as one that does not exist
+	rec := doJSON(t, server, http.MethodGet, paths[len(productReadPaths)], token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+
+	ctx := context.Background()
+	require.NoError(t, policies.Create(ctx, productReadPolicy()))
+	require.NoError(t, server.policyEngine.LoadPolicies(ctx))
+
+	for _, path := range productReadPaths {
+		rec := doJSON(t, server, http.MethodGet, path, token, nil)
+		assert.Equal(t, http.StatusOK, rec.Code, path+": "+rec.Body.String())
+	}
+	rec = doJSON(t, server, http.MethodGet, paths[len(productReadPaths)], token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+}
+
+func TestProductReadAccess_ConfiguredRoutes(t *testing.T) {
+	// Only product details stay public; the catalog listings need a token
+	t.Setenv("PRODUCTS_PUBLIC_ROUTES", constants.ProductRouteDetail)
+	server, policies := newProductAccessServer(t, "")
+
+	for _, path := range productReadPaths {
+		rec := doJSON(t, server, http.MethodGet, path, "", nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+	}
+	rec := doJSON(t, server, http.MethodGet, "/api/v1/products/"+uuid.NewString(), "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+
+	ctx := context.Background()
+	require.NoError(t, policies.Create(ctx, productReadPolicy()))
+	require.NoError(t, server.policyEngine.LoadPolicies(ctx))
+	_, token := registerAndLogin(t, server, "browser@example.com")
+	for _, path := range productReadPaths {
+		rec := doJSON(t, server, http.MethodGet, path, token, nil)
+		assert.Equal(t, http.StatusOK, rec.Code, path+": "+rec.Body.String())
+	}
+}
+
+func TestNewServerConfig_PublicProductRoutes(t *testing.T) {
+	t.Setenv("PRODUCTS_PUBLIC_ROUTES", "")
+	config, err := NewServerConfig()
+	require.NoError(t, err)
+	for _, route := range []string{constants.ProductRouteList, constants.ProductRouteCategory, constants.ProductRouteDetail} {
+		assert.True(t, config.ProductRoutePublic(route), route)
+	}
+
+	t.Setenv("PRODUCTS_PUBLIC", "false")
+	config, err = NewServerConfig()
+	require.NoError(t, err)
+	assert.False(t, config.ProductRoutePublic(constants.ProductRouteDetail), "PRODUCTS_PUBLIC=false locks every route")
+
+	t.Setenv("PRODUCTS_PUBLIC_ROUTES", "list,prices")
+	_, err = NewServerConfig()
+	assert.ErrorContains(t, err, "PRODUCTS_PUBLIC_ROUTES")
+}
+
+func TestProductReadAccess_PublicView(t *testing.T) {
+	t.Setenv("PRODUCTS_PUBLIC_VIEW", "true")
+	t.Setenv("RATE_LIMIT_PUBLIC_READS_PER_MINUTE", "4")
+	server, policies := newProductAccessServer(t, "")
+	ctx := context.Background()
+	require.NoError(t, policies.Create(ctx, productManagerPolicy()))
+	require.NoError(t, server.policyEngine.LoadPolicies(ctx))
+	managerID, token := registerAndLogin(t, server, "catalog@example.com")
+
+	rec := doJSON(t, server, http.MethodPost, "/api/v1/products", token,
+		map[string]interface{}{"name": "Lamp", "price": 20, "stock": 3, "category": "home"})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created struct {
+		Data struct {
+			Product struct {
+				ID string `json:"id"`
+			} `json:"product"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	read := func(path, token string) map[string]interface{} {
+		rec := doJSON(t, server, http.MethodGet, path, token, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp struct {
+			Data struct {
+				Product  map[string]interface{}   `json:"product"`
+				Products []map[string]interface{} `json:"products"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		if resp.Data.Product != nil {
+			return resp.Data.Product
+		}
+		require.Len(t, resp.Data.Products, 1)
+		return resp.Data.Products[0]
+	}
+
+	// Anonymous callers get the catalog fields only; authenticated callers get the full product
+	for _, path := range []string{"/api/v1/products/" + created.Data.Product.ID, "/api/v1/products"} {
+		anonymous := read(path, "")
+		assert.Equal(t, "Lamp", anonymous["name"], path)
+		assert.NotContains(t, anonymous, "created_by", path)
+		assert.NotContains(t, anonymous, "updated_by", path)
+
+		full := read(path, token)
+		assert.Equal(t, managerID, full["created_by"], path)
+		assert.Contains(t, full, "updated_by", path)
+	}
+
+	// A bad token is refused rather than served anonymously
+	rec = doJSON(t, server, http.MethodGet, "/api/v1/products", "not-a-token", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, rec.Body.String())
+
+	// Anonymous reads share a stricter per-IP quota that authenticated reads do not count against
+	read("/api/v1/products", "")
+	read("/api/v1/products", "")
+	rec = doJSON(t, server, http.MethodGet, "/api/v1/products", "", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, rec.Body.String())
+	read("/api/v1/products", token)
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// productManagerPolicy lets users do anything with products
+func productManagerPolicy() *entities.PolicyDocument {
+	resources := []string{
+		constants.ResourceProduct,
+		constants.PermissionProductCreate,
+		constants.PermissionProductRead,
+		constants.PermissionProductUpdate,
+		constants.PermissionProductDelete,
+		constants.PermissionProductList,
+	}
+	statements := make([]entities.PolicyStatement, 0, len(resources))
+	for _, resource := range resources {
+		statements = append(statements, entities.PolicyStatement{
+			ID:        uuid.New(),
+			Effect:    constants.PolicyEffectAllow,
+			Principal: "role:" + constants.RoleUser,
+			Action:    "*",
+			Resource:  resource,
+		})
+	}
+
+	return &entities.PolicyDocument{
+		ID:         uuid.New(),
+		Name:       "product-manager",
+		Version:    "1.0",
+		IsActive:   true,
+		Statements: statements,
+	}
+}
+
+func TestProductHistory(t *testing.T) {
+	server, policies := newProductAccessServer(t, "")
+	ctx := context.Background()
+	require.NoError(t, policies.Create(ctx, productManagerPolicy()))
+	require.NoError(t, server.policyEngine.LoadPolicies(ctx))
+	managerID, token := registerAndLogin(t, server, "manager@example.com")
+
+	product := map[string]interface{}{"name": "Lamp", "price": 20, "stock": 3, "category": "home"}
+	rec := doJSON(t, server, http.MethodPost, "/api/v1/products", token, product)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created struct {
+		Data struct {
+			Product struct {
+				ID string `json:"id"`
+			} `json:"product"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	path := "/api/v1/products/" + created.Data.Product.ID
+
+	product["price"] = 25
+	rec = doJSON(t, server, http.MethodPut, path, token, product)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Reads are audited as well but are not changes
+	rec = doJSON(t, server, http.MethodGet, path, token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doJSON(t, server, http.MethodGet, path+"/history", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var history struct {
+		Data struct {
+			History []entities.ChangeEntry `json:"history"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	require.Len(t, history.Data.History, 2)
+	assert.Equal(t, constants.ActionUpdate, history.Data.History[0].Action)
+	assert.Equal(t, constants.ActionCreate, history.Data.History[1].Action)
+	for _, entry := range history.Data.History {
+		assert.Equal(t, managerID, entry.UserID.String())
+	}
+
+	rec = doJSON(t, server, http.MethodGet, path+"/history", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestProductHistory_ReadAccess(t *testing.T) {
+	server, policies := newProductAccessServer(t, "")
+	_, token := registerAndLogin(t, server, "reader@example.com")
+	path := "/api/v1/products/" + uuid.NewString() + "/history"
+
+	// Without read access the product is concealed like a missing one
+	rec := doJSON(t, server, http.MethodGet, path, token, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+
+	// Reading the history needs read access only, not the write guards
+	ctx := context.Background()
+	require.NoError(t, policies.Create(ctx, productReadPolicy()))
+	require.NoError(t, server.policyEngine.LoadPolicies(ctx))
+	rec = doJSON(t, server, http.MethodGet, path, token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestProductRestore_UpdateAccess(t *testing.T) {
+	server, policies := newProductAccessServer(t, "")
+	ctx := context.Background()
+	manager := productManagerPolicy()
+	require.NoError(t, policies.Create(ctx, manager))
+	require.NoError(t, server.policyEngine.LoadPolicies(ctx))
+	_, token := registerAndLogin(t, server, "manager@example.com")
+
+	rec := doJSON(t, server, http.MethodPost, "/api/v1/products", token, map[string]interface{}{"name": "Lamp", "price": 20, "stock": 3})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created struct {
+		Data struct {
+			Product struct {
+				ID string `json:"id"`
+			} `json:"product"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	path := "/api/v1/products/" + created.Data.Product.ID
+	rec = doJSON(t, server, http.MethodDelete, path, token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Restoring needs update access only, not the create guard the other write routes stack
+	require.NoError(t, policies.Delete(ctx, manager.ID))
+	restorer := &entities.PolicyDocument{ID: uuid.New(), Name: "product-restorer", Version: "1.0", IsActive: true}
+	for _, resource := range []string{constants.ResourceProduct, constants.PermissionProductUpdate} {
+		restorer.Statements = append(restorer.Statements, entities.PolicyStatement{
+			ID:        uuid.New(),
+			Effect:    constants.PolicyEffectAllow,
+			Principal: "role:" + constants.RoleUser,
+			Action:    constants.ActionUpdate,
+			Resource:  resource,
+		})
+	}
+	require.NoError(t, policies.Create(ctx, restorer))
+	require.NoError(t, server.policyEngine.LoadPolicies(ctx))
+
+	rec = doJSON(t, server, http.MethodPost, path+"/restore", token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
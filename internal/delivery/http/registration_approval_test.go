@@ -0,0 +1,119 @@
+package http
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/infrastructure/database"
+	"clean-architecture-api/pkg/logger"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type approvalUsersResponse struct {
+	Data struct {
+		Users []entities.User `json:"users"`
+		User  entities.User   `json:"user"`
+	} `json:"data"`
+	Error struct {
+		Code string `json:"code"`
+	} `json:"error"`
+}
+
+func decodeApprovalResponse(t *testing.T, body []byte) approvalUsersResponse {
+	t.Helper()
+	var resp approvalUsersResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	return resp
+}
+
+func TestRegistrationApproval_PendingThenApproved(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	t.Setenv("REGISTRATION_REQUIRES_APPROVAL", "true")
+	db, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+	server, err := NewServer(testConfig(t), db, logger.NewLogger())
+	require.NoError(t, err)
+
+	// The first admin is promoted directly in the database, as an operator would
+	adminCredentials := map[string]string{
+		"email": "admin@example.com", "password": "password123", "first_name": "Ada", "last_name": "Admin",
+	}
+	rec := doJSON(t, server, http.MethodPost, "/api/v1/auth/register", "", adminCredentials)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NoError(t, db.Model(&entities.UserSQLite{}).Where("email = ?", "admin@example.com").
+		Updates(map[string]interface{}{"role": constants.RoleAdmin, "is_active": true, "approval_pending": false}).Error)
+	adminToken := loginAs(t, server, adminCredentials)
+
+	credentials := map[string]string{
+		"email": "newcomer@example.com", "password": "password123", "first_name": "New", "last_name": "Comer",
+	}
+	rec = doJSON(t, server, http.MethodPost, "/api/v1/auth/register", "", credentials)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	registered := decodeApprovalResponse(t, rec.Body.Bytes()).Data.User
+	assert.True(t, registered.ApprovalPending)
+	assert.False(t, registered.IsActive)
+	userPath := "/api/v1/admin/users/" + registered.ID.String()
+
+	rec = doJSON(t, server, http.MethodPost, "/api/v1/auth/login", "", credentials)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+	assert.Equal(t, domainerrors.ErrAccountPendingApproval.Code, decodeApprovalResponse(t, rec.Body.Bytes()).Error.Code)
+
+	// A wrong password does not reveal that the account exists and is pending
+	rec = doJSON(t, server, http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"email": "newcomer@example.com", "password": "wrong-password",
+	})
+	assert.Equal(t, domainerrors.ErrInvalidCredentials.Code, decodeApprovalResponse(t, rec.Body.Bytes()).Error.Code)
+
+	rec = doJSON(t, server, http.MethodGet, "/api/v1/admin/users?status=pending", adminToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	pending := decodeApprovalResponse(t, rec.Body.Bytes()).Data.Users
+	require.Len(t, pending, 1)
+	assert.Equal(t, registered.ID, pending[0].ID)
+
+	rec = doJSON(t, server, http.MethodGet, "/api/v1/admin/users?status=active", adminToken, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+
+	rec = doJSON(t, server, http.MethodPost, userPath+"/approve", adminToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	approved := decodeApprovalResponse(t, rec.Body.Bytes()).Data.User
+	assert.False(t, approved.ApprovalPending)
+	assert.True(t, approved.IsActive)
+
+	rec = doJSON(t, server, http.MethodPost, userPath+"/approve", adminToken, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+
+	rec = doJSON(t, server, http.MethodGet, "/api/v1/admin/users?status=pending", adminToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Empty(t, decodeApprovalResponse(t, rec.Body.Bytes()).Data.Users)
+
+	token := loginAs(t, server, credentials)
+	assert.NotEmpty(t, token)
+
+	// Only admins approve registrations
+	rec = doJSON(t, server, http.MethodPost, userPath+"/approve", token, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+}
+
+// loginAs logs in with credentials and returns the access token
+func loginAs(t *testing.T, server *Server, credentials map[string]string) string {
+	t.Helper()
+	rec := doJSON(t, server, http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"email": credentials["email"], "password": credentials["password"],
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var login struct {
+		Data struct {
+			Tokens struct {
+				AccessToken string `json:"access_token"`
+			} `json:"tokens"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &login))
+	return login.Data.Tokens.AccessToken
+}
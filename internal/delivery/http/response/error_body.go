@@ -0,0 +1,21 @@
+// Package response holds the pieces of an HTTP response body that handlers and middleware
+// must render the same way.
+package response
+
+import (
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorBody describes appErr for the response, with the message in the language the caller
+// prefers through Accept-Language, or English. The code stays the same in every language.
+func ErrorBody(c *gin.Context, appErr *domainerrors.AppError) gin.H {
+	locale := domainerrors.MatchLocale(c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", locale)
+	return gin.H{
+		"category": appErr.Category,
+		"code":     appErr.Code,
+		"message":  appErr.LocalizedMessage(locale),
+	}
+}
@@ -1,44 +1,91 @@
 package http
 
 import (
+	appconfig "clean-architecture-api/internal/config"
+	graphqldelivery "clean-architecture-api/internal/delivery/graphql"
+	grpcdelivery "clean-architecture-api/internal/delivery/grpc"
 	"clean-architecture-api/internal/delivery/http/handlers"
+	"clean-architecture-api/internal/delivery/http/openapi"
 	"clean-architecture-api/internal/delivery/middleware"
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/internal/infrastructure/challenge"
+	"clean-architecture-api/internal/infrastructure/events"
 	"clean-architecture-api/internal/infrastructure/repository"
 	"clean-architecture-api/internal/usecase"
 	"clean-architecture-api/pkg/logger"
+	"clean-architecture-api/pkg/mail"
+	"clean-architecture-api/pkg/metrics"
+	"clean-architecture-api/pkg/version"
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/newrelic/go-agent/v3/integrations/nrgin"
+	"github.com/gin-gonic/gin/binding"
 	newrelicagent "github.com/newrelic/go-agent/v3/newrelic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"gorm.io/gorm"
 )
 
 type Server struct {
-	router *gin.Engine
-	db     *gorm.DB
-	logger logger.Logger
-	nrApp  *newrelicagent.Application
+	router     *gin.Engine
+	db         *gorm.DB
+	logger     logger.Logger
+	nrApp      *newrelicagent.Application
+	cfg        *appconfig.Config
+	config     *ServerConfig
+	httpServer *http.Server
+
+	policyEngine repositories.PolicyEngine
+	// products is kept for stock reservations, which no route exposes yet
+	products     usecase.ProductUseCase
+	events       repositories.EventPublisher
+	outboxWorker *events.OutboxWorker
+	sweeper      *repository.ReservationSweeper
+	auditSweeper *repository.AuditRetentionSweeper
+	grpcServer   *grpcdelivery.Server
+	rateLimiter  *middleware.RateLimiter
 }
 
-func NewServer(db *gorm.DB, logger logger.Logger) (*Server, error) {
-	return NewServerWithNewRelic(db, logger, nil)
+func NewServer(cfg *appconfig.Config, db *gorm.DB, logger logger.Logger) (*Server, error) {
+	return NewServerWithNewRelic(cfg, db, logger, nil)
 }
 
 // NewServerWithNewRelic creates a new server with New Relic monitoring.
-func NewServerWithNewRelic(db *gorm.DB, logger logger.Logger, nrApp *newrelicagent.Application) (*Server, error) {
+func NewServerWithNewRelic(cfg *appconfig.Config, db *gorm.DB, logger logger.Logger, nrApp *newrelicagent.Application) (*Server, error) {
+	config, err := NewServerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server config: %w", err)
+	}
+
 	gin.SetMode(gin.ReleaseMode)
 
 	router := gin.New()
+	// Without trusted proxies ClientIP is the socket peer, so a client cannot spoof its address
+	// for audit entries through X-Forwarded-For
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
+	router.Use(middleware.SlowRequests(config.SlowRequestThreshold, logger, metrics.Default))
+	if config.CORS != nil {
+		router.Use(middleware.CORS(*config.CORS))
+	}
+	router.Use(middleware.RequestTimeout(config.RequestTimeout))
+	router.Use(middleware.PermissionCache())
 
 	// Add New Relic middleware if application is provided
 	if nrApp != nil {
-		router.Use(nrgin.Middleware(nrApp))
+		router.Use(middleware.NewRelic(nrApp))
 		logger.Info("New Relic monitoring enabled for HTTP server")
 	} else {
 		logger.Info("New Relic monitoring disabled for HTTP server")
@@ -49,6 +96,15 @@ func NewServerWithNewRelic(db *gorm.DB, logger logger.Logger, nrApp *newrelicage
 		db:     db,
 		logger: logger,
 		nrApp:  nrApp,
+		cfg:    cfg,
+		config: config,
+		httpServer: &http.Server{
+			Handler:           router,
+			ReadTimeout:       config.ReadTimeout,
+			ReadHeaderTimeout: config.ReadHeaderTimeout,
+			WriteTimeout:      config.WriteTimeout,
+			IdleTimeout:       config.IdleTimeout,
+		},
 	}
 
 	if err := server.setupRoutes(); err != nil {
@@ -58,112 +114,395 @@ func NewServerWithNewRelic(db *gorm.DB, logger logger.Logger, nrApp *newrelicage
 }
 
 func (s *Server) setupRoutes() error {
+	s.router.Use(middleware.Compression(s.config.Compression))
+	s.router.Use(middleware.RequireJSON(map[string][]string{
+		"/api/v1/admin/policies/import": {binding.MIMEMultipartPOSTForm},
+	}))
+	s.router.Use(middleware.BodyLimit(s.config.BodyLimit.MaxBytes, map[string]int64{
+		"/api/v1/admin/policies/import": s.config.BodyLimit.ImportMaxBytes,
+	}))
+
+	s.rateLimiter = middleware.NewRateLimiter(s.config.RateLimit)
 	handlers, authMiddleware, err := s.initializeDependencies()
 	if err != nil {
 		return err
 	}
-	s.setupHealthCheck()
+	s.setupHealthCheck(handlers.health)
+	s.setupJWKS(handlers.jwks)
+	if err := s.setupOpenAPI(); err != nil {
+		return err
+	}
 	s.setupAPIRoutes(handlers, authMiddleware)
 
 	return nil
 }
 
 func (s *Server) initializeDependencies() (*routeHandlers, *middleware.AuthMiddleware, error) {
-	authService, err := auth.NewAuthService()
+	signingKeys, err := auth.LoadKeySet(s.cfg.JWT.KeySet())
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create auth service: %w", err)
 	}
-	authLogger := auth.NewAuditLogger(s.logger)
+	authService := auth.NewAuthServiceWithKeys(signingKeys, s.cfg.JWT.ClockSkew())
+	auditRepo := repository.NewAuditRepository(s.db)
+	authLogger := auth.NewPersistentAuditLogger(auditRepo, s.logger)
 
 	var policyRepo repositories.PolicyRepository
-	if os.Getenv("ENV") == "production" {
+	if s.cfg.Production() {
 		policyRepo = repository.NewPolicyRepository(s.db, s.logger)
 	} else {
 		policyRepo = repository.NewPolicySQLiteRepository(s.db, s.logger)
 	}
-	policyEngine := auth.NewPolicyEngine(policyRepo, s.logger)
+	notifier, err := s.newPolicyChangeNotifier()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create policy change notifier: %w", err)
+	}
+	policyEngine := auth.NewPolicyEngineWithNotifier(policyRepo, notifier, s.logger)
+	s.policyEngine = policyEngine
 	authzService := auth.NewAuthorizationService(policyEngine)
 
-	userRepo := repository.NewUserRepository(s.db, authzService, authLogger, s.logger)
-	productRepo := repository.NewProductRepository(s.db, authzService, authLogger, s.logger)
+	auditReads := repository.ReadAuditing(s.cfg.AuditReads)
+	var userRepo repositories.UserRepository
+	var productRepo repositories.ProductRepository
+	var reservationRepo repositories.ReservationRepository
+	if s.cfg.DBDriver == constants.DBDriverMemory {
+		userRepo = repository.NewMemoryUserRepository(authzService, authLogger, auditReads, s.logger)
+		productRepo = repository.NewMemoryProductRepository(authzService, authLogger, auditReads, s.logger)
+		reservationRepo, err = repository.NewMemoryReservationRepository(productRepo)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create reservation repository: %w", err)
+		}
+	} else {
+		userRepo = repository.NewUserRepository(s.db, authzService, authLogger, auditReads, s.logger)
+		productRepo = repository.NewProductRepository(s.db, authzService, authLogger, auditReads, s.logger)
+		reservationRepo = repository.NewReservationRepository(s.db)
+	}
 
-	authUseCase := usecase.NewAuthUseCase(userRepo, authService, s.logger)
-	userUseCase := usecase.NewUserUseCase(userRepo, s.logger)
-	productUseCase := usecase.NewProductUseCase(productRepo, s.logger)
+	txManager := s.setupEventPublishing()
+	challengeVerifier, err := challenge.NewVerifierFromEnv()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create challenge verifier: %w", err)
+	}
+	mailer, err := mail.NewMailerFromEnv(s.logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create mailer: %w", err)
+	}
+	if _, ok := mailer.(*mail.LogMailer); ok {
+		// The log mailer writes confirmation codes where anyone reading the logs can use them
+		if s.cfg.Production() || s.cfg.EmailVerificationEnabled {
+			return nil, nil, errors.New("the log mail driver cannot be used with ENV=production or EMAIL_VERIFICATION_ENABLED; " +
+				"set SMTP_ADDR and SMTP_FROM")
+		}
+		s.logger.Warn("No SMTP relay configured; outgoing mail is written to the log")
+	}
+	authUseCase := usecase.NewAuthUseCase(userRepo, repository.NewRefreshTokenRepository(s.db), authService, authLogger,
+		challengeVerifier, mailer, s.events, txManager,
+		usecase.AuthSettings{
+			RegistrationRequiresApproval: s.cfg.RegistrationRequiresApproval,
+			EmailVerificationEnabled:     s.cfg.EmailVerificationEnabled,
+		}, s.logger)
+	userUseCase := usecase.NewUserUseCase(userRepo, auditRepo, s.events, txManager, s.logger)
+	productUseCase := usecase.NewProductUseCase(productRepo, reservationRepo, auditRepo, s.events, txManager,
+		usecase.ProductSettings{CategoryCase: s.cfg.ProductCategoryCase, PublicReads: s.config.ProductsPublic}, s.logger)
+	s.products = productUseCase
+	s.startReservationSweeper(reservationRepo)
+	s.startAuditRetentionSweeper(auditRepo)
+	policyUseCase := usecase.NewPolicyUseCase(policyRepo, policyEngine, userRepo, s.logger)
+
+	s.grpcServer, err = s.newGRPCServer(authUseCase, userUseCase, productUseCase, authzService)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create gRPC server: %w", err)
+	}
+
+	graphqlHandler, err := graphqldelivery.NewHandler(userUseCase, productUseCase, authzService)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create GraphQL handler: %w", err)
+	}
+
+	health := handlers.NewHealthHandler(map[string]handlers.HealthChecker{
+		"users":    userRepo,
+		"products": productRepo,
+	}, s.logger)
+	if checker, ok := policyEngine.(handlers.HealthChecker); ok {
+		health.AddDegradableCheck("policy_cache", checker)
+	}
 
 	handlers := &routeHandlers{
-		auth:    handlers.NewAuthHandler(authUseCase, s.logger),
-		user:    handlers.NewUserHandler(userUseCase, s.logger),
-		product: handlers.NewProductHandler(productUseCase, s.logger),
+		auth:       handlers.NewAuthHandler(authUseCase, s.logger),
+		user:       handlers.NewUserHandler(userUseCase, s.logger),
+		product:    handlers.NewProductHandler(productUseCase, s.logger).WithPublicView(s.config.ProductsPublicView),
+		permission: handlers.NewPermissionHandler(authzService, s.logger),
+		policy:     handlers.NewPolicyHandler(policyUseCase, s.logger),
+		audit:      handlers.NewAuditHandler(s.auditSweeper, s.logger),
+		health:     health,
+		graphql:    graphqlHandler,
+	}
+	handlers.user.WithPaginationHeaders(s.cfg.PaginationHeaders)
+	handlers.product.WithPaginationHeaders(s.cfg.PaginationHeaders)
+	if signingKeys.Asymmetric() {
+		jwks := signingKeys.JWKS()
+		handlers.jwks = &jwks
 	}
 
-	authMiddleware := middleware.NewAuthMiddleware(authUseCase, authzService, s.logger)
+	authMiddleware := middleware.NewAuthMiddlewareWithRateLimiter(authUseCase, authzService, s.rateLimiter, s.logger)
+
+	// Accept identities forwarded by other instances only when they share a signing secret
+	if secret := os.Getenv("SERVICE_CONTEXT_SECRET"); secret != "" {
+		if codec, ok := authzService.(repositories.ServiceContextCodec); ok {
+			s.router.Use(middleware.ServiceContext(codec, secret, s.logger))
+		}
+	}
 
 	return handlers, authMiddleware, nil
 }
 
+// newPolicyChangeNotifier shares policy changes across instances through Redis when REDIS_URL is set
+func (s *Server) newPolicyChangeNotifier() (repositories.PolicyChangeNotifier, error) {
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		return auth.NewNoopPolicyChangeNotifier(), nil
+	}
+
+	channel := getEnvOrDefault("POLICY_CHANGE_CHANNEL", constants.DefaultPolicyChangeChannel)
+	s.logger.Info("Policy change notifications enabled on Redis channel " + channel)
+	return auth.NewRedisPolicyChangeNotifier(redisURL, channel, s.logger)
+}
+
+// setupEventPublishing delivers domain events to WEBHOOK_URL when it is set. Events are
+// written to the outbox in the same transaction as the change and sent by a background
+// worker, so the returned transaction manager is nil when events are disabled.
+func (s *Server) setupEventPublishing() repositories.TransactionManager {
+	config := events.NewWebhookConfigFromEnv()
+	if config == nil {
+		s.events = events.NewNoopPublisher()
+		return nil
+	}
+
+	interval := constants.DefaultOutboxPollInterval
+	if value, err := time.ParseDuration(os.Getenv("OUTBOX_POLL_INTERVAL")); err == nil && value > 0 {
+		interval = value
+	}
+
+	// The worker retries on every poll, so each send is a single attempt
+	sender := events.NewWebhookPublisher(*config, s.logger)
+	outboxRepo := repository.NewOutboxRepository(s.db)
+	s.events = events.NewOutboxPublisher(outboxRepo)
+	s.outboxWorker = events.NewOutboxWorker(outboxRepo, sender, interval, config.MaxAttempts, s.logger)
+	s.outboxWorker.Start()
+
+	s.logger.Info("Webhook event delivery enabled through the outbox")
+	return repository.NewTransactionManager(s.db)
+}
+
+// startReservationSweeper releases expired stock reservations every RESERVATION_SWEEP_INTERVAL
+func (s *Server) startReservationSweeper(reservations repositories.ReservationRepository) {
+	interval := constants.DefaultReservationSweepInterval
+	if value, err := time.ParseDuration(os.Getenv("RESERVATION_SWEEP_INTERVAL")); err == nil && value > 0 {
+		interval = value
+	}
+
+	s.sweeper = repository.NewReservationSweeper(reservations, interval, s.logger)
+	s.sweeper.Start()
+}
+
+// startAuditRetentionSweeper deletes audit entries older than AUDIT_RETENTION_DAYS every
+// AUDIT_RETENTION_INTERVAL
+func (s *Server) startAuditRetentionSweeper(audit repositories.AuditRepository) {
+	days := constants.DefaultAuditRetentionDays
+	if value, err := strconv.Atoi(os.Getenv("AUDIT_RETENTION_DAYS")); err == nil && value > 0 {
+		days = value
+	}
+	interval := constants.DefaultAuditRetentionInterval
+	if value, err := time.ParseDuration(os.Getenv("AUDIT_RETENTION_INTERVAL")); err == nil && value > 0 {
+		interval = value
+	}
+
+	s.auditSweeper = repository.NewAuditRetentionSweeper(audit, time.Duration(days)*24*time.Hour, interval, s.logger)
+	s.auditSweeper.Start()
+}
+
+// newGRPCServer exposes the same use cases over gRPC, with TLS when TLS_CERT_FILE and TLS_KEY_FILE are set
+func (s *Server) newGRPCServer(
+	authUseCase usecase.AuthUseCase,
+	userUseCase usecase.UserUseCase,
+	productUseCase usecase.ProductUseCase,
+	authzService repositories.AuthorizationService,
+) (*grpcdelivery.Server, error) {
+	var opts []grpc.ServerOption
+	if s.cfg.TLS.Enabled() {
+		cert, err := tls.LoadX509KeyPair(s.cfg.TLS.CertFile, s.cfg.TLS.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig := s.config.TLSConfig()
+		tlsConfig.Certificates = []tls.Certificate{cert}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	return grpcdelivery.NewServer(authUseCase, userUseCase, productUseCase, authzService, s.logger, opts...), nil
+}
+
 type routeHandlers struct {
-	auth    *handlers.AuthHandler
-	user    *handlers.UserHandler
-	product *handlers.ProductHandler
+	auth       *handlers.AuthHandler
+	user       *handlers.UserHandler
+	product    *handlers.ProductHandler
+	permission *handlers.PermissionHandler
+	policy     *handlers.PolicyHandler
+	audit      *handlers.AuditHandler
+	health     *handlers.HealthHandler
+	graphql    http.Handler
+	jwks       *auth.JWKS
 }
 
-func (s *Server) setupHealthCheck() {
-	s.router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
+func (s *Server) setupHealthCheck(healthHandler *handlers.HealthHandler) {
+	s.router.GET("/health", healthHandler.Live)
+	s.router.GET("/health/ready", healthHandler.Ready)
+	s.router.GET("/metrics", gin.WrapH(metrics.Default.Handler()))
+	// Unauthenticated, so it is limited per client IP like other anonymous traffic
+	s.router.GET("/version", s.rateLimiter.Middleware(), func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"build":            version.Get(),
+			"newrelic_enabled": s.nrApp != nil,
+		})
 	})
 }
 
+// setupJWKS publishes the RS256 public keys for external verifiers. HMAC secrets are never exposed,
+// so the route only exists with asymmetric signing.
+func (s *Server) setupJWKS(jwks *auth.JWKS) {
+	if jwks == nil {
+		return
+	}
+	s.router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, jwks)
+	})
+}
+
+// setupOpenAPI serves the spec at /openapi.json and, unless disabled, Swagger UI under /swagger/.
+// The UI is off by default in production; SWAGGER_UI_ENABLED overrides that either way.
+func (s *Server) setupOpenAPI() error {
+	specHandler, err := openapi.SpecHandler(newOpenAPIDocument())
+	if err != nil {
+		return fmt.Errorf("failed to build OpenAPI document: %w", err)
+	}
+	s.router.GET(openAPISpecPath, specHandler)
+
+	uiEnabled := !s.cfg.Production()
+	if value, err := strconv.ParseBool(os.Getenv("SWAGGER_UI_ENABLED")); err == nil {
+		uiEnabled = value
+	}
+	if uiEnabled {
+		s.router.GET("/swagger/*any", openapi.UIHandler("Clean Architecture API", openAPISpecPath))
+	}
+
+	return nil
+}
+
 func (s *Server) setupAPIRoutes(h *routeHandlers, authMiddleware *middleware.AuthMiddleware) {
 	api := s.router.Group("/api/v1")
 	{
-		s.setupAuthRoutes(api, h.auth)
+		s.setupAuthRoutes(api, h.auth, h.permission, authMiddleware)
 		s.setupUserRoutes(api, h.user, authMiddleware)
 		s.setupProductRoutes(api, h.product, authMiddleware)
+		s.setupAdminRoutes(api, h.policy, h.auth, h.user, h.product, h.audit, authMiddleware)
 	}
+
+	s.router.POST("/graphql", authMiddleware.AuthRequired(), gin.WrapH(h.graphql))
 }
 
-func (s *Server) setupAuthRoutes(api *gin.RouterGroup, authHandler *handlers.AuthHandler) {
+func (s *Server) setupAuthRoutes(
+	api *gin.RouterGroup,
+	authHandler *handlers.AuthHandler,
+	permissionHandler *handlers.PermissionHandler,
+	authMiddleware *middleware.AuthMiddleware,
+) {
 	auth := api.Group("/auth")
 	{
-		auth.POST("/register", authHandler.Register)
-		auth.POST("/login", authHandler.Login)
-		auth.POST("/refresh", authHandler.RefreshToken)
-	}
-}
+		// Routes without a token are limited per client IP; the auth middleware limits the rest per user
+		perIP := s.rateLimiter.Middleware()
+		auth.POST("/register", perIP, authHandler.Register)
+		auth.POST("/login", perIP, authHandler.Login)
+		auth.POST("/refresh", perIP, authHandler.RefreshToken)
+		auth.POST("/logout", perIP, authHandler.Logout)
+		auth.POST("/introspect", perIP, authHandler.Introspect)
 
-func (s *Server) setupUserRoutes(api *gin.RouterGroup, userHandler *handlers.UserHandler, authMiddleware *middleware.AuthMiddleware) {
-	users := api.Group("/users")
-	{
-		usersProtected := users.Group("")
-		usersProtected.Use(authMiddleware.UserListAccess())
+		sessions := auth.Group("/sessions")
+		sessions.Use(authMiddleware.AuthRequired())
 		{
-			usersProtected.GET("", userHandler.ListUsers)
+			sessions.GET("", authHandler.ListSessions)
+			sessions.DELETE("/:id", authHandler.RevokeSession)
 		}
 
-		usersProtected.Use(authMiddleware.UserReadAccess())
+		email := auth.Group("/email")
+		email.Use(authMiddleware.AuthRequired())
 		{
-			usersProtected.GET("/:id", userHandler.GetUserByID)
+			email.PUT("", authHandler.ChangeEmail)
+			email.POST("/confirm", authHandler.ConfirmEmailChange)
 		}
 
-		usersProtected.Use(authMiddleware.UserUpdateAccess())
-		{
-			usersProtected.PUT("/:id", userHandler.UpdateUser)
-		}
+		auth.PUT("/password", authMiddleware.AuthRequired(), authHandler.ChangePassword)
 
-		usersProtected.Use(authMiddleware.UserDeleteAccess())
+		permissions := auth.Group("/permissions")
+		permissions.Use(authMiddleware.AuthRequired())
 		{
-			usersProtected.DELETE("/:id", userHandler.DeleteUser)
+			permissions.GET("", permissionHandler.GetEffectivePermissions)
+			permissions.GET("/:resource/actions", permissionHandler.GetAllowedActions)
+			permissions.POST("/check", permissionHandler.CheckPermissions)
 		}
 	}
 }
 
+func (s *Server) setupUserRoutes(api *gin.RouterGroup, userHandler *handlers.UserHandler, authMiddleware *middleware.AuthMiddleware) {
+	users := api.Group("/users")
+	{
+		users.GET("/by-email", authMiddleware.AdminRequired(), userHandler.GetUserByEmail)
+		users.GET("/me/activity", authMiddleware.AuthRequired(), userHandler.GetMyActivity)
+
+		// Each route carries only its own guard; a shared group would stack them, so reading
+		// a user would also require list access
+		users.GET("", authMiddleware.UserListAccess(), userHandler.ListUsers)
+		users.GET("/:id", authMiddleware.UserReadAccess(), userHandler.GetUserByID)
+		users.PUT("/:id", authMiddleware.UserUpdateAccess(), userHandler.UpdateUser)
+		users.DELETE("/:id", authMiddleware.UserDeleteAccess(), userHandler.DeleteUser)
+	}
+}
+
 func (s *Server) setupProductRoutes(api *gin.RouterGroup, productHandler *handlers.ProductHandler, authMiddleware *middleware.AuthMiddleware) {
 	products := api.Group("/products")
 	{
-		products.GET("", productHandler.ListProducts)
-		products.GET("/:id", productHandler.GetProductByID)
-		products.GET("/category/:category", productHandler.GetProductsByCategory)
+		// A read route that is not public goes through the same guards as the others; with
+		// PRODUCTS_PUBLIC=false the use case checks again. With PRODUCTS_PUBLIC_VIEW a public route
+		// still authenticates callers that send a token, so they get full products.
+		guarded := func(route string, guard gin.HandlerFunc, handler gin.HandlerFunc) []gin.HandlerFunc {
+			if s.config.ProductRoutePublic(route) {
+				if s.config.ProductsPublicView {
+					return []gin.HandlerFunc{authMiddleware.OptionalAuth(), s.rateLimiter.PublicReads(), handler}
+				}
+				return []gin.HandlerFunc{s.rateLimiter.PublicReads(), handler}
+			}
+			return []gin.HandlerFunc{guard, handler}
+		}
+		products.GET("", guarded(constants.ProductRouteList, authMiddleware.ProductListAccess(), productHandler.ListProducts)...)
+		products.GET("/category/:category",
+			guarded(constants.ProductRouteCategory, authMiddleware.ProductListAccess(), productHandler.GetProductsByCategory)...)
+		// Category stats are an aggregate of the list, so they are public exactly when it is
+		products.GET("/stats/categories",
+			guarded(constants.ProductRouteList, authMiddleware.ProductListAccess(), productHandler.GetCategoryStats)...)
+		products.GET("/:id", guarded(constants.ProductRouteDetail, authMiddleware.ProductReadAccess(), productHandler.GetProductByID)...)
+
+		// History and restore get their own groups; productsProtected stacks the write guards, so
+		// on it they would also require create access
+		history := products.Group("/:id/history")
+		history.Use(authMiddleware.ProductReadAccess())
+		{
+			history.GET("", productHandler.GetProductHistory)
+		}
+
+		restore := products.Group("/:id/restore")
+		restore.Use(authMiddleware.ProductUpdateAccess())
+		{
+			restore.POST("", productHandler.RestoreProduct)
+		}
 
 		productsProtected := products.Group("")
 		productsProtected.Use(authMiddleware.ProductCreateAccess())
@@ -183,6 +522,86 @@ func (s *Server) setupProductRoutes(api *gin.RouterGroup, productHandler *handle
 	}
 }
 
+func (s *Server) setupAdminRoutes(
+	api *gin.RouterGroup,
+	policyHandler *handlers.PolicyHandler,
+	authHandler *handlers.AuthHandler,
+	userHandler *handlers.UserHandler,
+	productHandler *handlers.ProductHandler,
+	auditHandler *handlers.AuditHandler,
+	authMiddleware *middleware.AuthMiddleware,
+) {
+	admin := api.Group("/admin")
+	admin.Use(authMiddleware.AdminRequired())
+	{
+		admin.POST("/users/:id/impersonate", authHandler.Impersonate)
+		admin.POST("/users/:id/revoke-tokens", authHandler.RevokeUserTokens)
+		admin.GET("/users", authHandler.ListUsersByStatus)
+		admin.GET("/users/search", userHandler.SearchUsers)
+		admin.POST("/users/:id/approve", authHandler.ApproveUser)
+		admin.GET("/users/:id/policies", policyHandler.GetUserPolicies)
+		admin.GET("/products/low-stock", productHandler.LowStockReport)
+		admin.POST("/audit-logs/purge", auditHandler.PurgeAuditLogs)
+
+		policies := admin.Group("/policies")
+		{
+			policies.GET("/export", policyHandler.ExportPolicies)
+			policies.POST("/import", policyHandler.ImportPolicies)
+			policies.POST("/simulate", policyHandler.SimulatePolicy)
+			policies.POST("/reload", policyHandler.ReloadPolicies)
+			policies.GET("/:id/versions", policyHandler.GetPolicyVersions)
+			policies.POST("/:id/rollback/:version", policyHandler.RollbackPolicy)
+		}
+	}
+}
+
+// Run serves plain HTTP until Shutdown is called.
 func (s *Server) Run(addr string) error {
-	return s.router.Run(addr)
+	s.httpServer.Addr = addr
+	return ignoreServerClosed(s.httpServer.ListenAndServe())
+}
+
+// RunTLS serves HTTPS with the given certificate until Shutdown is called.
+func (s *Server) RunTLS(addr, certFile, keyFile string) error {
+	s.httpServer.Addr = addr
+	s.httpServer.TLSConfig = s.config.TLSConfig()
+	return ignoreServerClosed(s.httpServer.ListenAndServeTLS(certFile, keyFile))
+}
+
+// RunGRPC serves the gRPC API on its own port until Shutdown is called.
+func (s *Server) RunGRPC(addr string) error {
+	return s.grpcServer.Run(addr)
+}
+
+// Shutdown gracefully stops the server, waiting for in-flight requests until ctx expires.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.httpServer.Shutdown(ctx)
+	s.grpcServer.Shutdown(ctx)
+	if s.policyEngine != nil {
+		if closeErr := s.policyEngine.Close(); closeErr != nil {
+			s.logger.Error("Failed to close policy engine", closeErr)
+		}
+	}
+	if s.outboxWorker != nil {
+		s.outboxWorker.Stop()
+	}
+	if s.sweeper != nil {
+		s.sweeper.Stop()
+	}
+	if s.auditSweeper != nil {
+		s.auditSweeper.Stop()
+	}
+	if s.events != nil {
+		if closeErr := s.events.Close(); closeErr != nil {
+			s.logger.Error("Failed to close event publisher", closeErr)
+		}
+	}
+	return err
+}
+
+func ignoreServerClosed(err error) error {
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
 }
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"bytes"
+	"clean-architecture-api/internal/domain/entities"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sessionTokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// loginFrom logs in as email from a client with the given user agent
+func loginFrom(t *testing.T, server *Server, email, userAgent string) sessionTokens {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{"email": email, "password": "password123"})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var login struct {
+		Data struct {
+			Tokens sessionTokens `json:"tokens"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &login))
+	return login.Data.Tokens
+}
+
+func listSessions(t *testing.T, server *Server, token string) []entities.Session {
+	t.Helper()
+	rec := doJSON(t, server, http.MethodGet, "/api/v1/auth/sessions", token, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp struct {
+		Data struct {
+			Sessions []entities.Session `json:"sessions"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.Data.Sessions
+}
+
+func TestSessions_ListAndRevoke(t *testing.T) {
+	server, _ := newProductAccessServer(t, "")
+	registerAndLogin(t, server, "traveller@example.com")
+	laptop := loginFrom(t, server, "traveller@example.com", "Laptop/1.0")
+	phone := loginFrom(t, server, "traveller@example.com", "Phone/2.0")
+
+	sessions := listSessions(t, server, phone.AccessToken)
+	require.Len(t, sessions, 3)
+	byAgent := make(map[string]entities.Session)
+	for _, session := range sessions {
+		byAgent[session.UserAgent] = session
+	}
+	require.Contains(t, byAgent, "Laptop/1.0")
+	require.Contains(t, byAgent, "Phone/2.0")
+	assert.Equal(t, "192.0.2.1", byAgent["Laptop/1.0"].IPAddress)
+	assert.False(t, byAgent["Laptop/1.0"].CreatedAt.IsZero())
+	assert.False(t, byAgent["Laptop/1.0"].LastUsedAt.IsZero())
+
+	rec := doJSON(t, server, http.MethodDelete, "/api/v1/auth/sessions/"+byAgent["Laptop/1.0"].ID.String(), phone.AccessToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doJSON(t, server, http.MethodPost, "/api/v1/auth/refresh", "", map[string]string{"refresh_token": laptop.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, rec.Body.String())
+	rec = doJSON(t, server, http.MethodPost, "/api/v1/auth/refresh", "", map[string]string{"refresh_token": phone.RefreshToken})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	sessions = listSessions(t, server, phone.AccessToken)
+	assert.Len(t, sessions, 2)
+	for _, session := range sessions {
+		assert.NotEqual(t, "Laptop/1.0", session.UserAgent)
+	}
+
+	// A session of another user cannot be revoked, and an ended one is gone
+	_, otherToken := registerAndLogin(t, server, "stranger@example.com")
+	rec = doJSON(t, server, http.MethodDelete, "/api/v1/auth/sessions/"+byAgent["Phone/2.0"].ID.String(), otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	rec = doJSON(t, server, http.MethodDelete, "/api/v1/auth/sessions/"+byAgent["Laptop/1.0"].ID.String(), phone.AccessToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+
+	rec = doJSON(t, server, http.MethodGet, "/api/v1/auth/sessions", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
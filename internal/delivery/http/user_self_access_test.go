@@ -0,0 +1,146 @@
+package http
+
+import (
+	"bytes"
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/infrastructure/database"
+	"clean-architecture-api/internal/infrastructure/repository"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// selfAccessPolicy lets users read and update only their own record. The route guard checks
+// the user:<action> permission and the repository checks the plain user resource.
+func selfAccessPolicy() *entities.PolicyDocument {
+	owner := map[string]interface{}{"resource_owner": true}
+	statement := func(action, resource string) entities.PolicyStatement {
+		return entities.PolicyStatement{
+			ID:         uuid.New(),
+			Effect:     constants.PolicyEffectAllow,
+			Principal:  "role:" + constants.RoleUser,
+			Action:     action,
+			Resource:   resource,
+			Conditions: owner,
+		}
+	}
+
+	return &entities.PolicyDocument{
+		ID:       uuid.New(),
+		Name:     "user-self-access",
+		Version:  "1.0",
+		IsActive: true,
+		Statements: []entities.PolicyStatement{
+			statement(constants.ActionRead, constants.PermissionUserRead),
+			statement(constants.ActionRead, constants.ResourceUser),
+			statement(constants.ActionUpdate, constants.PermissionUserUpdate),
+			statement(constants.ActionUpdate, constants.ResourceUser),
+		},
+	}
+}
+
+func doJSON(t *testing.T, server *Server, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&payload).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// registerAndLogin returns the new user's ID and access token
+func registerAndLogin(t *testing.T, server *Server, email string) (string, string) {
+	rec := doJSON(t, server, http.MethodPost, "/api/v1/auth/register", "", map[string]string{
+		"email": email, "password": "password123", "first_name": "Test", "last_name": "User",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var registered struct {
+		Data struct {
+			User struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &registered))
+
+	rec = doJSON(t, server, http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"email": email, "password": "password123",
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var login struct {
+		Data struct {
+			Tokens struct {
+				AccessToken string `json:"access_token"`
+			} `json:"tokens"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &login))
+
+	return registered.Data.User.ID, login.Data.Tokens.AccessToken
+}
+
+func TestUserSelfAccess(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	db, err := database.NewInMemoryDatabase()
+	require.NoError(t, err)
+	require.NoError(t, repository.NewPolicySQLiteRepository(db, logger.NewLogger()).Create(context.Background(), selfAccessPolicy()))
+	server, err := NewServer(testConfig(t), db, logger.NewLogger())
+	require.NoError(t, err)
+
+	aliceID, aliceToken := registerAndLogin(t, server, "alice@example.com")
+	bobID, _ := registerAndLogin(t, server, "bob@example.com")
+
+	rec := doJSON(t, server, http.MethodGet, "/api/v1/users/"+aliceID, aliceToken, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Another user's profile answers exactly like an ID that does not exist
+	rec = doJSON(t, server, http.MethodGet, "/api/v1/users/"+bobID, aliceToken, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	missing := doJSON(t, server, http.MethodGet, "/api/v1/users/"+uuid.NewString(), aliceToken, nil)
+	assert.Equal(t, http.StatusNotFound, missing.Code, missing.Body.String())
+	assert.JSONEq(t, missing.Body.String(), rec.Body.String())
+
+	// ...in every language, although the route guard answers the first and the handler the second
+	localized := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+id, nil)
+		req.Header.Set("Authorization", "Bearer "+aliceToken)
+		req.Header.Set("Accept-Language", "vi")
+		rec := httptest.NewRecorder()
+		server.router.ServeHTTP(rec, req)
+		return rec
+	}
+	rec, missing = localized(bobID), localized(uuid.NewString())
+	assert.Equal(t, "vi", rec.Header().Get("Content-Language"))
+	assert.Equal(t, missing.Header().Get("Content-Language"), rec.Header().Get("Content-Language"))
+	assert.JSONEq(t, missing.Body.String(), rec.Body.String())
+
+	update := map[string]interface{}{"first_name": "Alice", "last_name": "Updated", "role": constants.RoleUser, "is_active": true}
+	rec = doJSON(t, server, http.MethodPut, "/api/v1/users/"+aliceID, aliceToken, update)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doJSON(t, server, http.MethodPut, "/api/v1/users/"+bobID, aliceToken, update)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Self-access does not extend to granting yourself a role
+	update["role"] = constants.RoleAdmin
+	rec = doJSON(t, server, http.MethodPut, "/api/v1/users/"+aliceID, aliceToken, update)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doJSON(t, server, http.MethodGet, "/api/v1/users", aliceToken, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
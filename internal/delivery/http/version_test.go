@@ -0,0 +1,18 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersion_RateLimited(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ANONYMOUS_PER_MINUTE", "1")
+	server := newTestServer(t)
+
+	rec := doJSON(t, server, http.MethodGet, "/version", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = doJSON(t, server, http.MethodGet, "/version", "", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, rec.Body.String())
+}
@@ -3,12 +3,12 @@
 package middleware
 
 import (
+	"clean-architecture-api/internal/delivery/http/response"
 	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/errors"
 	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/internal/usecase"
 	"clean-architecture-api/pkg/logger"
-	"context"
 	"net/http"
 	"strings"
 
@@ -16,10 +16,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// authenticatedKey marks a gin context whose caller authenticate has already verified
+const authenticatedKey = "authenticated"
+
 // AuthMiddleware provides authentication and authorization middleware
 type AuthMiddleware struct {
 	authUseCase usecase.AuthUseCase
 	authService repositories.AuthorizationService
+	limiter     *RateLimiter
 	logger      logger.Logger
 }
 
@@ -28,10 +32,22 @@ func NewAuthMiddleware(
 	authUseCase usecase.AuthUseCase,
 	authService repositories.AuthorizationService,
 	logger logger.Logger,
+) *AuthMiddleware {
+	return NewAuthMiddlewareWithRateLimiter(authUseCase, authService, nil, logger)
+}
+
+// NewAuthMiddlewareWithRateLimiter creates an authentication middleware that charges every
+// authenticated request to the caller's user ID on limiter. A nil limiter disables limiting.
+func NewAuthMiddlewareWithRateLimiter(
+	authUseCase usecase.AuthUseCase,
+	authService repositories.AuthorizationService,
+	limiter *RateLimiter,
+	logger logger.Logger,
 ) *AuthMiddleware {
 	return &AuthMiddleware{
 		authUseCase: authUseCase,
 		authService: authService,
+		limiter:     limiter,
 		logger:      logger,
 	}
 }
@@ -39,54 +55,101 @@ func NewAuthMiddleware(
 // AuthRequired middleware ensures the request has a valid authentication token
 func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := extractToken(c)
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": errors.ErrAuthorizationHeaderRequired.Error()})
-			c.Abort()
+		if !m.authenticate(c) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// OptionalAuth identifies the caller when the request carries a bearer token and lets it through
+// anonymously when it carries none. A token that does not validate is still rejected with 401.
+func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if extractToken(c) != "" && !m.authenticate(c) {
 			return
 		}
 
-		claims, err := m.authUseCase.ValidateToken(c.Request.Context(), token)
-		if err != nil {
+		c.Next()
+	}
+}
+
+// authenticate validates the bearer token and stores the caller identity on the
+// request without advancing the handler chain. It aborts and returns false on failure.
+func (m *AuthMiddleware) authenticate(c *gin.Context) bool {
+	// Later guards on the same route find the request already authenticated
+	if c.GetBool(authenticatedKey) {
+		return true
+	}
+
+	// Identity forwarded by another instance carries no token, but its user gets the same checks
+	if userID, ok := constants.UserIDFromContext(c.Request.Context()); ok {
+		forwardedAt, _ := constants.ForwardedAtFromContext(c.Request.Context())
+		if err := m.authUseCase.ValidateForwardedUser(c.Request.Context(), userID, forwardedAt); err != nil {
 			m.logger.Error(errors.ErrFailedToValidateToken.Error(), err)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": errors.ErrInvalidOrExpiredToken.Error()})
 			c.Abort()
-			return
+			return false
 		}
+		return m.authenticated(c)
+	}
 
-		c.Set(string(constants.ContextUserID), claims.UserID)
-		c.Set(string(constants.ContextUserEmail), claims.Email)
-		c.Set(string(constants.ContextUserRole), claims.Role)
+	token := extractToken(c)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errors.ErrAuthorizationHeaderRequired.Error()})
+		c.Abort()
+		return false
+	}
 
-		enrichedCtx := m.authService.CreateEnrichedContext(
-			c.Request.Context(),
-			claims.UserID,
-			claims.Role,
-			claims.Email,
-		)
-		enrichedCtx = context.WithValue(enrichedCtx, constants.ContextClientIP, c.ClientIP())
-		c.Request = c.Request.WithContext(enrichedCtx)
+	claims, err := m.authUseCase.ValidateToken(c.Request.Context(), token)
+	if err != nil {
+		m.logger.Error(errors.ErrFailedToValidateToken.Error(), err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errors.ErrInvalidOrExpiredToken.Error()})
+		c.Abort()
+		return false
+	}
 
-		c.Next()
+	enrichedCtx := m.authService.CreateEnrichedContext(
+		c.Request.Context(),
+		claims.UserID,
+		claims.Role,
+		claims.Email,
+	)
+	enrichedCtx = constants.WithClientIP(enrichedCtx, c.ClientIP())
+	if claims.ImpersonatedBy != nil {
+		enrichedCtx = constants.WithImpersonator(enrichedCtx, *claims.ImpersonatedBy)
+		m.logger.Info("Impersonated request by admin "+claims.ImpersonatedBy.String()+" as user "+claims.UserID.String(),
+			c.Request.Method+" "+c.Request.URL.Path)
 	}
+	c.Request = c.Request.WithContext(enrichedCtx)
+
+	return m.authenticated(c)
+}
+
+// authenticated marks the request as authenticated and charges it to the caller on the rate
+// limiter, once per request however many guards the route stacks
+func (m *AuthMiddleware) authenticated(c *gin.Context) bool {
+	c.Set(authenticatedKey, true)
+	if m.limiter != nil && !m.limiter.Allow(c) {
+		return false
+	}
+	return true
 }
 
 func (m *AuthMiddleware) ResourceAccess(resource, action string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		m.AuthRequired()(c)
-		if c.IsAborted() {
+		if !m.authenticate(c) {
 			return
 		}
 
-		userID, exists := c.Get(string(constants.ContextUserID))
+		userUUID, exists := constants.UserIDFromContext(c.Request.Context())
 		if !exists {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": errors.ErrUserIDNotFound.Error()})
 			c.Abort()
 			return
 		}
 
-		userUUID := userID.(uuid.UUID)
-
 		if err := m.authService.CheckPermission(c.Request.Context(), userUUID, resource, action); err != nil {
 			c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrInsufficientPermissions.Error()})
 			c.Abort()
@@ -98,24 +161,53 @@ func (m *AuthMiddleware) ResourceAccess(resource, action string) gin.HandlerFunc
 }
 
 func (m *AuthMiddleware) ResourceAccessWithID(resource, action string) gin.HandlerFunc {
+	return m.ResourceAccessWithOwner(resource, action, nil)
+}
+
+// OwnerResolver returns the owner of the resource identified by resourceID, or false when
+// it cannot be determined
+type OwnerResolver func(c *gin.Context, resourceID string) (uuid.UUID, bool)
+
+// ResourceAccessWithOwner checks the permission for the :id resource after putting its owner
+// in the request context, so policies with a resource_owner condition can grant self-access.
+// When the owner is unknown those policies fail closed.
+func (m *AuthMiddleware) ResourceAccessWithOwner(resource, action string, owner OwnerResolver) gin.HandlerFunc {
+	return m.resourceAccess(resource, action, owner, nil)
+}
+
+// concealedReadAccess guards reads of the :id resource like ResourceAccessWithOwner, but a
+// denied read gets notFound, the same response as a missing record, so IDs cannot be probed
+func (m *AuthMiddleware) concealedReadAccess(resource string, owner OwnerResolver, notFound *errors.AppError) gin.HandlerFunc {
+	return m.resourceAccess(resource, constants.ActionRead, owner, notFound)
+}
+
+func (m *AuthMiddleware) resourceAccess(resource, action string, owner OwnerResolver, notFound *errors.AppError) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		m.AuthRequired()(c)
-		if c.IsAborted() {
+		if !m.authenticate(c) {
 			return
 		}
 
-		userID, exists := c.Get(string(constants.ContextUserID))
+		userUUID, exists := constants.UserIDFromContext(c.Request.Context())
 		if !exists {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": errors.ErrUserIDNotFound.Error()})
 			c.Abort()
 			return
 		}
 
-		userUUID := userID.(uuid.UUID)
 		resourceID := c.Param("id")
+		if owner != nil {
+			if ownerID, ok := owner(c, resourceID); ok {
+				c.Request = c.Request.WithContext(constants.WithResourceOwner(c.Request.Context(), ownerID))
+			}
+		}
 
 		if err := m.authService.CheckResourcePermission(c.Request.Context(), userUUID, resource, action, resourceID); err != nil {
-			c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrInsufficientPermissions.Error()})
+			if notFound != nil {
+				// Same body as BaseHandler.SendErrorResponse gives a missing record
+				c.JSON(http.StatusNotFound, gin.H{"error": response.ErrorBody(c, notFound)})
+			} else {
+				c.JSON(http.StatusForbidden, gin.H{"error": errors.ErrInsufficientPermissions.Error()})
+			}
 			c.Abort()
 			return
 		}
@@ -124,14 +216,19 @@ func (m *AuthMiddleware) ResourceAccessWithID(resource, action string) gin.Handl
 	}
 }
 
+// userOwner resolves a user record's owner: every user owns their own record
+func userOwner(_ *gin.Context, resourceID string) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(resourceID)
+	return userID, err == nil
+}
+
 func (m *AuthMiddleware) RoleRequired(requiredRole string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		m.AuthRequired()(c)
-		if c.IsAborted() {
+		if !m.authenticate(c) {
 			return
 		}
 
-		userRole, exists := c.Get(string(constants.ContextUserRole))
+		userRole, exists := constants.UserRoleFromContext(c.Request.Context())
 		if !exists {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": errors.ErrUserRoleNotFound.Error()})
 			c.Abort()
@@ -157,11 +254,11 @@ func (m *AuthMiddleware) UserCreateAccess() gin.HandlerFunc {
 }
 
 func (m *AuthMiddleware) UserReadAccess() gin.HandlerFunc {
-	return m.ResourceAccessWithID(constants.PermissionUserRead, constants.ActionRead)
+	return m.concealedReadAccess(constants.PermissionUserRead, userOwner, errors.ErrUserNotFound)
 }
 
 func (m *AuthMiddleware) UserUpdateAccess() gin.HandlerFunc {
-	return m.ResourceAccessWithID(constants.PermissionUserUpdate, constants.ActionUpdate)
+	return m.ResourceAccessWithOwner(constants.PermissionUserUpdate, constants.ActionUpdate, userOwner)
 }
 
 func (m *AuthMiddleware) UserDeleteAccess() gin.HandlerFunc {
@@ -177,7 +274,7 @@ func (m *AuthMiddleware) ProductCreateAccess() gin.HandlerFunc {
 }
 
 func (m *AuthMiddleware) ProductReadAccess() gin.HandlerFunc {
-	return m.ResourceAccessWithID(constants.PermissionProductRead, constants.ActionRead)
+	return m.concealedReadAccess(constants.PermissionProductRead, nil, errors.ErrProductNotFound)
 }
 
 func (m *AuthMiddleware) ProductUpdateAccess() gin.HandlerFunc {
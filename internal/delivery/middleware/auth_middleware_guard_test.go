@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/internal/usecase"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// guardAuthUseCase accepts "valid-token" as a caller with role
+type guardAuthUseCase struct {
+	usecase.AuthUseCase
+	role string
+}
+
+func (f *guardAuthUseCase) ValidateToken(_ context.Context, token string) (*auth.Claims, error) {
+	if token != "valid-token" {
+		return nil, errors.New("invalid token")
+	}
+	return &auth.Claims{UserID: uuid.New(), Role: f.role}, nil
+}
+
+// denyingAuthorizationService denies every permission check
+type denyingAuthorizationService struct {
+	repositories.AuthorizationService
+}
+
+func (s *denyingAuthorizationService) CreateEnrichedContext(ctx context.Context, userID uuid.UUID, role, email string) context.Context {
+	return auth.NewAuthorizationService(nil).CreateEnrichedContext(ctx, userID, role, email)
+}
+
+func (s *denyingAuthorizationService) CheckPermission(_ context.Context, _ uuid.UUID, _, _ string) error {
+	return domainerrors.ErrInsufficientPermissions
+}
+
+func TestGuards_CheckBeforeRunningTheHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := NewAuthMiddleware(&guardAuthUseCase{role: constants.RoleUser}, &denyingAuthorizationService{}, logger.NewLogger())
+
+	guards := map[string]gin.HandlerFunc{
+		"RoleRequired":   m.AdminRequired(),
+		"ResourceAccess": m.ResourceAccess(constants.PermissionUserList, constants.ActionList),
+	}
+	for name, guard := range guards {
+		t.Run(name, func(t *testing.T) {
+			handled := false
+			router := gin.New()
+			router.GET("/guarded", guard, func(c *gin.Context) {
+				handled = true
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/guarded", nil)
+			req.Header.Set("Authorization", "Bearer valid-token")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+			assert.False(t, handled, "a denied request must not reach the handler")
+		})
+	}
+}
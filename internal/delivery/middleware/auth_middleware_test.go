@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/internal/usecase"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuthUseCase struct {
+	usecase.AuthUseCase
+	claims *auth.Claims
+	// forwardedErr is what ValidateForwardedUser reports for every forwarded identity
+	forwardedErr error
+}
+
+func (f *fakeAuthUseCase) ValidateToken(_ context.Context, token string) (*auth.Claims, error) {
+	if token != "valid-token" {
+		return nil, errors.New("invalid token")
+	}
+	return f.claims, nil
+}
+
+func (f *fakeAuthUseCase) ValidateForwardedUser(_ context.Context, _ uuid.UUID, _ time.Time) error {
+	return f.forwardedErr
+}
+
+type fakeAuthorizationService struct {
+	repositories.AuthorizationService
+}
+
+func (f *fakeAuthorizationService) CreateEnrichedContext(ctx context.Context, userID uuid.UUID, role, email string) context.Context {
+	return auth.NewAuthorizationService(nil).CreateEnrichedContext(ctx, userID, role, email)
+}
+
+// impersonatorSeenBy runs AuthRequired for claims and returns the impersonator the handler saw
+func impersonatorSeenBy(t *testing.T, claims *auth.Claims) (uuid.UUID, bool) {
+	gin.SetMode(gin.TestMode)
+	m := NewAuthMiddleware(&fakeAuthUseCase{claims: claims}, &fakeAuthorizationService{}, logger.NewLogger())
+
+	var impersonatorID uuid.UUID
+	var found bool
+	router := gin.New()
+	router.GET("/me", m.AuthRequired(), func(c *gin.Context) {
+		impersonatorID, found = constants.ImpersonatorFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	return impersonatorID, found
+}
+
+func TestAuthRequired_SurfacesImpersonator(t *testing.T) {
+	adminID := uuid.New()
+	claims := &auth.Claims{UserID: uuid.New(), Role: constants.RoleUser, ImpersonatedBy: &adminID}
+
+	impersonatorID, found := impersonatorSeenBy(t, claims)
+	assert.True(t, found)
+	assert.Equal(t, adminID, impersonatorID)
+
+	_, found = impersonatorSeenBy(t, &auth.Claims{UserID: uuid.New(), Role: constants.RoleUser})
+	assert.False(t, found)
+}
+
+func TestAuthRequired_ChecksForwardedIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	serve := func(authUseCase *fakeAuthUseCase, limiter *RateLimiter) int {
+		m := NewAuthMiddlewareWithRateLimiter(authUseCase, &fakeAuthorizationService{}, limiter, logger.NewLogger())
+		router := gin.New()
+		// Stands in for the ServiceContext middleware restoring another instance's caller
+		router.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(constants.WithUserID(c.Request.Context(), userID))
+		})
+		router.GET("/me", m.AuthRequired(), m.AuthRequired(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/me", nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve(&fakeAuthUseCase{forwardedErr: errors.New("deactivated")}, nil))
+
+	// A forwarded user is charged to the rate limiter once per request, like a token user
+	limiter, _ := newTestRateLimiter(RateLimitConfig{AuthenticatedPerMinute: 1})
+	assert.Equal(t, http.StatusOK, serve(&fakeAuthUseCase{}, limiter))
+	assert.Equal(t, http.StatusTooManyRequests, serve(&fakeAuthUseCase{}, limiter))
+}
+
+// readerProductRepository records the user the product use case reads as
+type readerProductRepository struct {
+	repositories.ProductRepository
+	readAs uuid.UUID
+}
+
+func (r *readerProductRepository) GetByID(_ context.Context, id uuid.UUID, userID uuid.UUID) (*entities.Product, error) {
+	r.readAs = userID
+	return &entities.Product{BaseEntity: entities.BaseEntity{ID: id}}, nil
+}
+
+func TestAuthRequired_IdentityReachesProductUseCase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("PRODUCTS_PUBLIC", "true")
+	userID := uuid.New()
+	m := NewAuthMiddleware(&fakeAuthUseCase{claims: &auth.Claims{UserID: userID, Role: constants.RoleUser}},
+		&fakeAuthorizationService{}, logger.NewLogger())
+	repo := &readerProductRepository{}
+	products := usecase.NewProductUseCase(repo, nil, nil, nil, nil, usecase.ProductSettings{}, logger.NewLogger())
+
+	router := gin.New()
+	router.GET("/products/:id", m.AuthRequired(), func(c *gin.Context) {
+		_, err := products.GetByID(c.Request.Context(), uuid.MustParse(c.Param("id")))
+		require.NoError(t, err)
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/products/"+uuid.NewString(), nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, userID, repo.readAs, "the use case must read as the caller, not uuid.Nil or the system user")
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bytes"
+	"clean-architecture-api/internal/domain/constants"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BodyLimitConfig struct {
+	// MaxBytes caps every request body
+	MaxBytes int64
+	// ImportMaxBytes replaces MaxBytes on bulk import routes, which take whole documents
+	ImportMaxBytes int64
+}
+
+func NewBodyLimitConfigFromEnv() (BodyLimitConfig, error) {
+	config := BodyLimitConfig{
+		MaxBytes:       constants.DefaultMaxRequestBodyBytes,
+		ImportMaxBytes: constants.DefaultMaxImportBodyBytes,
+	}
+	for key, target := range map[string]*int64{
+		"MAX_REQUEST_BODY_BYTES": &config.MaxBytes,
+		"MAX_IMPORT_BODY_BYTES":  &config.ImportMaxBytes,
+	} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limit <= 0 {
+			return BodyLimitConfig{}, fmt.Errorf("invalid %s %q: must be a positive byte count", key, value)
+		}
+		*target = limit
+	}
+	return config, nil
+}
+
+// BodyLimit rejects request bodies larger than maxBytes with 413. Routes listed in overrides, by
+// their registered path, get their own limit. The body is read up front through
+// http.MaxBytesReader, so handlers never see a truncated payload and oversized ones never reach
+// the JSON decoder.
+func BodyLimit(maxBytes int64, overrides map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		limit := maxBytes
+		if override, ok := overrides[c.FullPath()]; ok {
+			limit = override
+		}
+		if c.Request.ContentLength > limit {
+			abortTooLarge(c)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				abortTooLarge(c)
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": domainerrors.ErrInvalidRequest.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func abortTooLarge(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": domainerrors.ErrRequestBodyTooLarge.Error()})
+}
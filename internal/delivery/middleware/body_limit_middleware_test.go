@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bodyLimitRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimit(16, map[string]int64{"/import": 64}))
+	echo := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	}
+	router.POST("/login", echo)
+	router.POST("/import", echo)
+	return router
+}
+
+func TestBodyLimit(t *testing.T) {
+	router := bodyLimitRouter()
+
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		chunked bool
+		want    int
+	}{
+		{name: "within limit", path: "/login", body: strings.Repeat("a", 16), want: http.StatusOK},
+		{name: "oversized", path: "/login", body: strings.Repeat("a", 17), want: http.StatusRequestEntityTooLarge},
+		{name: "oversized without content length", path: "/login", body: strings.Repeat("a", 17), chunked: true, want: http.StatusRequestEntityTooLarge},
+		{name: "import override", path: "/import", body: strings.Repeat("a", 64), want: http.StatusOK},
+		{name: "import oversized", path: "/import", body: strings.Repeat("a", 65), want: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.want == http.StatusOK {
+				assert.Equal(t, tt.body, rec.Body.String(), "the handler should see the whole body")
+			}
+		})
+	}
+}
+
+func TestNewBodyLimitConfigFromEnv(t *testing.T) {
+	t.Setenv("MAX_REQUEST_BODY_BYTES", "2048")
+	t.Setenv("MAX_IMPORT_BODY_BYTES", "")
+	config, err := NewBodyLimitConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2048), config.MaxBytes)
+	assert.Equal(t, int64(constants.DefaultMaxImportBodyBytes), config.ImportMaxBytes)
+
+	t.Setenv("MAX_IMPORT_BODY_BYTES", "-1")
+	_, err = NewBodyLimitConfigFromEnv()
+	assert.ErrorContains(t, err, "MAX_IMPORT_BODY_BYTES")
+}
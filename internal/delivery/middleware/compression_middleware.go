@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"bytes"
+	"clean-architecture-api/internal/domain/constants"
+	"compress/gzip"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CompressionConfig struct {
+	// MinSize is the smallest body, in bytes, worth compressing
+	MinSize int
+	// ContentTypes lists the media types that are compressed; anything else passes through
+	ContentTypes []string
+}
+
+func NewCompressionConfigFromEnv() (CompressionConfig, error) {
+	config := CompressionConfig{
+		MinSize:      constants.DefaultCompressionMinSize,
+		ContentTypes: splitHeaderList(envOrDefault("COMPRESSION_CONTENT_TYPES", constants.DefaultCompressionContentTypes)),
+	}
+	if value := os.Getenv("COMPRESSION_MIN_SIZE"); value != "" {
+		minSize, err := strconv.Atoi(value)
+		if err != nil || minSize < 0 {
+			return CompressionConfig{}, fmt.Errorf("invalid COMPRESSION_MIN_SIZE %q: must be a non-negative byte count", value)
+		}
+		config.MinSize = minSize
+	}
+	return config, nil
+}
+
+// Compression gzips responses for clients that accept it. The body is buffered until MinSize
+// bytes are written, so small responses go out untouched; responses that already carry a
+// Content-Encoding or whose type is not allowlisted are never compressed.
+func Compression(config CompressionConfig) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(config.ContentTypes))
+	for _, contentType := range config.ContentTypes {
+		allowed[strings.ToLower(contentType)] = true
+	}
+
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: c.Writer, minSize: config.MinSize, allowed: allowed}
+		c.Writer = writer
+		defer func() {
+			writer.finish()
+			c.Writer = writer.ResponseWriter
+		}()
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, honouring q=0 opt-outs
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minSize int
+	allowed map[string]bool
+
+	buf     bytes.Buffer
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf.Write(data)
+	if w.buf.Len() >= w.minSize {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		_ = w.decide()
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide picks compressed or plain output once enough of the body is known and writes out
+// whatever was buffered
+func (w *gzipResponseWriter) decide() error {
+	w.decided = true
+	if w.compressible() {
+		header := w.Header()
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+		_, err := w.gz.Write(w.buf.Bytes())
+		w.buf.Reset()
+		return err
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *gzipResponseWriter) compressible() bool {
+	if w.buf.Len() < w.minSize || w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+	switch w.Status() {
+	case http.StatusNoContent, http.StatusNotModified:
+		return false
+	}
+
+	contentType := w.Header().Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(w.buf.Bytes())
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && w.allowed[mediaType]
+}
+
+func (w *gzipResponseWriter) finish() {
+	if !w.decided {
+		if w.buf.Len() == 0 {
+			return
+		}
+		_ = w.decide()
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compressionRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Compression(CompressionConfig{MinSize: 1024, ContentTypes: []string{"application/json"}}))
+	router.GET("/products", func(c *gin.Context) {
+		products := make([]gin.H, 100)
+		for i := range products {
+			products[i] = gin.H{"name": "Product", "description": strings.Repeat("lorem ipsum ", 5)}
+		}
+		c.JSON(http.StatusOK, gin.H{"products": products})
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	router.GET("/archive", func(c *gin.Context) {
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", []byte(strings.Repeat("x", 2048)))
+	})
+	return router
+}
+
+func TestCompression_GzipsLargeJSON(t *testing.T) {
+	router := compressionRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Accept-Encoding", "br;q=1.0, gzip;q=0.8")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+
+	reader, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	var payload struct {
+		Products []map[string]string `json:"products"`
+	}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Len(t, payload.Products, 100)
+	assert.Less(t, rec.Body.Len(), len(body))
+}
+
+func TestCompression_SkipsIneligibleResponses(t *testing.T) {
+	router := compressionRouter()
+
+	for _, tc := range []struct {
+		name, path, acceptEncoding string
+	}{
+		{"client does not accept gzip", "/products", ""},
+		{"client opts out of gzip", "/products", "gzip;q=0"},
+		{"body below minimum size", "/small", "gzip"},
+		{"already encoded", "/archive", "gzip"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+			if tc.path == "/archive" {
+				assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+				assert.Equal(t, strings.Repeat("x", 2048), rec.Body.String(), "pre-encoded bodies must not be compressed twice")
+				return
+			}
+			assert.Empty(t, rec.Header().Get("Content-Encoding"))
+			assert.True(t, json.Valid(rec.Body.Bytes()))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// RequireJSON rejects POST, PUT and PATCH requests whose body is not application/json with 415,
+// before a handler's ShouldBindJSON fails on it with a less helpful error. Routes listed in
+// allowed, by their registered path, also accept the media types given for them, such as
+// multipart/form-data on file uploads. Requests without a body pass through.
+func RequireJSON(allowed map[string][]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength == 0 {
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err == nil && acceptsMediaType(mediaType, allowed[c.FullPath()]) {
+			c.Next()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": domainerrors.ErrUnsupportedMediaType.Error()})
+	}
+}
+
+func acceptsMediaType(mediaType string, extra []string) bool {
+	if mediaType == binding.MIMEJSON {
+		return true
+	}
+	for _, allowed := range extra {
+		if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
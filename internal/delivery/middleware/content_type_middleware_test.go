@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequireJSON(map[string][]string{"/import": {"multipart/form-data"}}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.POST("/login", ok)
+	router.PUT("/login", ok)
+	router.GET("/login", ok)
+	router.POST("/import", ok)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		want        int
+	}{
+		{name: "json", method: http.MethodPost, path: "/login", contentType: "application/json", body: "{}", want: http.StatusOK},
+		{name: "json with charset", method: http.MethodPut, path: "/login", contentType: "Application/JSON; charset=utf-8", body: "{}", want: http.StatusOK},
+		{name: "form body", method: http.MethodPost, path: "/login", contentType: "application/x-www-form-urlencoded", body: "email=a", want: http.StatusUnsupportedMediaType},
+		{name: "missing content type", method: http.MethodPost, path: "/login", body: "{}", want: http.StatusUnsupportedMediaType},
+		{name: "no body", method: http.MethodPost, path: "/login", want: http.StatusOK},
+		{name: "read", method: http.MethodGet, path: "/login", contentType: "text/plain", body: "x", want: http.StatusOK},
+		{name: "multipart on allowed route", method: http.MethodPost, path: "/import", contentType: "multipart/form-data; boundary=x", body: "--x--", want: http.StatusOK},
+		{name: "multipart elsewhere", method: http.MethodPost, path: "/login", contentType: "multipart/form-data; boundary=x", body: "--x--", want: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CORSConfig struct {
+	// AllowedOrigins lists exact origins, or "*" for any origin
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight result; zero omits the header
+	MaxAge time.Duration
+}
+
+// NewCORSConfigFromEnv returns nil when CORS_ALLOWED_ORIGINS is unset, leaving CORS disabled.
+// Allowing credentials together with the "*" origin is a configuration error, since browsers
+// reject that combination and echoing any origin instead would hand out credentials to every site.
+func NewCORSConfigFromEnv() (*CORSConfig, error) {
+	origins := splitHeaderList(os.Getenv("CORS_ALLOWED_ORIGINS"))
+	if len(origins) == 0 {
+		return nil, nil
+	}
+
+	config := &CORSConfig{
+		AllowedOrigins: origins,
+		AllowedMethods: splitHeaderList(envOrDefault("CORS_ALLOWED_METHODS", constants.DefaultCORSAllowedMethods)),
+		AllowedHeaders: splitHeaderList(envOrDefault("CORS_ALLOWED_HEADERS", constants.DefaultCORSAllowedHeaders)),
+		ExposeHeaders:  splitHeaderList(os.Getenv("CORS_EXPOSE_HEADERS")),
+		MaxAge:         constants.DefaultCORSMaxAge,
+	}
+
+	if value := os.Getenv("CORS_ALLOW_CREDENTIALS"); value != "" {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS %q: must be true or false", value)
+		}
+		config.AllowCredentials = allow
+	}
+	if config.AllowCredentials && config.allowsAnyOrigin() {
+		return nil, errors.New("CORS_ALLOW_CREDENTIALS=true cannot be combined with CORS_ALLOWED_ORIGINS=*")
+	}
+
+	if value := os.Getenv("CORS_MAX_AGE"); value != "" {
+		maxAge, err := time.ParseDuration(value)
+		if err != nil || maxAge < 0 {
+			return nil, fmt.Errorf("invalid CORS_MAX_AGE %q: must be a duration such as 10m", value)
+		}
+		config.MaxAge = maxAge
+	}
+
+	return config, nil
+}
+
+// CORS answers preflight requests and decorates responses for allowed origins. Requests from
+// other origins get no CORS headers, and their preflights are refused.
+func CORS(config CORSConfig) gin.HandlerFunc {
+	anyOrigin := config.allowsAnyOrigin()
+	allowed := make(map[string]bool, len(config.AllowedOrigins))
+	for _, origin := range config.AllowedOrigins {
+		allowed[origin] = true
+	}
+	methods := strings.Join(config.AllowedMethods, ", ")
+	headers := strings.Join(config.AllowedHeaders, ", ")
+	exposed := strings.Join(config.ExposeHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		h := c.Writer.Header()
+		if !anyOrigin {
+			h.Add("Vary", "Origin")
+		}
+		if !anyOrigin && !allowed[origin] {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if anyOrigin {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		// Never paired with "*": NewCORSConfigFromEnv rejects that, and the check here covers
+		// configs built by hand
+		if config.AllowCredentials && !anyOrigin {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposed != "" {
+				h.Set("Access-Control-Expose-Headers", exposed)
+			}
+			c.Next()
+			return
+		}
+
+		h.Set("Access-Control-Allow-Methods", methods)
+		if headers != "" {
+			h.Set("Access-Control-Allow-Headers", headers)
+		}
+		if config.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+func (c CORSConfig) allowsAnyOrigin() bool {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func splitHeaderList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
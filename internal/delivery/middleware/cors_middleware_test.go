@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func corsRouter(config CORSConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(config))
+	router.GET("/products", func(c *gin.Context) {
+		c.Header("X-Request-ID", "req-1")
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	router := corsRouter(CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		MaxAge:         10 * time.Minute,
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/products", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization, Content-Type", rec.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, "Origin", rec.Header().Get("Vary"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+
+	req = httptest.NewRequest(http.MethodOptions, "/products", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_CredentialedRequest(t *testing.T) {
+	router := corsRouter(CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		ExposeHeaders:    []string{"X-Request-ID"},
+		AllowCredentials: true,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "X-Request-ID", rec.Header().Get("Access-Control-Expose-Headers"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Max-Age"), "max age only applies to preflights")
+}
+
+func TestCORS_WildcardNeverAllowsCredentials(t *testing.T) {
+	router := corsRouter(CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Origin", "https://anywhere.example.com")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestNewCORSConfigFromEnv(t *testing.T) {
+	config, err := NewCORSConfigFromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, config)
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", "*")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	_, err = NewCORSConfigFromEnv()
+	assert.Error(t, err, "credentials must not be combined with a wildcard origin")
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com, https://admin.example.com")
+	t.Setenv("CORS_EXPOSE_HEADERS", "X-Request-ID")
+	t.Setenv("CORS_MAX_AGE", "1h")
+	config, err = NewCORSConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://app.example.com", "https://admin.example.com"}, config.AllowedOrigins)
+	assert.Equal(t, []string{"X-Request-ID"}, config.ExposeHeaders)
+	assert.True(t, config.AllowCredentials)
+	assert.Equal(t, time.Hour, config.MaxAge)
+	assert.Equal(t, []string{"Authorization", "Content-Type", "X-Request-Timeout"}, config.AllowedHeaders)
+}
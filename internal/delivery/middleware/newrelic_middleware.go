@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+
+	"github.com/gin-gonic/gin"
+	"github.com/newrelic/go-agent/v3/newrelic"
+)
+
+// unmatchedTransactionName groups requests that match no route, so probes for random paths do not
+// each create a transaction name
+const unmatchedTransactionName = "NotFound"
+
+// NewRelic starts a transaction per request named by method and route template, e.g.
+// "GET /api/v1/products/:id". The transaction is stored in the request context, so the GORM
+// segments started by repositories nest under it. User ID and role are attached once the auth
+// middleware has run.
+func NewRelic(app *newrelic.Application) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedTransactionName
+		}
+
+		txn := app.StartTransaction(c.Request.Method + " " + route)
+		defer txn.End()
+		txn.SetWebRequestHTTP(c.Request)
+		c.Request = c.Request.WithContext(newrelic.NewContext(c.Request.Context(), txn))
+
+		c.Next()
+
+		// AuthRequired replaced c.Request with one carrying the caller, if there is one
+		if userID, exists := constants.UserIDFromContext(c.Request.Context()); exists {
+			txn.AddAttribute("user.id", userID.String())
+		}
+		if role, exists := constants.UserRoleFromContext(c.Request.Context()); exists {
+			txn.AddAttribute("user.role", role)
+		}
+		txn.SetWebResponse(nil).WriteHeader(c.Writer.Status())
+		if len(c.Errors) > 0 {
+			txn.NoticeError(c.Errors.Last())
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRelic_NamesTransactionByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app, err := newrelic.NewApplication(newrelic.ConfigAppName("test"), newrelic.ConfigEnabled(false))
+	require.NoError(t, err)
+
+	var txn *newrelic.Transaction
+	router := gin.New()
+	router.Use(NewRelic(app))
+	router.GET("/products/:id", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(constants.WithUserRole(c.Request.Context(), constants.RoleAdmin))
+		txn = newrelic.FromContext(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/42", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	require.NotNil(t, txn, "handlers must find the transaction in the request context")
+	assert.Equal(t, "GET /products/:id", txn.Name())
+
+	router.NoRoute(func(c *gin.Context) {
+		txn = newrelic.FromContext(c.Request.Context())
+		c.Status(http.StatusNotFound)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/wp-login.php", nil))
+	assert.Equal(t, "GET "+unmatchedTransactionName, txn.Name())
+}
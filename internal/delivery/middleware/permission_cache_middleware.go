@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/infrastructure/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionCache memoizes permission checks for the duration of a request, so the route
+// guard and the repositories it calls do not evaluate the same policy twice
+func PermissionCache() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(auth.WithPermissionCache(c.Request.Context()))
+		c.Next()
+	}
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RateLimitConfig struct {
+	// AuthenticatedPerMinute caps requests per user ID; 0 leaves authenticated traffic unlimited
+	AuthenticatedPerMinute int
+	// AnonymousPerMinute caps requests per client IP on routes that take no token; 0 leaves them unlimited
+	AnonymousPerMinute int
+	// PublicReadsPerMinute caps anonymous reads of the public product catalog per client IP; 0 falls
+	// back to AnonymousPerMinute
+	PublicReadsPerMinute int
+}
+
+func NewRateLimitConfigFromEnv() (RateLimitConfig, error) {
+	config := RateLimitConfig{
+		AuthenticatedPerMinute: constants.DefaultRateLimitAuthenticatedPerMinute,
+		AnonymousPerMinute:     constants.DefaultRateLimitAnonymousPerMinute,
+		PublicReadsPerMinute:   constants.DefaultRateLimitPublicReadsPerMinute,
+	}
+	for key, target := range map[string]*int{
+		"RATE_LIMIT_AUTHENTICATED_PER_MINUTE": &config.AuthenticatedPerMinute,
+		"RATE_LIMIT_ANONYMOUS_PER_MINUTE":     &config.AnonymousPerMinute,
+		"RATE_LIMIT_PUBLIC_READS_PER_MINUTE":  &config.PublicReadsPerMinute,
+	} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return RateLimitConfig{}, fmt.Errorf("invalid %s %q: must be a non-negative request count", key, value)
+		}
+		*target = limit
+	}
+	return config, nil
+}
+
+// RateLimiter counts requests per caller in fixed one-minute windows. Authenticated callers are
+// keyed on their user ID, so users sharing an address behind NAT do not exhaust each other's
+// quota; anonymous callers are keyed on their client IP.
+type RateLimiter struct {
+	config RateLimitConfig
+	now    func() time.Time
+
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		config:  config,
+		now:     time.Now,
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// Middleware limits each request by its caller. Put it after the auth middleware on protected
+// routes so the user ID is known; without one the request counts against its client IP.
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.Allow(c) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// PublicReads limits anonymous reads of the public product catalog per client IP, separately from
+// and usually more strictly than other anonymous traffic. Callers the auth middleware identified
+// were already charged to their user ID and pass through.
+func (l *RateLimiter) PublicReads() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := constants.UserIDFromContext(c.Request.Context()); !ok {
+			limit := l.config.PublicReadsPerMinute
+			if limit <= 0 {
+				limit = l.config.AnonymousPerMinute
+			}
+			if !l.allowKey(c, "public:"+c.ClientIP(), limit) {
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// Allow charges the request to its caller and aborts it with 429 once the caller has used up
+// the current window
+func (l *RateLimiter) Allow(c *gin.Context) bool {
+	if userID, ok := constants.UserIDFromContext(c.Request.Context()); ok {
+		return l.allowKey(c, "user:"+userID.String(), l.config.AuthenticatedPerMinute)
+	}
+	return l.allowKey(c, "ip:"+c.ClientIP(), l.config.AnonymousPerMinute)
+}
+
+func (l *RateLimiter) allowKey(c *gin.Context, key string, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	retryAfter, ok := l.take(key, limit)
+	if !ok {
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds()+0.5)))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": domainerrors.ErrRateLimited.Error()})
+		return false
+	}
+	return true
+}
+
+// take counts one request for key and reports how long until its window resets when the
+// limit is already reached
+func (l *RateLimiter) take(key string, limit int) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	// Windows of callers that went quiet would otherwise accumulate forever
+	if now.Sub(l.lastSweep) >= time.Minute {
+		for k, window := range l.windows {
+			if now.Sub(window.start) >= time.Minute {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	window, ok := l.windows[key]
+	if !ok || now.Sub(window.start) >= time.Minute {
+		window = &rateWindow{start: now}
+		l.windows[key] = window
+	}
+	if window.count >= limit {
+		return window.start.Add(time.Minute).Sub(now), false
+	}
+	window.count++
+	return 0, true
+}
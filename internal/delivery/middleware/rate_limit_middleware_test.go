@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRateLimiter(config RateLimitConfig) (*RateLimiter, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(config)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func serveFrom(router *gin.Engine, path, remoteAddr, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimiter_KeysOnClientIPWhenAnonymous(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter, now := newTestRateLimiter(RateLimitConfig{AuthenticatedPerMinute: 10, AnonymousPerMinute: 2})
+	router := gin.New()
+	router.GET("/products", limiter.Middleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, serveFrom(router, "/products", "198.51.100.1:1000", "").Code)
+	}
+	rec := serveFrom(router, "/products", "198.51.100.1:2000", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+
+	// Another address has its own quota, and the window resets after a minute
+	assert.Equal(t, http.StatusOK, serveFrom(router, "/products", "198.51.100.2:1000", "").Code)
+	*now = now.Add(time.Minute)
+	assert.Equal(t, http.StatusOK, serveFrom(router, "/products", "198.51.100.1:1000", "").Code)
+}
+
+func TestRateLimiter_KeysOnUserIDWhenAuthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter, _ := newTestRateLimiter(RateLimitConfig{AuthenticatedPerMinute: 3, AnonymousPerMinute: 1})
+	router := gin.New()
+	identify := func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetHeader("X-Test-User"))
+		if err == nil {
+			c.Request = c.Request.WithContext(constants.WithUserID(c.Request.Context(), userID))
+		}
+	}
+	router.GET("/me", identify, limiter.Middleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Users behind the same address each get the authenticated quota
+	alice, bob := uuid.NewString(), uuid.NewString()
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, serveFrom(router, "/me", "203.0.113.7:1000", alice).Code)
+		assert.Equal(t, http.StatusOK, serveFrom(router, "/me", "203.0.113.7:1000", bob).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serveFrom(router, "/me", "203.0.113.7:1000", alice).Code)
+
+	// Their traffic does not count against anonymous callers from that address
+	assert.Equal(t, http.StatusOK, serveFrom(router, "/me", "203.0.113.7:1000", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serveFrom(router, "/me", "203.0.113.7:1000", "").Code)
+}
+
+func TestAuthRequired_ChargesRateLimitPerUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter, _ := newTestRateLimiter(RateLimitConfig{AuthenticatedPerMinute: 2})
+	claims := &auth.Claims{UserID: uuid.New(), Role: constants.RoleUser}
+	m := NewAuthMiddlewareWithRateLimiter(&fakeAuthUseCase{claims: claims}, &fakeAuthorizationService{}, limiter, logger.NewLogger())
+	router := gin.New()
+	// Stacked guards must not charge the request twice
+	router.GET("/me", m.AuthRequired(), m.AuthRequired(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	codes := make([]int, 3)
+	for i := range codes {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer valid-token")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		codes[i] = rec.Code
+	}
+	require.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+}
+
+func TestNewRateLimitConfigFromEnv(t *testing.T) {
+	t.Setenv("RATE_LIMIT_AUTHENTICATED_PER_MINUTE", "600")
+	t.Setenv("RATE_LIMIT_ANONYMOUS_PER_MINUTE", "60")
+	t.Setenv("RATE_LIMIT_PUBLIC_READS_PER_MINUTE", "30")
+	config, err := NewRateLimitConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, RateLimitConfig{AuthenticatedPerMinute: 600, AnonymousPerMinute: 60, PublicReadsPerMinute: 30}, config)
+
+	t.Setenv("RATE_LIMIT_ANONYMOUS_PER_MINUTE", "-1")
+	_, err = NewRateLimitConfigFromEnv()
+	assert.Error(t, err)
+}
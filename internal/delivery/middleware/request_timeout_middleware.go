@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeoutHeader lets a client ask for a shorter deadline than the server default, either as
+// whole seconds or as a Go duration such as 1500ms
+const RequestTimeoutHeader = "X-Request-Timeout"
+
+type RequestTimeoutConfig struct {
+	// Default is the deadline of requests that send no RequestTimeoutHeader
+	Default time.Duration
+	// Max caps the deadline a client may ask for
+	Max time.Duration
+}
+
+func NewRequestTimeoutConfigFromEnv() (RequestTimeoutConfig, error) {
+	config := RequestTimeoutConfig{
+		Default: constants.DefaultRequestTimeout,
+		Max:     constants.DefaultMaxRequestTimeout,
+	}
+	for key, target := range map[string]*time.Duration{
+		"REQUEST_TIMEOUT":     &config.Default,
+		"REQUEST_TIMEOUT_MAX": &config.Max,
+	} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return RequestTimeoutConfig{}, fmt.Errorf("invalid %s %q: must be a positive duration such as 30s", key, value)
+		}
+		*target = timeout
+	}
+	if config.Max < config.Default {
+		return RequestTimeoutConfig{}, fmt.Errorf("invalid REQUEST_TIMEOUT_MAX %s: must not be below REQUEST_TIMEOUT %s", config.Max, config.Default)
+	}
+	return config, nil
+}
+
+// RequestTimeout sets the deadline of the request context, which the use cases and repositories
+// pass on to the database. Clients may pick their own deadline through RequestTimeoutHeader, for
+// example to fail fast within their own SLA; hints above config.Max are lowered to it and
+// malformed ones are rejected with 400.
+func RequestTimeout(config RequestTimeoutConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := config.Default
+		if hint := c.GetHeader(RequestTimeoutHeader); hint != "" {
+			requested, ok := parseRequestTimeout(hint, config.Max)
+			if !ok {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": domainerrors.ErrInvalidRequestTimeout.Error()})
+				return
+			}
+			timeout = min(requested, config.Max)
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// parseRequestTimeout reads a RequestTimeoutHeader value. Whole seconds above limit are lowered
+// to it before the conversion to a Duration, which would overflow for huge values.
+func parseRequestTimeout(value string, limit time.Duration) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds > int(limit/time.Second) {
+			return limit, true
+		}
+		return time.Duration(seconds) * time.Second, seconds > 0
+	}
+	timeout, err := time.ParseDuration(value)
+	return timeout, err == nil && timeout > 0
+}
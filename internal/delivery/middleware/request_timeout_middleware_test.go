@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestTimeout(RequestTimeoutConfig{Default: 10 * time.Second, Max: 20 * time.Second}))
+	router.GET("/deadline", func(c *gin.Context) {
+		deadline, ok := c.Request.Context().Deadline()
+		require.True(t, ok)
+		c.String(http.StatusOK, time.Until(deadline).Round(time.Second).String())
+	})
+
+	tests := []struct {
+		name string
+		hint string
+		code int
+		want string
+	}{
+		{name: "default", code: http.StatusOK, want: "10s"},
+		{name: "seconds", hint: "2", code: http.StatusOK, want: "2s"},
+		{name: "duration", hint: "2500ms", code: http.StatusOK, want: "2s"},
+		{name: "longer than default", hint: "15s", code: http.StatusOK, want: "15s"},
+		{name: "capped at max", hint: "90", code: http.StatusOK, want: "20s"},
+		{name: "seconds overflowing a duration", hint: "18446744074", code: http.StatusOK, want: "20s"},
+		{name: "seconds beyond any integer", hint: "99999999999999999999", code: http.StatusBadRequest},
+		{name: "zero", hint: "0", code: http.StatusBadRequest},
+		{name: "negative", hint: "-5s", code: http.StatusBadRequest},
+		{name: "garbage", hint: "soon", code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/deadline", nil)
+			if tt.hint != "" {
+				req.Header.Set(RequestTimeoutHeader, tt.hint)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.code, rec.Code, rec.Body.String())
+			if tt.want != "" {
+				assert.Equal(t, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequestTimeout_ExpiresContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestTimeout(RequestTimeoutConfig{Default: time.Minute, Max: time.Minute}))
+	router.GET("/slow", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			c.Status(http.StatusGatewayTimeout)
+		case <-time.After(time.Second):
+			c.Status(http.StatusOK)
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.Header.Set(RequestTimeoutHeader, "20ms")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code, "the handler sees the client's shorter deadline")
+}
+
+func TestNewRequestTimeoutConfigFromEnv(t *testing.T) {
+	config, err := NewRequestTimeoutConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, RequestTimeoutConfig{Default: 30 * time.Second, Max: 30 * time.Second}, config)
+
+	t.Setenv("REQUEST_TIMEOUT", "5s")
+	t.Setenv("REQUEST_TIMEOUT_MAX", "1m")
+	config, err = NewRequestTimeoutConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, RequestTimeoutConfig{Default: 5 * time.Second, Max: time.Minute}, config)
+
+	t.Setenv("REQUEST_TIMEOUT_MAX", "1s")
+	_, err = NewRequestTimeoutConfigFromEnv()
+	assert.ErrorContains(t, err, "REQUEST_TIMEOUT_MAX")
+
+	t.Setenv("REQUEST_TIMEOUT", "0s")
+	_, err = NewRequestTimeoutConfigFromEnv()
+	assert.ErrorContains(t, err, "REQUEST_TIMEOUT")
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/pkg/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceContext restores the caller identity forwarded by another instance through
+// httpclient.ServiceContextTransport. Requests without the header pass through untouched;
+// a header with a bad or stale signature is rejected.
+func ServiceContext(codec repositories.ServiceContextCodec, secret string, logger logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.GetHeader(constants.ServiceContextHeader)
+		if value == "" {
+			c.Next()
+			return
+		}
+
+		ctx, err := auth.DecodeServiceContext(
+			c.Request.Context(),
+			codec,
+			secret,
+			value,
+			c.GetHeader(constants.ServiceContextSignatureHeader),
+		)
+		if err != nil {
+			logger.Warn("Rejected service context from "+c.ClientIP(), err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errors.ErrInvalidServiceContext.Error()})
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
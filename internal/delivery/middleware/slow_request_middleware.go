@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/pkg/logger"
+	"clean-architecture-api/pkg/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SlowRequests logs at warn level, and counts in http_slow_requests_total, every request that takes
+// longer than threshold. The route is the registered path, so requests for different IDs add up to
+// one series. The user is read after the handler ran, so it is known on every route the auth
+// middleware guards.
+func SlowRequests(threshold time.Duration, log logger.Logger, registry *metrics.Registry) gin.HandlerFunc {
+	slowRequests := registry.NewCounterVec("http_slow_requests_total",
+		"Requests slower than SLOW_REQUEST_THRESHOLD, by method and route.", "method", "route")
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		latency := time.Since(start)
+		if latency <= threshold {
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		slowRequests.Inc(c.Request.Method, route)
+
+		entry := log.WithField("method", c.Request.Method).
+			WithField("route", route).
+			WithField("status", c.Writer.Status()).
+			WithField("latency_ms", latency.Milliseconds()).
+			WithField("threshold_ms", threshold.Milliseconds())
+		if userID, ok := constants.UserIDFromContext(c.Request.Context()); ok {
+			entry = entry.WithField("user_id", userID.String())
+		}
+		entry.Warn("Slow request: " + c.Request.Method + " " + route + " took " + latency.Round(time.Millisecond).String())
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/pkg/logger"
+	"clean-architecture-api/pkg/metrics"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type warning struct {
+	message string
+	fields  map[string]any
+}
+
+// warnRecorder keeps the warnings logged through it with their fields
+type warnRecorder struct {
+	mutex    *sync.Mutex
+	warnings *[]warning
+	fields   map[string]any
+}
+
+func newWarnRecorder() *warnRecorder {
+	return &warnRecorder{mutex: &sync.Mutex{}, warnings: &[]warning{}, fields: map[string]any{}}
+}
+
+func (l *warnRecorder) Info(args ...any)  {}
+func (l *warnRecorder) Error(args ...any) {}
+func (l *warnRecorder) Fatal(args ...any) {}
+func (l *warnRecorder) Debug(args ...any) {}
+
+func (l *warnRecorder) Warn(args ...any) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	*l.warnings = append(*l.warnings, warning{message: fmt.Sprint(args...), fields: l.fields})
+}
+
+func (l *warnRecorder) WithField(key string, value any) logger.Logger {
+	fields := make(map[string]any, len(l.fields)+1)
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	return &warnRecorder{mutex: l.mutex, warnings: l.warnings, fields: fields}
+}
+
+func (l *warnRecorder) WithError(err error) logger.Logger { return l.WithField("error", err) }
+
+func TestSlowRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := newWarnRecorder()
+	registry := metrics.NewRegistry()
+	userID := uuid.New()
+
+	router := gin.New()
+	router.Use(SlowRequests(20*time.Millisecond, log, registry))
+	router.GET("/products/:id", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(constants.WithUserID(c.Request.Context(), userID))
+		if c.Query("slow") != "" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(path string) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	serve("/products/" + uuid.NewString())
+	assert.Empty(t, *log.warnings, "fast requests are not reported")
+
+	serve("/products/" + uuid.NewString() + "?slow=1")
+	serve("/products/" + uuid.NewString() + "?slow=1")
+
+	require.Len(t, *log.warnings, 2)
+	fields := (*log.warnings)[0].fields
+	assert.Equal(t, "/products/:id", fields["route"])
+	assert.Equal(t, userID.String(), fields["user_id"])
+	assert.GreaterOrEqual(t, fields["latency_ms"], int64(50))
+	assert.Equal(t, float64(2), registry.NewCounterVec("http_slow_requests_total", "").Value(http.MethodGet, "/products/:id"))
+}
+
+func TestSlowRequests_AnonymousAndUnmatched(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := newWarnRecorder()
+	registry := metrics.NewRegistry()
+
+	router := gin.New()
+	router.Use(SlowRequests(0, log, registry))
+	router.NoRoute(func(c *gin.Context) {
+		time.Sleep(time.Millisecond)
+		c.Status(http.StatusNotFound)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing/42", nil))
+
+	require.Len(t, *log.warnings, 1)
+	assert.Equal(t, "unmatched", (*log.warnings)[0].fields["route"])
+	assert.NotContains(t, (*log.warnings)[0].fields, "user_id")
+	assert.Equal(t, float64(1), registry.NewCounterVec("http_slow_requests_total", "").Value(http.MethodGet, "unmatched"))
+}
@@ -1,23 +1,108 @@
 package constants
 
+import "time"
+
 const (
 	DefaultLimit  = 10
 	DefaultOffset = 0
 	MaxLimit      = 100
 
+	DefaultProductLimit = 20
+	DefaultUserLimit    = 10
+
+	DefaultLowStockThreshold = 5
+
+	CategoryCaseLower   = "lower"
+	CategoryCaseTitle   = "title"
+	DefaultCategoryCase = CategoryCaseLower
+
+	DefaultProductsPublic     = true
+	DefaultProductsPublicView = false
+
+	// Product read routes PRODUCTS_PUBLIC_ROUTES can name
+	ProductRouteList           = "list"
+	ProductRouteCategory       = "category"
+	ProductRouteDetail         = "detail"
+	DefaultPublicProductRoutes = ProductRouteList + "," + ProductRouteCategory + "," + ProductRouteDetail
+
+	DefaultMaxProductPrice = 1000000.0
+	MaxPriceDecimalPlaces  = 2
+
 	RoleUser  = "user"
 	RoleAdmin = "admin"
 
-	JWTAccessTokenDuration  = 15
-	JWTRefreshTokenDuration = 7
+	JWTAccessTokenDuration            = 15
+	JWTRefreshTokenDuration           = 7
+	JWTRememberMeRefreshTokenDuration = 30
+	// DefaultJWTClockSkewSeconds is how far past exp, or before nbf, a token is still accepted
+	DefaultJWTClockSkewSeconds = 30
 
 	DefaultDBHost = "localhost"
 	DefaultDBPort = "5432"
 	DefaultDBUser = "postgres"
 	DefaultDBName = "clean_architecture_api"
 
-	DefaultPort = "8080"
-	DefaultEnv  = "development"
+	// DBDriverPostgres and DBDriverMemory are the values of DB_DRIVER. The memory driver keeps users,
+	// products and stock reservations in process memory and the remaining tables in an in-memory
+	// SQLite database.
+	DBDriverPostgres = "postgres"
+	DBDriverMemory   = "memory"
+
+	DefaultDBRetryMaxAttempts    = 3
+	DefaultDBRetryInitialBackoff = 50 * time.Millisecond
+	DefaultDBRetryMaxBackoff     = 1 * time.Second
+	DefaultDBRetrySQLStates      = "08,40001,40P01"
+
+	DefaultPort     = "8080"
+	DefaultGRPCPort = "9090"
+	DefaultEnv      = "development"
+
+	// DefaultTLSCipherSuites are the forward-secret AEAD suites for TLS 1.2; TLS 1.3 suites are
+	// chosen by the Go runtime and cannot be configured
+	DefaultTLSCipherSuites = "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256," +
+		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384," +
+		"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"
+
+	DefaultHTTPReadTimeout       = 15 * time.Second
+	DefaultHTTPReadHeaderTimeout = 5 * time.Second
+	DefaultHTTPWriteTimeout      = 30 * time.Second
+	DefaultHTTPIdleTimeout       = 60 * time.Second
+	DefaultSlowRequestThreshold  = 1 * time.Second
+	DefaultRequestTimeout        = 30 * time.Second
+	DefaultMaxRequestTimeout     = 30 * time.Second
+
+	ReadinessCheckTimeout = 2 * time.Second
+
+	DefaultCORSAllowedMethods = "GET,POST,PUT,PATCH,DELETE,OPTIONS"
+	DefaultCORSAllowedHeaders = "Authorization,Content-Type,X-Request-Timeout"
+	DefaultCORSMaxAge         = 10 * time.Minute
+
+	DefaultCompressionMinSize      = 1024
+	DefaultCompressionContentTypes = "application/json,application/problem+json,text/plain,text/html,text/css,application/javascript"
+
+	DefaultMaxRequestBodyBytes = 1 << 20
+	DefaultMaxImportBodyBytes  = 10 << 20
+	DefaultMaxBulkItems        = 1000
+
+	DefaultRateLimitAuthenticatedPerMinute = 0
+	DefaultRateLimitAnonymousPerMinute     = 0
+	DefaultRateLimitPublicReadsPerMinute   = 0
+
+	DefaultChallengeTimeout = 5 * time.Second
+
+	EmailChangeTokenLifetime = 24 * time.Hour
+
+	ReservationStatusPending   = "pending"
+	ReservationStatusConfirmed = "confirmed"
+	ReservationStatusReleased  = "released"
+
+	MaxReservationTTL               = 1 * time.Hour
+	DefaultReservationSweepInterval = 30 * time.Second
+
+	DefaultAuditRetentionDays     = 90
+	DefaultAuditRetentionInterval = 1 * time.Hour
+	AuditRetentionBatchSize       = 1000
 
-	SystemUserID = "00000000-0000-0000-0000-000000000000"
+	DefaultSystemUserID = "ffffffff-ffff-ffff-ffff-ffffffffffff"
+	SystemUserEmail     = "system@system.invalid"
 )
@@ -0,0 +1,214 @@
+package constants
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// contextKey is the type of every value this package stores in a context. It is unexported so
+// no other package can collide with these keys; use the With* and *FromContext functions.
+type contextKey string
+
+const (
+	contextUserID         contextKey = "user_id"
+	contextUserRole       contextKey = "user_role"
+	contextUserEmail      contextKey = "user_email"
+	contextClientIP       contextKey = "client_ip"
+	contextImpersonatorID contextKey = "impersonator_id"
+	contextResourceOwner  contextKey = "resource_owner_id"
+	contextPrimaryRead    contextKey = "primary_read"
+	contextPolicyExplain  contextKey = "policy_explain"
+	contextChallenge      contextKey = "challenge"
+	contextClientInfo     contextKey = "client_info"
+	contextSortOrder      contextKey = "sort_order"
+	contextForwardedAt    contextKey = "forwarded_at"
+)
+
+// WithUserID records the authenticated user.
+func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextUserID, userID)
+}
+
+// UserIDFromContext returns the authenticated user ID stored by WithUserID.
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	if ctx == nil {
+		return uuid.Nil, false
+	}
+	userID, ok := ctx.Value(contextUserID).(uuid.UUID)
+	return userID, ok
+}
+
+// WithUserRole records the authenticated user's role.
+func WithUserRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, contextUserRole, role)
+}
+
+// UserRoleFromContext returns the authenticated user role stored by WithUserRole.
+func UserRoleFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	role, ok := ctx.Value(contextUserRole).(string)
+	return role, ok && role != ""
+}
+
+// WithUserEmail records the authenticated user's email.
+func WithUserEmail(ctx context.Context, email string) context.Context {
+	return context.WithValue(ctx, contextUserEmail, email)
+}
+
+// UserEmailFromContext returns the email stored by WithUserEmail.
+func UserEmailFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	email, ok := ctx.Value(contextUserEmail).(string)
+	return email, ok && email != ""
+}
+
+// WithClientIP records the address the request came from.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, contextClientIP, ip)
+}
+
+// ClientIPFromContext returns the address stored by WithClientIP.
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	ip, ok := ctx.Value(contextClientIP).(string)
+	return ip, ok && ip != ""
+}
+
+// WithImpersonator records the admin behind an impersonation token.
+func WithImpersonator(ctx context.Context, impersonatorID uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextImpersonatorID, impersonatorID)
+}
+
+// ImpersonatorFromContext returns the admin acting on behalf of the authenticated user, if any.
+func ImpersonatorFromContext(ctx context.Context) (uuid.UUID, bool) {
+	if ctx == nil {
+		return uuid.Nil, false
+	}
+	impersonatorID, ok := ctx.Value(contextImpersonatorID).(uuid.UUID)
+	return impersonatorID, ok
+}
+
+// WithForwardedAt records when another instance signed the caller identity it forwarded.
+func WithForwardedAt(ctx context.Context, forwardedAt time.Time) context.Context {
+	return context.WithValue(ctx, contextForwardedAt, forwardedAt)
+}
+
+// ForwardedAtFromContext returns the time stored by WithForwardedAt.
+func ForwardedAtFromContext(ctx context.Context) (time.Time, bool) {
+	if ctx == nil {
+		return time.Time{}, false
+	}
+	forwardedAt, ok := ctx.Value(contextForwardedAt).(time.Time)
+	return forwardedAt, ok
+}
+
+// WithResourceOwner records the owner of the resource a request is about to access.
+func WithResourceOwner(ctx context.Context, ownerID uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextResourceOwner, ownerID)
+}
+
+// ResourceOwnerFromContext returns the owner stored by WithResourceOwner.
+func ResourceOwnerFromContext(ctx context.Context) (uuid.UUID, bool) {
+	if ctx == nil {
+		return uuid.Nil, false
+	}
+	ownerID, ok := ctx.Value(contextResourceOwner).(uuid.UUID)
+	return ownerID, ok
+}
+
+// WithPrimaryRead marks ctx so repository reads go to the primary database instead of a replica.
+func WithPrimaryRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextPrimaryRead, true)
+}
+
+// PrimaryReadFromContext reports whether ctx was marked with WithPrimaryRead.
+func PrimaryReadFromContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	primary, _ := ctx.Value(contextPrimaryRead).(bool)
+	return primary
+}
+
+// WithPolicyExplain marks ctx so the policy engine records why each statement did or did not match.
+func WithPolicyExplain(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextPolicyExplain, true)
+}
+
+// PolicyExplainFromContext reports whether ctx was marked with WithPolicyExplain.
+func PolicyExplainFromContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	explain, _ := ctx.Value(contextPolicyExplain).(bool)
+	return explain
+}
+
+// SortOrder is a column to order list results by. Callers must whitelist Field since it is
+// used as a column name.
+type SortOrder struct {
+	Field      string
+	Descending bool
+}
+
+// WithSortOrder asks repository list queries to order by sort instead of their default order.
+func WithSortOrder(ctx context.Context, sort SortOrder) context.Context {
+	return context.WithValue(ctx, contextSortOrder, sort)
+}
+
+// SortOrderFromContext returns the order stored by WithSortOrder.
+func SortOrderFromContext(ctx context.Context) (SortOrder, bool) {
+	if ctx == nil {
+		return SortOrder{}, false
+	}
+	sort, ok := ctx.Value(contextSortOrder).(SortOrder)
+	return sort, ok && sort.Field != ""
+}
+
+// Challenge is the bot-check response a client sent along with a register or login request.
+type Challenge struct {
+	Response string
+	RemoteIP string
+}
+
+// WithChallenge hands the client's challenge response to the auth use case.
+func WithChallenge(ctx context.Context, challenge Challenge) context.Context {
+	return context.WithValue(ctx, contextChallenge, challenge)
+}
+
+// ChallengeFromContext returns the challenge stored by WithChallenge.
+func ChallengeFromContext(ctx context.Context) (Challenge, bool) {
+	if ctx == nil {
+		return Challenge{}, false
+	}
+	challenge, ok := ctx.Value(contextChallenge).(Challenge)
+	return challenge, ok
+}
+
+// ClientInfo identifies the client that sent a request, for display in the session list.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// WithClientInfo hands the client's address and user agent to the auth use case.
+func WithClientInfo(ctx context.Context, client ClientInfo) context.Context {
+	return context.WithValue(ctx, contextClientInfo, client)
+}
+
+// ClientInfoFromContext returns the client stored by WithClientInfo.
+func ClientInfoFromContext(ctx context.Context) (ClientInfo, bool) {
+	if ctx == nil {
+		return ClientInfo{}, false
+	}
+	client, ok := ctx.Value(contextClientInfo).(ClientInfo)
+	return client, ok
+}
@@ -0,0 +1,21 @@
+package constants
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityFromContext_WrongTypes(t *testing.T) {
+	// Values of the wrong type under the identity keys read as missing rather than panicking
+	ctx := context.WithValue(context.Background(), contextUserID, "not-a-uuid")
+	ctx = context.WithValue(ctx, contextUserRole, 42)
+
+	userID, ok := UserIDFromContext(ctx)
+	assert.False(t, ok)
+	assert.Equal(t, uuid.Nil, userID)
+	_, ok = UserRoleFromContext(ctx)
+	assert.False(t, ok)
+}
@@ -0,0 +1,27 @@
+package constants
+
+import "time"
+
+const (
+	EventUserCreated     = "user.created"
+	EventUserUpdated     = "user.updated"
+	EventUserDeleted     = "user.deleted"
+	EventUserDeactivated = "user.deactivated"
+	EventUserApproved    = "user.approved"
+	EventProductCreated  = "product.created"
+	EventProductUpdated  = "product.updated"
+	EventProductDeleted  = "product.deleted"
+	EventProductRestored = "product.restored"
+
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookIDHeader        = "X-Webhook-ID"
+
+	DefaultWebhookMaxAttempts    = 5
+	DefaultWebhookInitialBackoff = 1 * time.Second
+	DefaultWebhookTimeout        = 5 * time.Second
+	DefaultWebhookQueueSize      = 100
+
+	DefaultOutboxPollInterval = 5 * time.Second
+	DefaultOutboxBatchSize    = 50
+)
@@ -1,10 +1,10 @@
 package constants
 
-type ContextKey string
-
 const (
 	ResourceUser    = "user"
 	ResourceProduct = "product"
+	// ResourceActivity names the caller's audit trail for pagination limits; it is not a policy resource
+	ResourceActivity = "activity"
 
 	ActionCreate = "create"
 	ActionRead   = "read"
@@ -12,6 +12,16 @@ const (
 	ActionDelete = "delete"
 	ActionList   = "list"
 
+	// ActionRestore is recorded in the audit log when a soft-deleted entity is brought back
+	ActionRestore = "restore"
+
+	// ActionImpersonate is recorded in the audit log when an admin acts as another user
+	ActionImpersonate = "impersonate"
+	// ActionRevokeTokens is recorded in the audit log when an admin signs a user out everywhere
+	ActionRevokeTokens = "revoke_tokens"
+	// ActionApprove is recorded in the audit log when an admin approves a pending registration
+	ActionApprove = "approve"
+
 	PermissionUserCreate = "user:create"
 	PermissionUserRead   = "user:read"
 	PermissionUserUpdate = "user:update"
@@ -27,8 +37,44 @@ const (
 	PolicyEffectAllow = "allow"
 	PolicyEffectDeny  = "deny"
 
-	ContextUserID    = ContextKey("user_id")
-	ContextUserRole  = ContextKey("user_role")
-	ContextUserEmail = ContextKey("user_email")
-	ContextClientIP  = ContextKey("client_ip")
+	DefaultPolicyEffect = PolicyEffectDeny
+
+	// Reasons reported in PermissionResponse.Reason
+	PolicyReasonInvalidRequest = "invalid_request"
+	PolicyReasonNoPolicies     = "no_policies"
+	PolicyReasonNoMatch        = "no_match"
+	PolicyReasonExplicitDeny   = "explicit_deny"
+	PolicyReasonExplicitAllow  = "explicit_allow"
+
+	// PolicyExplainKey holds the []entities.StatementTrace in PermissionResponse.Context
+	PolicyExplainKey = "explain"
+
+	// Values of the "event" field on policy evaluation log lines; alerting keys off these
+	PolicyEventDenied  = "policy_denied"
+	PolicyEventAllowed = "policy_allowed"
+
+	DefaultPolicyAllowLogSample = 1
+
+	PolicyChangeAdded      = "added"
+	PolicyChangeRemoved    = "removed"
+	PolicyChangeImported   = "imported"
+	PolicyChangeRolledBack = "rolled_back"
+	PolicyChangeReloaded   = "reloaded"
+
+	DefaultPolicyChangeChannel = "policy-changes"
+
+	PolicyImportCreated = "created"
+	PolicyImportUpdated = "updated"
+	PolicyImportInvalid = "invalid"
+
+	// Attributes of the caller that policy conditions can name. They are also the field names
+	// of a serialized service context.
+	PolicyContextUserID        = "user_id"
+	PolicyContextUserRole      = "user_role"
+	PolicyContextUserEmail     = "user_email"
+	PolicyContextClientIP      = "client_ip"
+	PolicyContextResourceOwner = "resource_owner_id"
+
+	ServiceContextHeader          = "X-Service-Context"
+	ServiceContextSignatureHeader = "X-Service-Context-Signature"
 )
@@ -0,0 +1,21 @@
+package constants
+
+import "github.com/google/uuid"
+
+var systemUserID = uuid.MustParse(DefaultSystemUserID)
+
+// SystemUserID identifies internal operations, such as registration and token refresh, in audit
+// logs and created_by columns. It is DefaultSystemUserID unless SYSTEM_USER_ID configures another.
+func SystemUserID() uuid.UUID {
+	return systemUserID
+}
+
+// SetSystemUserID replaces the system identity. Call it once at startup, before serving requests.
+func SetSystemUserID(id uuid.UUID) {
+	systemUserID = id
+}
+
+// IsSystemUser reports whether userID is the system identity
+func IsSystemUser(userID uuid.UUID) bool {
+	return userID == systemUserID
+}
@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditLogEntry struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index:idx_audit_user_time"`
+	Action    string    `json:"action" gorm:"not null"`
+	Resource  string    `json:"resource" gorm:"not null"`
+	EntityID  uuid.UUID `json:"entity_id" gorm:"type:uuid;index:idx_audit_entity_time"`
+	Timestamp time.Time `json:"timestamp" gorm:"not null;index:idx_audit_user_time;index:idx_audit_entity_time"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+}
+
+func (ale *AuditLogEntry) BeforeCreate(_ *gorm.DB) error {
+	if ale.ID == uuid.Nil {
+		ale.ID = uuid.New()
+	}
+	return nil
+}
+
+// ActivityEntry is an audit entry as shown to the user it belongs to. Resource is the public
+// resource name, without the internal action suffix.
+type ActivityEntry struct {
+	ID        uuid.UUID  `json:"id"`
+	Action    string     `json:"action"`
+	Resource  string     `json:"resource"`
+	EntityID  *uuid.UUID `json:"entity_id,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+// ChangeEntry is an audit entry as shown in the change history of the entity it concerns
+type ChangeEntry struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Action    string    `json:"action"`
+	Timestamp time.Time `json:"timestamp"`
+}
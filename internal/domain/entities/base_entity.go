@@ -12,6 +12,32 @@ type BaseEntity struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	CreatedBy uuid.UUID      `json:"created_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID      `json:"updated_by" gorm:"type:uuid"`
+}
+
+// Auditable entities record who created and last updated them. The base repository fills both
+// from the acting user on Create and Update.
+type Auditable interface {
+	SetCreatedBy(userID uuid.UUID)
+	SetUpdatedBy(userID uuid.UUID)
+}
+
+// Identifiable entities expose their ID, so audit entries can point at the entity they concern
+type Identifiable interface {
+	GetID() uuid.UUID
+}
+
+func (e *BaseEntity) GetID() uuid.UUID {
+	return e.ID
+}
+
+func (e *BaseEntity) SetCreatedBy(userID uuid.UUID) {
+	e.CreatedBy = userID
+}
+
+func (e *BaseEntity) SetUpdatedBy(userID uuid.UUID) {
+	e.UpdatedBy = userID
 }
 
 func (e *BaseEntity) BeforeCreate(_ *gorm.DB) error {
@@ -12,6 +12,8 @@ type BaseSQLiteEntity struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	CreatedBy string         `json:"created_by" gorm:"type:text"`
+	UpdatedBy string         `json:"updated_by" gorm:"type:text"`
 }
 
 func (e *BaseSQLiteEntity) BeforeCreate(_ *gorm.DB) error {
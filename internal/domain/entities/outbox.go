@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is a domain event stored in the same transaction as the change that produced it,
+// waiting to be delivered by the outbox worker
+type OutboxEvent struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	EventType string     `json:"event_type" gorm:"not null;index"`
+	Payload   string     `json:"payload" gorm:"type:text;not null"`
+	Attempts  int        `json:"attempts" gorm:"not null;default:0"`
+	LastError string     `json:"last_error,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime;index"`
+	SentAt    *time.Time `json:"sent_at,omitempty" gorm:"index"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
+
+func NewOutboxEvent(event DomainEvent) (*OutboxEvent, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxEvent{
+		ID:        event.ID,
+		EventType: event.Type,
+		Payload:   string(payload),
+	}, nil
+}
+
+// DomainEvent decodes the stored payload; Data is left as generic JSON
+func (o *OutboxEvent) DomainEvent() (DomainEvent, error) {
+	var event DomainEvent
+	err := json.Unmarshal([]byte(o.Payload), &event)
+	return event, err
+}
@@ -2,6 +2,11 @@ package entities
 
 import (
 	"clean-architecture-api/internal/domain/constants"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,12 +27,20 @@ type PolicyStatement struct {
 
 type PolicyDocument struct {
 	ID         uuid.UUID         `json:"id" gorm:"type:uuid;primary_key"`
-	Name       string            `json:"name" gorm:"not null;unique"`
-	Version    string            `json:"version" gorm:"not null;default:'1.0'"`
+	Name       string            `json:"name" gorm:"not null;uniqueIndex:idx_policy_name_version"`
+	Version    string            `json:"version" gorm:"not null;default:'1.0';uniqueIndex:idx_policy_name_version"`
 	Statements []PolicyStatement `json:"statements" gorm:"foreignKey:PolicyID"`
 	IsActive   bool              `json:"is_active" gorm:"default:true"`
 	CreatedAt  time.Time         `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt  time.Time         `json:"updated_at" gorm:"autoUpdateTime"`
+	// CreatedBy is who created the policy and UpdatedBy who wrote this version; both are nil for
+	// policies seeded at startup
+	CreatedBy uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	UpdatedBy uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+
+	// BuiltinRevision is the revision of the built-in definition this version was seeded from;
+	// 0 for versions written through the API
+	BuiltinRevision int `json:"-" gorm:"not null;default:0"`
 }
 
 type PermissionRequest struct {
@@ -46,6 +59,56 @@ type PermissionResponse struct {
 	Context  map[string]interface{} `json:"context,omitempty"`
 }
 
+// StatementTrace records how one policy statement fared against a permission request. It is only
+// collected in explain mode.
+type StatementTrace struct {
+	Policy            string `json:"policy"`
+	StatementID       string `json:"statement_id"`
+	Effect            string `json:"effect"`
+	Matched           bool   `json:"matched"`
+	PrincipalMatched  bool   `json:"principal_matched"`
+	ActionMatched     bool   `json:"action_matched"`
+	ResourceMatched   bool   `json:"resource_matched"`
+	ConditionsMatched bool   `json:"conditions_matched"`
+	Reason            string `json:"reason"`
+}
+
+// PolicyChangeEvent announces that a policy was created, updated or removed on some instance
+type PolicyChangeEvent struct {
+	PolicyID uuid.UUID `json:"policy_id"`
+	Action   string    `json:"action"`
+	SourceID string    `json:"source_id"`
+}
+
+// PolicySet is the JSON document used to export and import policies in bulk
+type PolicySet struct {
+	ExportedAt time.Time         `json:"exported_at,omitempty"`
+	Policies   []*PolicyDocument `json:"policies"`
+}
+
+// UserPolicies lists the policies that apply to a user through their role
+type UserPolicies struct {
+	UserID   uuid.UUID         `json:"user_id"`
+	Role     string            `json:"role"`
+	Policies []*PolicyDocument `json:"policies"`
+}
+
+// PolicyImportResult reports what an import did, or would have done, with one policy
+type PolicyImportResult struct {
+	Name   string    `json:"name"`
+	ID     uuid.UUID `json:"id,omitempty"`
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+}
+
+func NewPolicyImportResult(policy *PolicyDocument, status string) PolicyImportResult {
+	return PolicyImportResult{
+		Name:   policy.Name,
+		ID:     policy.ID,
+		Status: status,
+	}
+}
+
 type Permission struct {
 	Resource   string `json:"resource"`
 	Action     string `json:"action"`
@@ -67,6 +130,64 @@ func (pd *PolicyDocument) BeforeCreate(_ *gorm.DB) error {
 	return nil
 }
 
+// Clone returns a copy of the policy that shares no slices or maps with the original
+func (pd *PolicyDocument) Clone() *PolicyDocument {
+	clone := *pd
+	clone.Statements = make([]PolicyStatement, len(pd.Statements))
+	for i, statement := range pd.Statements {
+		if statement.Conditions != nil {
+			conditions := make(map[string]interface{}, len(statement.Conditions))
+			for key, value := range statement.Conditions {
+				conditions[key] = value
+			}
+			statement.Conditions = conditions
+		}
+		clone.Statements[i] = statement
+	}
+	return &clone
+}
+
+// SameStatements reports whether both policies hold the same statements, ignoring statement IDs
+// and order
+func (pd *PolicyDocument) SameStatements(other *PolicyDocument) bool {
+	keys, otherKeys := pd.statementKeys(), other.statementKeys()
+	if len(keys) != len(otherKeys) {
+		return false
+	}
+	for i := range keys {
+		if keys[i] != otherKeys[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (pd *PolicyDocument) statementKeys() []string {
+	keys := make([]string, len(pd.Statements))
+	for i, statement := range pd.Statements {
+		conditions := statement.Conditions
+		if len(conditions) == 0 {
+			conditions = nil
+		}
+		key, _ := json.Marshal([]interface{}{statement.Effect, statement.Principal, statement.Action, statement.Resource, conditions})
+		keys[i] = string(key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NextPolicyVersion returns the version that follows the highest major version in versions
+func NextPolicyVersion(versions []string) string {
+	highest := 0
+	for _, version := range versions {
+		major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+		if err == nil && major > highest {
+			highest = major
+		}
+	}
+	return fmt.Sprintf("%d.0", highest+1)
+}
+
 func (ps *PolicyStatement) IsValid() bool {
 	return ps.Effect == constants.PolicyEffectAllow || ps.Effect == constants.PolicyEffectDeny
 }
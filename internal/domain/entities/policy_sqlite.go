@@ -25,10 +25,12 @@ func (PolicyStatementSQLite) TableName() string {
 
 type PolicyDocumentSQLite struct {
 	BaseSQLiteEntity
-	Name       string                  `json:"name" gorm:"not null;unique"`
-	Version    string                  `json:"version" gorm:"not null;default:1.0"`
+	Name       string                  `json:"name" gorm:"not null;uniqueIndex:idx_policy_name_version"`
+	Version    string                  `json:"version" gorm:"not null;default:1.0;uniqueIndex:idx_policy_name_version"`
 	Statements []PolicyStatementSQLite `json:"statements" gorm:"foreignKey:PolicyID"`
 	IsActive   bool                    `json:"is_active" gorm:"default:true"`
+
+	BuiltinRevision int `json:"-" gorm:"not null;default:0"`
 }
 
 func (PolicyDocumentSQLite) TableName() string {
@@ -37,6 +39,8 @@ func (PolicyDocumentSQLite) TableName() string {
 
 func (p *PolicyDocumentSQLite) ToPolicyDocument() *PolicyDocument {
 	id, _ := uuid.Parse(p.ID)
+	createdBy, _ := uuid.Parse(p.CreatedBy)
+	updatedBy, _ := uuid.Parse(p.UpdatedBy)
 
 	statements := make([]PolicyStatement, len(p.Statements))
 	for i, stmt := range p.Statements {
@@ -64,13 +68,16 @@ func (p *PolicyDocumentSQLite) ToPolicyDocument() *PolicyDocument {
 	}
 
 	return &PolicyDocument{
-		ID:         id,
-		Name:       p.Name,
-		Version:    p.Version,
-		Statements: statements,
-		IsActive:   p.IsActive,
-		CreatedAt:  p.CreatedAt,
-		UpdatedAt:  p.UpdatedAt,
+		ID:              id,
+		Name:            p.Name,
+		Version:         p.Version,
+		Statements:      statements,
+		IsActive:        p.IsActive,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+		CreatedBy:       createdBy,
+		UpdatedBy:       updatedBy,
+		BuiltinRevision: p.BuiltinRevision,
 	}
 }
 
@@ -97,10 +104,13 @@ func FromPolicyDocument(policy *PolicyDocument) *PolicyDocumentSQLite {
 			ID:        policy.ID.String(),
 			CreatedAt: policy.CreatedAt,
 			UpdatedAt: policy.UpdatedAt,
+			CreatedBy: policy.CreatedBy.String(),
+			UpdatedBy: policy.UpdatedBy.String(),
 		},
-		Name:       policy.Name,
-		Version:    policy.Version,
-		Statements: statements,
-		IsActive:   policy.IsActive,
+		Name:            policy.Name,
+		Version:         policy.Version,
+		Statements:      statements,
+		IsActive:        policy.IsActive,
+		BuiltinRevision: policy.BuiltinRevision,
 	}
 }
@@ -3,18 +3,45 @@ package entities
 import (
 	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/validators"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Product struct {
 	BaseEntity
-	Name        string    `json:"name" gorm:"not null"`
+	Name        string  `json:"name" gorm:"not null"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price" gorm:"not null"`
+	Stock       int     `json:"stock" gorm:"default:0"`
+	Category    string  `json:"category"`
+}
+
+// PublicProduct is the catalog view of a product served to anonymous callers. It leaves out who
+// created and last updated the product.
+type PublicProduct struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
 	Description string    `json:"description"`
-	Price       float64   `json:"price" gorm:"not null"`
-	Stock       int       `json:"stock" gorm:"default:0"`
+	Price       float64   `json:"price"`
+	Stock       int       `json:"stock"`
 	Category    string    `json:"category"`
-	CreatedBy   uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Public returns the catalog view of p
+func (p *Product) Public() *PublicProduct {
+	return &PublicProduct{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       p.Price,
+		Stock:       p.Stock,
+		Category:    p.Category,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
 }
 
 func (Product) TableName() string {
@@ -14,7 +14,6 @@ type ProductSQLite struct {
 	Price       float64 `json:"price" gorm:"not null"`
 	Stock       int     `json:"stock" gorm:"default:0"`
 	Category    string  `json:"category"`
-	CreatedBy   string  `json:"created_by" gorm:"type:text"`
 }
 
 func (ProductSQLite) TableName() string {
@@ -37,19 +36,21 @@ func (p *ProductSQLite) Validate() error {
 func (p *ProductSQLite) ToProduct() *Product {
 	id, _ := uuid.Parse(p.ID)
 	createdBy, _ := uuid.Parse(p.CreatedBy)
+	updatedBy, _ := uuid.Parse(p.UpdatedBy)
 	product := &Product{
 		BaseEntity: BaseEntity{
 			ID:        id,
 			CreatedAt: p.CreatedAt,
 			UpdatedAt: p.UpdatedAt,
 			DeletedAt: p.DeletedAt,
+			CreatedBy: createdBy,
+			UpdatedBy: updatedBy,
 		},
 		Name:        p.Name,
 		Description: p.Description,
 		Price:       p.Price,
 		Stock:       p.Stock,
 		Category:    p.Category,
-		CreatedBy:   createdBy,
 	}
 	return product
 }
@@ -61,12 +62,13 @@ func FromProduct(product *Product) *ProductSQLite {
 			CreatedAt: product.CreatedAt,
 			UpdatedAt: product.UpdatedAt,
 			DeletedAt: product.DeletedAt,
+			CreatedBy: product.CreatedBy.String(),
+			UpdatedBy: product.UpdatedBy.String(),
 		},
 		Name:        product.Name,
 		Description: product.Description,
 		Price:       product.Price,
 		Stock:       product.Stock,
 		Category:    product.Category,
-		CreatedBy:   product.CreatedBy.String(),
 	}
 }
@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken records an issued refresh token by its jti. Tokens obtained by rotating one
+// another share a FamilyID, so presenting a token that was already used can revoke the chain.
+// Only a SHA-256 hash of the token itself is stored.
+type RefreshToken struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	FamilyID  uuid.UUID  `json:"family_id" gorm:"type:uuid;not null;index"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty" gorm:"type:uuid"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	TokenHash string     `json:"-" gorm:"size:64"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	// The login that started the family; rotated tokens copy them from their parent
+	SessionStartedAt time.Time `json:"session_started_at"`
+	IPAddress        string    `json:"ip_address" gorm:"size:64"`
+	UserAgent        string    `json:"user_agent" gorm:"size:512"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// Session is one login as its owner sees it. Its ID is the refresh token family, so it stays the
+// same while the token rotates. LastUsedAt is when the current token was issued, which is the
+// login or the latest refresh.
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+}
+
+// Session describes the login this token currently represents. Tokens stored before sessions
+// were tracked have no start time and report their own issue time instead.
+func (t *RefreshToken) Session() *Session {
+	createdAt := t.SessionStartedAt
+	if createdAt.IsZero() {
+		createdAt = t.CreatedAt
+	}
+	return &Session{
+		ID:         t.FamilyID,
+		CreatedAt:  createdAt,
+		LastUsedAt: t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		IPAddress:  t.IPAddress,
+		UserAgent:  t.UserAgent,
+	}
+}
@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reservation holds stock for a checkout. Reserving takes the quantity out of the product's
+// stock right away; confirming keeps it out, while releasing or letting it expire puts it back.
+type Reservation struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	ProductID uuid.UUID `json:"product_id" gorm:"type:uuid;not null;index"`
+	Quantity  int       `json:"quantity" gorm:"not null"`
+	Status    string    `json:"status" gorm:"not null;index:idx_reservations_status_expires"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index:idx_reservations_status_expires"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (Reservation) TableName() string {
+	return "product_reservations"
+}
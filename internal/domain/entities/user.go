@@ -3,16 +3,28 @@ package entities
 import (
 	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/validators"
+	"time"
 )
 
 type User struct {
 	BaseEntity
-	Email     string `json:"email" gorm:"uniqueIndex;not null"`
+	Email     string `json:"email" gorm:"uniqueIndex:idx_users_email_active,where:deleted_at IS NULL;not null"`
 	Password  string `json:"-" gorm:"not null"`
 	FirstName string `json:"first_name" gorm:"not null"`
 	LastName  string `json:"last_name" gorm:"not null"`
 	Role      string `json:"role" gorm:"default:user"`
 	IsActive  bool   `json:"is_active" gorm:"default:true"`
+	// ApprovalPending marks a registration an admin has not approved yet; the user stays inactive until then
+	ApprovalPending bool `json:"approval_pending" gorm:"not null;default:false;index"`
+
+	// PendingEmail waits for confirmation from its owner; Email stays in use until then.
+	// Only a hash of the confirmation token is stored.
+	PendingEmail         *string    `json:"pending_email,omitempty"`
+	EmailChangeTokenHash string     `json:"-"`
+	EmailChangeExpiresAt *time.Time `json:"-"`
+
+	// TokensValidAfter rejects every token issued before it; see RevokeTokens
+	TokensValidAfter *time.Time `json:"-"`
 }
 
 func (User) TableName() string {
@@ -34,3 +46,15 @@ func (u *User) Validate() error {
 func (u *User) IsAdmin() bool {
 	return u.Role == constants.RoleAdmin
 }
+
+// RevokeTokens invalidates every token issued to the user so far. JWT iat has second precision, so
+// the cutoff is truncated to the second; a token issued later in that second must stay valid.
+func (u *User) RevokeTokens(now time.Time) {
+	cutoff := now.Truncate(time.Second)
+	u.TokensValidAfter = &cutoff
+}
+
+// TokenRevoked reports whether a token issued at issuedAt predates the last RevokeTokens
+func (u *User) TokenRevoked(issuedAt time.Time) bool {
+	return u.TokensValidAfter != nil && issuedAt.Before(*u.TokensValidAfter)
+}
@@ -3,18 +3,28 @@ package entities
 import (
 	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/validators"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type UserSQLite struct {
 	BaseSQLiteEntity
-	Email     string `json:"email" gorm:"uniqueIndex;not null"`
-	Password  string `json:"-" gorm:"not null"`
-	FirstName string `json:"first_name" gorm:"not null"`
-	LastName  string `json:"last_name" gorm:"not null"`
-	Role      string `json:"role" gorm:"default:user"`
-	IsActive  bool   `json:"is_active" gorm:"default:true"`
+	Email           string `json:"email" gorm:"uniqueIndex:idx_users_email_active,where:deleted_at IS NULL;not null"`
+	Password        string `json:"-" gorm:"not null"`
+	FirstName       string `json:"first_name" gorm:"not null"`
+	LastName        string `json:"last_name" gorm:"not null"`
+	Role            string `json:"role" gorm:"default:user"`
+	IsActive        bool   `json:"is_active" gorm:"default:true"`
+	ApprovalPending bool   `json:"approval_pending" gorm:"not null;default:false;index"`
+
+	// PendingEmail waits for confirmation from its owner; Email stays in use until then.
+	// Only a hash of the confirmation token is stored.
+	PendingEmail         *string    `json:"pending_email,omitempty"`
+	EmailChangeTokenHash string     `json:"-"`
+	EmailChangeExpiresAt *time.Time `json:"-"`
+
+	TokensValidAfter *time.Time `json:"-"`
 }
 
 func (UserSQLite) TableName() string {
@@ -46,12 +56,16 @@ func (u *UserSQLite) IsAdmin() bool {
 
 func (u *UserSQLite) ToUser() *User {
 	id, _ := uuid.Parse(u.ID)
+	createdBy, _ := uuid.Parse(u.CreatedBy)
+	updatedBy, _ := uuid.Parse(u.UpdatedBy)
 	user := &User{
 		BaseEntity: BaseEntity{
 			ID:        id,
 			CreatedAt: u.CreatedAt,
 			UpdatedAt: u.UpdatedAt,
 			DeletedAt: u.DeletedAt,
+			CreatedBy: createdBy,
+			UpdatedBy: updatedBy,
 		},
 		Email:     u.Email,
 		Password:  u.Password,
@@ -59,6 +73,14 @@ func (u *UserSQLite) ToUser() *User {
 		LastName:  u.LastName,
 		Role:      u.Role,
 		IsActive:  u.IsActive,
+
+		ApprovalPending: u.ApprovalPending,
+
+		PendingEmail:         u.PendingEmail,
+		EmailChangeTokenHash: u.EmailChangeTokenHash,
+		EmailChangeExpiresAt: u.EmailChangeExpiresAt,
+
+		TokensValidAfter: u.TokensValidAfter,
 	}
 	return user
 }
@@ -70,6 +92,8 @@ func FromUser(user *User) *UserSQLite {
 			CreatedAt: user.CreatedAt,
 			UpdatedAt: user.UpdatedAt,
 			DeletedAt: user.DeletedAt,
+			CreatedBy: user.CreatedBy.String(),
+			UpdatedBy: user.UpdatedBy.String(),
 		},
 		Email:     user.Email,
 		Password:  user.Password,
@@ -77,5 +101,13 @@ func FromUser(user *User) *UserSQLite {
 		LastName:  user.LastName,
 		Role:      user.Role,
 		IsActive:  user.IsActive,
+
+		ApprovalPending: user.ApprovalPending,
+
+		PendingEmail:         user.PendingEmail,
+		EmailChangeTokenHash: user.EmailChangeTokenHash,
+		EmailChangeExpiresAt: user.EmailChangeExpiresAt,
+
+		TokensValidAfter: user.TokensValidAfter,
 	}
 }
@@ -15,6 +15,7 @@ const (
 	CategoryUnauthorized ErrorCategory = "unauthorized"
 	CategoryForbidden    ErrorCategory = "forbidden"
 	CategoryConflict     ErrorCategory = "conflict"
+	CategoryRateLimited  ErrorCategory = "rate_limited"
 	CategoryInternal     ErrorCategory = "internal"
 	CategoryDatabase     ErrorCategory = "database"
 )
@@ -83,6 +84,15 @@ func NewConflictError(code, message string) *AppError {
 	}
 }
 
+func NewRateLimitedError(code, message string) *AppError {
+	return &AppError{
+		Category: CategoryRateLimited,
+		Code:     code,
+		Message:  message,
+		Status:   http.StatusTooManyRequests,
+	}
+}
+
 func NewInternalError(code, message string, cause error) *AppError {
 	return &AppError{
 		Category: CategoryInternal,
@@ -104,24 +114,45 @@ func NewDatabaseError(code, message string, cause error) *AppError {
 }
 
 var (
-	ErrInvalidRequest      = NewValidationError("INVALID_REQUEST", "invalid request")
-	ErrInvalidCredentials  = NewValidationError("INVALID_CREDENTIALS", "invalid credentials")
-	ErrInvalidEmail        = NewValidationError("INVALID_EMAIL", "invalid email format")
-	ErrInvalidID           = NewValidationError("INVALID_ID", "invalid ID")
-	ErrInvalidUserID       = NewValidationError("INVALID_USER_ID", "invalid user ID")
-	ErrInvalidProductID    = NewValidationError("INVALID_PRODUCT_ID", "invalid product ID")
-	ErrEmailIsRequired     = NewValidationError("EMAIL_REQUIRED", "email is required")
-	ErrFirstNameIsRequired = NewValidationError("FIRST_NAME_REQUIRED", "first name is required")
-	ErrLastNameIsRequired  = NewValidationError("LAST_NAME_REQUIRED", "last name is required")
-	ErrRoleIsRequired      = NewValidationError("ROLE_REQUIRED", "role is required")
-	ErrInvalidRole         = NewValidationError("INVALID_ROLE", "invalid role")
-	ErrCategoryRequired    = NewValidationError("CATEGORY_REQUIRED", "category is required")
-	ErrPasswordRequired    = NewValidationError("PASSWORD_REQUIRED", "password is required")
-	ErrPasswordTooShort    = NewValidationError("PASSWORD_TOO_SHORT", "password must be at least 6 characters")
+	ErrInvalidRequest        = NewValidationError("INVALID_REQUEST", "invalid request")
+	ErrInvalidCredentials    = NewValidationError("INVALID_CREDENTIALS", "invalid credentials")
+	ErrInvalidEmail          = NewValidationError("INVALID_EMAIL", "invalid email format")
+	ErrInvalidID             = NewValidationError("INVALID_ID", "invalid ID")
+	ErrInvalidUserID         = NewValidationError("INVALID_USER_ID", "invalid user ID")
+	ErrInvalidProductID      = NewValidationError("INVALID_PRODUCT_ID", "invalid product ID")
+	ErrEmailIsRequired       = NewValidationError("EMAIL_REQUIRED", "email is required")
+	ErrFirstNameIsRequired   = NewValidationError("FIRST_NAME_REQUIRED", "first name is required")
+	ErrLastNameIsRequired    = NewValidationError("LAST_NAME_REQUIRED", "last name is required")
+	ErrRoleIsRequired        = NewValidationError("ROLE_REQUIRED", "role is required")
+	ErrInvalidRole           = NewValidationError("INVALID_ROLE", "invalid role")
+	ErrCategoryRequired      = NewValidationError("CATEGORY_REQUIRED", "category is required")
+	ErrNameIsRequired        = NewValidationError("NAME_REQUIRED", "name is required")
+	ErrInvalidStock          = NewValidationError("INVALID_STOCK", "stock cannot be negative")
+	ErrPasswordRequired      = NewValidationError("PASSWORD_REQUIRED", "password is required")
+	ErrPasswordTooShort      = NewValidationError("PASSWORD_TOO_SHORT", "password must be at least 6 characters")
+	ErrInvalidPrice          = NewValidationError("INVALID_PRICE", "price must be greater than zero")
+	ErrPriceTooHigh          = NewValidationError("PRICE_TOO_HIGH", "price exceeds the maximum allowed")
+	ErrPriceTooPrecise       = NewValidationError("PRICE_TOO_PRECISE", "price must have at most 2 decimal places")
+	ErrInvalidSortField      = NewValidationError("INVALID_SORT_FIELD", "unsupported sort field")
+	ErrInvalidQuery          = NewValidationError("INVALID_QUERY", "invalid query parameters")
+	ErrInvalidPolicyDoc      = NewValidationError("INVALID_POLICY_DOCUMENT", "policy document is invalid; nothing was applied")
+	ErrEmailUnchanged        = NewValidationError("EMAIL_UNCHANGED", "new email is the same as the current one")
+	ErrInvalidThreshold      = NewValidationError("INVALID_THRESHOLD", "threshold must be a non-negative integer")
+	ErrInvalidQuantity       = NewValidationError("INVALID_QUANTITY", "quantity must be greater than zero")
+	ErrInvalidReservationTTL = NewValidationError("INVALID_RESERVATION_TTL", "reservation lifetime must be positive and at most one hour")
+	ErrInvalidEmailChange    = NewValidationError("INVALID_EMAIL_CHANGE", "no pending email change matches this token, or it has expired")
+	ErrRequestBodyTooLarge   = NewValidationError("REQUEST_BODY_TOO_LARGE", "request body is too large")
+	ErrUnsupportedMediaType  = NewValidationError("UNSUPPORTED_MEDIA_TYPE", "request body must be application/json")
+	ErrTooManyBulkItems      = NewValidationError("TOO_MANY_BULK_ITEMS", "batch has more items than allowed")
+	ErrInvalidRequestTimeout = NewValidationError("INVALID_REQUEST_TIMEOUT", "X-Request-Timeout must be a positive number of seconds or a duration such as 500ms")
 
 	// Not found errors
-	ErrUserNotFound    = NewNotFoundError("USER_NOT_FOUND", "user not found")
-	ErrProductNotFound = NewNotFoundError("PRODUCT_NOT_FOUND", "product not found")
+	ErrUserNotFound          = NewNotFoundError("USER_NOT_FOUND", "user not found")
+	ErrProductNotFound       = NewNotFoundError("PRODUCT_NOT_FOUND", "product not found")
+	ErrPolicyNotFound        = NewNotFoundError("POLICY_NOT_FOUND", "policy not found")
+	ErrPolicyVersionNotFound = NewNotFoundError("POLICY_VERSION_NOT_FOUND", "policy version not found")
+	ErrReservationNotFound   = NewNotFoundError("RESERVATION_NOT_FOUND", "reservation not found")
+	ErrSessionNotFound       = NewNotFoundError("SESSION_NOT_FOUND", "session not found")
 
 	// Unauthorized errors
 	ErrInvalidOrExpiredToken       = NewUnauthorizedError("INVALID_TOKEN", "invalid or expired token")
@@ -133,13 +164,29 @@ var (
 	ErrInvalidToken                = NewUnauthorizedError("INVALID_TOKEN", "invalid token")
 	ErrUnexpectedSigningMethod     = NewUnauthorizedError("UNEXPECTED_SIGNING_METHOD", "unexpected signing method")
 	ErrUserAccountIsDeactivated    = NewUnauthorizedError("USER_DEACTIVATED", "user account is deactivated")
+	ErrInvalidServiceContext       = NewUnauthorizedError("INVALID_SERVICE_CONTEXT", "invalid or unsigned service context")
+	ErrTokenReused                 = NewUnauthorizedError("TOKEN_REUSED", "refresh token was already used; please log in again")
 
 	// Forbidden errors
 	ErrInsufficientPermissions = NewForbiddenError("INSUFFICIENT_PERMISSIONS", "insufficient permissions")
+	ErrCannotDeleteSelf        = NewForbiddenError("CANNOT_DELETE_SELF", "cannot delete your own account")
+	ErrCannotChangeOwnAccess   = NewForbiddenError("CANNOT_CHANGE_OWN_ACCESS", "cannot change your own role or active status")
+	ErrCannotImpersonate       = NewForbiddenError("CANNOT_IMPERSONATE", "only active non-admin users other than yourself can be impersonated")
+	ErrChallengeFailed         = NewForbiddenError("CHALLENGE_FAILED", "challenge verification failed")
+	ErrAccountPendingApproval  = NewForbiddenError("ACCOUNT_PENDING_APPROVAL", "account is waiting for admin approval")
+
+	// Rate limit errors
+	ErrRateLimited = NewRateLimitedError("RATE_LIMITED", "too many requests; retry later")
 
 	// Conflict errors
-	ErrUserAlreadyExists    = NewConflictError("USER_EXISTS", "user already exists")
-	ErrProductAlreadyExists = NewConflictError("PRODUCT_EXISTS", "product already exists")
+	ErrUserAlreadyExists      = NewConflictError("USER_EXISTS", "user already exists")
+	ErrEmailAlreadyInUse      = NewConflictError("EMAIL_IN_USE", "email is already in use")
+	ErrInsufficientStock      = NewConflictError("INSUFFICIENT_STOCK", "not enough stock available")
+	ErrReservationNotPending  = NewConflictError("RESERVATION_NOT_PENDING", "reservation was already confirmed, released or has expired")
+	ErrProductAlreadyExists   = NewConflictError("PRODUCT_EXISTS", "product already exists")
+	ErrPolicyAlreadyExists    = NewConflictError("POLICY_EXISTS", "a policy with this name already exists")
+	ErrCannotDeleteLastAdmin  = NewConflictError("CANNOT_DELETE_LAST_ADMIN", "cannot delete the last remaining admin")
+	ErrUserNotPendingApproval = NewConflictError("USER_NOT_PENDING_APPROVAL", "user is not waiting for approval")
 
 	// Internal errors
 	ErrFailedToCreateUser           = NewInternalError("USER_CREATE_FAILED", "failed to create user", nil)
@@ -156,6 +203,7 @@ var (
 	ErrFailedToGenerateRefreshToken = NewInternalError("REFRESH_TOKEN_FAILED", "failed to generate refresh token", nil)
 	ErrFailedToProcessPassword      = NewInternalError("PASSWORD_PROCESS_FAILED", "failed to process password", nil)
 	ErrFailedToGenerateTokens       = NewInternalError("TOKEN_GENERATION_FAILED", "failed to generate tokens", nil)
+	ErrFailedToSendEmail            = NewInternalError("EMAIL_SEND_FAILED", "failed to send email", nil)
 
 	// Deprecated aliases - kept for backward compatibility
 	ErrDeleteUser      = ErrFailedToDeleteUser
@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the language AppError messages are declared in. It is served whenever the
+// caller accepts none of the catalog's locales.
+const DefaultLocale = "en"
+
+// messageCatalog translates AppError messages, keyed by locale and then by AppError.Code. A code
+// missing from a locale keeps its English message, so the catalog may lag behind new errors.
+var messageCatalog = map[string]map[string]string{
+	"vi": {
+		"INVALID_REQUEST":          "yêu cầu không hợp lệ",
+		"INVALID_REQUEST_BODY":     "nội dung yêu cầu không hợp lệ",
+		"INVALID_CREDENTIALS":      "thông tin đăng nhập không hợp lệ",
+		"INVALID_EMAIL":            "định dạng email không hợp lệ",
+		"INVALID_ID":               "ID không hợp lệ",
+		"INVALID_USER_ID":          "ID người dùng không hợp lệ",
+		"INVALID_PRODUCT_ID":       "ID sản phẩm không hợp lệ",
+		"EMAIL_REQUIRED":           "email là bắt buộc",
+		"FIRST_NAME_REQUIRED":      "tên là bắt buộc",
+		"LAST_NAME_REQUIRED":       "họ là bắt buộc",
+		"ROLE_REQUIRED":            "vai trò là bắt buộc",
+		"INVALID_ROLE":             "vai trò không hợp lệ",
+		"CATEGORY_REQUIRED":        "danh mục là bắt buộc",
+		"NAME_REQUIRED":            "tên là bắt buộc",
+		"INVALID_STOCK":            "số lượng tồn kho không được âm",
+		"PASSWORD_REQUIRED":        "mật khẩu là bắt buộc",
+		"PASSWORD_TOO_SHORT":       "mật khẩu phải có ít nhất 6 ký tự",
+		"INVALID_PRICE":            "giá phải lớn hơn 0",
+		"PRICE_TOO_HIGH":           "giá vượt quá mức tối đa cho phép",
+		"PRICE_TOO_PRECISE":        "giá chỉ được có tối đa 2 chữ số thập phân",
+		"INVALID_SORT_FIELD":       "trường sắp xếp không được hỗ trợ",
+		"INVALID_QUERY":            "tham số truy vấn không hợp lệ",
+		"INVALID_POLICY_DOCUMENT":  "tài liệu chính sách không hợp lệ; không có thay đổi nào được áp dụng",
+		"REQUEST_BODY_TOO_LARGE":   "nội dung yêu cầu quá lớn",
+		"UNSUPPORTED_MEDIA_TYPE":   "nội dung yêu cầu phải là application/json",
+		"TOO_MANY_BULK_ITEMS":      "lô có nhiều mục hơn mức cho phép",
+		"USER_NOT_FOUND":           "không tìm thấy người dùng",
+		"PRODUCT_NOT_FOUND":        "không tìm thấy sản phẩm",
+		"POLICY_NOT_FOUND":         "không tìm thấy chính sách",
+		"SESSION_NOT_FOUND":        "không tìm thấy phiên đăng nhập",
+		"INVALID_TOKEN":            "token không hợp lệ hoặc đã hết hạn",
+		"AUTH_HEADER_REQUIRED":     "thiếu header xác thực",
+		"USER_DEACTIVATED":         "tài khoản người dùng đã bị vô hiệu hóa",
+		"INSUFFICIENT_PERMISSIONS": "không đủ quyền",
+		"CANNOT_DELETE_SELF":       "không thể xóa tài khoản của chính bạn",
+		"ACCOUNT_PENDING_APPROVAL": "tài khoản đang chờ quản trị viên phê duyệt",
+		"RATE_LIMITED":             "quá nhiều yêu cầu; vui lòng thử lại sau",
+		"USER_EXISTS":              "người dùng đã tồn tại",
+		"EMAIL_IN_USE":             "email đã được sử dụng",
+		"INSUFFICIENT_STOCK":       "không đủ hàng trong kho",
+		"PRODUCT_EXISTS":           "sản phẩm đã tồn tại",
+		"POLICY_EXISTS":            "đã có chính sách với tên này",
+	},
+}
+
+// HasLocale reports whether error messages can be served in locale
+func HasLocale(locale string) bool {
+	if locale == DefaultLocale {
+		return true
+	}
+	_, ok := messageCatalog[locale]
+	return ok
+}
+
+// LocalizedMessage returns the message in locale, or the English one when the catalog has no
+// translation for the code. Code is never translated.
+func (e AppError) LocalizedMessage(locale string) string {
+	if message, ok := messageCatalog[locale][e.Code]; ok {
+		return message
+	}
+	return e.Message
+}
+
+// MatchLocale picks the locale for an Accept-Language header value: the most preferred language
+// the catalog has, matching a regional tag such as vi-VN on its base language, or DefaultLocale.
+func MatchLocale(acceptLanguage string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var ranges []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			ranges = append(ranges, weighted{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	for _, r := range ranges {
+		base, _, _ := strings.Cut(r.tag, "-")
+		if HasLocale(r.tag) {
+			return r.tag
+		}
+		if HasLocale(base) {
+			return base
+		}
+	}
+	return DefaultLocale
+}
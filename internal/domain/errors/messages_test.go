@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchLocale(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: "en"},
+		{header: "vi", want: "vi"},
+		{header: "vi-VN,vi;q=0.9,en;q=0.8", want: "vi"},
+		{header: "en-US,en;q=0.9,vi;q=0.8", want: "en"},
+		{header: "fr-FR,fr;q=0.9", want: "en"},
+		{header: "fr;q=0.9,vi;q=0.5", want: "vi"},
+		{header: "en;q=0.1,VI;q=0.7", want: "vi"},
+		{header: "vi;q=0,de", want: "en"},
+		{header: "*", want: "en"},
+		{header: "vi;q=abc", want: "en"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchLocale(tt.header), "Accept-Language: %q", tt.header)
+	}
+}
+
+func TestAppError_LocalizedMessage(t *testing.T) {
+	assert.Equal(t, "không tìm thấy người dùng", ErrUserNotFound.LocalizedMessage("vi"))
+	assert.Equal(t, "user not found", ErrUserNotFound.LocalizedMessage("en"))
+	assert.Equal(t, "user not found", ErrUserNotFound.LocalizedMessage("fr"))
+
+	// Codes the locale has no translation for keep their English message
+	assert.Equal(t, ErrTokenReused.Message, ErrTokenReused.LocalizedMessage("vi"))
+}
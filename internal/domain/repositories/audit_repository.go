@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditRepository interface {
+	Add(ctx context.Context, entry *entities.AuditLogEntry) error
+	// ListByUser returns the entries recorded for userID, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.AuditLogEntry, error)
+	// ListByEntity returns the entries recorded against entityID under one of resources, newest first
+	ListByEntity(ctx context.Context, entityID uuid.UUID, resources []string, limit, offset int) ([]*entities.AuditLogEntry, error)
+	// DeleteBefore removes entries recorded before cutoff, batchSize rows per statement, and
+	// returns how many it removed
+	DeleteBefore(ctx context.Context, cutoff time.Time, batchSize int) (int, error)
+}
@@ -13,11 +13,23 @@ type AuthorizationService interface {
 	GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]entities.Permission, error)
 	GetEffectivePermissions(ctx context.Context, userID uuid.UUID) ([]entities.Permission, error)
 	QuickCheck(userRole, resource, action string) bool
+	QuickCheckForUser(userID uuid.UUID, userRole, resource, action string) bool
 	ValidateRole(userRole string) error
 	GetAllowedActionsForRole(userRole, resource string) ([]string, error)
 	CreateEnrichedContext(ctx context.Context, userID uuid.UUID, role, email string) context.Context
 }
 
+// ServiceContextCodec carries the caller identity between service instances
+type ServiceContextCodec interface {
+	SerializeContextForMicroservice(ctx context.Context) (string, error)
+	CreateContextFromMicroserviceData(baseCtx context.Context, data string) (context.Context, error)
+}
+
+// ChallengeVerifier checks a CAPTCHA-style response before registration and login
+type ChallengeVerifier interface {
+	Verify(ctx context.Context, response, remoteIP string) error
+}
+
 type AuditLogger interface {
 	LogAccess(ctx context.Context, userID uuid.UUID, action, resource string, entityID uuid.UUID) error
 	LogDataAccess(ctx context.Context, userID uuid.UUID, action, resource string, data interface{}) error
@@ -26,9 +38,13 @@ type AuditLogger interface {
 type BaseRepository[T any] interface {
 	Create(ctx context.Context, entity *T, userID uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*T, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) ([]*T, error)
 	Update(ctx context.Context, entity *T, userID uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 	List(ctx context.Context, limit, offset int, userID uuid.UUID) ([]*T, error)
+	// Count returns the number of rows List pages through
+	Count(ctx context.Context, userID uuid.UUID) (int64, error)
+	HealthCheck(ctx context.Context) error
 
 	ValidateAccess(ctx context.Context, userID uuid.UUID, action string) error
 	AuditLog(ctx context.Context, userID uuid.UUID, action string, entity *T) error
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"context"
+)
+
+// EventPublisher delivers domain events to downstream systems without blocking the caller
+type EventPublisher interface {
+	Publish(ctx context.Context, event entities.DomainEvent) error
+	Close() error
+}
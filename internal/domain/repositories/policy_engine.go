@@ -10,9 +10,21 @@ import (
 type PolicyEngine interface {
 	Evaluate(ctx context.Context, req *entities.PermissionRequest) (*entities.PermissionResponse, error)
 	LoadPolicies(ctx context.Context) error
+	// ReloadPolicies is LoadPolicies on demand, propagated to other instances; it returns the number loaded
+	ReloadPolicies(ctx context.Context) (int, error)
 	AddPolicy(ctx context.Context, policy *entities.PolicyDocument) error
 	RemovePolicy(ctx context.Context, policyID uuid.UUID) error
 	GetPoliciesForRole(ctx context.Context, role string) ([]*entities.PolicyDocument, error)
+	ImportPolicies(ctx context.Context, policies []*entities.PolicyDocument) ([]entities.PolicyImportResult, error)
+	RollbackPolicy(ctx context.Context, policyID uuid.UUID, version string) (*entities.PolicyDocument, error)
+	Close() error
+}
+
+// PolicyChangeNotifier propagates policy mutations between application instances
+type PolicyChangeNotifier interface {
+	Publish(ctx context.Context, event entities.PolicyChangeEvent) error
+	// Subscribe delivers events to handler until the returned unsubscribe function is called
+	Subscribe(ctx context.Context, handler func(entities.PolicyChangeEvent)) (unsubscribe func(), err error)
 }
 
 type PolicyRepository interface {
@@ -21,4 +33,7 @@ type PolicyRepository interface {
 	GetActive(ctx context.Context) ([]*entities.PolicyDocument, error)
 	Update(ctx context.Context, policy *entities.PolicyDocument) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	UpsertByName(ctx context.Context, policies []*entities.PolicyDocument) ([]entities.PolicyImportResult, error)
+	GetVersions(ctx context.Context, id uuid.UUID) ([]*entities.PolicyDocument, error)
+	Rollback(ctx context.Context, id uuid.UUID, version string) (*entities.PolicyDocument, error)
 }
@@ -3,9 +3,22 @@ package repositories
 import (
 	"clean-architecture-api/internal/domain/entities"
 	"context"
+
+	"github.com/google/uuid"
 )
 
 type ProductRepository interface {
 	BaseRepository[entities.Product]
+	// GetByCategory returns an empty list, not an error, when no live product is in category.
+	// Categories are free text on products, so an unknown category cannot be told apart from an
+	// empty one.
 	GetByCategory(ctx context.Context, category string, limit, offset int) ([]*entities.Product, error)
+	CountInCategory(ctx context.Context, category string) (int64, error)
+	// CountByCategory returns the number of live products in each category, keyed in lower
+	// case. Products without a category are counted under the empty key.
+	CountByCategory(ctx context.Context) (map[string]int64, error)
+	// GetLowStock returns products with stock at or below threshold, lowest stock first
+	GetLowStock(ctx context.Context, threshold, limit, offset int) ([]*entities.Product, error)
+	// Restore clears DeletedAt on a soft-deleted product and returns it
+	Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.Product, error)
 }
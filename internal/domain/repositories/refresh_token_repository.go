@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entities.RefreshToken) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.RefreshToken, error)
+	// MarkUsed reports false when the token was already used or revoked, so two concurrent
+	// refreshes with the same token cannot both succeed
+	MarkUsed(ctx context.Context, id uuid.UUID) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	// ListActiveByUser returns the user's tokens that can still be refreshed, one per session,
+	// newest first
+	ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]*entities.RefreshToken, error)
+	// RevokeUserFamily revokes a session of userID and reports false when the user has no such
+	// session that is still open
+	RevokeUserFamily(ctx context.Context, userID, familyID uuid.UUID) (bool, error)
+}
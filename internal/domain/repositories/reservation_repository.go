@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReservationRepository interface {
+	// Reserve stores reservation and takes its quantity out of the product's stock in one
+	// transaction; it fails without changing anything when the stock is too low
+	Reserve(ctx context.Context, reservation *entities.Reservation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Reservation, error)
+	// Confirm finalizes a pending reservation that has not expired
+	Confirm(ctx context.Context, id uuid.UUID) error
+	// Release cancels a pending reservation and returns its quantity to stock
+	Release(ctx context.Context, id uuid.UUID) error
+	// ReleaseExpired releases every pending reservation that expired at or before now
+	ReleaseExpired(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TransactionManager runs fn in a database transaction carried by the context it receives.
+// Repository calls made with that context join the transaction.
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type OutboxRepository interface {
+	Add(ctx context.Context, event *entities.OutboxEvent) error
+	FetchPending(ctx context.Context, limit, maxAttempts int) ([]*entities.OutboxEvent, error)
+	MarkSent(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string) error
+}
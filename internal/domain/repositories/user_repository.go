@@ -5,7 +5,26 @@ import (
 	"context"
 )
 
+// UserSearch narrows an admin user search. Empty fields do not filter.
+type UserSearch struct {
+	// Query matches part of the first name, last name or email, ignoring case
+	Query  string
+	Role   string
+	Active *bool
+}
+
+// UserRepository leaves the system user out of List, Count and Search; GetByID still finds it
 type UserRepository interface {
 	BaseRepository[entities.User]
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
+	// GetByEmailIncludingDeleted also returns soft-deleted users, newest first, for admin recovery
+	GetByEmailIncludingDeleted(ctx context.Context, email string) ([]*entities.User, error)
+	// CountByRole counts the active users with role. The system user is never counted, so it
+	// cannot stand in for the last real admin.
+	CountByRole(ctx context.Context, role string) (int64, error)
+	// ListPendingApproval returns the users whose registration waits for admin approval, oldest first
+	ListPendingApproval(ctx context.Context, limit, offset int) ([]*entities.User, error)
+	// Search returns a page of the users matching every filter in search, in the list order,
+	// along with the number of matches across all pages
+	Search(ctx context.Context, search UserSearch, limit, offset int) ([]*entities.User, int64, error)
 }
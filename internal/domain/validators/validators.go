@@ -5,7 +5,10 @@ package validators
 import (
 	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/errors"
+	"math"
+	"os"
 	"regexp"
+	"strconv"
 )
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
@@ -32,7 +35,7 @@ func ValidateRequired(field, value string) error {
 		case constants.FieldRole:
 			return errors.ErrRoleIsRequired
 		case constants.FieldName:
-			return errors.ErrCategoryRequired
+			return errors.ErrNameIsRequired
 		default:
 			return errors.ErrInvalidRequest
 		}
@@ -42,16 +45,42 @@ func ValidateRequired(field, value string) error {
 
 // ValidatePrice validates that a price is positive and within reasonable limits
 func ValidatePrice(price float64) error {
-	if price <= 0 {
-		return errors.ErrInvalidRequest
+	return ValidatePriceWithMax(price, MaxProductPrice())
+}
+
+// ValidatePriceWithMax validates a price against an explicit upper bound and
+// rejects values with more than MaxPriceDecimalPlaces decimal places
+func ValidatePriceWithMax(price, maxPrice float64) error {
+	if price <= 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return errors.ErrInvalidPrice
+	}
+	if price > maxPrice {
+		return errors.ErrPriceTooHigh
+	}
+
+	scale := math.Pow10(constants.MaxPriceDecimalPlaces)
+	scaled := price * scale
+	if math.Abs(scaled-math.Round(scaled)) > 1e-6 {
+		return errors.ErrPriceTooPrecise
 	}
 	return nil
 }
 
+// MaxProductPrice returns the MAX_PRODUCT_PRICE upper bound, falling back to
+// DefaultMaxProductPrice when it is unset or not a positive number
+func MaxProductPrice() float64 {
+	if value := os.Getenv("MAX_PRODUCT_PRICE"); value != "" {
+		if maxPrice, err := strconv.ParseFloat(value, 64); err == nil && maxPrice > 0 {
+			return maxPrice
+		}
+	}
+	return constants.DefaultMaxProductPrice
+}
+
 // ValidateStock validates that stock quantity is non-negative
 func ValidateStock(stock int) error {
 	if stock < 0 {
-		return errors.ErrInvalidRequest
+		return errors.ErrInvalidStock
 	}
 	return nil
 }
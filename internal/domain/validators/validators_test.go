@@ -0,0 +1,49 @@
+package validators
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePriceWithMax(t *testing.T) {
+	tests := []struct {
+		name          string
+		price         float64
+		expectedError error
+	}{
+		{name: "smallest valid price", price: 0.01, expectedError: nil},
+		{name: "two decimal places", price: 19.99, expectedError: nil},
+		{name: "exactly max", price: 1000, expectedError: nil},
+		{name: "zero", price: 0, expectedError: errors.ErrInvalidPrice},
+		{name: "negative", price: -5, expectedError: errors.ErrInvalidPrice},
+		{name: "just above max", price: 1000.01, expectedError: errors.ErrPriceTooHigh},
+		{name: "too many decimals", price: 9.999, expectedError: errors.ErrPriceTooPrecise},
+		{name: "sub-cent price", price: 0.001, expectedError: errors.ErrPriceTooPrecise},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePriceWithMax(tt.price, 1000)
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMaxProductPrice(t *testing.T) {
+	t.Setenv("MAX_PRODUCT_PRICE", "")
+	assert.Equal(t, constants.DefaultMaxProductPrice, MaxProductPrice())
+
+	t.Setenv("MAX_PRODUCT_PRICE", "500")
+	assert.Equal(t, 500.0, MaxProductPrice())
+	assert.Equal(t, errors.ErrPriceTooHigh, ValidatePrice(500.01))
+
+	t.Setenv("MAX_PRODUCT_PRICE", "not-a-number")
+	assert.Equal(t, constants.DefaultMaxProductPrice, MaxProductPrice())
+}
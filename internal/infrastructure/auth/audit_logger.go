@@ -1,35 +1,18 @@
 package auth
 
 import (
+	"clean-architecture-api/internal/domain/entities"
 	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/pkg/logger"
 	"context"
 	"time"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
-type AuditLogEntry struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
-	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
-	Action    string    `json:"action" gorm:"not null"`
-	Resource  string    `json:"resource" gorm:"not null"`
-	EntityID  uuid.UUID `json:"entity_id" gorm:"type:uuid"`
-	Timestamp time.Time `json:"timestamp" gorm:"not null"`
-	IPAddress string    `json:"ip_address"`
-	UserAgent string    `json:"user_agent"`
-}
-
-func (ale *AuditLogEntry) BeforeCreate(_ *gorm.DB) error {
-	if ale.ID == uuid.Nil {
-		ale.ID = uuid.New()
-	}
-	return nil
-}
-
 type AuditLoggerImpl struct {
-	logger logger.Logger
+	logger    logger.Logger
+	auditRepo repositories.AuditRepository
 }
 
 func NewAuditLogger(logger logger.Logger) repositories.AuditLogger {
@@ -38,8 +21,17 @@ func NewAuditLogger(logger logger.Logger) repositories.AuditLogger {
 	}
 }
 
-func (a *AuditLoggerImpl) LogAccess(_ context.Context, userID uuid.UUID, action, resource string, entityID uuid.UUID) error {
-	entry := AuditLogEntry{
+// NewPersistentAuditLogger also stores each entry through auditRepo, so users can review their
+// own activity. A failed write is returned to the caller like any other audit failure.
+func NewPersistentAuditLogger(auditRepo repositories.AuditRepository, logger logger.Logger) repositories.AuditLogger {
+	return &AuditLoggerImpl{
+		logger:    logger,
+		auditRepo: auditRepo,
+	}
+}
+
+func (a *AuditLoggerImpl) LogAccess(ctx context.Context, userID uuid.UUID, action, resource string, entityID uuid.UUID) error {
+	entry := entities.AuditLogEntry{
 		ID:        uuid.New(),
 		UserID:    userID,
 		Action:    action,
@@ -55,11 +47,11 @@ func (a *AuditLoggerImpl) LogAccess(_ context.Context, userID uuid.UUID, action,
 		WithField("timestamp", entry.Timestamp).
 		Info("Audit log entry")
 
-	return nil
+	return a.persist(ctx, &entry)
 }
 
 func (a *AuditLoggerImpl) LogDataAccess(ctx context.Context, userID uuid.UUID, action, resource string, data interface{}) error {
-	entry := AuditLogEntry{
+	entry := entities.AuditLogEntry{
 		ID:        uuid.New(),
 		UserID:    userID,
 		Action:    action,
@@ -74,5 +66,12 @@ func (a *AuditLoggerImpl) LogDataAccess(ctx context.Context, userID uuid.UUID, a
 		WithField("timestamp", entry.Timestamp).
 		Info("Data access audit log")
 
-	return nil
+	return a.persist(ctx, &entry)
+}
+
+func (a *AuditLoggerImpl) persist(ctx context.Context, entry *entities.AuditLogEntry) error {
+	if a.auditRepo == nil {
+		return nil
+	}
+	return a.auditRepo.Add(ctx, entry)
 }
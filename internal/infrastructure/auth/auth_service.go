@@ -1,54 +1,97 @@
 package auth
 
 import (
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/errors"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
+// ImpersonationTokenLifetime bounds impersonation sessions. It is deliberately not configurable.
+const ImpersonationTokenLifetime = 10 * time.Minute
+
+// TokenUseAccess and TokenUseRefresh are the values of the token_use claim. Access tokens
+// authenticate requests; refresh tokens are only good for getting a new pair.
+const (
+	TokenUseAccess  = "access"
+	TokenUseRefresh = "refresh"
+)
+
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Role   string    `json:"role"`
+	// TokenUse is TokenUseAccess or TokenUseRefresh; see Use
+	TokenUse string    `json:"token_use,omitempty"`
+	UserID   uuid.UUID `json:"user_id"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	// ImpersonatedBy is the admin acting as UserID, set only on impersonation tokens
+	ImpersonatedBy *uuid.UUID `json:"impersonated_by,omitempty"`
+	// RememberMe is set on refresh tokens issued with the longer lifetime, so rotation keeps it
+	RememberMe bool `json:"remember_me,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Use reports what the token is for. Tokens issued before the token_use claim existed are
+// told apart by their jti, which only refresh tokens carry.
+func (c *Claims) Use() string {
+	if c.TokenUse != "" {
+		return c.TokenUse
+	}
+	if c.ID != "" {
+		return TokenUseRefresh
+	}
+	return TokenUseAccess
+}
+
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 	ExpiresIn    int64  `json:"expires_in"`
+
+	// RefreshTokenID is the refresh token's jti, used to record it for rotation
+	RefreshTokenID   uuid.UUID `json:"-"`
+	RefreshExpiresAt time.Time `json:"-"`
 }
 
 type AuthService interface {
-	GenerateTokenPair(userID uuid.UUID, email, role string) (*TokenPair, error)
+	GenerateTokenPair(userID uuid.UUID, email, role string, rememberMe bool) (*TokenPair, error)
+	GenerateImpersonationToken(userID uuid.UUID, email, role string, impersonatorID uuid.UUID) (*TokenPair, error)
+	// ValidateToken accepts only access tokens
 	ValidateToken(tokenString string) (*Claims, error)
+	// ValidateRefreshToken accepts only refresh tokens
+	ValidateRefreshToken(tokenString string) (*Claims, error)
 	RefreshTokenPair(refreshToken string) (*TokenPair, error)
 }
 
 type authService struct {
-	secretKey []byte
+	keys *KeySet
+	// leeway tolerates clock skew between services when checking exp and nbf
+	leeway time.Duration
 }
 
-func NewAuthService() (AuthService, error) {
-	secretKey := os.Getenv("JWT_SECRET_KEY")
-	if secretKey == "" {
-		return nil, fmt.Errorf("JWT_SECRET_KEY environment variable is required")
-	}
-	return &authService{
-		secretKey: []byte(secretKey),
-	}, nil
+// NewAuthServiceWithKeys signs with keys and accepts tokens up to leeway past exp or before nbf
+func NewAuthServiceWithKeys(keys *KeySet, leeway time.Duration) AuthService {
+	return &authService{keys: keys, leeway: leeway}
+}
+
+func (s *authService) sign(claims *Claims) (string, error) {
+	keyID, key := s.keys.current()
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString(key.sign)
 }
 
-func (s *authService) GenerateTokenPair(userID uuid.UUID, email, role string) (*TokenPair, error) {
+// GenerateTokenPair issues an access and refresh token. rememberMe extends the refresh token's
+// lifetime; the access token lifetime is the same either way.
+func (s *authService) GenerateTokenPair(userID uuid.UUID, email, role string, rememberMe bool) (*TokenPair, error) {
 	accessTokenExp := time.Now().Add(15 * time.Minute)
 	accessTokenClaims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		TokenUse: TokenUseAccess,
+		UserID:   userID,
+		Email:    email,
+		Role:     role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(accessTokenExp),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -58,18 +101,25 @@ func (s *authService) GenerateTokenPair(userID uuid.UUID, email, role string) (*
 		},
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims)
-	accessTokenString, err := accessToken.SignedString(s.secretKey)
+	accessTokenString, err := s.sign(accessTokenClaims)
 	if err != nil {
 		return nil, errors.ErrFailedToGenerateAccessToken
 	}
 
-	refreshTokenExp := time.Now().Add(7 * 24 * time.Hour)
+	refreshTokenID := uuid.New()
+	refreshTokenDays := constants.JWTRefreshTokenDuration
+	if rememberMe {
+		refreshTokenDays = constants.JWTRememberMeRefreshTokenDuration
+	}
+	refreshTokenExp := time.Now().Add(time.Duration(refreshTokenDays) * 24 * time.Hour)
 	refreshTokenClaims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		TokenUse:   TokenUseRefresh,
+		UserID:     userID,
+		Email:      email,
+		Role:       role,
+		RememberMe: rememberMe,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        refreshTokenID.String(),
 			ExpiresAt: jwt.NewNumericDate(refreshTokenExp),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -78,8 +128,7 @@ func (s *authService) GenerateTokenPair(userID uuid.UUID, email, role string) (*
 		},
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshTokenClaims)
-	refreshTokenString, err := refreshToken.SignedString(s.secretKey)
+	refreshTokenString, err := s.sign(refreshTokenClaims)
 	if err != nil {
 		return nil, errors.ErrFailedToGenerateRefreshToken
 	}
@@ -88,21 +137,71 @@ func (s *authService) GenerateTokenPair(userID uuid.UUID, email, role string) (*
 		AccessToken:  accessTokenString,
 		RefreshToken: refreshTokenString,
 		ExpiresIn:    accessTokenExp.Unix(),
+
+		RefreshTokenID:   refreshTokenID,
+		RefreshExpiresAt: refreshTokenExp,
+	}, nil
+}
+
+// GenerateImpersonationToken issues a short-lived access token for userID that records the
+// admin behind it. No refresh token is issued, so the session ends when the token expires.
+func (s *authService) GenerateImpersonationToken(userID uuid.UUID, email, role string, impersonatorID uuid.UUID) (*TokenPair, error) {
+	expiresAt := time.Now().Add(ImpersonationTokenLifetime)
+	claims := &Claims{
+		TokenUse:       TokenUseAccess,
+		UserID:         userID,
+		Email:          email,
+		Role:           role,
+		ImpersonatedBy: &impersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    "clean-architecture-api",
+			Subject:   userID.String(),
+		},
+	}
+
+	accessToken, err := s.sign(claims)
+	if err != nil {
+		return nil, errors.ErrFailedToGenerateAccessToken
+	}
+
+	return &TokenPair{
+		AccessToken: accessToken,
+		ExpiresIn:   expiresAt.Unix(),
 	}, nil
 }
 
 func (s *authService) ValidateToken(tokenString string) (*Claims, error) {
+	return s.validate(tokenString, TokenUseAccess)
+}
+
+func (s *authService) ValidateRefreshToken(tokenString string) (*Claims, error) {
+	return s.validate(tokenString, TokenUseRefresh)
+}
+
+// validate verifies the token's signature and lifetime, and rejects a token issued for another
+// use, so a long-lived refresh token never works as a bearer token
+func (s *authService) validate(tokenString, use string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		keyID, _ := token.Header["kid"].(string)
+		key, ok := s.keys.lookup(keyID)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", keyID)
+		}
+		// The algorithm comes from the key, never the token, so an RS256 public key cannot
+		// be passed off as an HMAC secret
+		if token.Method.Alg() != key.method.Alg() {
 			return nil, errors.ErrUnexpectedSigningMethod
 		}
-		return s.secretKey, nil
-	})
+		return key.verify, nil
+	}, jwt.WithLeeway(s.leeway))
 	if err != nil {
 		return nil, errors.ErrFailedToParseToken
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.Use() == use {
 		return claims, nil
 	}
 
@@ -110,10 +209,10 @@ func (s *authService) ValidateToken(tokenString string) (*Claims, error) {
 }
 
 func (s *authService) RefreshTokenPair(refreshToken string) (*TokenPair, error) {
-	claims, err := s.ValidateToken(refreshToken)
+	claims, err := s.ValidateRefreshToken(refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.GenerateTokenPair(claims.UserID, claims.Email, claims.Role)
+	return s.GenerateTokenPair(claims.UserID, claims.Email, claims.Role, claims.RememberMe)
 }
@@ -0,0 +1,257 @@
+package auth
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuthService(t *testing.T, currentID string, keys map[string][]byte) AuthService {
+	keySet, err := NewKeySet(currentID, keys)
+	require.NoError(t, err)
+	return NewAuthServiceWithKeys(keySet, constants.DefaultJWTClockSkewSeconds*time.Second)
+}
+
+func tokenKeyID(t *testing.T, tokenString string) string {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+	require.NoError(t, err)
+	keyID, _ := token.Header["kid"].(string)
+	return keyID
+}
+
+func TestAuthService_KeyRotation(t *testing.T) {
+	keyA := []byte("secret-a")
+	keyB := []byte("secret-b")
+	userID := uuid.New()
+
+	before := newTestAuthService(t, "a", map[string][]byte{"a": keyA})
+	pairA, err := before.GenerateTokenPair(userID, "test@example.com", "user", false)
+	require.NoError(t, err)
+	assert.Equal(t, "a", tokenKeyID(t, pairA.AccessToken))
+
+	// Rotate: sign with B while A stays available for verification
+	after := newTestAuthService(t, "b", map[string][]byte{"a": keyA, "b": keyB})
+	pairB, err := after.GenerateTokenPair(userID, "test@example.com", "user", false)
+	require.NoError(t, err)
+	assert.Equal(t, "b", tokenKeyID(t, pairB.AccessToken))
+
+	claims, err := after.ValidateToken(pairA.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+
+	_, err = after.ValidateToken(pairB.AccessToken)
+	assert.NoError(t, err)
+
+	// Once A is removed its tokens stop validating
+	retired := newTestAuthService(t, "b", map[string][]byte{"b": keyB})
+	_, err = retired.ValidateToken(pairA.AccessToken)
+	assert.Error(t, err)
+	_, err = retired.ValidateToken(pairB.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestAuthService_RejectsKeyIDWithWrongKey(t *testing.T) {
+	attacker := newTestAuthService(t, "a", map[string][]byte{"a": []byte("guessed")})
+	pair, err := attacker.GenerateTokenPair(uuid.New(), "test@example.com", "admin", false)
+	require.NoError(t, err)
+
+	service := newTestAuthService(t, "a", map[string][]byte{"a": []byte("secret-a")})
+	_, err = service.ValidateToken(pair.AccessToken)
+	assert.Error(t, err)
+}
+
+func TestAuthService_TokenUse(t *testing.T) {
+	service := newTestAuthService(t, "a", map[string][]byte{"a": []byte("secret-a")})
+	pair, err := service.GenerateTokenPair(uuid.New(), "test@example.com", "user", false)
+	require.NoError(t, err)
+
+	claims, err := service.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, TokenUseAccess, claims.TokenUse)
+	_, err = service.ValidateToken(pair.RefreshToken)
+	assert.Error(t, err, "a refresh token is not a bearer token")
+
+	claims, err = service.ValidateRefreshToken(pair.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, TokenUseRefresh, claims.TokenUse)
+	_, err = service.ValidateRefreshToken(pair.AccessToken)
+	assert.Error(t, err)
+	_, err = service.RefreshTokenPair(pair.AccessToken)
+	assert.Error(t, err, "an access token cannot be refreshed")
+
+	// Tokens issued before token_use are classified by their jti
+	legacyRefresh, err := service.(*authService).sign(&Claims{
+		UserID:           uuid.New(),
+		RegisteredClaims: jwt.RegisteredClaims{ID: uuid.NewString(), ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	})
+	require.NoError(t, err)
+	_, err = service.ValidateToken(legacyRefresh)
+	assert.Error(t, err)
+	_, err = service.ValidateRefreshToken(legacyRefresh)
+	assert.NoError(t, err)
+}
+
+func TestAuthService_ClockSkewLeeway(t *testing.T) {
+	// signed issues a token that expired, or only becomes valid, some seconds from now
+	signed := func(t *testing.T, service AuthService, expiresIn, validIn time.Duration) string {
+		now := time.Now()
+		token, err := service.(*authService).sign(&Claims{
+			UserID: uuid.New(),
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+				NotBefore: jwt.NewNumericDate(now.Add(validIn)),
+			},
+		})
+		require.NoError(t, err)
+		return token
+	}
+	keys := map[string][]byte{"a": []byte("secret-a")}
+
+	t.Run("default tolerates small skew", func(t *testing.T) {
+		service := newTestAuthService(t, "a", keys)
+
+		_, err := service.ValidateToken(signed(t, service, -5*time.Second, -time.Minute))
+		assert.NoError(t, err, "expired a few seconds ago, within the leeway")
+		_, err = service.ValidateToken(signed(t, service, time.Minute, 5*time.Second))
+		assert.NoError(t, err, "not valid for a few seconds, within the leeway")
+
+		_, err = service.ValidateToken(signed(t, service, -2*time.Minute, -time.Hour))
+		assert.Error(t, err, "expired beyond the leeway")
+	})
+
+	t.Run("configured leeway", func(t *testing.T) {
+		keySet, err := NewKeySet("a", keys)
+		require.NoError(t, err)
+		service := NewAuthServiceWithKeys(keySet, 0)
+		_, err = service.ValidateToken(signed(t, service, -5*time.Second, -time.Minute))
+		assert.Error(t, err)
+
+		service = NewAuthServiceWithKeys(keySet, 5*time.Minute)
+		_, err = service.ValidateToken(signed(t, service, -2*time.Minute, -time.Hour))
+		assert.NoError(t, err)
+	})
+}
+
+func TestLoadKeySet(t *testing.T) {
+	t.Run("falls back to JWT_SECRET_KEY", func(t *testing.T) {
+		keys, err := LoadKeySet(KeySetConfig{SecretKey: "legacy"})
+		require.NoError(t, err)
+		keyID, key := keys.current()
+		assert.Equal(t, DefaultKeyID, keyID)
+		assert.Equal(t, []byte("legacy"), key.sign)
+	})
+
+	t.Run("selects the configured key ID", func(t *testing.T) {
+		keys, err := LoadKeySet(KeySetConfig{SigningKeys: "2024:old-secret, 2025:new-secret", SigningKeyID: "2025"})
+		require.NoError(t, err)
+		keyID, key := keys.current()
+		assert.Equal(t, "2025", keyID)
+		assert.Equal(t, []byte("new-secret"), key.sign)
+		_, ok := keys.lookup("2024")
+		assert.True(t, ok)
+	})
+
+	t.Run("rejects an unknown current key ID", func(t *testing.T) {
+		_, err := LoadKeySet(KeySetConfig{SigningKeys: "a:secret", SigningKeyID: "b"})
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects malformed entries", func(t *testing.T) {
+		_, err := LoadKeySet(KeySetConfig{SigningKeys: "no-separator"})
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthService_RS256(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keys, err := NewRSAKeySet("rsa-1", map[string]*rsa.PrivateKey{"rsa-1": privateKey})
+	require.NoError(t, err)
+	assert.True(t, keys.Asymmetric())
+
+	service := NewAuthServiceWithKeys(keys, constants.DefaultJWTClockSkewSeconds*time.Second)
+	pair, err := service.GenerateTokenPair(uuid.New(), "test@example.com", "user", false)
+	require.NoError(t, err)
+	_, err = service.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+
+	// A token verifiable with the published key alone, as an external verifier would
+	jwks := keys.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	jwk := jwks.Keys[0]
+	assert.Equal(t, "rsa-1", jwk.KeyID)
+	assert.Equal(t, "RS256", jwk.Algorithm)
+
+	modulus, err := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+	require.NoError(t, err)
+	exponent, err := base64.RawURLEncoding.DecodeString(jwk.Exponent)
+	require.NoError(t, err)
+	publicKey := &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(new(big.Int).SetBytes(exponent).Int64())}
+
+	_, err = jwt.Parse(pair.AccessToken, func(*jwt.Token) (interface{}, error) { return publicKey, nil },
+		jwt.WithValidMethods([]string{"RS256"}))
+	assert.NoError(t, err)
+}
+
+func TestAuthService_RejectsAlgorithmConfusion(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keys, err := NewRSAKeySet("rsa-1", map[string]*rsa.PrivateKey{"rsa-1": privateKey})
+	require.NoError(t, err)
+
+	// HS256 signed with the public key bytes, which a verifier trusting the header would accept
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserID: uuid.New(), Role: "admin"})
+	forged.Header["kid"] = "rsa-1"
+	forgedString, err := forged.SignedString(publicDER)
+	require.NoError(t, err)
+
+	_, err = NewAuthServiceWithKeys(keys, 0).ValidateToken(forgedString)
+	assert.Error(t, err)
+}
+
+func TestLoadKeySet_RS256(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	retiredKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	currentPath := filepath.Join(dir, "current.pem")
+	require.NoError(t, os.WriteFile(currentPath, pem.EncodeToMemory(&pem.Block{
+		Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}), 0o600))
+	retiredDER, err := x509.MarshalPKIXPublicKey(&retiredKey.PublicKey)
+	require.NoError(t, err)
+	retiredPath := filepath.Join(dir, "retired.pem")
+	require.NoError(t, os.WriteFile(retiredPath, pem.EncodeToMemory(&pem.Block{
+		Type: "PUBLIC KEY", Bytes: retiredDER,
+	}), 0o600))
+
+	cfg := KeySetConfig{Algorithm: "RS256", SigningKeys: "new:" + currentPath + ",old:" + retiredPath, SigningKeyID: "new"}
+	keys, err := LoadKeySet(cfg)
+	require.NoError(t, err)
+	assert.True(t, keys.Asymmetric())
+	jwks := keys.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, []string{"new", "old"}, []string{jwks.Keys[0].KeyID, jwks.Keys[1].KeyID}, "keys are ordered by kid")
+
+	// A public-only key cannot become the signing key
+	cfg.SigningKeyID = "old"
+	_, err = LoadKeySet(cfg)
+	assert.Error(t, err)
+}
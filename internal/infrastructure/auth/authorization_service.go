@@ -31,6 +31,21 @@ func (s *AuthorizationServiceImpl) CheckResourcePermission(ctx context.Context,
 		return err
 	}
 
+	cache, cached := permissionCacheFrom(ctx)
+	if !cached {
+		return s.evaluatePermission(ctx, userID, userRole, resource, action, resourceID)
+	}
+
+	check := permissionCheck{userID: userID, role: userRole, resource: resource, action: action, resourceID: resourceID}
+	if result, ok := cache.get(check); ok {
+		return result
+	}
+	result := s.evaluatePermission(ctx, userID, userRole, resource, action, resourceID)
+	cache.put(check, result)
+	return result
+}
+
+func (s *AuthorizationServiceImpl) evaluatePermission(ctx context.Context, userID uuid.UUID, userRole, resource, action, resourceID string) error {
 	req := &entities.PermissionRequest{
 		UserID:     userID,
 		Role:       userRole,
@@ -94,9 +109,20 @@ func (s *AuthorizationServiceImpl) GetEffectivePermissions(ctx context.Context,
 	return effectivePermissions, nil
 }
 
+// QuickCheck evaluates a role-only permission without a caller identity.
+// It runs with uuid.Nil as the user, so ownership and user-bound conditions
+// never match; use QuickCheckForUser when the caller is known.
 func (s *AuthorizationServiceImpl) QuickCheck(userRole, resource, action string) bool {
-	ctx := context.WithValue(context.Background(), constants.ContextUserRole, userRole)
-	userID := uuid.New()
+	return s.QuickCheckForUser(uuid.Nil, userRole, resource, action)
+}
+
+// QuickCheckForUser evaluates a permission for a known user and role so that
+// user-bound policy conditions are evaluated against the real identity.
+func (s *AuthorizationServiceImpl) QuickCheckForUser(userID uuid.UUID, userRole, resource, action string) bool {
+	ctx := constants.WithUserRole(context.Background(), userRole)
+	if userID != uuid.Nil {
+		ctx = constants.WithUserID(ctx, userID)
+	}
 	err := s.CheckPermission(ctx, userID, resource, action)
 	return err == nil
 }
@@ -112,10 +138,9 @@ func (s *AuthorizationServiceImpl) ValidateRole(userRole string) error {
 }
 
 func (s *AuthorizationServiceImpl) GetAllowedActionsForRole(userRole, resource string) ([]string, error) {
-	ctx := context.WithValue(context.Background(), constants.ContextUserRole, userRole)
-	userID := uuid.New()
+	ctx := constants.WithUserRole(context.Background(), userRole)
 
-	permissions, err := s.GetUserPermissions(ctx, userID)
+	permissions, err := s.GetUserPermissions(ctx, uuid.Nil)
 	if err != nil {
 		return nil, err
 	}
@@ -131,8 +156,8 @@ func (s *AuthorizationServiceImpl) GetAllowedActionsForRole(userRole, resource s
 }
 
 func (s *AuthorizationServiceImpl) validateUserRole(ctx context.Context) (string, error) {
-	userRole, exists := ctx.Value(constants.ContextUserRole).(string)
-	if !exists || userRole == "" {
+	userRole, exists := constants.UserRoleFromContext(ctx)
+	if !exists {
 		return "", errors.ErrUserRoleNotFound
 	}
 	return userRole, nil
@@ -141,20 +166,24 @@ func (s *AuthorizationServiceImpl) validateUserRole(ctx context.Context) (string
 func (s *AuthorizationServiceImpl) buildContextData(ctx context.Context, resourceID string) map[string]interface{} {
 	contextData := make(map[string]interface{})
 
-	if userID, exists := ctx.Value(constants.ContextUserID).(uuid.UUID); exists {
-		contextData[string(constants.ContextUserID)] = userID.String()
+	if userID, exists := constants.UserIDFromContext(ctx); exists {
+		contextData[constants.PolicyContextUserID] = userID.String()
+	}
+
+	if userRole, exists := constants.UserRoleFromContext(ctx); exists {
+		contextData[constants.PolicyContextUserRole] = userRole
 	}
 
-	if userRole, exists := ctx.Value(constants.ContextUserRole).(string); exists {
-		contextData[string(constants.ContextUserRole)] = userRole
+	if userEmail, exists := constants.UserEmailFromContext(ctx); exists {
+		contextData[constants.PolicyContextUserEmail] = userEmail
 	}
 
-	if userEmail, exists := ctx.Value(constants.ContextUserEmail).(string); exists {
-		contextData[string(constants.ContextUserEmail)] = userEmail
+	if clientIP, exists := constants.ClientIPFromContext(ctx); exists {
+		contextData[constants.PolicyContextClientIP] = clientIP
 	}
 
-	if clientIP, exists := ctx.Value(constants.ContextClientIP).(string); exists {
-		contextData[string(constants.ContextClientIP)] = clientIP
+	if ownerID, exists := constants.ResourceOwnerFromContext(ctx); exists {
+		contextData[constants.PolicyContextResourceOwner] = ownerID.String()
 	}
 
 	if resourceID != "" {
@@ -181,11 +210,11 @@ func (s *AuthorizationServiceImpl) extractPermissionsFromPolicies(policies []*en
 }
 
 func (s *AuthorizationServiceImpl) CreateEnrichedContext(baseCtx context.Context, userID uuid.UUID, role, email string) context.Context {
-	ctx := context.WithValue(baseCtx, constants.ContextUserID, userID)
-	ctx = context.WithValue(ctx, constants.ContextUserRole, role)
+	ctx := constants.WithUserID(baseCtx, userID)
+	ctx = constants.WithUserRole(ctx, role)
 
 	if email != "" {
-		ctx = context.WithValue(ctx, constants.ContextUserEmail, email)
+		ctx = constants.WithUserEmail(ctx, email)
 	}
 
 	return ctx
@@ -209,18 +238,18 @@ func (s *AuthorizationServiceImpl) CreateContextFromMicroserviceData(baseCtx con
 
 	ctx := baseCtx
 
-	if userIDStr, exists := contextData[string(constants.ContextUserID)].(string); exists {
+	if userIDStr, exists := contextData[constants.PolicyContextUserID].(string); exists {
 		if userID, parseErr := uuid.Parse(userIDStr); parseErr == nil {
-			ctx = context.WithValue(ctx, constants.ContextUserID, userID)
+			ctx = constants.WithUserID(ctx, userID)
 		}
 	}
 
-	if userRole, exists := contextData[string(constants.ContextUserRole)].(string); exists {
-		ctx = context.WithValue(ctx, constants.ContextUserRole, userRole)
+	if userRole, exists := contextData[constants.PolicyContextUserRole].(string); exists {
+		ctx = constants.WithUserRole(ctx, userRole)
 	}
 
-	if userEmail, exists := contextData[string(constants.ContextUserEmail)].(string); exists {
-		ctx = context.WithValue(ctx, constants.ContextUserEmail, userEmail)
+	if userEmail, exists := contextData[constants.PolicyContextUserEmail].(string); exists {
+		ctx = constants.WithUserEmail(ctx, userEmail)
 	}
 
 	return ctx, nil
@@ -4,6 +4,7 @@ import (
 	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/entities"
 	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
 	"context"
 	"testing"
 
@@ -26,6 +27,11 @@ func (m *MockPolicyEngine) LoadPolicies(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *MockPolicyEngine) ReloadPolicies(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockPolicyEngine) AddPolicy(ctx context.Context, policy *entities.PolicyDocument) error {
 	args := m.Called(ctx, policy)
 	return args.Error(0)
@@ -41,6 +47,80 @@ func (m *MockPolicyEngine) GetPoliciesForRole(ctx context.Context, role string)
 	return args.Get(0).([]*entities.PolicyDocument), args.Error(1)
 }
 
+func (m *MockPolicyEngine) ImportPolicies(ctx context.Context, policies []*entities.PolicyDocument) ([]entities.PolicyImportResult, error) {
+	args := m.Called(ctx, policies)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.PolicyImportResult), args.Error(1)
+}
+
+func (m *MockPolicyEngine) RollbackPolicy(ctx context.Context, policyID uuid.UUID, version string) (*entities.PolicyDocument, error) {
+	args := m.Called(ctx, policyID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.PolicyDocument), args.Error(1)
+}
+
+func (m *MockPolicyEngine) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+type MockPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockPolicyRepository) Create(ctx context.Context, policy *entities.PolicyDocument) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *MockPolicyRepository) GetByRole(ctx context.Context, role string) ([]*entities.PolicyDocument, error) {
+	args := m.Called(ctx, role)
+	return args.Get(0).([]*entities.PolicyDocument), args.Error(1)
+}
+
+func (m *MockPolicyRepository) GetActive(ctx context.Context) ([]*entities.PolicyDocument, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*entities.PolicyDocument), args.Error(1)
+}
+
+func (m *MockPolicyRepository) Update(ctx context.Context, policy *entities.PolicyDocument) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *MockPolicyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPolicyRepository) UpsertByName(ctx context.Context, policies []*entities.PolicyDocument) ([]entities.PolicyImportResult, error) {
+	args := m.Called(ctx, policies)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.PolicyImportResult), args.Error(1)
+}
+
+func (m *MockPolicyRepository) GetVersions(ctx context.Context, id uuid.UUID) ([]*entities.PolicyDocument, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.PolicyDocument), args.Error(1)
+}
+
+func (m *MockPolicyRepository) Rollback(ctx context.Context, id uuid.UUID, version string) (*entities.PolicyDocument, error) {
+	args := m.Called(ctx, id, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.PolicyDocument), args.Error(1)
+}
+
 func TestNewAuthorizationService(t *testing.T) {
 	mockEngine := &MockPolicyEngine{}
 	service := NewAuthorizationService(mockEngine)
@@ -118,7 +198,7 @@ func runCheckPermissionTest(t *testing.T, service repositories.AuthorizationServ
 ) {
 	var ctx context.Context
 	if tt.userRole != "" {
-		ctx = context.WithValue(context.Background(), constants.ContextUserRole, tt.userRole)
+		ctx = constants.WithUserRole(context.Background(), tt.userRole)
 	} else {
 		ctx = context.Background()
 	}
@@ -160,7 +240,7 @@ func TestAuthorizationService_CheckResourcePermission(t *testing.T) {
 	userID := uuid.New()
 	resourceID := "test-resource-id"
 
-	ctx := context.WithValue(context.Background(), constants.ContextUserRole, constants.RoleUser)
+	ctx := constants.WithUserRole(context.Background(), constants.RoleUser)
 
 	mockEngine.On("Evaluate", mock.Anything, mock.MatchedBy(func(req *entities.PermissionRequest) bool {
 		return req.ResourceID == resourceID
@@ -193,7 +273,7 @@ func TestAuthorizationService_GetUserPermissions(t *testing.T) {
 		},
 	}
 
-	ctx := context.WithValue(context.Background(), constants.ContextUserRole, constants.RoleUser)
+	ctx := constants.WithUserRole(context.Background(), constants.RoleUser)
 
 	mockEngine.On("GetPoliciesForRole", ctx, constants.RoleUser).
 		Return(mockPolicies, nil).Once()
@@ -259,3 +339,46 @@ func TestAuthorizationService_QuickCheck(t *testing.T) {
 	assert.True(t, result)
 	mockEngine.AssertExpectations(t)
 }
+
+// newOwnerBoundEngine builds a real policy engine whose only policy allows
+// product updates for the given owner through a user-bound condition.
+func newOwnerBoundEngine(t *testing.T, ownerID uuid.UUID) repositories.PolicyEngine {
+	mockRepo := &MockPolicyRepository{}
+	mockRepo.On("GetActive", mock.Anything).Return([]*entities.PolicyDocument{
+		{
+			ID:       uuid.New(),
+			Name:     "owner-only",
+			IsActive: true,
+			Statements: []entities.PolicyStatement{
+				{
+					Effect:    constants.PolicyEffectAllow,
+					Principal: "role:" + constants.RoleUser,
+					Action:    constants.ActionUpdate,
+					Resource:  constants.PermissionProductUpdate,
+					Conditions: map[string]interface{}{
+						constants.PolicyContextUserID: ownerID.String(),
+					},
+				},
+			},
+		},
+	}, nil)
+
+	engine := NewPolicyEngine(mockRepo, logger.NewLogger())
+	mockRepo.AssertExpectations(t)
+	return engine
+}
+
+func TestAuthorizationService_QuickCheckForUser(t *testing.T) {
+	ownerID := uuid.New()
+	service := NewAuthorizationService(newOwnerBoundEngine(t, ownerID))
+
+	assert.True(t, service.QuickCheckForUser(ownerID, constants.RoleUser, constants.PermissionProductUpdate, constants.ActionUpdate))
+	assert.False(t, service.QuickCheckForUser(uuid.New(), constants.RoleUser, constants.PermissionProductUpdate, constants.ActionUpdate))
+}
+
+func TestAuthorizationService_QuickCheckIgnoresOwnership(t *testing.T) {
+	ownerID := uuid.New()
+	service := NewAuthorizationService(newOwnerBoundEngine(t, ownerID))
+
+	assert.False(t, service.QuickCheck(constants.RoleUser, constants.PermissionProductUpdate, constants.ActionUpdate))
+}
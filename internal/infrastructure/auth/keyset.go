@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultKeyID names the key taken from JWT_SECRET_KEY when no keyset is configured
+const DefaultKeyID = "default"
+
+// signingKey pairs a key with its algorithm. For HMAC both sides are the secret; an RSA key
+// loaded from a public key file can only verify.
+type signingKey struct {
+	method jwt.SigningMethod
+	sign   interface{}
+	verify interface{}
+}
+
+// KeySet holds every key tokens may be verified with. New tokens are signed with the current
+// key and carry its ID in the kid header; retired keys stay until the tokens they signed expire.
+type KeySet struct {
+	currentID string
+	keys      map[string]signingKey
+}
+
+// NewKeySet builds an HS256 keyset from shared secrets
+func NewKeySet(currentID string, secrets map[string][]byte) (*KeySet, error) {
+	keys := make(map[string]signingKey, len(secrets))
+	for id, secret := range secrets {
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("signing key %q has an empty secret", id)
+		}
+		keys[id] = signingKey{method: jwt.SigningMethodHS256, sign: secret, verify: secret}
+	}
+	return newKeySet(currentID, keys)
+}
+
+// NewRSAKeySet builds an RS256 keyset whose public halves can be published as a JWKS
+func NewRSAKeySet(currentID string, privateKeys map[string]*rsa.PrivateKey) (*KeySet, error) {
+	keys := make(map[string]signingKey, len(privateKeys))
+	for id, key := range privateKeys {
+		keys[id] = signingKey{method: jwt.SigningMethodRS256, sign: key, verify: &key.PublicKey}
+	}
+	return newKeySet(currentID, keys)
+}
+
+func newKeySet(currentID string, keys map[string]signingKey) (*KeySet, error) {
+	current, ok := keys[currentID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q is not in the keyset", currentID)
+	}
+	if current.sign == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", currentID)
+	}
+	if _, ok := keys[""]; ok {
+		return nil, fmt.Errorf("keyset entries need a key ID")
+	}
+	return &KeySet{currentID: currentID, keys: keys}, nil
+}
+
+// KeySetConfig holds the JWT_* signing settings, as loaded by config.Load
+type KeySetConfig struct {
+	Algorithm    string
+	SecretKey    string
+	SigningKeys  string
+	SigningKeyID string
+}
+
+// LoadKeySet reads SigningKeys as comma-separated "kid:value" pairs and signs with SigningKeyID,
+// defaulting to the first pair. Values are secrets for HS256 and PEM file paths when Algorithm
+// is RS256. Without SigningKeys it falls back to SecretKey under DefaultKeyID.
+func LoadKeySet(cfg KeySetConfig) (*KeySet, error) {
+	algorithm := cfg.Algorithm
+	if algorithm == "" {
+		algorithm = jwt.SigningMethodHS256.Alg()
+	}
+	if algorithm != jwt.SigningMethodHS256.Alg() && algorithm != jwt.SigningMethodRS256.Alg() {
+		return nil, fmt.Errorf("JWT_SIGNING_ALGORITHM must be HS256 or RS256, got %q", algorithm)
+	}
+
+	raw := cfg.SigningKeys
+	if raw == "" {
+		if algorithm == jwt.SigningMethodRS256.Alg() {
+			return nil, fmt.Errorf("JWT_SIGNING_KEYS is required for RS256")
+		}
+		if cfg.SecretKey == "" {
+			return nil, fmt.Errorf("JWT_SECRET_KEY environment variable is required")
+		}
+		return NewKeySet(DefaultKeyID, map[string][]byte{DefaultKeyID: []byte(cfg.SecretKey)})
+	}
+
+	keys := make(map[string]signingKey)
+	currentID := cfg.SigningKeyID
+	for _, entry := range strings.Split(raw, ",") {
+		id, value, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found || value == "" {
+			return nil, fmt.Errorf("JWT_SIGNING_KEYS entries must look like kid:value")
+		}
+		if _, exists := keys[id]; exists {
+			return nil, fmt.Errorf("JWT_SIGNING_KEYS has duplicate key ID %q", id)
+		}
+
+		if algorithm == jwt.SigningMethodRS256.Alg() {
+			key, err := loadRSAKey(value)
+			if err != nil {
+				return nil, fmt.Errorf("signing key %q: %w", id, err)
+			}
+			keys[id] = key
+		} else {
+			keys[id] = signingKey{method: jwt.SigningMethodHS256, sign: []byte(value), verify: []byte(value)}
+		}
+		if currentID == "" {
+			currentID = id
+		}
+	}
+	return newKeySet(currentID, keys)
+}
+
+// loadRSAKey reads a PEM private key, or a public key for a retired entry that only verifies
+func loadRSAKey(path string) (signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return signingKey{}, err
+	}
+	if privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return signingKey{method: jwt.SigningMethodRS256, sign: privateKey, verify: &privateKey.PublicKey}, nil
+	}
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return signingKey{}, fmt.Errorf("%s holds neither an RSA private nor public key", path)
+	}
+	return signingKey{method: jwt.SigningMethodRS256, verify: publicKey}, nil
+}
+
+func (k *KeySet) current() (string, signingKey) {
+	return k.currentID, k.keys[k.currentID]
+}
+
+// lookup returns the key for a kid. Tokens issued before key IDs existed have none and are
+// checked against the current key.
+func (k *KeySet) lookup(id string) (signingKey, bool) {
+	if id == "" {
+		id = k.currentID
+	}
+	key, ok := k.keys[id]
+	return key, ok
+}
+
+// Asymmetric reports whether tokens are signed with a key that has a publishable public half
+func (k *KeySet) Asymmetric() bool {
+	_, current := k.current()
+	return current.method == jwt.SigningMethodRS256
+}
+
+// JWK is the public part of an RSA signing key as described by RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS lists the public keys of every RSA key in the set, retired ones included, so tokens
+// they signed can still be verified elsewhere. Keys are ordered by kid, so the document is stable.
+func (k *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for id, key := range k.keys {
+		publicKey, ok := key.verify.(*rsa.PublicKey)
+		if !ok {
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, JWK{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: key.method.Alg(),
+			KeyID:     id,
+			Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		})
+	}
+	slices.SortFunc(jwks.Keys, func(a, b JWK) int { return strings.Compare(a.KeyID, b.KeyID) })
+	return jwks
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type permissionCacheKey struct{}
+
+type permissionCheck struct {
+	userID     uuid.UUID
+	role       string
+	resource   string
+	action     string
+	resourceID string
+}
+
+// permissionCache memoizes permission decisions for a single request. It lives in the request
+// context, so it is dropped with the request and a policy change is seen by the next one.
+type permissionCache struct {
+	mu      sync.Mutex
+	results map[permissionCheck]error
+}
+
+// WithPermissionCache returns a context in which repeated permission checks for the same user,
+// role, resource, action and resource ID are evaluated once. Attach it once per request.
+func WithPermissionCache(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(permissionCacheKey{}).(*permissionCache); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, permissionCacheKey{}, &permissionCache{results: make(map[permissionCheck]error)})
+}
+
+func permissionCacheFrom(ctx context.Context) (*permissionCache, bool) {
+	cache, ok := ctx.Value(permissionCacheKey{}).(*permissionCache)
+	return cache, ok
+}
+
+func (c *permissionCache) get(check permissionCheck) (error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err, ok := c.results[check]
+	return err, ok
+}
+
+func (c *permissionCache) put(check permissionCheck, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[check] = err
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingPolicyEngine allows everything except deletes and counts evaluations
+type countingPolicyEngine struct {
+	repositories.PolicyEngine
+	evaluations atomic.Int64
+}
+
+func (e *countingPolicyEngine) Evaluate(_ context.Context, req *entities.PermissionRequest) (*entities.PermissionResponse, error) {
+	e.evaluations.Add(1)
+	return &entities.PermissionResponse{Allowed: req.Action != constants.ActionDelete}, nil
+}
+
+func newPermissionCacheContext(userID uuid.UUID) context.Context {
+	ctx := constants.WithUserID(context.Background(), userID)
+	return constants.WithUserRole(ctx, constants.RoleUser)
+}
+
+func TestPermissionCache_MemoizesWithinRequest(t *testing.T) {
+	engine := &countingPolicyEngine{}
+	service := NewAuthorizationService(engine)
+	userID := uuid.New()
+	ctx := WithPermissionCache(newPermissionCacheContext(userID))
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, service.CheckPermission(ctx, userID, "product", constants.ActionRead))
+		assert.Error(t, service.CheckPermission(ctx, userID, "product", constants.ActionDelete))
+	}
+	assert.EqualValues(t, 2, engine.evaluations.Load(), "denials are cached as well as grants")
+
+	// A different resource ID is a different decision
+	assert.NoError(t, service.CheckResourcePermission(ctx, userID, "product", constants.ActionRead, "42"))
+	assert.EqualValues(t, 3, engine.evaluations.Load())
+
+	// A new request starts with an empty cache
+	assert.NoError(t, service.CheckPermission(WithPermissionCache(newPermissionCacheContext(userID)), userID, "product", constants.ActionRead))
+	assert.EqualValues(t, 4, engine.evaluations.Load())
+}
+
+func TestPermissionCache_DisabledWithoutRequestScope(t *testing.T) {
+	engine := &countingPolicyEngine{}
+	service := NewAuthorizationService(engine)
+	userID := uuid.New()
+	ctx := newPermissionCacheContext(userID)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, service.CheckPermission(ctx, userID, "product", constants.ActionRead))
+	}
+	assert.EqualValues(t, 3, engine.evaluations.Load())
+}
+
+// BenchmarkPermissionCache_List checks one permission per item of a 100 item list and reports
+// how many policy evaluations that costs per request
+func BenchmarkPermissionCache_List(b *testing.B) {
+	const items = 100
+	userID := uuid.New()
+
+	for _, bc := range []struct {
+		name   string
+		cached bool
+	}{{"uncached", false}, {"cached", true}} {
+		b.Run(bc.name, func(b *testing.B) {
+			engine := &countingPolicyEngine{}
+			service := NewAuthorizationService(engine)
+			base := newPermissionCacheContext(userID)
+
+			for i := 0; i < b.N; i++ {
+				ctx := base
+				if bc.cached {
+					ctx = WithPermissionCache(base)
+				}
+				for j := 0; j < items; j++ {
+					_ = service.CheckPermission(ctx, userID, "product", constants.ActionRead)
+				}
+			}
+			b.ReportMetric(float64(engine.evaluations.Load())/float64(b.N), "evaluations/op")
+		})
+	}
+}
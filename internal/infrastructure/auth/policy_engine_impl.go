@@ -6,64 +6,159 @@ import (
 	"clean-architecture-api/internal/domain/errors"
 	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/pkg/logger"
+	"clean-architecture-api/pkg/metrics"
 	"context"
 	"fmt"
+	"os"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+var policyCacheLastLoad = metrics.Default.NewGaugeVec("policy_cache_last_load_timestamp",
+	"Unix time of the last successful policy cache load")
+
 type PolicyEngineImpl struct {
-	policyRepo repositories.PolicyRepository
-	logger     logger.Logger
-	cache      map[string][]*entities.PolicyDocument
-	mutex      sync.RWMutex
+	policyRepo    repositories.PolicyRepository
+	logger        logger.Logger
+	defaultEffect string
+	cache         map[string][]*entities.PolicyDocument
+	mutex         sync.RWMutex
+	notifier      repositories.PolicyChangeNotifier
+	instanceID    string
+	unsubscribe   func()
+	closeOnce     sync.Once
+
+	// lastLoadAt and loadedPolicies describe the last successful load, lastLoadErr the last
+	// attempt when it failed; all are guarded by mutex
+	lastLoadAt     time.Time
+	loadedPolicies int
+	lastLoadErr    error
+
+	// allowLogSample logs one in every n allowed decisions; 0 disables allow logging
+	allowLogSample uint64
+	allowCount     atomic.Uint64
 }
 
 func NewPolicyEngine(policyRepo repositories.PolicyRepository, logger logger.Logger) repositories.PolicyEngine {
+	return NewPolicyEngineWithNotifier(policyRepo, NewNoopPolicyChangeNotifier(), logger)
+}
+
+// NewPolicyEngineWithNotifier creates a policy engine that publishes its policy mutations
+// and reloads its cache when another instance publishes one.
+func NewPolicyEngineWithNotifier(
+	policyRepo repositories.PolicyRepository,
+	notifier repositories.PolicyChangeNotifier,
+	logger logger.Logger,
+) repositories.PolicyEngine {
 	engine := &PolicyEngineImpl{
-		policyRepo: policyRepo,
-		logger:     logger,
-		cache:      make(map[string][]*entities.PolicyDocument),
+		policyRepo:     policyRepo,
+		logger:         logger,
+		defaultEffect:  loadDefaultEffect(logger),
+		cache:          make(map[string][]*entities.PolicyDocument),
+		notifier:       notifier,
+		instanceID:     uuid.NewString(),
+		unsubscribe:    func() {},
+		allowLogSample: loadAllowLogSample(logger),
 	}
 
 	if err := engine.LoadPolicies(context.Background()); err != nil {
 		logger.Error("Failed to load initial policies", err)
 	}
 
+	unsubscribe, err := notifier.Subscribe(context.Background(), engine.handlePolicyChange)
+	if err != nil {
+		logger.Error("Failed to subscribe to policy changes", err)
+	} else {
+		engine.unsubscribe = unsubscribe
+	}
+
 	return engine
 }
 
-func (pe *PolicyEngineImpl) Evaluate(_ context.Context, req *entities.PermissionRequest) (*entities.PermissionResponse, error) {
+// loadDefaultEffect reads POLICY_DEFAULT_EFFECT, the decision used when no statement
+// matches a request. Production always denies by default regardless of the setting.
+func loadDefaultEffect(logger logger.Logger) string {
+	value := os.Getenv("POLICY_DEFAULT_EFFECT")
+	switch {
+	case value == "":
+		return constants.DefaultPolicyEffect
+	case value != constants.PolicyEffectAllow && value != constants.PolicyEffectDeny:
+		logger.Warn(fmt.Sprintf("Invalid POLICY_DEFAULT_EFFECT %q, must be %q or %q; using %q",
+			value, constants.PolicyEffectAllow, constants.PolicyEffectDeny, constants.DefaultPolicyEffect))
+		return constants.DefaultPolicyEffect
+	case value == constants.PolicyEffectAllow && os.Getenv("ENV") == "production":
+		logger.Warn("POLICY_DEFAULT_EFFECT=allow is ignored in production; denying by default")
+		return constants.PolicyEffectDeny
+	}
+	return value
+}
+
+// loadAllowLogSample reads POLICY_ALLOW_LOG_SAMPLE: allowed decisions are logged one in every n,
+// and not at all when it is 0. Denials are always logged.
+func loadAllowLogSample(logger logger.Logger) uint64 {
+	value := os.Getenv("POLICY_ALLOW_LOG_SAMPLE")
+	if value == "" {
+		return constants.DefaultPolicyAllowLogSample
+	}
+	sample, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Invalid POLICY_ALLOW_LOG_SAMPLE %q; using %d", value, constants.DefaultPolicyAllowLogSample))
+		return constants.DefaultPolicyAllowLogSample
+	}
+	return sample
+}
+
+// Evaluate decides req against the cached policies for its role. When ctx is marked with
+// constants.WithPolicyExplain, the response context carries a trace of every statement evaluated.
+func (pe *PolicyEngineImpl) Evaluate(ctx context.Context, req *entities.PermissionRequest) (*entities.PermissionResponse, error) {
 	if req == nil {
 		return &entities.PermissionResponse{
 			Allowed: false,
-			Reason:  "invalid request",
+			Reason:  constants.PolicyReasonInvalidRequest,
 		}, errors.ErrInvalidRequest
 	}
 
 	policies := pe.getPoliciesFromCache(req.Role)
 	if len(policies) == 0 {
 		pe.logger.Info(fmt.Sprintf("No policies found for role: %s", req.Role))
-		return &entities.PermissionResponse{
-			Allowed: false,
-			Reason:  "no policies found for role",
-		}, nil
+		response := pe.defaultResponse(constants.PolicyReasonNoPolicies)
+		if constants.PolicyExplainFromContext(ctx) {
+			response.Context = map[string]interface{}{constants.PolicyExplainKey: []entities.StatementTrace{}}
+		}
+		pe.logEvaluation(req, response)
+		return response, nil
 	}
 
-	response := pe.evaluatePolicies(policies, req)
+	response := pe.evaluatePolicies(policies, req, constants.PolicyExplainFromContext(ctx))
 	pe.logEvaluation(req, response)
 
 	return response, nil
 }
 
-func (pe *PolicyEngineImpl) evaluatePolicies(policies []*entities.PolicyDocument, req *entities.PermissionRequest) *entities.PermissionResponse {
+func (pe *PolicyEngineImpl) evaluatePolicies(
+	policies []*entities.PolicyDocument,
+	req *entities.PermissionRequest,
+	explain bool,
+) *entities.PermissionResponse {
 	var allowPolicies []string
 	var denyPolicies []string
+	var traces []entities.StatementTrace
 
 	for _, policy := range policies {
 		for _, statement := range policy.Statements {
-			if pe.statementMatches(statement, req) {
+			matched := false
+			if explain {
+				trace := pe.traceStatement(policy.Name, statement, req)
+				traces = append(traces, trace)
+				matched = trace.Matched
+			} else {
+				matched = pe.statementMatches(statement, req)
+			}
+			if matched {
 				switch statement.Effect {
 				case constants.PolicyEffectAllow:
 					allowPolicies = append(allowPolicies, policy.Name)
@@ -74,25 +169,71 @@ func (pe *PolicyEngineImpl) evaluatePolicies(policies []*entities.PolicyDocument
 		}
 	}
 
-	if len(denyPolicies) > 0 {
-		return &entities.PermissionResponse{
+	var response *entities.PermissionResponse
+	switch {
+	case len(denyPolicies) > 0:
+		response = &entities.PermissionResponse{
 			Allowed:  false,
-			Reason:   "denied by policy",
+			Reason:   constants.PolicyReasonExplicitDeny,
 			Policies: denyPolicies,
 		}
-	}
-
-	if len(allowPolicies) > 0 {
-		return &entities.PermissionResponse{
+	case len(allowPolicies) > 0:
+		response = &entities.PermissionResponse{
 			Allowed:  true,
-			Reason:   "allowed by policy",
+			Reason:   constants.PolicyReasonExplicitAllow,
 			Policies: allowPolicies,
 		}
+	default:
+		response = pe.defaultResponse(constants.PolicyReasonNoMatch)
+	}
+
+	if explain {
+		if traces == nil {
+			traces = []entities.StatementTrace{}
+		}
+		response.Context = map[string]interface{}{constants.PolicyExplainKey: traces}
+	}
+	return response
+}
+
+// traceStatement evaluates every part of statement, rather than stopping at the first mismatch,
+// and names the first part that failed
+func (pe *PolicyEngineImpl) traceStatement(
+	policyName string,
+	statement entities.PolicyStatement,
+	req *entities.PermissionRequest,
+) entities.StatementTrace {
+	trace := entities.StatementTrace{
+		Policy:            policyName,
+		StatementID:       statement.ID.String(),
+		Effect:            statement.Effect,
+		PrincipalMatched:  pe.matchesPrincipal(statement.Principal, req.Role),
+		ActionMatched:     pe.matchesAction(statement.Action, req.Action),
+		ResourceMatched:   pe.matchesResource(statement.Resource, req.Resource),
+		ConditionsMatched: pe.matchesConditions(statement.Conditions, req),
 	}
+	trace.Matched = trace.PrincipalMatched && trace.ActionMatched && trace.ResourceMatched && trace.ConditionsMatched
+
+	switch {
+	case !trace.PrincipalMatched:
+		trace.Reason = fmt.Sprintf("principal %q does not match role %q", statement.Principal, req.Role)
+	case !trace.ActionMatched:
+		trace.Reason = fmt.Sprintf("action %q does not match %q", statement.Action, req.Action)
+	case !trace.ResourceMatched:
+		trace.Reason = fmt.Sprintf("resource %q does not match %q", statement.Resource, req.Resource)
+	case !trace.ConditionsMatched:
+		trace.Reason = "conditions not satisfied"
+	default:
+		trace.Reason = "matched; effect " + statement.Effect
+	}
+	return trace
+}
 
+// defaultResponse applies the configured default effect when no statement decided the request
+func (pe *PolicyEngineImpl) defaultResponse(reason string) *entities.PermissionResponse {
 	return &entities.PermissionResponse{
-		Allowed: false,
-		Reason:  "no matching policy found",
+		Allowed: pe.defaultEffect == constants.PolicyEffectAllow,
+		Reason:  reason,
 	}
 }
 
@@ -143,7 +284,7 @@ func (pe *PolicyEngineImpl) checkResourceOwnership(req *entities.PermissionReque
 		return true
 	}
 
-	contextOwner, exists := req.Context["resource_owner_id"]
+	contextOwner, exists := req.Context[constants.PolicyContextResourceOwner]
 	if !exists {
 		return false
 	}
@@ -151,42 +292,122 @@ func (pe *PolicyEngineImpl) checkResourceOwnership(req *entities.PermissionReque
 	return contextOwner == req.UserID.String()
 }
 
+// logEvaluation logs denials at WARN with event=policy_denied so they can be alerted on, and a
+// sample of allows at DEBUG
 func (pe *PolicyEngineImpl) logEvaluation(req *entities.PermissionRequest, response *entities.PermissionResponse) {
-	pe.logger.Info(fmt.Sprintf(
-		"Policy evaluation: UserID=%s, Role=%s, Resource=%s, Action=%s, ResourceID=%s, Allowed=%t, Reason=%s",
-		req.UserID.String(),
-		req.Role,
-		req.Resource,
-		req.Action,
-		req.ResourceID,
-		response.Allowed,
-		response.Reason,
-	))
+	if response.Allowed && !pe.sampleAllow() {
+		return
+	}
+
+	log := pe.logger.
+		WithField("user_id", req.UserID.String()).
+		WithField("role", req.Role).
+		WithField("resource", req.Resource).
+		WithField("action", req.Action).
+		WithField("resource_id", req.ResourceID).
+		WithField("reason", response.Reason)
+
+	if !response.Allowed {
+		log.WithField("event", constants.PolicyEventDenied).
+			WithField("deny_policies", response.Policies).
+			Warn("Policy denied")
+		return
+	}
+
+	log.WithField("event", constants.PolicyEventAllowed).
+		WithField("allow_policies", response.Policies).
+		Debug("Policy allowed")
+}
+
+func (pe *PolicyEngineImpl) sampleAllow() bool {
+	if pe.allowLogSample == 0 {
+		return false
+	}
+	return (pe.allowCount.Add(1)-1)%pe.allowLogSample == 0
 }
 
 func (pe *PolicyEngineImpl) LoadPolicies(ctx context.Context) error {
-	policies, err := pe.policyRepo.GetActive(ctx)
+	_, err := pe.loadPolicies(ctx)
+	return err
+}
+
+// ReloadPolicies re-reads the active policies, picking up edits made directly in the database,
+// and asks other instances to do the same. It returns the number of policies loaded.
+func (pe *PolicyEngineImpl) ReloadPolicies(ctx context.Context) (int, error) {
+	count, err := pe.loadPolicies(ctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	pe.mutex.Lock()
-	defer pe.mutex.Unlock()
+	pe.publishChange(ctx, uuid.Nil, constants.PolicyChangeReloaded)
+	return count, nil
+}
 
-	pe.cache = make(map[string][]*entities.PolicyDocument)
+func (pe *PolicyEngineImpl) loadPolicies(ctx context.Context) (int, error) {
+	policies, err := pe.policyRepo.GetActive(ctx)
+	if err != nil {
+		pe.mutex.Lock()
+		pe.lastLoadErr = err
+		pe.mutex.Unlock()
+		return 0, err
+	}
+
+	// Build the new cache from private copies outside the lock, so Evaluate never sees
+	// documents that the repository or a caller might still mutate, then swap it in.
+	cache := make(map[string][]*entities.PolicyDocument)
 	for _, policy := range policies {
-		for _, statement := range policy.Statements {
+		cached := policy.Clone()
+		for _, statement := range cached.Statements {
 			role := pe.extractRoleFromPrincipal(statement.Principal)
 			if role != "" {
-				pe.cache[role] = append(pe.cache[role], policy)
+				cache[role] = append(cache[role], cached)
 			}
 		}
 	}
 
+	loadedAt := time.Now()
+	pe.mutex.Lock()
+	pe.cache = cache
+	pe.lastLoadAt = loadedAt
+	pe.loadedPolicies = len(policies)
+	pe.lastLoadErr = nil
+	pe.mutex.Unlock()
+	policyCacheLastLoad.Set(float64(loadedAt.Unix()))
+
 	pe.logger.Info(fmt.Sprintf("Loaded %d policies into cache", len(policies)))
+	return len(policies), nil
+}
+
+// HealthCheck fails until the cache has loaded at least once, since until then every request
+// falls through to the default effect. A failed reload after that keeps serving the old cache.
+func (pe *PolicyEngineImpl) HealthCheck(ctx context.Context) error {
+	pe.mutex.RLock()
+	defer pe.mutex.RUnlock()
+
+	if pe.lastLoadAt.IsZero() {
+		return fmt.Errorf("policy cache has not loaded: %w", pe.lastLoadErr)
+	}
 	return nil
 }
 
+// HealthDetails reports when the cache last loaded and how many policies it holds
+func (pe *PolicyEngineImpl) HealthDetails() map[string]interface{} {
+	pe.mutex.RLock()
+	defer pe.mutex.RUnlock()
+
+	details := map[string]interface{}{
+		"last_load_at": nil,
+		"policies":     pe.loadedPolicies,
+	}
+	if !pe.lastLoadAt.IsZero() {
+		details["last_load_at"] = pe.lastLoadAt.UTC().Format(time.RFC3339)
+	}
+	if pe.lastLoadErr != nil {
+		details["last_error"] = pe.lastLoadErr.Error()
+	}
+	return details
+}
+
 func (pe *PolicyEngineImpl) extractRoleFromPrincipal(principal string) string {
 	if principal == "*" {
 		return "*"
@@ -197,6 +418,8 @@ func (pe *PolicyEngineImpl) extractRoleFromPrincipal(principal string) string {
 	return ""
 }
 
+// getPoliciesFromCache returns a freshly allocated slice; the documents it points to are
+// never modified once cached, so callers may read them without holding the lock.
 func (pe *PolicyEngineImpl) getPoliciesFromCache(role string) []*entities.PolicyDocument {
 	pe.mutex.RLock()
 	defer pe.mutex.RUnlock()
@@ -228,16 +451,40 @@ func (pe *PolicyEngineImpl) deduplicatePolicies(policies []*entities.PolicyDocum
 	return result
 }
 
+// AddPolicy stores a new policy. A name that is already taken fails with ErrPolicyAlreadyExists:
+// active policies are caught from the cache without a write, the rest by the repository.
 func (pe *PolicyEngineImpl) AddPolicy(ctx context.Context, policy *entities.PolicyDocument) error {
 	if err := pe.validatePolicy(policy); err != nil {
 		return err
 	}
+	if pe.hasCachedPolicy(policy.Name) {
+		return errors.ErrPolicyAlreadyExists
+	}
 
 	if err := pe.policyRepo.Create(ctx, policy); err != nil {
 		return err
 	}
 
-	return pe.LoadPolicies(ctx)
+	if err := pe.LoadPolicies(ctx); err != nil {
+		return err
+	}
+
+	pe.publishChange(ctx, policy.ID, constants.PolicyChangeAdded)
+	return nil
+}
+
+func (pe *PolicyEngineImpl) hasCachedPolicy(name string) bool {
+	pe.mutex.RLock()
+	defer pe.mutex.RUnlock()
+
+	for _, policies := range pe.cache {
+		for _, policy := range policies {
+			if policy.Name == name {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func (pe *PolicyEngineImpl) validatePolicy(policy *entities.PolicyDocument) error {
@@ -259,7 +506,77 @@ func (pe *PolicyEngineImpl) RemovePolicy(ctx context.Context, policyID uuid.UUID
 		return err
 	}
 
-	return pe.LoadPolicies(ctx)
+	if err := pe.LoadPolicies(ctx); err != nil {
+		return err
+	}
+
+	pe.publishChange(ctx, policyID, constants.PolicyChangeRemoved)
+	return nil
+}
+
+// ImportPolicies upserts the given policies by name in one transaction and reloads the cache
+func (pe *PolicyEngineImpl) ImportPolicies(ctx context.Context, policies []*entities.PolicyDocument) ([]entities.PolicyImportResult, error) {
+	for _, policy := range policies {
+		if err := pe.validatePolicy(policy); err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := pe.policyRepo.UpsertByName(ctx, policies)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pe.LoadPolicies(ctx); err != nil {
+		return nil, err
+	}
+
+	pe.publishChange(ctx, uuid.Nil, constants.PolicyChangeImported)
+	return results, nil
+}
+
+// RollbackPolicy reactivates an earlier version of a policy and reloads the cache
+func (pe *PolicyEngineImpl) RollbackPolicy(ctx context.Context, policyID uuid.UUID, version string) (*entities.PolicyDocument, error) {
+	policy, err := pe.policyRepo.Rollback(ctx, policyID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pe.LoadPolicies(ctx); err != nil {
+		return nil, err
+	}
+
+	pe.publishChange(ctx, policy.ID, constants.PolicyChangeRolledBack)
+	return policy, nil
+}
+
+// publishChange notifies other instances; the local change already succeeded, so failures are only logged
+func (pe *PolicyEngineImpl) publishChange(ctx context.Context, policyID uuid.UUID, action string) {
+	event := entities.PolicyChangeEvent{
+		PolicyID: policyID,
+		Action:   action,
+		SourceID: pe.instanceID,
+	}
+	if err := pe.notifier.Publish(ctx, event); err != nil {
+		pe.logger.Error("Failed to publish policy change", err)
+	}
+}
+
+func (pe *PolicyEngineImpl) handlePolicyChange(event entities.PolicyChangeEvent) {
+	if event.SourceID == pe.instanceID {
+		return
+	}
+
+	pe.logger.Info(fmt.Sprintf("Reloading policies after remote %s of policy %s", event.Action, event.PolicyID))
+	if err := pe.LoadPolicies(context.Background()); err != nil {
+		pe.logger.Error("Failed to reload policies after remote change", err)
+	}
+}
+
+// Close stops listening for policy changes from other instances
+func (pe *PolicyEngineImpl) Close() error {
+	pe.closeOnce.Do(pe.unsubscribe)
+	return nil
 }
 
 // GetPoliciesForRole retrieves all policies for a specific role
@@ -0,0 +1,440 @@
+package auth
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	domainerrors "clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sharedPolicyRepository stands in for the database both instances read from
+type sharedPolicyRepository struct {
+	mutex    sync.Mutex
+	policies []*entities.PolicyDocument
+}
+
+func (r *sharedPolicyRepository) Create(_ context.Context, policy *entities.PolicyDocument) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.policies = append(r.policies, policy)
+	return nil
+}
+
+func (r *sharedPolicyRepository) GetByRole(ctx context.Context, _ string) ([]*entities.PolicyDocument, error) {
+	return r.GetActive(ctx)
+}
+
+func (r *sharedPolicyRepository) GetActive(_ context.Context) ([]*entities.PolicyDocument, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]*entities.PolicyDocument(nil), r.policies...), nil
+}
+
+func (r *sharedPolicyRepository) Update(_ context.Context, _ *entities.PolicyDocument) error {
+	return nil
+}
+
+func (r *sharedPolicyRepository) Delete(_ context.Context, id uuid.UUID) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i, policy := range r.policies {
+		if policy.ID == id {
+			r.policies = append(r.policies[:i], r.policies[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *sharedPolicyRepository) UpsertByName(_ context.Context, policies []*entities.PolicyDocument) ([]entities.PolicyImportResult, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	results := make([]entities.PolicyImportResult, 0, len(policies))
+	for _, policy := range policies {
+		status := constants.PolicyImportCreated
+		for i, existing := range r.policies {
+			if existing.Name == policy.Name {
+				policy.ID = existing.ID
+				r.policies = append(r.policies[:i], r.policies[i+1:]...)
+				status = constants.PolicyImportUpdated
+				break
+			}
+		}
+		r.policies = append(r.policies, policy)
+		results = append(results, entities.NewPolicyImportResult(policy, status))
+	}
+	return results, nil
+}
+
+func (r *sharedPolicyRepository) GetVersions(_ context.Context, _ uuid.UUID) ([]*entities.PolicyDocument, error) {
+	return nil, nil
+}
+
+func (r *sharedPolicyRepository) Rollback(_ context.Context, _ uuid.UUID, _ string) (*entities.PolicyDocument, error) {
+	return nil, domainerrors.ErrPolicyVersionNotFound
+}
+
+func TestPolicyEngine_ReloadsOnRemoteChange(t *testing.T) {
+	repo := &sharedPolicyRepository{}
+	notifier := NewInMemoryPolicyChangeNotifier()
+	log := logger.NewLogger()
+
+	first := NewPolicyEngineWithNotifier(repo, notifier, log)
+	second := NewPolicyEngineWithNotifier(repo, notifier, log)
+	defer first.Close()
+	defer second.Close()
+
+	req := &entities.PermissionRequest{
+		UserID:   uuid.New(),
+		Role:     "auditor",
+		Resource: "report",
+		Action:   constants.ActionRead,
+	}
+
+	response, err := second.Evaluate(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, response.Allowed)
+
+	policy := &entities.PolicyDocument{
+		ID:   uuid.New(),
+		Name: "auditor-reports",
+		Statements: []entities.PolicyStatement{{
+			ID:        uuid.New(),
+			Effect:    constants.PolicyEffectAllow,
+			Principal: "role:auditor",
+			Action:    constants.ActionRead,
+			Resource:  "report",
+		}},
+	}
+	require.NoError(t, first.AddPolicy(context.Background(), policy))
+
+	response, err = second.Evaluate(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, response.Allowed)
+
+	require.NoError(t, second.Close())
+	require.NoError(t, first.RemovePolicy(context.Background(), policy.ID))
+
+	response, err = second.Evaluate(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, response.Allowed, "closed engine should no longer receive reloads")
+}
+
+func TestPolicyEngine_ReloadPicksUpPoliciesWrittenDirectly(t *testing.T) {
+	repo := &sharedPolicyRepository{}
+	notifier := NewInMemoryPolicyChangeNotifier()
+	log := logger.NewLogger()
+
+	first := NewPolicyEngineWithNotifier(repo, notifier, log)
+	second := NewPolicyEngineWithNotifier(repo, notifier, log)
+	defer first.Close()
+	defer second.Close()
+
+	req := &entities.PermissionRequest{UserID: uuid.New(), Role: "auditor", Resource: "report", Action: constants.ActionRead}
+	ctx := context.Background()
+
+	// Written straight to the store, as an operator editing the database would
+	require.NoError(t, repo.Create(ctx, &entities.PolicyDocument{
+		ID:   uuid.New(),
+		Name: "auditor-reports",
+		Statements: []entities.PolicyStatement{{
+			ID:        uuid.New(),
+			Effect:    constants.PolicyEffectAllow,
+			Principal: "role:auditor",
+			Action:    constants.ActionRead,
+			Resource:  "report",
+		}},
+	}))
+
+	response, err := first.Evaluate(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, response.Allowed, "the cache should not see the policy before a reload")
+
+	count, err := first.ReloadPolicies(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	for _, engine := range []repositories.PolicyEngine{first, second} {
+		response, err = engine.Evaluate(ctx, req)
+		require.NoError(t, err)
+		assert.True(t, response.Allowed)
+	}
+}
+
+func TestPolicyEngine_AddPolicyRejectsDuplicateName(t *testing.T) {
+	repo := &sharedPolicyRepository{}
+	engine := NewPolicyEngine(repo, logger.NewLogger())
+	ctx := context.Background()
+
+	policy := func() *entities.PolicyDocument {
+		return &entities.PolicyDocument{
+			ID:   uuid.New(),
+			Name: "auditor-reports",
+			Statements: []entities.PolicyStatement{{
+				ID:        uuid.New(),
+				Effect:    constants.PolicyEffectAllow,
+				Principal: "role:auditor",
+				Action:    constants.ActionRead,
+				Resource:  "report",
+			}},
+		}
+	}
+	require.NoError(t, engine.AddPolicy(ctx, policy()))
+
+	err := engine.AddPolicy(ctx, policy())
+	require.ErrorIs(t, err, domainerrors.ErrPolicyAlreadyExists)
+	assert.Equal(t, domainerrors.CategoryConflict, domainerrors.ErrPolicyAlreadyExists.Category)
+	assert.Len(t, repo.policies, 1, "the duplicate is turned away before it is written")
+}
+
+func TestPolicyEngine_DefaultEffect(t *testing.T) {
+	req := &entities.PermissionRequest{UserID: uuid.New(), Role: "guest", Resource: "report", Action: constants.ActionRead}
+
+	tests := []struct {
+		name          string
+		env           string
+		defaultEffect string
+		wantAllowed   bool
+	}{
+		{name: "unset denies", wantAllowed: false},
+		{name: "allow in development", env: "development", defaultEffect: constants.PolicyEffectAllow, wantAllowed: true},
+		{name: "allow ignored in production", env: "production", defaultEffect: constants.PolicyEffectAllow, wantAllowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ENV", tt.env)
+			t.Setenv("POLICY_DEFAULT_EFFECT", tt.defaultEffect)
+			engine := NewPolicyEngine(&sharedPolicyRepository{}, logger.NewLogger())
+
+			response, err := engine.Evaluate(context.Background(), req)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAllowed, response.Allowed)
+			assert.Equal(t, constants.PolicyReasonNoPolicies, response.Reason)
+		})
+	}
+}
+
+// Run with -race: Evaluate must not race with LoadPolicies swapping the cache
+func TestPolicyEngine_ConcurrentEvaluateAndReload(t *testing.T) {
+	repo := &sharedPolicyRepository{}
+	require.NoError(t, repo.Create(context.Background(), &entities.PolicyDocument{
+		ID:   uuid.New(),
+		Name: "user-reports",
+		Statements: []entities.PolicyStatement{{
+			Effect:     constants.PolicyEffectAllow,
+			Principal:  "role:" + constants.RoleUser,
+			Action:     constants.ActionRead,
+			Resource:   "report",
+			Conditions: map[string]interface{}{},
+		}},
+	}))
+	engine := NewPolicyEngine(repo, logger.NewLogger())
+
+	req := &entities.PermissionRequest{
+		UserID:   uuid.New(),
+		Role:     constants.RoleUser,
+		Resource: "report",
+		Action:   constants.ActionRead,
+		Context:  map[string]interface{}{},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				response, err := engine.Evaluate(context.Background(), req)
+				if assert.NoError(t, err) {
+					assert.True(t, response.Allowed)
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 50; i++ {
+		require.NoError(t, engine.LoadPolicies(context.Background()))
+	}
+	wg.Wait()
+}
+
+type logLine struct {
+	level   string
+	message string
+	fields  map[string]any
+}
+
+// recordingLogger keeps every line with the fields attached through WithField
+type recordingLogger struct {
+	mutex  *sync.Mutex
+	lines  *[]logLine
+	fields map[string]any
+}
+
+func newRecordingLogger() *recordingLogger {
+	return &recordingLogger{mutex: &sync.Mutex{}, lines: &[]logLine{}, fields: map[string]any{}}
+}
+
+func (l *recordingLogger) record(level string, args ...any) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	*l.lines = append(*l.lines, logLine{level: level, message: fmt.Sprint(args...), fields: l.fields})
+}
+
+func (l *recordingLogger) Info(args ...any)  { l.record("info", args...) }
+func (l *recordingLogger) Error(args ...any) { l.record("error", args...) }
+func (l *recordingLogger) Fatal(args ...any) { l.record("fatal", args...) }
+func (l *recordingLogger) Warn(args ...any)  { l.record("warn", args...) }
+func (l *recordingLogger) Debug(args ...any) { l.record("debug", args...) }
+
+func (l *recordingLogger) WithField(key string, value any) logger.Logger {
+	fields := make(map[string]any, len(l.fields)+1)
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	return &recordingLogger{mutex: l.mutex, lines: l.lines, fields: fields}
+}
+
+func (l *recordingLogger) WithError(err error) logger.Logger { return l.WithField("error", err) }
+
+func (l *recordingLogger) events(event string) []logLine {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	var matched []logLine
+	for _, line := range *l.lines {
+		if line.fields["event"] == event {
+			matched = append(matched, line)
+		}
+	}
+	return matched
+}
+
+func TestPolicyEngine_LogsDenialsAtWarn(t *testing.T) {
+	repo := &sharedPolicyRepository{}
+	require.NoError(t, repo.Create(context.Background(), &entities.PolicyDocument{
+		ID:   uuid.New(),
+		Name: "no-deletes",
+		Statements: []entities.PolicyStatement{
+			{Effect: constants.PolicyEffectAllow, Principal: "role:" + constants.RoleUser, Action: "*", Resource: "report"},
+			{Effect: constants.PolicyEffectDeny, Principal: "role:" + constants.RoleUser, Action: constants.ActionDelete, Resource: "report"},
+		},
+	}))
+	log := newRecordingLogger()
+	engine := NewPolicyEngine(repo, log)
+	userID := uuid.New()
+
+	request := func(action string) *entities.PermissionRequest {
+		return &entities.PermissionRequest{UserID: userID, Role: constants.RoleUser, Resource: "report", Action: action}
+	}
+	_, err := engine.Evaluate(context.Background(), request(constants.ActionDelete))
+	require.NoError(t, err)
+	_, err = engine.Evaluate(context.Background(), request(constants.ActionRead))
+	require.NoError(t, err)
+
+	denied := log.events(constants.PolicyEventDenied)
+	require.Len(t, denied, 1)
+	assert.Equal(t, "warn", denied[0].level)
+	assert.Equal(t, userID.String(), denied[0].fields["user_id"])
+	assert.Equal(t, "report", denied[0].fields["resource"])
+	assert.Equal(t, constants.ActionDelete, denied[0].fields["action"])
+	assert.Equal(t, []string{"no-deletes"}, denied[0].fields["deny_policies"])
+
+	allowed := log.events(constants.PolicyEventAllowed)
+	require.Len(t, allowed, 1)
+	assert.Equal(t, "debug", allowed[0].level)
+}
+
+func TestPolicyEngine_SamplesAllowLogs(t *testing.T) {
+	tests := []struct {
+		sample     string
+		wantLogged int
+	}{
+		{sample: "0", wantLogged: 0},
+		{sample: "3", wantLogged: 2},
+		{sample: "", wantLogged: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run("sample="+tt.sample, func(t *testing.T) {
+			t.Setenv("POLICY_ALLOW_LOG_SAMPLE", tt.sample)
+			t.Setenv("POLICY_DEFAULT_EFFECT", constants.PolicyEffectAllow)
+			log := newRecordingLogger()
+			engine := NewPolicyEngine(&sharedPolicyRepository{}, log)
+
+			req := &entities.PermissionRequest{UserID: uuid.New(), Role: constants.RoleUser, Resource: "report", Action: constants.ActionRead}
+			for i := 0; i < 6; i++ {
+				_, err := engine.Evaluate(context.Background(), req)
+				require.NoError(t, err)
+			}
+
+			assert.Len(t, log.events(constants.PolicyEventAllowed), tt.wantLogged)
+		})
+	}
+}
+
+func TestPolicyEngine_ExplainTracesEveryStatement(t *testing.T) {
+	repo := &sharedPolicyRepository{}
+	require.NoError(t, repo.Create(context.Background(), &entities.PolicyDocument{
+		ID:   uuid.New(),
+		Name: "user-reports",
+		Statements: []entities.PolicyStatement{
+			{ID: uuid.New(), Effect: constants.PolicyEffectAllow, Principal: "role:" + constants.RoleUser, Action: constants.ActionRead, Resource: "report"},
+			{ID: uuid.New(), Effect: constants.PolicyEffectAllow, Principal: "role:" + constants.RoleUser, Action: constants.ActionDelete, Resource: "report"},
+			{ID: uuid.New(), Effect: constants.PolicyEffectDeny, Principal: "role:" + constants.RoleAdmin, Action: "*", Resource: "*"},
+			{
+				ID: uuid.New(), Effect: constants.PolicyEffectDeny, Principal: "*", Action: "*", Resource: "report",
+				Conditions: map[string]interface{}{"department": "finance"},
+			},
+		},
+	}))
+	engine := NewPolicyEngine(repo, logger.NewLogger())
+	req := &entities.PermissionRequest{
+		UserID:   uuid.New(),
+		Role:     constants.RoleUser,
+		Resource: "report",
+		Action:   constants.ActionRead,
+		Context:  map[string]interface{}{"department": "sales"},
+	}
+
+	response, err := engine.Evaluate(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, response.Allowed)
+	assert.Nil(t, response.Context, "explain must be off by default")
+
+	response, err = engine.Evaluate(constants.WithPolicyExplain(context.Background()), req)
+	require.NoError(t, err)
+	assert.True(t, response.Allowed)
+	assert.Equal(t, constants.PolicyReasonExplicitAllow, response.Reason)
+
+	traces, ok := response.Context[constants.PolicyExplainKey].([]entities.StatementTrace)
+	require.True(t, ok)
+	require.Len(t, traces, 4)
+
+	assert.True(t, traces[0].Matched)
+	assert.Contains(t, traces[0].Reason, "matched")
+
+	assert.False(t, traces[1].Matched)
+	assert.True(t, traces[1].PrincipalMatched)
+	assert.False(t, traces[1].ActionMatched)
+	assert.Contains(t, traces[1].Reason, "action")
+
+	assert.False(t, traces[2].PrincipalMatched)
+	assert.Contains(t, traces[2].Reason, "principal")
+
+	assert.True(t, traces[3].ResourceMatched)
+	assert.False(t, traces[3].ConditionsMatched)
+	assert.Equal(t, "conditions not satisfied", traces[3].Reason)
+}
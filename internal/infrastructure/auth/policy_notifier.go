@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"context"
+	"sync"
+)
+
+// NoopPolicyChangeNotifier is used by single-instance deployments where the local reload is enough
+type NoopPolicyChangeNotifier struct{}
+
+func NewNoopPolicyChangeNotifier() repositories.PolicyChangeNotifier {
+	return NoopPolicyChangeNotifier{}
+}
+
+func (NoopPolicyChangeNotifier) Publish(_ context.Context, _ entities.PolicyChangeEvent) error {
+	return nil
+}
+
+func (NoopPolicyChangeNotifier) Subscribe(_ context.Context, _ func(entities.PolicyChangeEvent)) (func(), error) {
+	return func() {}, nil
+}
+
+// InMemoryPolicyChangeNotifier fans events out to subscribers in the same process
+type InMemoryPolicyChangeNotifier struct {
+	mutex    sync.RWMutex
+	nextID   int
+	handlers map[int]func(entities.PolicyChangeEvent)
+}
+
+func NewInMemoryPolicyChangeNotifier() *InMemoryPolicyChangeNotifier {
+	return &InMemoryPolicyChangeNotifier{
+		handlers: make(map[int]func(entities.PolicyChangeEvent)),
+	}
+}
+
+func (n *InMemoryPolicyChangeNotifier) Publish(_ context.Context, event entities.PolicyChangeEvent) error {
+	n.mutex.RLock()
+	handlers := make([]func(entities.PolicyChangeEvent), 0, len(n.handlers))
+	for _, handler := range n.handlers {
+		handlers = append(handlers, handler)
+	}
+	n.mutex.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+	return nil
+}
+
+func (n *InMemoryPolicyChangeNotifier) Subscribe(_ context.Context, handler func(entities.PolicyChangeEvent)) (func(), error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	id := n.nextID
+	n.nextID++
+	n.handlers[id] = handler
+
+	return func() {
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+		delete(n.handlers, id)
+	}, nil
+}
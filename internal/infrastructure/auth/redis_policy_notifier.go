@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisPolicyChangeNotifier distributes policy change events over a Redis pub/sub channel
+type RedisPolicyChangeNotifier struct {
+	client  *redis.Client
+	channel string
+	logger  logger.Logger
+}
+
+// NewRedisPolicyChangeNotifier connects to the Redis server described by redisURL
+func NewRedisPolicyChangeNotifier(redisURL, channel string, logger logger.Logger) (repositories.PolicyChangeNotifier, error) {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+
+	return &RedisPolicyChangeNotifier{
+		client:  redis.NewClient(options),
+		channel: channel,
+		logger:  logger,
+	}, nil
+}
+
+func (n *RedisPolicyChangeNotifier) Publish(ctx context.Context, event entities.PolicyChangeEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode policy change event: %w", err)
+	}
+	return n.client.Publish(ctx, n.channel, payload).Err()
+}
+
+func (n *RedisPolicyChangeNotifier) Subscribe(ctx context.Context, handler func(entities.PolicyChangeEvent)) (func(), error) {
+	pubsub := n.client.Subscribe(ctx, n.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", n.channel, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for message := range pubsub.Channel() {
+			var event entities.PolicyChangeEvent
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				n.logger.Error("Failed to decode policy change event", err)
+				continue
+			}
+			handler(event)
+		}
+	}()
+
+	return func() {
+		if err := pubsub.Close(); err != nil {
+			n.logger.Error("Failed to close policy change subscription", err)
+		}
+		<-done
+	}, nil
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/domain/repositories"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServiceContextMaxAge bounds how long a signed service context is accepted, limiting replay
+const ServiceContextMaxAge = 5 * time.Minute
+
+// EncodeServiceContext serializes the caller identity in ctx into a header value and a signature
+// of the form "<unix timestamp>.<hex HMAC-SHA256>", keyed with the secret shared by all instances
+func EncodeServiceContext(ctx context.Context, codec repositories.ServiceContextCodec, secret string) (string, string, error) {
+	data, err := codec.SerializeContextForMicroservice(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	value := base64.RawURLEncoding.EncodeToString([]byte(data))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	return value, timestamp + "." + signServiceContext(secret, value, timestamp), nil
+}
+
+// DecodeServiceContext verifies a header produced by EncodeServiceContext and restores the
+// caller identity onto baseCtx, along with the time it was signed
+func DecodeServiceContext(
+	baseCtx context.Context,
+	codec repositories.ServiceContextCodec,
+	secret, value, signature string,
+) (context.Context, error) {
+	timestamp, mac, found := strings.Cut(signature, ".")
+	if !found || secret == "" {
+		return nil, errors.ErrInvalidServiceContext
+	}
+
+	issuedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errors.ErrInvalidServiceContext
+	}
+	age := time.Since(time.Unix(issuedAt, 0))
+	if age > ServiceContextMaxAge || age < -ServiceContextMaxAge {
+		return nil, errors.ErrInvalidServiceContext
+	}
+
+	expected := signServiceContext(secret, value, timestamp)
+	if !hmac.Equal([]byte(mac), []byte(expected)) {
+		return nil, errors.ErrInvalidServiceContext
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.ErrInvalidServiceContext
+	}
+
+	return codec.CreateContextFromMicroserviceData(constants.WithForwardedAt(baseCtx, time.Unix(issuedAt, 0)), string(data))
+}
+
+func signServiceContext(secret, value, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
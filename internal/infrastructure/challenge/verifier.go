@@ -0,0 +1,110 @@
+// Package challenge verifies the CAPTCHA-style responses clients send with registration and login.
+package challenge
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrMissingResponse = errors.New("challenge response is missing")
+
+// NoopVerifier accepts every request; it is used while CHALLENGE_ENABLED is off
+type NoopVerifier struct{}
+
+func NewNoopVerifier() repositories.ChallengeVerifier {
+	return NoopVerifier{}
+}
+
+func (NoopVerifier) Verify(_ context.Context, _, _ string) error {
+	return nil
+}
+
+type HTTPVerifierConfig struct {
+	URL     string
+	Secret  string
+	Timeout time.Duration
+}
+
+// HTTPVerifier checks responses against a reCAPTCHA-style siteverify endpoint: it POSTs the
+// secret, response and remote IP as a form and expects {"success": true} back
+type HTTPVerifier struct {
+	config HTTPVerifierConfig
+	client *http.Client
+}
+
+func NewHTTPVerifier(config HTTPVerifierConfig) *HTTPVerifier {
+	if config.Timeout <= 0 {
+		config.Timeout = constants.DefaultChallengeTimeout
+	}
+	return &HTTPVerifier{config: config, client: &http.Client{Timeout: config.Timeout}}
+}
+
+func (v *HTTPVerifier) Verify(ctx context.Context, response, remoteIP string) error {
+	if strings.TrimSpace(response) == "" {
+		return ErrMissingResponse
+	}
+
+	form := url.Values{"secret": {v.config.Secret}, "response": {response}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.config.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("challenge verifier returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if !result.Success {
+		return fmt.Errorf("challenge rejected: %s", strings.Join(result.ErrorCodes, ","))
+	}
+	return nil
+}
+
+// NewVerifierFromEnv returns an HTTPVerifier when CHALLENGE_ENABLED=true and a NoopVerifier
+// otherwise. Enabling it without CHALLENGE_VERIFY_URL is a configuration error.
+func NewVerifierFromEnv() (repositories.ChallengeVerifier, error) {
+	if enabled, _ := strconv.ParseBool(os.Getenv("CHALLENGE_ENABLED")); !enabled {
+		return NewNoopVerifier(), nil
+	}
+
+	config := HTTPVerifierConfig{
+		URL:     os.Getenv("CHALLENGE_VERIFY_URL"),
+		Secret:  os.Getenv("CHALLENGE_SECRET"),
+		Timeout: constants.DefaultChallengeTimeout,
+	}
+	if config.URL == "" {
+		return nil, errors.New("CHALLENGE_VERIFY_URL is required when CHALLENGE_ENABLED=true")
+	}
+	if value, err := time.ParseDuration(os.Getenv("CHALLENGE_TIMEOUT")); err == nil && value > 0 {
+		config.Timeout = value
+	}
+
+	return NewHTTPVerifier(config), nil
+}
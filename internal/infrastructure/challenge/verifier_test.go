@@ -0,0 +1,50 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPVerifier_Verify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "site-secret", r.PostForm.Get("secret"))
+		assert.Equal(t, "203.0.113.7", r.PostForm.Get("remoteip"))
+
+		result := map[string]interface{}{"success": r.PostForm.Get("response") == "human"}
+		if r.PostForm.Get("response") != "human" {
+			result["error-codes"] = []string{"invalid-input-response"}
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+
+	verifier := NewHTTPVerifier(HTTPVerifierConfig{URL: server.URL, Secret: "site-secret"})
+	ctx := context.Background()
+
+	assert.NoError(t, verifier.Verify(ctx, "human", "203.0.113.7"))
+	assert.ErrorContains(t, verifier.Verify(ctx, "bot", "203.0.113.7"), "invalid-input-response")
+	assert.ErrorIs(t, verifier.Verify(ctx, "", "203.0.113.7"), ErrMissingResponse)
+}
+
+func TestNewVerifierFromEnv(t *testing.T) {
+	verifier, err := NewVerifierFromEnv()
+	require.NoError(t, err)
+	assert.IsType(t, NoopVerifier{}, verifier)
+	assert.NoError(t, verifier.Verify(context.Background(), "", ""))
+
+	t.Setenv("CHALLENGE_ENABLED", "true")
+	_, err = NewVerifierFromEnv()
+	assert.Error(t, err, "enabling without a verify URL must fail")
+
+	t.Setenv("CHALLENGE_VERIFY_URL", "https://challenge.example.com/siteverify")
+	verifier, err = NewVerifierFromEnv()
+	require.NoError(t, err)
+	assert.IsType(t, &HTTPVerifier{}, verifier)
+}
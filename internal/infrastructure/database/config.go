@@ -3,7 +3,9 @@ package database
 import (
 	"clean-architecture-api/internal/domain/constants"
 	"fmt"
+	"net"
 	"os"
+	"strings"
 )
 
 type DatabaseConfig struct {
@@ -12,6 +14,8 @@ type DatabaseConfig struct {
 	User     string
 	Password string
 	Name     string
+	// ReplicaHosts lists read replicas as "host" or "host:port"; they share credentials with the primary
+	ReplicaHosts []string
 }
 
 func NewDatabaseConfig() (*DatabaseConfig, error) {
@@ -26,9 +30,41 @@ func NewDatabaseConfig() (*DatabaseConfig, error) {
 		User:     getEnvOrDefault("DB_USER", constants.DefaultDBUser),
 		Password: password,
 		Name:     getEnvOrDefault("DB_NAME", constants.DefaultDBName),
+
+		ReplicaHosts: ParseReplicaHosts(os.Getenv("DB_REPLICA_HOSTS")),
 	}, nil
 }
 
+// DSN builds a Postgres connection string for the given host and port
+func (c *DatabaseConfig) DSN(host, port string) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Ho_Chi_Minh",
+		host, c.User, c.Password, c.Name, port)
+}
+
+// ReplicaDSNs returns one DSN per configured replica, defaulting to the primary port
+func (c *DatabaseConfig) ReplicaDSNs() []string {
+	dsns := make([]string, 0, len(c.ReplicaHosts))
+	for _, replica := range c.ReplicaHosts {
+		host, port, err := net.SplitHostPort(replica)
+		if err != nil {
+			host, port = replica, c.Port
+		}
+		dsns = append(dsns, c.DSN(host, port))
+	}
+	return dsns
+}
+
+// ParseReplicaHosts splits the comma-separated DB_REPLICA_HOSTS value
+func ParseReplicaHosts(value string) []string {
+	var hosts []string
+	for _, host := range strings.Split(value, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
 type SQLiteConfig struct {
 	DBPath string
 }
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseConfig_ReplicaDSNs(t *testing.T) {
+	config := &DatabaseConfig{
+		Port:         "5432",
+		User:         "app",
+		Password:     "secret",
+		Name:         "shop",
+		ReplicaHosts: ParseReplicaHosts(" replica-a , replica-b:6432,,"),
+	}
+
+	dsns := config.ReplicaDSNs()
+	if assert.Len(t, dsns, 2) {
+		assert.Contains(t, dsns[0], "host=replica-a ")
+		assert.Contains(t, dsns[0], "port=5432 ", "a replica without a port uses the primary's")
+		assert.Contains(t, dsns[1], "host=replica-b ")
+		assert.Contains(t, dsns[1], "port=6432 ")
+		assert.Contains(t, dsns[1], "user=app password=secret dbname=shop ")
+	}
+
+	assert.Empty(t, (&DatabaseConfig{ReplicaHosts: ParseReplicaHosts("")}).ReplicaDSNs())
+}
@@ -3,8 +3,10 @@ package database
 import (
 	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/entities"
-	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/infrastructure/repository"
 	"clean-architecture-api/pkg/logger"
+	nrgorm "clean-architecture-api/pkg/newrelic"
 	"context"
 	"fmt"
 
@@ -13,6 +15,7 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
 func NewDatabase() (*gorm.DB, error) {
@@ -25,20 +28,34 @@ func NewDatabaseWithNewRelic(nrApp *newrelic.Application) (*gorm.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load database config: %w", err)
 	}
+	return NewDatabaseFromConfig(config, nrApp)
+}
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Ho_Chi_Minh",
-		config.Host, config.User, config.Password, config.Name, config.Port)
+// NewDatabaseFromConfig connects to the Postgres primary and replicas described by config and
+// migrates the schema.
+func NewDatabaseFromConfig(config *DatabaseConfig, nrApp *newrelic.Application) (*gorm.DB, error) {
+	dsn := config.DSN(config.Host, config.Port)
 
 	// Configure GORM logger
 	gormLogger := gormlogger.Default.LogMode(gormlogger.Info)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: gormLogger,
+		// Report unique violations as gorm.ErrDuplicatedKey, whatever the driver
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := registerReplicas(db, config.ReplicaDSNs()); err != nil {
+		return nil, fmt.Errorf("failed to register read replicas: %w", err)
+	}
+
+	if err := nrgorm.InstrumentGorm(db, nrApp); err != nil {
+		return nil, fmt.Errorf("failed to instrument database: %w", err)
+	}
+
 	if err := autoMigrate(db); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
@@ -46,41 +63,174 @@ func NewDatabaseWithNewRelic(nrApp *newrelic.Application) (*gorm.DB, error) {
 	return db, nil
 }
 
+// registerReplicas routes plain reads to the replicas and everything else, including
+// transactions, to the primary. It is a no-op when no replicas are configured.
+func registerReplicas(db *gorm.DB, replicaDSNs []string) error {
+	if len(replicaDSNs) == 0 {
+		return nil
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(replicaDSNs))
+	for _, dsn := range replicaDSNs {
+		replicas = append(replicas, postgres.Open(dsn))
+	}
+
+	return db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}))
+}
+
 func autoMigrate(db *gorm.DB) error {
+	if err := dropLegacyEmailIndex(db, &entities.User{}); err != nil {
+		return err
+	}
 	return db.AutoMigrate(
 		&entities.User{},
 		&entities.Product{},
 		&entities.PolicyDocument{},
 		&entities.PolicyStatement{},
-		&auth.AuditLogEntry{},
+		&entities.OutboxEvent{},
+		&entities.RefreshToken{},
+		&entities.Reservation{},
+		&entities.AuditLogEntry{},
 	)
 }
 
+// dropLegacyEmailIndex removes the old unique index on users.email, which also covered soft-deleted
+// rows and so blocked re-registering a deleted user's address. AutoMigrate creates the partial
+// replacement but never drops indexes on its own.
+func dropLegacyEmailIndex(db *gorm.DB, model interface{}) error {
+	const legacyIndex = "idx_users_email"
+	if !db.Migrator().HasIndex(model, legacyIndex) {
+		return nil
+	}
+	return db.Migrator().DropIndex(model, legacyIndex)
+}
+
+// InitializeDefaultPolicies seeds the built-in policies, upgrading them when their definitions
+// change. Policies created through the API are left alone.
 func InitializeDefaultPolicies(db *gorm.DB, logger logger.Logger) error {
-	return initializePoliciesWithModel(db, logger, &entities.PolicyDocument{}, func() error {
-		ctx := context.Background()
-		policies := []*entities.PolicyDocument{
-			createAdminPolicy(),
-			createUserPolicy(),
+	return seedBuiltinPolicies(context.Background(), db, logger, &entities.PolicyDocument{},
+		repository.NewPolicyRepository(db, logger))
+}
+
+// SeedSystemUser creates the user row behind constants.SystemUserID, so audit entries and
+// created_by columns written by internal operations point at a real user
+func SeedSystemUser(db *gorm.DB, logger logger.Logger) error {
+	return seedSystemUserWithModel(db, logger, &entities.User{}, newSystemUser())
+}
+
+// newSystemUser is inactive and its password is not a bcrypt hash, so nobody can log in as it
+func newSystemUser() *entities.User {
+	id := constants.SystemUserID()
+	return &entities.User{
+		BaseEntity: entities.BaseEntity{ID: id, CreatedBy: id, UpdatedBy: id},
+		Email:      constants.SystemUserEmail,
+		Password:   "!",
+		FirstName:  "System",
+		LastName:   "User",
+		Role:       constants.RoleAdmin,
+		IsActive:   false,
+	}
+}
+
+func seedSystemUserWithModel(db *gorm.DB, logger logger.Logger, model interface{}, user interface{}) error {
+	id := constants.SystemUserID().String()
+	var count int64
+	if err := db.Unscoped().Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	// is_active defaults to true on insert, so the false value has to be written separately
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Model(user).Update("is_active", false).Error
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info("Created system user " + id)
+	return nil
+}
+
+// builtinPolicyRevision marks the built-in policy definitions below. Bump it whenever one of them
+// changes so that existing databases pick the change up on their next start.
+const builtinPolicyRevision = 1
+
+func builtinPolicies() []*entities.PolicyDocument {
+	return []*entities.PolicyDocument{
+		createAdminPolicy(),
+		createUserPolicy(),
+	}
+}
+
+// seedBuiltinPolicies creates missing built-in policies and stores a new version of each one whose
+// seeded revision is older than builtinPolicyRevision and whose statements differ. A built-in
+// policy edited through the API keeps the edit until the next revision bump.
+func seedBuiltinPolicies(ctx context.Context, db *gorm.DB, logger logger.Logger, model interface{},
+	policyRepo repositories.PolicyRepository) error {
+	active, err := policyRepo.GetActive(ctx)
+	if err != nil {
+		return err
+	}
+	activeByName := make(map[string]*entities.PolicyDocument, len(active))
+	for _, policy := range active {
+		activeByName[policy.Name] = policy
+	}
+
+	var pending []*entities.PolicyDocument
+	for _, builtin := range builtinPolicies() {
+		var seeded int
+		err := db.WithContext(ctx).Unscoped().Model(model).Where("name = ?", builtin.Name).
+			Select("COALESCE(MAX(builtin_revision), 0)").Scan(&seeded).Error
+		if err != nil {
+			return err
 		}
 
-		for _, policy := range policies {
-			if err := createPolicyWithStatements(ctx, db, policy); err != nil {
-				logger.Error("Failed to create policy: "+policy.Name, err)
+		current := activeByName[builtin.Name]
+		switch {
+		case seeded >= builtin.BuiltinRevision:
+			continue
+		case current != nil && current.SameStatements(builtin):
+			err := db.WithContext(ctx).Model(model).Where("id = ?", current.ID.String()).
+				Update("builtin_revision", builtin.BuiltinRevision).Error
+			if err != nil {
 				return err
 			}
-			logger.Info("Created policy: " + policy.Name)
+		default:
+			pending = append(pending, builtin)
 		}
+	}
+
+	if len(pending) == 0 {
+		logger.Info("Built-in policies are up to date")
 		return nil
-	})
+	}
+
+	results, err := policyRepo.UpsertByName(ctx, pending)
+	if err != nil {
+		logger.Error("Failed to seed built-in policies", err)
+		return err
+	}
+	for i, result := range results {
+		logger.Info(fmt.Sprintf("Built-in policy %s %s as version %s", result.Name, result.Status, pending[i].Version))
+	}
+	return nil
 }
 
 func createAdminPolicy() *entities.PolicyDocument {
 	return &entities.PolicyDocument{
-		ID:       uuid.New(),
-		Name:     "admin-full-access",
-		Version:  "1.0",
-		IsActive: true,
+		ID:              uuid.New(),
+		Name:            "admin-full-access",
+		Version:         "1.0",
+		IsActive:        true,
+		BuiltinRevision: builtinPolicyRevision,
 		Statements: []entities.PolicyStatement{
 			{
 				ID:         uuid.New(),
@@ -125,50 +275,11 @@ func createUserPolicy() *entities.PolicyDocument {
 	}
 
 	return &entities.PolicyDocument{
-		ID:         uuid.New(),
-		Name:       "user-product-access",
-		Version:    "1.0",
-		IsActive:   true,
-		Statements: statements,
-	}
-}
-
-func createPolicyWithStatements(ctx context.Context, db *gorm.DB, policy *entities.PolicyDocument) error {
-	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(policy).Error; err != nil {
-			return err
-		}
-
-		for i := range policy.Statements {
-			policy.Statements[i].ID = uuid.New()
-			policy.Statements[i].PolicyID = policy.ID
-		}
-
-		if len(policy.Statements) > 0 {
-			if err := tx.Create(&policy.Statements).Error; err != nil {
-				return err
-			}
-		}
-
-		return nil
-	})
-}
-
-func initializePoliciesWithModel(db *gorm.DB, logger logger.Logger, model interface{}, createFunc func() error) error {
-	var count int64
-	if err := db.Model(model).Count(&count).Error; err != nil {
-		return err
-	}
-
-	if count > 0 {
-		logger.Info("Policies already exist, skipping initialization")
-		return nil
+		ID:              uuid.New(),
+		Name:            "user-product-access",
+		Version:         "1.0",
+		IsActive:        true,
+		Statements:      statements,
+		BuiltinRevision: builtinPolicyRevision,
 	}
-
-	if err := createFunc(); err != nil {
-		return err
-	}
-
-	logger.Info("Default policies initialized successfully")
-	return nil
 }
@@ -11,7 +11,8 @@ import (
 
 func NewInMemoryDatabase() (*gorm.DB, error) {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger:         logger.Default.LogMode(logger.Info),
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, err
@@ -22,6 +23,10 @@ func NewInMemoryDatabase() (*gorm.DB, error) {
 		&entities.ProductSQLite{},
 		&entities.PolicyDocumentSQLite{},
 		&entities.PolicyStatementSQLite{},
+		&entities.OutboxEvent{},
+		&entities.RefreshToken{},
+		&entities.Reservation{},
+		&entities.AuditLogEntry{},
 	); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/infrastructure/repository"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func activePolicies(t *testing.T, db *gorm.DB) map[string]*entities.PolicyDocument {
+	t.Helper()
+	policies, err := repository.NewPolicySQLiteRepository(db, logger.NewLogger()).GetActive(context.Background())
+	require.NoError(t, err)
+
+	byName := make(map[string]*entities.PolicyDocument, len(policies))
+	for _, policy := range policies {
+		byName[policy.Name] = policy
+	}
+	return byName
+}
+
+func countPolicyVersions(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	require.NoError(t, db.Model(&entities.PolicyDocumentSQLite{}).Count(&count).Error)
+	return count
+}
+
+func TestInitializeSQLiteDefaultPolicies_FirstRun(t *testing.T) {
+	db, err := NewInMemoryDatabase()
+	require.NoError(t, err)
+
+	require.NoError(t, InitializeSQLiteDefaultPolicies(db, logger.NewLogger()))
+
+	active := activePolicies(t, db)
+	require.Len(t, active, 2)
+	for _, builtin := range builtinPolicies() {
+		policy := active[builtin.Name]
+		require.NotNil(t, policy, builtin.Name)
+		assert.Equal(t, "1.0", policy.Version)
+		assert.Equal(t, builtinPolicyRevision, policy.BuiltinRevision)
+		assert.True(t, policy.SameStatements(builtin), builtin.Name)
+	}
+
+	// Seeding again is a no-op
+	require.NoError(t, InitializeSQLiteDefaultPolicies(db, logger.NewLogger()))
+	assert.EqualValues(t, 2, countPolicyVersions(t, db))
+}
+
+func TestInitializeSQLiteDefaultPolicies_UpgradesChangedBuiltins(t *testing.T) {
+	db, err := NewInMemoryDatabase()
+	require.NoError(t, err)
+	ctx := context.Background()
+	policyRepo := repository.NewPolicySQLiteRepository(db, logger.NewLogger())
+
+	// A database seeded before revisions existed: the admin policy matches the code, the user
+	// policy is an older definition, and an operator has added a policy of their own
+	admin := createAdminPolicy()
+	admin.BuiltinRevision = 0
+	require.NoError(t, policyRepo.Create(ctx, admin))
+	stale := createUserPolicy()
+	stale.BuiltinRevision = 0
+	stale.Statements = stale.Statements[:1]
+	require.NoError(t, policyRepo.Create(ctx, stale))
+	custom := &entities.PolicyDocument{
+		Name:     "support-read-users",
+		Version:  "1.0",
+		IsActive: true,
+		Statements: []entities.PolicyStatement{{
+			Effect:    constants.PolicyEffectAllow,
+			Principal: "role:support",
+			Action:    constants.ActionRead,
+			Resource:  constants.PermissionUserRead,
+		}},
+	}
+	require.NoError(t, policyRepo.Create(ctx, custom))
+
+	require.NoError(t, InitializeSQLiteDefaultPolicies(db, logger.NewLogger()))
+
+	active := activePolicies(t, db)
+	require.Len(t, active, 3)
+	assert.Equal(t, "1.0", active["admin-full-access"].Version, "an unchanged policy is only marked, not re-versioned")
+	assert.Equal(t, builtinPolicyRevision, active["admin-full-access"].BuiltinRevision)
+
+	userPolicy := active["user-product-access"]
+	assert.Equal(t, "2.0", userPolicy.Version)
+	assert.Equal(t, builtinPolicyRevision, userPolicy.BuiltinRevision)
+	assert.True(t, userPolicy.SameStatements(createUserPolicy()))
+	versions, err := policyRepo.GetVersions(ctx, userPolicy.ID)
+	require.NoError(t, err)
+	assert.Len(t, versions, 2, "the previous definition is kept for rollback")
+
+	assert.Equal(t, "1.0", active["support-read-users"].Version)
+	assert.True(t, active["support-read-users"].SameStatements(custom))
+
+	// An admin's later edit to a built-in policy survives restarts until the revision is bumped
+	edited := createUserPolicy()
+	edited.BuiltinRevision = 0
+	edited.Statements = edited.Statements[1:2]
+	require.NoError(t, policyRepo.Update(ctx, edited))
+	versionCount := countPolicyVersions(t, db)
+
+	require.NoError(t, InitializeSQLiteDefaultPolicies(db, logger.NewLogger()))
+	assert.Equal(t, versionCount, countPolicyVersions(t, db))
+	assert.True(t, activePolicies(t, db)["user-product-access"].SameStatements(edited))
+}
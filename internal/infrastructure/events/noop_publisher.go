@@ -0,0 +1,22 @@
+package events
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"context"
+)
+
+// NoopPublisher discards events; it is used when no webhook is configured
+type NoopPublisher struct{}
+
+func NewNoopPublisher() repositories.EventPublisher {
+	return NoopPublisher{}
+}
+
+func (NoopPublisher) Publish(_ context.Context, _ entities.DomainEvent) error {
+	return nil
+}
+
+func (NoopPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,130 @@
+package events
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Sender makes one synchronous delivery attempt for an event
+type Sender interface {
+	Send(ctx context.Context, event entities.DomainEvent) error
+}
+
+// OutboxPublisher records events in the outbox table instead of sending them. Called with
+// a transactional context, the event is only stored if the surrounding change commits.
+type OutboxPublisher struct {
+	outboxRepo repositories.OutboxRepository
+}
+
+func NewOutboxPublisher(outboxRepo repositories.OutboxRepository) *OutboxPublisher {
+	return &OutboxPublisher{outboxRepo: outboxRepo}
+}
+
+func (p *OutboxPublisher) Publish(ctx context.Context, event entities.DomainEvent) error {
+	outboxEvent, err := entities.NewOutboxEvent(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox event: %w", err)
+	}
+	return p.outboxRepo.Add(ctx, outboxEvent)
+}
+
+func (p *OutboxPublisher) Close() error {
+	return nil
+}
+
+// OutboxWorker delivers pending outbox rows and marks them sent. An event whose delivery
+// succeeded but could not be marked is sent again, so delivery is at least once.
+type OutboxWorker struct {
+	outboxRepo  repositories.OutboxRepository
+	sender      Sender
+	logger      logger.Logger
+	batchSize   int
+	maxAttempts int
+	interval    time.Duration
+
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+func NewOutboxWorker(
+	outboxRepo repositories.OutboxRepository,
+	sender Sender,
+	interval time.Duration,
+	maxAttempts int,
+	logger logger.Logger,
+) *OutboxWorker {
+	return &OutboxWorker{
+		outboxRepo:  outboxRepo,
+		sender:      sender,
+		logger:      logger,
+		batchSize:   constants.DefaultOutboxBatchSize,
+		maxAttempts: maxAttempts,
+		interval:    interval,
+		stop:        make(chan struct{}),
+	}
+}
+
+// RunOnce delivers one batch of pending events and returns how many were sent
+func (w *OutboxWorker) RunOnce(ctx context.Context) (int, error) {
+	pending, err := w.outboxRepo.FetchPending(ctx, w.batchSize, w.maxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, outboxEvent := range pending {
+		event, err := outboxEvent.DomainEvent()
+		if err == nil {
+			err = w.sender.Send(ctx, event)
+		}
+		if err != nil {
+			w.logger.Error(fmt.Sprintf("Outbox delivery failed for event %s (%s)", outboxEvent.ID, outboxEvent.EventType), err)
+			if markErr := w.outboxRepo.MarkFailed(ctx, outboxEvent.ID, err.Error()); markErr != nil {
+				return sent, markErr
+			}
+			continue
+		}
+
+		if err := w.outboxRepo.MarkSent(ctx, outboxEvent.ID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// Start polls the outbox every interval until Stop is called
+func (w *OutboxWorker) Start() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				if _, err := w.RunOnce(context.Background()); err != nil {
+					w.logger.Error("Outbox worker run failed", err)
+				}
+			}
+		}
+	}()
+}
+
+func (w *OutboxWorker) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+		w.wg.Wait()
+	})
+}
@@ -0,0 +1,141 @@
+package events
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/infrastructure/repository"
+	"clean-architecture-api/internal/usecase"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type recordingSender struct {
+	events []entities.DomainEvent
+	err    error
+}
+
+func (s *recordingSender) Send(ctx context.Context, event entities.DomainEvent) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+type failingOutboxRepository struct {
+	repositories.OutboxRepository
+}
+
+func (r *failingOutboxRepository) Add(ctx context.Context, event *entities.OutboxEvent) error {
+	return errors.New("outbox unavailable")
+}
+
+func setupOutboxDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+
+	// Every connection to :memory: is a separate database
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&entities.Product{}, &entities.OutboxEvent{}))
+	return db
+}
+
+func newOutboxProductUseCase(db *gorm.DB, outboxRepo repositories.OutboxRepository) usecase.ProductUseCase {
+	log := logger.NewLogger()
+	productRepo := repository.NewProductRepository(db, nil, nil, nil, log)
+	return usecase.NewProductUseCase(productRepo, repository.NewReservationRepository(db), repository.NewAuditRepository(db), NewOutboxPublisher(outboxRepo), repository.NewTransactionManager(db), usecase.ProductSettings{}, log)
+}
+
+func pendingOutboxEvents(t *testing.T, db *gorm.DB) []entities.OutboxEvent {
+	var pending []entities.OutboxEvent
+	require.NoError(t, db.Where("sent_at IS NULL").Find(&pending).Error)
+	return pending
+}
+
+func TestOutbox_EventSurvivesCrashBeforeDelivery(t *testing.T) {
+	db := setupOutboxDB(t)
+	outboxRepo := repository.NewOutboxRepository(db)
+	productUseCase := newOutboxProductUseCase(db, outboxRepo)
+	ctx := context.Background()
+
+	product := &entities.Product{Name: "Widget", Price: 10, Stock: 1}
+	require.NoError(t, productUseCase.Create(ctx, product, uuid.New()))
+
+	// No worker ran, as if the process died right after the commit
+	pending := pendingOutboxEvents(t, db)
+	require.Len(t, pending, 1)
+	assert.Equal(t, constants.EventProductCreated, pending[0].EventType)
+
+	sender := &recordingSender{}
+	worker := NewOutboxWorker(outboxRepo, sender, time.Minute, 3, logger.NewLogger())
+
+	sent, err := worker.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, sender.events, 1)
+	assert.Equal(t, pending[0].ID, sender.events[0].ID)
+	assert.Equal(t, constants.EventProductCreated, sender.events[0].Type)
+	data, ok := sender.events[0].Data.(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, product.ID.String(), data["id"])
+	assert.Empty(t, pendingOutboxEvents(t, db))
+
+	sent, err = worker.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Len(t, sender.events, 1)
+}
+
+func TestOutbox_FailedDeliveryStaysPending(t *testing.T) {
+	db := setupOutboxDB(t)
+	outboxRepo := repository.NewOutboxRepository(db)
+	productUseCase := newOutboxProductUseCase(db, outboxRepo)
+	ctx := context.Background()
+
+	require.NoError(t, productUseCase.Create(ctx, &entities.Product{Name: "Widget", Price: 10, Stock: 1}, uuid.New()))
+
+	worker := NewOutboxWorker(outboxRepo, &recordingSender{err: errors.New("receiver down")}, time.Minute, 2, logger.NewLogger())
+	for i := 0; i < 2; i++ {
+		sent, err := worker.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+	}
+
+	pending := pendingOutboxEvents(t, db)
+	require.Len(t, pending, 1)
+	assert.Equal(t, 2, pending[0].Attempts)
+	assert.Equal(t, "receiver down", pending[0].LastError)
+
+	// Rows that used up their attempts are no longer picked up
+	fetched, err := outboxRepo.FetchPending(ctx, 10, 2)
+	require.NoError(t, err)
+	assert.Empty(t, fetched)
+}
+
+func TestOutbox_FailedWriteRollsBackChange(t *testing.T) {
+	db := setupOutboxDB(t)
+	productUseCase := newOutboxProductUseCase(db, &failingOutboxRepository{})
+
+	err := productUseCase.Create(context.Background(), &entities.Product{Name: "Widget", Price: 10, Stock: 1}, uuid.New())
+	assert.Error(t, err)
+
+	var count int64
+	require.NoError(t, db.Model(&entities.Product{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,188 @@
+package events
+
+import (
+	"bytes"
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrQueueFull = errors.New("webhook queue is full")
+
+type WebhookConfig struct {
+	URL            string
+	Secret         string
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	Timeout        time.Duration
+	QueueSize      int
+}
+
+// NewWebhookConfigFromEnv returns nil when WEBHOOK_URL is not set
+func NewWebhookConfigFromEnv() *WebhookConfig {
+	url := os.Getenv("WEBHOOK_URL")
+	if url == "" {
+		return nil
+	}
+
+	config := &WebhookConfig{
+		URL:            url,
+		Secret:         os.Getenv("WEBHOOK_SECRET"),
+		MaxAttempts:    constants.DefaultWebhookMaxAttempts,
+		InitialBackoff: constants.DefaultWebhookInitialBackoff,
+		Timeout:        constants.DefaultWebhookTimeout,
+		QueueSize:      constants.DefaultWebhookQueueSize,
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS")); err == nil && value > 0 {
+		config.MaxAttempts = value
+	}
+	if value, err := time.ParseDuration(os.Getenv("WEBHOOK_TIMEOUT")); err == nil && value > 0 {
+		config.Timeout = value
+	}
+
+	return config
+}
+
+// WebhookPublisher POSTs each event as JSON to a single URL from a background worker,
+// signing the body with HMAC-SHA256 and retrying failed deliveries with backoff.
+type WebhookPublisher struct {
+	config    WebhookConfig
+	client    *http.Client
+	logger    logger.Logger
+	queue     chan entities.DomainEvent
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+func NewWebhookPublisher(config WebhookConfig, logger logger.Logger) *WebhookPublisher {
+	if config.QueueSize <= 0 {
+		config.QueueSize = constants.DefaultWebhookQueueSize
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+
+	publisher := &WebhookPublisher{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		logger: logger,
+		queue:  make(chan entities.DomainEvent, config.QueueSize),
+		done:   make(chan struct{}),
+	}
+
+	publisher.wg.Add(1)
+	go publisher.run()
+
+	return publisher
+}
+
+// Publish enqueues the event and returns immediately; it fails only when the queue is full
+func (p *WebhookPublisher) Publish(_ context.Context, event entities.DomainEvent) error {
+	select {
+	case p.queue <- event:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting retries and waits for the worker to deliver what is already queued
+func (p *WebhookPublisher) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.done)
+		p.wg.Wait()
+	})
+	return nil
+}
+
+func (p *WebhookPublisher) run() {
+	defer p.wg.Done()
+	for {
+		select {
+		case event := <-p.queue:
+			p.deliver(event)
+		case <-p.done:
+			for {
+				select {
+				case event := <-p.queue:
+					p.deliver(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *WebhookPublisher) deliver(event entities.DomainEvent) {
+	backoff := p.config.InitialBackoff
+	var err error
+	for attempt := 1; attempt <= p.config.MaxAttempts; attempt++ {
+		if err = p.Send(context.Background(), event); err == nil {
+			return
+		}
+		if attempt == p.config.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-p.done:
+			// Shutting down: skip the remaining retries so Close doesn't hang
+			p.logger.Warn(fmt.Sprintf("Dropping retries for webhook event %s during shutdown", event.ID))
+			return
+		}
+		backoff *= 2
+	}
+
+	p.logger.Error(fmt.Sprintf("Giving up on webhook event %s (%s) after %d attempts", event.ID, event.Type, p.config.MaxAttempts), err)
+}
+
+// Send makes a single signed delivery attempt and reports whether the webhook accepted it
+func (p *WebhookPublisher) Send(ctx context.Context, event entities.DomainEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(constants.WebhookEventHeader, event.Type)
+	req.Header.Set(constants.WebhookIDHeader, event.ID.String())
+	req.Header.Set(constants.WebhookSignatureHeader, Sign(p.config.Secret, body))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for body: "sha256=" followed by the hex HMAC-SHA256
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,80 @@
+package events
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedWebhook struct {
+	body    []byte
+	headers http.Header
+}
+
+func TestWebhookPublisher_DeliversSignedPayloadWithRetry(t *testing.T) {
+	var calls int32
+	received := make(chan receivedWebhook, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedWebhook{body: body, headers: r.Header.Clone()}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	publisher := NewWebhookPublisher(WebhookConfig{
+		URL:            server.URL,
+		Secret:         "test-secret",
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Timeout:        time.Second,
+	}, logger.NewLogger())
+	defer publisher.Close()
+
+	productID := uuid.New()
+	event := entities.NewDomainEvent(constants.EventProductDeleted, map[string]uuid.UUID{"id": productID})
+	require.NoError(t, publisher.Publish(context.Background(), event))
+
+	var webhook receivedWebhook
+	select {
+	case webhook = <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, "application/json", webhook.headers.Get("Content-Type"))
+	assert.Equal(t, constants.EventProductDeleted, webhook.headers.Get(constants.WebhookEventHeader))
+	assert.Equal(t, event.ID.String(), webhook.headers.Get(constants.WebhookIDHeader))
+	assert.Equal(t, Sign("test-secret", webhook.body), webhook.headers.Get(constants.WebhookSignatureHeader))
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(webhook.body, &payload))
+	assert.Equal(t, event.ID.String(), payload["id"])
+	assert.Equal(t, constants.EventProductDeleted, payload["type"])
+	assert.NotEmpty(t, payload["occurred_at"])
+	assert.Equal(t, map[string]interface{}{"id": productID.String()}, payload["data"])
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"a":1}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t,
+		"sha256=aa9e2e3575f5d7098b6caccd790888c36d5fdb63342a73bada2d6a51747a8494",
+		Sign("secret", []byte(`{"a":1}`)),
+	)
+}
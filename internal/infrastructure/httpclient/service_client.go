@@ -0,0 +1,51 @@
+// Package httpclient provides outbound HTTP clients for calling other service instances.
+package httpclient
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"net/http"
+	"time"
+)
+
+// ServiceContextTransport attaches the signed caller identity from each request's context,
+// so the receiving instance's ServiceContext middleware can act on behalf of the same user
+type ServiceContextTransport struct {
+	Base   http.RoundTripper
+	codec  repositories.ServiceContextCodec
+	secret string
+}
+
+func NewServiceContextTransport(base http.RoundTripper, codec repositories.ServiceContextCodec, secret string) *ServiceContextTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &ServiceContextTransport{Base: base, codec: codec, secret: secret}
+}
+
+func (t *ServiceContextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := constants.UserIDFromContext(req.Context()); !ok {
+		return t.Base.RoundTrip(req)
+	}
+
+	value, signature, err := auth.EncodeServiceContext(req.Context(), t.codec, t.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	// RoundTrippers must not modify the caller's request
+	outbound := req.Clone(req.Context())
+	outbound.Header.Set(constants.ServiceContextHeader, value)
+	outbound.Header.Set(constants.ServiceContextSignatureHeader, signature)
+	return t.Base.RoundTrip(outbound)
+}
+
+// NewServiceClient returns an http.Client that forwards the caller identity; build requests
+// with http.NewRequestWithContext using the incoming request's context
+func NewServiceClient(codec repositories.ServiceContextCodec, secret string, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: NewServiceContextTransport(nil, codec, secret),
+		Timeout:   timeout,
+	}
+}
@@ -0,0 +1,137 @@
+package httpclient
+
+import (
+	"clean-architecture-api/internal/delivery/middleware"
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "shared-secret"
+
+type identity struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+	Email  string `json:"email"`
+}
+
+// rewriteTransport alters the signature after ServiceContextTransport has set it
+type rewriteTransport struct {
+	base   http.RoundTripper
+	mutate func(signature string) string
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signature := req.Header.Get(constants.ServiceContextSignatureHeader)
+	req.Header.Set(constants.ServiceContextSignatureHeader, t.mutate(signature))
+	return t.base.RoundTrip(req)
+}
+
+func newReceivingInstance(t *testing.T) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ServiceContext(&auth.AuthorizationServiceImpl{}, testSecret, logger.NewLogger()))
+	router.GET("/whoami", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var id identity
+		if userID, ok := constants.UserIDFromContext(ctx); ok {
+			id.UserID = userID.String()
+		}
+		id.Role, _ = constants.UserRoleFromContext(ctx)
+		id.Email, _ = constants.UserEmailFromContext(ctx)
+		c.JSON(http.StatusOK, id)
+	})
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func callerContext(userID uuid.UUID) context.Context {
+	authService := &auth.AuthorizationServiceImpl{}
+	return authService.CreateEnrichedContext(context.Background(), userID, constants.RoleAdmin, "admin@example.com")
+}
+
+func TestServiceClient_PreservesIdentity(t *testing.T) {
+	server := newReceivingInstance(t)
+	client := NewServiceClient(&auth.AuthorizationServiceImpl{}, testSecret, 5*time.Second)
+	userID := uuid.New()
+
+	req, err := http.NewRequestWithContext(callerContext(userID), http.MethodGet, server.URL+"/whoami", nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var id identity
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&id))
+	assert.Equal(t, userID.String(), id.UserID)
+	assert.Equal(t, constants.RoleAdmin, id.Role)
+	assert.Equal(t, "admin@example.com", id.Email)
+
+	assert.Empty(t, req.Header.Get(constants.ServiceContextHeader), "caller's request must not be modified")
+}
+
+func TestServiceClient_AnonymousRequestCarriesNoIdentity(t *testing.T) {
+	server := newReceivingInstance(t)
+	client := NewServiceClient(&auth.AuthorizationServiceImpl{}, testSecret, 5*time.Second)
+
+	resp, err := client.Get(server.URL + "/whoami")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var id identity
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&id))
+	assert.Empty(t, id.UserID)
+}
+
+func TestServiceContext_RejectsBadSignature(t *testing.T) {
+	server := newReceivingInstance(t)
+	codec := &auth.AuthorizationServiceImpl{}
+
+	tests := []struct {
+		name   string
+		secret string
+		mutate func(signature string) string
+	}{
+		{"wrong secret", "other-secret", func(signature string) string { return signature }},
+		{"tampered signature", testSecret, func(signature string) string { return signature + "00" }},
+		{"expired", testSecret, func(signature string) string {
+			stale := strconv.FormatInt(time.Now().Add(-2*auth.ServiceContextMaxAge).Unix(), 10)
+			return stale + signature[strings.Index(signature, "."):]
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: NewServiceContextTransport(
+				&rewriteTransport{base: http.DefaultTransport, mutate: tt.mutate},
+				codec,
+				tt.secret,
+			)}
+			req, err := http.NewRequestWithContext(callerContext(uuid.New()), http.MethodGet, server.URL+"/whoami", nil)
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) repositories.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Add joins the transaction in ctx, so an audited change and its entry commit together
+func (r *auditRepository) Add(ctx context.Context, entry *entities.AuditLogEntry) error {
+	return connFor(ctx, r.db).WithContext(ctx).Create(entry).Error
+}
+
+func (r *auditRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.AuditLogEntry, error) {
+	var entries []*entities.AuditLogEntry
+	err := connFor(ctx, r.db).WithContext(ctx).Where("user_id = ?", userID).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "timestamp"}, Desc: true}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: true}).
+		Limit(limit).Offset(offset).Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *auditRepository) ListByEntity(
+	ctx context.Context,
+	entityID uuid.UUID,
+	resources []string,
+	limit, offset int,
+) ([]*entities.AuditLogEntry, error) {
+	var entries []*entities.AuditLogEntry
+	err := connFor(ctx, r.db).WithContext(ctx).Where("entity_id = ? AND resource IN ?", entityID, resources).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "timestamp"}, Desc: true}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: true}).
+		Limit(limit).Offset(offset).Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// DeleteBefore deletes in batches so a large purge never holds a long lock on the table
+func (r *auditRepository) DeleteBefore(ctx context.Context, cutoff time.Time, batchSize int) (int, error) {
+	deleted := 0
+	for {
+		db := r.db.WithContext(ctx)
+		batch := db.Model(&entities.AuditLogEntry{}).Select("id").Where("timestamp < ?", cutoff).Limit(batchSize)
+		result := db.Where("id IN (?)", batch).Delete(&entities.AuditLogEntry{})
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		deleted += int(result.RowsAffected)
+		if result.RowsAffected < int64(batchSize) {
+			return deleted, nil
+		}
+	}
+}
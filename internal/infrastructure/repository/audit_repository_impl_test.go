@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepository_ListByUser(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&entities.AuditLogEntry{}))
+	repo := NewAuditRepository(db)
+	ctx := context.Background()
+
+	alice, bob := uuid.New(), uuid.New()
+	start := time.Now().Add(-time.Hour)
+	for i, action := range []string{"create", "read", "update"} {
+		require.NoError(t, repo.Add(ctx, &entities.AuditLogEntry{
+			UserID: alice, Action: action, Resource: "user:" + action, Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	require.NoError(t, repo.Add(ctx, &entities.AuditLogEntry{UserID: bob, Action: "delete", Resource: "user:delete", Timestamp: start}))
+
+	entries, err := repo.ListByUser(ctx, alice, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{"update", "read", "create"}, []string{entries[0].Action, entries[1].Action, entries[2].Action})
+
+	page, err := repo.ListByUser(ctx, alice, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "read", page[0].Action)
+}
+
+func TestAuditRetentionSweeper_RemovesOnlyExpiredEntries(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&entities.AuditLogEntry{}))
+	repo := NewAuditRepository(db)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, repo.Add(ctx, &entities.AuditLogEntry{
+			UserID: userID, Action: "old", Resource: "user:read", Timestamp: time.Now().AddDate(0, 0, -40-i),
+		}))
+	}
+	require.NoError(t, repo.Add(ctx, &entities.AuditLogEntry{
+		UserID: userID, Action: "recent", Resource: "user:read", Timestamp: time.Now().AddDate(0, 0, -10),
+	}))
+
+	deleted, err := repo.DeleteBefore(ctx, time.Now().AddDate(0, 0, -42), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, deleted, "batches must continue until no expired rows remain")
+
+	sweeper := NewAuditRetentionSweeper(repo, 30*24*time.Hour, time.Hour, logger.NewLogger())
+	deleted, err = sweeper.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	remaining, err := repo.ListByUser(ctx, userID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "recent", remaining[0].Action)
+}
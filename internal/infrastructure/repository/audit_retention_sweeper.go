@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+)
+
+// AuditRetentionSweeper deletes audit entries older than the retention window, so the audit
+// table does not grow without bound
+type AuditRetentionSweeper struct {
+	*periodicRunner
+	audit     repositories.AuditRepository
+	logger    logger.Logger
+	retention time.Duration
+}
+
+func NewAuditRetentionSweeper(
+	audit repositories.AuditRepository,
+	retention time.Duration,
+	interval time.Duration,
+	logger logger.Logger,
+) *AuditRetentionSweeper {
+	s := &AuditRetentionSweeper{
+		audit:     audit,
+		logger:    logger,
+		retention: retention,
+	}
+	s.periodicRunner = newPeriodicRunner(interval, func(ctx context.Context) error {
+		_, err := s.RunOnce(ctx)
+		return err
+	}, "Audit retention sweep failed", logger)
+	return s
+}
+
+// RunOnce deletes the entries that fell out of the retention window and returns how many it deleted
+func (s *AuditRetentionSweeper) RunOnce(ctx context.Context) (int, error) {
+	deleted, err := s.audit.DeleteBefore(ctx, time.Now().Add(-s.retention), constants.AuditRetentionBatchSize)
+	if deleted > 0 {
+		s.logger.Info(fmt.Sprintf("Purged %d audit entries older than %s", deleted, s.retention))
+	}
+	return deleted, err
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
 	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/pkg/logger"
 	"context"
@@ -11,14 +13,16 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
 )
 
 type CleanBaseRepositoryImpl[T any] struct {
-	db           *gorm.DB
-	auditLogger  repositories.AuditLogger
-	logger       logger.Logger
-	resourceName string
-	authService  repositories.AuthorizationService
+	repositoryHooks[T]
+	db          *gorm.DB
+	retryPolicy RetryPolicy
+	// listScope narrows List and Count to the rows callers may see listed; nil lists every row
+	listScope func(*gorm.DB) *gorm.DB
 }
 
 func NewCleanBaseRepository[T any](
@@ -29,20 +33,38 @@ func NewCleanBaseRepository[T any](
 	authService repositories.AuthorizationService,
 ) *CleanBaseRepositoryImpl[T] {
 	return &CleanBaseRepositoryImpl[T]{
-		db:           db,
-		auditLogger:  auditLogger,
-		logger:       logger,
-		resourceName: resourceName,
-		authService:  authService,
+		repositoryHooks: newRepositoryHooks[T](auditLogger, logger, resourceName, authService),
+		db:              db,
+		retryPolicy:     NewRetryPolicyFromEnv(),
 	}
 }
 
+// WithRetryPolicy overrides the retry policy applied to write operations
+func (r *CleanBaseRepositoryImpl[T]) WithRetryPolicy(policy RetryPolicy) *CleanBaseRepositoryImpl[T] {
+	r.retryPolicy = policy
+	return r
+}
+
+// WithReadAuditing overrides whether reads and lists are audited
+func (r *CleanBaseRepositoryImpl[T]) WithReadAuditing(enabled bool) *CleanBaseRepositoryImpl[T] {
+	r.auditReads = enabled
+	return r
+}
+
 func (r *CleanBaseRepositoryImpl[T]) Create(ctx context.Context, entity *T, userID uuid.UUID) error {
 	if err := r.ValidateAccess(ctx, userID, "create"); err != nil {
 		return err
 	}
 
-	if err := r.db.WithContext(ctx).Create(entity).Error; err != nil {
+	if auditable, ok := any(entity).(entities.Auditable); ok {
+		auditable.SetCreatedBy(userID)
+		auditable.SetUpdatedBy(userID)
+	}
+
+	err := r.retryWrite(ctx, func() error {
+		return r.writeDB(ctx).Create(entity).Error
+	})
+	if err != nil {
 		r.logger.Error("Database create operation failed", err)
 		return r.handleDatabaseError(err, "create", r.resourceName)
 	}
@@ -56,7 +78,7 @@ func (r *CleanBaseRepositoryImpl[T]) GetByID(ctx context.Context, id uuid.UUID,
 	}
 
 	var entity T
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&entity).Error
+	err := r.readDB(ctx).Where("id = ?", id).First(&entity).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domainerrors.NewNotFoundError(
@@ -68,20 +90,51 @@ func (r *CleanBaseRepositoryImpl[T]) GetByID(ctx context.Context, id uuid.UUID,
 		return nil, r.handleDatabaseError(err, "read", r.resourceName)
 	}
 
-	if err := r.AuditLog(ctx, userID, "read", &entity); err != nil {
+	if err := r.auditRead(ctx, userID, "read", &entity); err != nil {
 		r.logger.Error("Failed to audit log read operation", err)
 	}
 
 	return &entity, nil
 }
 
+// GetByIDs loads all entities whose ID is in ids with a single query; missing IDs are skipped
+func (r *CleanBaseRepositoryImpl[T]) GetByIDs(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) ([]*T, error) {
+	if err := r.ValidateAccess(ctx, userID, "read"); err != nil {
+		return nil, err
+	}
+
+	found := []*T{}
+	if len(ids) == 0 {
+		return found, nil
+	}
+
+	err := r.readDB(ctx).Where("id IN ?", ids).Find(&found).Error
+	if err != nil {
+		r.logger.Error("Database batch read operation failed", err)
+		return nil, r.handleDatabaseError(err, "read", r.resourceName)
+	}
+
+	if err := r.auditRead(ctx, userID, "read", nil); err != nil {
+		r.logger.Error("Failed to audit log batch read operation", err)
+	}
+
+	return found, nil
+}
+
 // Update updates an existing entity in the database
 func (r *CleanBaseRepositoryImpl[T]) Update(ctx context.Context, entity *T, userID uuid.UUID) error {
 	if err := r.ValidateAccess(ctx, userID, "update"); err != nil {
 		return err
 	}
 
-	if err := r.db.WithContext(ctx).Save(entity).Error; err != nil {
+	if auditable, ok := any(entity).(entities.Auditable); ok {
+		auditable.SetUpdatedBy(userID)
+	}
+
+	err := r.retryWrite(ctx, func() error {
+		return r.writeDB(ctx).Save(entity).Error
+	})
+	if err != nil {
 		r.logger.Error("Database update operation failed", err)
 		return r.handleDatabaseError(err, "update", r.resourceName)
 	}
@@ -94,12 +147,15 @@ func (r *CleanBaseRepositoryImpl[T]) Delete(ctx context.Context, id uuid.UUID, u
 		return err
 	}
 
-	if err := r.db.WithContext(ctx).Delete(new(T), "id = ?", id).Error; err != nil {
+	err := r.retryWrite(ctx, func() error {
+		return r.writeDB(ctx).Delete(new(T), "id = ?", id).Error
+	})
+	if err != nil {
 		r.logger.Error("Database delete operation failed", err)
 		return r.handleDatabaseError(err, "delete", r.resourceName)
 	}
 
-	return r.AuditLog(ctx, userID, "delete", nil)
+	return r.auditEntity(ctx, userID, "delete", id)
 }
 
 func (r *CleanBaseRepositoryImpl[T]) List(ctx context.Context, limit, offset int, userID uuid.UUID) ([]*T, error) {
@@ -108,63 +164,91 @@ func (r *CleanBaseRepositoryImpl[T]) List(ctx context.Context, limit, offset int
 	}
 
 	var entities []*T
-	err := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&entities).Error
+	err := listOrder(ctx, r.listed(r.readDB(ctx))).Limit(limit).Offset(offset).Find(&entities).Error
 	if err != nil {
 		r.logger.Error("Database list operation failed", err)
 		return nil, r.handleDatabaseError(err, "list", r.resourceName)
 	}
 
-	if err := r.AuditLog(ctx, userID, "list", nil); err != nil {
+	if err := r.auditRead(ctx, userID, "list", nil); err != nil {
 		r.logger.Error("Failed to audit log list operation", err)
 	}
 
 	return entities, nil
 }
 
-func (r *CleanBaseRepositoryImpl[T]) ValidateAccess(ctx context.Context, userID uuid.UUID, action string) error {
-	if r.authService == nil {
-		return nil
+func (r *CleanBaseRepositoryImpl[T]) Count(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if err := r.ValidateAccess(ctx, userID, "list"); err != nil {
+		return 0, err
 	}
 
-	// System user bypass - allow system operations
-	if userID.String() == "00000000-0000-0000-0000-000000000000" {
-		return nil
+	var count int64
+	if err := r.listed(r.readDB(ctx).Model(new(T))).Count(&count).Error; err != nil {
+		return 0, r.handleDatabaseError(err, "list", r.resourceName)
+	}
+	return count, nil
+}
+
+// HealthCheck verifies the database answers a trivial query and that the backing table exists
+func (r *CleanBaseRepositoryImpl[T]) HealthCheck(ctx context.Context) error {
+	var one int
+	if err := r.readDB(ctx).Raw("SELECT 1").Scan(&one).Error; err != nil {
+		return r.handleDatabaseError(err, "health_check", r.resourceName)
+	}
+
+	if !r.readDB(ctx).Migrator().HasTable(new(T)) {
+		return domainerrors.NewDatabaseError(
+			fmt.Sprintf("%s_TABLE_MISSING", r.resourceName),
+			fmt.Sprintf("table for %s does not exist", r.resourceName),
+			nil,
+		)
 	}
 
-	return r.authService.CheckPermission(ctx, userID, r.resourceName, action)
+	return nil
 }
 
-func (r *CleanBaseRepositoryImpl[T]) AuditLog(ctx context.Context, userID uuid.UUID, action string, _ *T) error {
-	if r.auditLogger == nil {
-		return nil
+func (r *CleanBaseRepositoryImpl[T]) listed(db *gorm.DB) *gorm.DB {
+	if r.listScope == nil {
+		return db
 	}
+	return r.listScope(db)
+}
 
-	resource := r.resourceName + ":" + action
-	return r.auditLogger.LogAccess(ctx, userID, action, resource, uuid.Nil)
+func (r *CleanBaseRepositoryImpl[T]) GetDB() *gorm.DB {
+	return r.db
 }
 
-func (r *CleanBaseRepositoryImpl[T]) handleDatabaseError(err error, operation, resource string) error {
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return domainerrors.NewNotFoundError(
-			fmt.Sprintf("%s_NOT_FOUND", resource),
-			fmt.Sprintf("%s not found", resource),
-		)
+// readDB may be served by a read replica unless ctx was marked with constants.WithPrimaryRead
+func (r *CleanBaseRepositoryImpl[T]) readDB(ctx context.Context) *gorm.DB {
+	db := connFor(ctx, r.db).WithContext(ctx)
+	if constants.PrimaryReadFromContext(ctx) {
+		return db.Clauses(dbresolver.Write)
 	}
+	return db
+}
 
-	if errors.Is(err, gorm.ErrDuplicatedKey) {
-		return domainerrors.NewConflictError(
-			fmt.Sprintf("%s_ALREADY_EXISTS", resource),
-			fmt.Sprintf("%s already exists", resource),
-		)
+// listOrder keeps offset pagination stable. Without an ORDER BY, Postgres may return rows in a
+// different order for each page, so pages can repeat or skip rows. The sort from
+// constants.WithSortOrder comes first, and id always breaks ties.
+func listOrder(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if sort, ok := constants.SortOrderFromContext(ctx); ok {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: sort.Field}, Desc: sort.Descending})
+	} else {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: "created_at"}})
 	}
+	return db.Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}})
+}
 
-	return domainerrors.NewDatabaseError(
-		fmt.Sprintf("%s_%s_FAILED", operation, resource),
-		fmt.Sprintf("database %s operation failed for %s", operation, resource),
-		err,
-	)
+// writeDB is always pinned to the primary database and joins the transaction in ctx, if any
+func (r *CleanBaseRepositoryImpl[T]) writeDB(ctx context.Context) *gorm.DB {
+	return connFor(ctx, r.db).WithContext(ctx).Clauses(dbresolver.Write)
 }
 
-func (r *CleanBaseRepositoryImpl[T]) GetDB() *gorm.DB {
-	return r.db
+// retryWrite retries op on transient errors, except inside a transaction where a failed
+// statement aborts the whole transaction and only the caller can start over
+func (r *CleanBaseRepositoryImpl[T]) retryWrite(ctx context.Context, op func() error) error {
+	if _, inTx := txFromContext(ctx); inTx {
+		return op()
+	}
+	return r.retryPolicy.Do(ctx, op)
 }
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	domainerrors "clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:         gormlogger.Default.LogMode(gormlogger.Silent),
+		TranslateError: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entities.Product{}, &entities.User{}))
+	return db
+}
+
+func TestCleanBaseRepository_GetByIDs(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCleanBaseRepository[entities.Product](db, nil, logger.NewLogger(), "product", nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	first := &entities.Product{Name: "First", Price: 10}
+	second := &entities.Product{Name: "Second", Price: 20}
+	require.NoError(t, repo.Create(ctx, first, userID))
+	require.NoError(t, repo.Create(ctx, second, userID))
+
+	products, err := repo.GetByIDs(ctx, []uuid.UUID{first.ID, uuid.New(), second.ID}, userID)
+	assert.NoError(t, err)
+	assert.Len(t, products, 2)
+
+	ids := []uuid.UUID{products[0].ID, products[1].ID}
+	assert.ElementsMatch(t, []uuid.UUID{first.ID, second.ID}, ids)
+
+	empty, err := repo.GetByIDs(ctx, nil, userID)
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestCleanBaseRepository_TracksCreatedAndUpdatedBy(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCleanBaseRepository[entities.User](db, nil, logger.NewLogger(), "user", nil)
+	ctx := context.Background()
+	creator := uuid.New()
+	editor := uuid.New()
+
+	user := &entities.User{Email: "owner@example.com", Password: "hash", FirstName: "O", LastName: "Wner"}
+	require.NoError(t, repo.Create(ctx, user, creator))
+
+	stored, err := repo.GetByID(ctx, user.ID, creator)
+	require.NoError(t, err)
+	assert.Equal(t, creator, stored.CreatedBy)
+	assert.Equal(t, creator, stored.UpdatedBy)
+
+	stored.FirstName = "Edited"
+	require.NoError(t, repo.Update(ctx, stored, editor))
+
+	updated, err := repo.GetByID(ctx, user.ID, editor)
+	require.NoError(t, err)
+	assert.Equal(t, creator, updated.CreatedBy, "created_by never changes")
+	assert.Equal(t, editor, updated.UpdatedBy)
+}
+
+func TestCleanBaseRepository_HealthCheck(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	products := NewCleanBaseRepository[entities.Product](db, nil, logger.NewLogger(), "product", nil)
+	assert.NoError(t, products.HealthCheck(ctx))
+
+	require.NoError(t, db.Migrator().DropTable(&entities.Product{}))
+	assert.Error(t, products.HealthCheck(ctx))
+}
+
+func TestCleanBaseRepository_ListPagesVisitEveryRowOnce(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewCleanBaseRepository[entities.Product](db, nil, logger.NewLogger(), "product", nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// Identical timestamps leave id as the only thing keeping the order stable
+	createdAt := time.Now()
+	const total = 10
+	for i := 0; i < total; i++ {
+		product := &entities.Product{Name: fmt.Sprintf("Product %d", i), Price: float64(total - i)}
+		product.CreatedAt = createdAt
+		require.NoError(t, repo.Create(ctx, product, userID))
+	}
+
+	seen := make(map[uuid.UUID]int)
+	for offset := 0; offset < total; offset += 3 {
+		page, err := repo.List(ctx, 3, offset, userID)
+		require.NoError(t, err)
+		for _, product := range page {
+			seen[product.ID]++
+		}
+	}
+	assert.Len(t, seen, total)
+	for id, count := range seen {
+		assert.Equal(t, 1, count, "product %s listed more than once", id)
+	}
+
+	count, err := repo.Count(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(total), count)
+
+	sorted, err := repo.List(constants.WithSortOrder(ctx, constants.SortOrder{Field: "price", Descending: true}), total, 0, userID)
+	require.NoError(t, err)
+	require.Len(t, sorted, total)
+	for i := 1; i < total; i++ {
+		assert.GreaterOrEqual(t, sorted[i-1].Price, sorted[i].Price)
+	}
+}
+
+// recordingAuditLogger keeps the actions it was asked to log
+type recordingAuditLogger struct {
+	actions []string
+}
+
+func (l *recordingAuditLogger) LogAccess(_ context.Context, _ uuid.UUID, action, _ string, _ uuid.UUID) error {
+	l.actions = append(l.actions, action)
+	return nil
+}
+
+func (l *recordingAuditLogger) LogDataAccess(_ context.Context, _ uuid.UUID, action, _ string, _ interface{}) error {
+	l.actions = append(l.actions, action)
+	return nil
+}
+
+func TestCleanBaseRepository_ReadAuditing(t *testing.T) {
+	readAll := func(t *testing.T, reads ReadAuditing) []string {
+		audit := &recordingAuditLogger{}
+		repo := NewCleanBaseRepository[entities.Product](setupTestDB(t), audit, logger.NewLogger(), "product", nil).
+			WithReadAuditing(reads.covers("product"))
+		ctx := context.Background()
+		userID := uuid.New()
+
+		product := &entities.Product{Name: "Audited", Price: 10}
+		require.NoError(t, repo.Create(ctx, product, userID))
+		_, err := repo.GetByID(ctx, product.ID, userID)
+		require.NoError(t, err)
+		_, err = repo.GetByIDs(ctx, []uuid.UUID{product.ID}, userID)
+		require.NoError(t, err)
+		_, err = repo.List(ctx, 10, 0, userID)
+		require.NoError(t, err)
+		product.Price = 12
+		require.NoError(t, repo.Update(ctx, product, userID))
+		return audit.actions
+	}
+
+	// Mutations are always audited; reads only for the resources AUDIT_READS names
+	assert.Equal(t, []string{"create", "update"}, readAll(t, nil))
+	assert.Equal(t, []string{"create", "update"}, readAll(t, ReadAuditing{"user"}))
+	assert.Equal(t, []string{"create", "read", "read", "list", "update"}, readAll(t, ReadAuditing{"user", "Product"}))
+	assert.Equal(t, []string{"create", "read", "read", "list", "update"}, readAll(t, ReadAuditing{"*"}))
+}
+
+func TestCleanBaseRepository_RoutesReadsToReplica(t *testing.T) {
+	dir := t.TempDir()
+	open := func(path string) *gorm.DB {
+		db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+			Logger:         gormlogger.Default.LogMode(gormlogger.Silent),
+			TranslateError: true,
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.AutoMigrate(&entities.Product{}))
+		return db
+	}
+	replicaPath := filepath.Join(dir, "replica.db")
+	open(replicaPath)
+	primary := open(filepath.Join(dir, "primary.db"))
+	require.NoError(t, primary.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{sqlite.Open(replicaPath)},
+	})))
+
+	repo := NewCleanBaseRepository[entities.Product](primary, nil, logger.NewLogger(), "product", nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// The replica never receives the write, as if it were lagging behind the primary
+	product := &entities.Product{Name: "Fresh", Price: 10}
+	require.NoError(t, repo.Create(ctx, product, userID))
+
+	_, err := repo.GetByID(ctx, product.ID, userID)
+	var appErr *domainerrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, domainerrors.CategoryNotFound, appErr.Category, "a plain read is served by the replica")
+
+	found, err := repo.GetByID(constants.WithPrimaryRead(ctx), product.ID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "Fresh", found.Name)
+
+	product.Name = "Renamed"
+	require.NoError(t, repo.Update(ctx, product, userID))
+	require.NoError(t, repo.Delete(ctx, product.ID, userID))
+
+	var remaining int64
+	require.NoError(t, primary.Clauses(dbresolver.Write).Model(&entities.Product{}).Count(&remaining).Error)
+	assert.Zero(t, remaining, "updates and deletes reach the primary")
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MemoryBaseRepository keeps entities in a map instead of a database, applying the same access
+// checks and audit trail as CleanBaseRepositoryImpl. It stores copies, so callers never share
+// state with the store, and deletes are soft like GORM's. Transactions in ctx are ignored:
+// writes take effect immediately and are not rolled back with the SQL tables.
+type MemoryBaseRepository[T any] struct {
+	repositoryHooks[T]
+	// base reaches the embedded BaseEntity, which holds the ID, timestamps and soft-delete marker
+	base func(*T) *entities.BaseEntity
+	// sortColumns compare two entities by each field list queries may sort on
+	sortColumns map[string]func(a, b *T) int
+	// conflicts reports whether two live entities may not be stored together, such as two users
+	// with one email
+	conflicts func(a, b *T) bool
+	// listed narrows List and Count to the entities callers may see listed; nil lists them all
+	listed func(*T) bool
+	// detach gives a shallow copy its own pointer, slice and map fields, so the copy shares no
+	// state with the original; nil when T has none
+	detach func(*T)
+	now    func() time.Time
+
+	mu   sync.RWMutex
+	rows map[uuid.UUID]*T
+}
+
+func NewMemoryBaseRepository[T any](
+	base func(*T) *entities.BaseEntity,
+	auditLogger repositories.AuditLogger,
+	logger logger.Logger,
+	resourceName string,
+	authService repositories.AuthorizationService,
+) *MemoryBaseRepository[T] {
+	r := &MemoryBaseRepository[T]{
+		repositoryHooks: newRepositoryHooks[T](auditLogger, logger, resourceName, authService),
+		base:            base,
+		now:             time.Now,
+		rows:            make(map[uuid.UUID]*T),
+	}
+	r.sortColumns = map[string]func(a, b *T) int{"created_at": r.compareCreatedAt}
+	return r
+}
+
+// WithReadAuditing overrides whether reads and lists are audited
+func (r *MemoryBaseRepository[T]) WithReadAuditing(enabled bool) *MemoryBaseRepository[T] {
+	r.auditReads = enabled
+	return r
+}
+
+func (r *MemoryBaseRepository[T]) Create(ctx context.Context, entity *T, userID uuid.UUID) error {
+	if err := r.ValidateAccess(ctx, userID, "create"); err != nil {
+		return err
+	}
+
+	if auditable, ok := any(entity).(entities.Auditable); ok {
+		auditable.SetCreatedBy(userID)
+		auditable.SetUpdatedBy(userID)
+	}
+	base := r.base(entity)
+	if base.ID == uuid.Nil {
+		base.ID = uuid.New()
+	}
+	now := r.now()
+	if base.CreatedAt.IsZero() {
+		base.CreatedAt = now
+	}
+	if base.UpdatedAt.IsZero() {
+		base.UpdatedAt = now
+	}
+
+	r.mu.Lock()
+	_, taken := r.rows[base.ID]
+	if !taken {
+		taken = r.conflictsLocked(entity)
+	}
+	if !taken {
+		r.rows[base.ID] = r.copyOf(entity)
+	}
+	r.mu.Unlock()
+	if taken {
+		return r.handleDatabaseError(gorm.ErrDuplicatedKey, "create", r.resourceName)
+	}
+
+	return r.AuditLog(ctx, userID, "create", entity)
+}
+
+func (r *MemoryBaseRepository[T]) GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*T, error) {
+	if err := r.ValidateAccess(ctx, userID, "read"); err != nil {
+		return nil, err
+	}
+
+	entity, ok := r.get(id)
+	if !ok {
+		return nil, r.handleDatabaseError(gorm.ErrRecordNotFound, "read", r.resourceName)
+	}
+
+	if err := r.auditRead(ctx, userID, "read", entity); err != nil {
+		r.logger.Error("Failed to audit log read operation", err)
+	}
+
+	return entity, nil
+}
+
+// GetByIDs returns the live entities whose ID is in ids; missing IDs are skipped
+func (r *MemoryBaseRepository[T]) GetByIDs(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) ([]*T, error) {
+	if err := r.ValidateAccess(ctx, userID, "read"); err != nil {
+		return nil, err
+	}
+
+	found := r.filter(func(entity *T) bool { return slices.Contains(ids, r.base(entity).ID) })
+
+	if len(ids) > 0 {
+		if err := r.auditRead(ctx, userID, "read", nil); err != nil {
+			r.logger.Error("Failed to audit log batch read operation", err)
+		}
+	}
+
+	return found, nil
+}
+
+// Update replaces the stored entity, or stores it when it is new, like GORM's Save
+func (r *MemoryBaseRepository[T]) Update(ctx context.Context, entity *T, userID uuid.UUID) error {
+	if err := r.ValidateAccess(ctx, userID, "update"); err != nil {
+		return err
+	}
+
+	if auditable, ok := any(entity).(entities.Auditable); ok {
+		auditable.SetUpdatedBy(userID)
+	}
+	base := r.base(entity)
+	if base.ID == uuid.Nil {
+		base.ID = uuid.New()
+	}
+	base.UpdatedAt = r.now()
+
+	r.mu.Lock()
+	conflict := r.conflictsLocked(entity)
+	if !conflict {
+		r.rows[base.ID] = r.copyOf(entity)
+	}
+	r.mu.Unlock()
+	if conflict {
+		return r.handleDatabaseError(gorm.ErrDuplicatedKey, "update", r.resourceName)
+	}
+
+	return r.AuditLog(ctx, userID, "update", entity)
+}
+
+// Delete soft-deletes the entity; deleting one that does not exist is not an error
+func (r *MemoryBaseRepository[T]) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	if err := r.ValidateAccess(ctx, userID, "delete"); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	if entity, ok := r.rows[id]; ok && !r.deleted(entity) {
+		r.base(entity).DeletedAt = gorm.DeletedAt{Time: r.now(), Valid: true}
+	}
+	r.mu.Unlock()
+
+	return r.auditEntity(ctx, userID, "delete", id)
+}
+
+func (r *MemoryBaseRepository[T]) List(ctx context.Context, limit, offset int, userID uuid.UUID) ([]*T, error) {
+	if err := r.ValidateAccess(ctx, userID, "list"); err != nil {
+		return nil, err
+	}
+
+	items := page(r.sorted(ctx, r.filter(r.listed)), limit, offset)
+
+	if err := r.auditRead(ctx, userID, "list", nil); err != nil {
+		r.logger.Error("Failed to audit log list operation", err)
+	}
+
+	return items, nil
+}
+
+func (r *MemoryBaseRepository[T]) Count(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if err := r.ValidateAccess(ctx, userID, "list"); err != nil {
+		return 0, err
+	}
+	return int64(len(r.filter(r.listed))), nil
+}
+
+// HealthCheck always succeeds; there is no connection to lose
+func (r *MemoryBaseRepository[T]) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+func (r *MemoryBaseRepository[T]) get(id uuid.UUID) (*T, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entity, ok := r.rows[id]
+	if !ok || r.deleted(entity) {
+		return nil, false
+	}
+	return r.copyOf(entity), true
+}
+
+// filter returns copies of the live entities match accepts, or of all of them when match is nil
+func (r *MemoryBaseRepository[T]) filter(match func(*T) bool) []*T {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	found := []*T{}
+	for _, entity := range r.rows {
+		if !r.deleted(entity) && (match == nil || match(entity)) {
+			found = append(found, r.copyOf(entity))
+		}
+	}
+	return found
+}
+
+// sorted orders entities the way listOrder orders rows: by the sort from
+// constants.WithSortOrder or created_at, then by id
+func (r *MemoryBaseRepository[T]) sorted(ctx context.Context, items []*T) []*T {
+	compare := r.compareCreatedAt
+	descending := false
+	if sort, ok := constants.SortOrderFromContext(ctx); ok {
+		if column, known := r.sortColumns[sort.Field]; known {
+			compare, descending = column, sort.Descending
+		}
+	}
+
+	slices.SortFunc(items, func(a, b *T) int {
+		c := compare(a, b)
+		if descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+		return compareIDs(r.base(a).ID, r.base(b).ID)
+	})
+	return items
+}
+
+func (r *MemoryBaseRepository[T]) compareCreatedAt(a, b *T) int {
+	return r.base(a).CreatedAt.Compare(r.base(b).CreatedAt)
+}
+
+func (r *MemoryBaseRepository[T]) conflictsLocked(entity *T) bool {
+	if r.conflicts == nil {
+		return false
+	}
+	id := r.base(entity).ID
+	for otherID, other := range r.rows {
+		if otherID != id && !r.deleted(other) && r.conflicts(entity, other) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryBaseRepository[T]) deleted(entity *T) bool {
+	return r.base(entity).DeletedAt.Valid
+}
+
+func (r *MemoryBaseRepository[T]) copyOf(entity *T) *T {
+	clone := *entity
+	if r.detach != nil {
+		r.detach(&clone)
+	}
+	return &clone
+}
+
+// clonePointer returns a pointer to a copy of *p, or nil when p is nil
+func clonePointer[V any](p *V) *V {
+	if p == nil {
+		return nil
+	}
+	clone := *p
+	return &clone
+}
+
+// compareIDs orders UUIDs byte by byte, which is how both databases order the id column
+func compareIDs(a, b uuid.UUID) int {
+	return slices.Compare(a[:], b[:])
+}
+
+// page applies limit and offset like SQL; a negative limit means no limit
+func page[T any](items []*T, limit, offset int) []*T {
+	if offset > len(items) {
+		offset = len(items)
+	}
+	items = items[max(offset, 0):]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
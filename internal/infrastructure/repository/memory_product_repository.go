@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type memoryProductRepository struct {
+	*MemoryBaseRepository[entities.Product]
+}
+
+// NewMemoryProductRepository returns a ProductRepository that keeps products in process memory.
+// Stock reservations against these products need NewMemoryReservationRepository.
+func NewMemoryProductRepository(
+	authService repositories.AuthorizationService,
+	auditLogger repositories.AuditLogger,
+	reads ReadAuditing,
+	logger logger.Logger,
+) repositories.ProductRepository {
+	base := NewMemoryBaseRepository(func(p *entities.Product) *entities.BaseEntity { return &p.BaseEntity },
+		auditLogger, logger, "product", authService).WithReadAuditing(reads.covers("product"))
+	base.sortColumns["name"] = func(a, b *entities.Product) int { return strings.Compare(a.Name, b.Name) }
+	base.sortColumns["price"] = func(a, b *entities.Product) int { return cmp.Compare(a.Price, b.Price) }
+	base.sortColumns["stock"] = func(a, b *entities.Product) int { return cmp.Compare(a.Stock, b.Stock) }
+	return &memoryProductRepository{MemoryBaseRepository: base}
+}
+
+func (r *memoryProductRepository) GetByCategory(ctx context.Context, category string, limit, offset int) ([]*entities.Product, error) {
+	products := r.filter(func(p *entities.Product) bool { return strings.EqualFold(p.Category, category) })
+	return page(r.sorted(ctx, products), limit, offset), nil
+}
+
+func (r *memoryProductRepository) CountInCategory(ctx context.Context, category string) (int64, error) {
+	return int64(len(r.filter(func(p *entities.Product) bool { return strings.EqualFold(p.Category, category) }))), nil
+}
+
+func (r *memoryProductRepository) CountByCategory(ctx context.Context) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	for _, product := range r.filter(nil) {
+		counts[strings.ToLower(product.Category)]++
+	}
+	return counts, nil
+}
+
+func (r *memoryProductRepository) GetLowStock(ctx context.Context, threshold, limit, offset int) ([]*entities.Product, error) {
+	products := r.filter(func(p *entities.Product) bool { return p.Stock <= threshold })
+	slices.SortFunc(products, func(a, b *entities.Product) int {
+		if c := cmp.Compare(a.Stock, b.Stock); c != 0 {
+			return c
+		}
+		return compareIDs(a.ID, b.ID)
+	})
+	return page(products, limit, offset), nil
+}
+
+// Restore returns ErrProductNotFound unless a soft-deleted product has this ID, so restoring a
+// live product is reported the same way as restoring one that never existed
+func (r *memoryProductRepository) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.Product, error) {
+	if err := r.ValidateAccess(ctx, userID, "update"); err != nil {
+		return nil, err
+	}
+
+	var product *entities.Product
+	r.mu.Lock()
+	if stored, ok := r.rows[id]; ok && r.deleted(stored) {
+		stored.DeletedAt = gorm.DeletedAt{}
+		stored.UpdatedBy = userID
+		product = r.copyOf(stored)
+	}
+	r.mu.Unlock()
+	if product == nil {
+		return nil, domainerrors.ErrProductNotFound
+	}
+
+	return product, r.AuditLog(ctx, userID, constants.ActionRestore, product)
+}
+
+// adjustStock adds delta to a live product's stock unless that would take it below zero, and
+// reports whether it did, like the conditional UPDATE of reservationRepository
+func (r *memoryProductRepository) adjustStock(id uuid.UUID, delta int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.rows[id]
+	if !ok || r.deleted(product) || product.Stock+delta < 0 {
+		return false
+	}
+	product.Stock += delta
+	product.UpdatedAt = r.now()
+	return true
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"testing"
+	"time"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// actionAuthorizer allows only the actions it lists
+type actionAuthorizer struct {
+	repositories.AuthorizationService
+	allowed map[string]bool
+}
+
+func (a actionAuthorizer) CheckPermission(_ context.Context, _ uuid.UUID, _, action string) error {
+	if a.allowed[action] {
+		return nil
+	}
+	return domainerrors.ErrInsufficientPermissions
+}
+
+func TestMemoryUserRepository_CRUD(t *testing.T) {
+	audit := &recordingAuditLogger{}
+	repo := NewMemoryUserRepository(nil, audit, nil, logger.NewLogger())
+	ctx := context.Background()
+	actor := uuid.New()
+
+	user := &entities.User{Email: "ada@example.com", Password: "hash", FirstName: "Ada", LastName: "Lovelace"}
+	require.NoError(t, repo.Create(ctx, user, actor))
+	require.NotEqual(t, uuid.Nil, user.ID)
+	assert.Equal(t, constants.RoleUser, user.Role, "Create validates and fills the default role")
+	assert.Equal(t, actor, user.CreatedBy)
+	duplicate := &entities.User{Email: "ada@example.com", Password: "hash", FirstName: "Other", LastName: "Ada"}
+	assert.ErrorIs(t, repo.Create(ctx, duplicate, actor), domainerrors.ErrUserAlreadyExists)
+
+	found, err := repo.GetByID(ctx, user.ID, actor)
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", found.FirstName)
+	// The store keeps its own copy
+	found.FirstName = "Changed"
+	byEmail, err := repo.GetByEmail(ctx, "ada@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", byEmail.FirstName)
+
+	user.LastName = "King"
+	cutoff := time.Now().UTC()
+	user.TokensValidAfter = &cutoff
+	require.NoError(t, repo.Update(ctx, user, actor))
+	found, err = repo.GetByID(ctx, user.ID, actor)
+	require.NoError(t, err)
+	assert.Equal(t, "King", found.LastName)
+	// Pointer fields are copied too, so changing them through either side leaves the store alone
+	cutoff = cutoff.Add(time.Hour)
+	*found.TokensValidAfter = cutoff
+	found, err = repo.GetByID(ctx, user.ID, actor)
+	require.NoError(t, err)
+	assert.Equal(t, cutoff.Add(-time.Hour), *found.TokensValidAfter)
+
+	other := &entities.User{Email: "alan@example.com", Password: "hash", FirstName: "Alan", LastName: "Turing", Role: constants.RoleAdmin, IsActive: true}
+	require.NoError(t, repo.Create(ctx, other, actor))
+	sorted, err := repo.List(constants.WithSortOrder(ctx, constants.SortOrder{Field: "email"}), 10, 0, actor)
+	require.NoError(t, err)
+	require.Len(t, sorted, 2)
+	assert.Equal(t, []string{"ada@example.com", "alan@example.com"}, []string{sorted[0].Email, sorted[1].Email})
+	admins, err := repo.CountByRole(ctx, constants.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), admins)
+	users, total, err := repo.Search(ctx, repositories.UserSearch{Query: "TUR"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, other.ID, users[0].ID)
+	assert.Equal(t, int64(1), total)
+
+	require.NoError(t, repo.Delete(ctx, user.ID, actor))
+	_, err = repo.GetByID(ctx, user.ID, actor)
+	var appErr *domainerrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "user_NOT_FOUND", appErr.Code)
+	_, err = repo.GetByEmail(ctx, "ada@example.com")
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
+	count, err := repo.Count(ctx, actor)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// A deleted user's email can be registered again, and the old row is still kept
+	require.NoError(t, repo.Create(ctx, &entities.User{Email: "ada@example.com", Password: "hash", FirstName: "Ada", LastName: "Again"}, actor))
+	all, err := repo.GetByEmailIncludingDeleted(ctx, "ada@example.com")
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	assert.Equal(t, []string{"create", "update", "create", "delete", "create"}, audit.actions)
+}
+
+func TestMemoryProductRepository_CRUD(t *testing.T) {
+	repo := NewMemoryProductRepository(nil, nil, nil, logger.NewLogger())
+	ctx := context.Background()
+	actor := uuid.New()
+
+	seed := []struct {
+		name, category string
+		stock          int
+	}{
+		{"Keyboard", "peripherals", 3},
+		{"Mouse", "peripherals", 40},
+		{"Cable", "", 1},
+	}
+	ids := make([]uuid.UUID, len(seed))
+	for i, s := range seed {
+		product := &entities.Product{Name: s.name, Category: s.category, Stock: s.stock, Price: 10}
+		require.NoError(t, repo.Create(ctx, product, actor))
+		ids[i] = product.ID
+	}
+
+	page, err := repo.List(constants.WithSortOrder(ctx, constants.SortOrder{Field: "name"}), 2, 1, actor)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, []string{"Keyboard", "Mouse"}, []string{page[0].Name, page[1].Name})
+
+	peripherals, err := repo.GetByCategory(ctx, "peripherals", 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, peripherals, 2)
+	counts, err := repo.CountByCategory(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"peripherals": 2, "": 1}, counts)
+	low, err := repo.GetLowStock(ctx, 5, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, low, 2)
+	assert.Equal(t, []string{"Cable", "Keyboard"}, []string{low[0].Name, low[1].Name})
+
+	found, err := repo.GetByIDs(ctx, []uuid.UUID{ids[0], uuid.New(), ids[2]}, actor)
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	_, err = repo.Restore(ctx, ids[0], actor)
+	assert.ErrorIs(t, err, domainerrors.ErrProductNotFound, "a live product cannot be restored")
+	require.NoError(t, repo.Delete(ctx, ids[0], actor))
+	_, err = repo.GetByID(ctx, ids[0], actor)
+	assert.Error(t, err)
+	restored, err := repo.Restore(ctx, ids[0], actor)
+	require.NoError(t, err)
+	assert.Equal(t, "Keyboard", restored.Name)
+	_, err = repo.GetByID(ctx, ids[0], actor)
+	assert.NoError(t, err)
+}
+
+func TestMemoryReservationRepository_TakesStockFromMemoryProducts(t *testing.T) {
+	products := NewMemoryProductRepository(nil, nil, nil, logger.NewLogger())
+	repo, err := NewMemoryReservationRepository(products)
+	require.NoError(t, err)
+	ctx := context.Background()
+	systemID := constants.SystemUserID()
+	product := &entities.Product{Name: "Widget", Price: 10, Stock: 5}
+	require.NoError(t, products.Create(ctx, product, systemID))
+	stock := func() int {
+		found, err := products.GetByID(ctx, product.ID, systemID)
+		require.NoError(t, err)
+		return found.Stock
+	}
+
+	first := newReservation(product.ID, 3, time.Minute)
+	require.NoError(t, repo.Reserve(ctx, first))
+	assert.Equal(t, 2, stock())
+	assert.ErrorIs(t, repo.Reserve(ctx, newReservation(product.ID, 3, time.Minute)), domainerrors.ErrInsufficientStock)
+	assert.ErrorIs(t, repo.Reserve(ctx, newReservation(uuid.New(), 1, time.Minute)), domainerrors.ErrInsufficientStock)
+
+	second := newReservation(product.ID, 2, time.Minute)
+	require.NoError(t, repo.Reserve(ctx, second))
+	require.NoError(t, repo.Confirm(ctx, first.ID))
+	require.NoError(t, repo.Release(ctx, second.ID))
+	assert.Equal(t, 2, stock(), "confirmed units stay sold, released units return")
+	assert.ErrorIs(t, repo.Release(ctx, first.ID), domainerrors.ErrReservationNotPending)
+	assert.ErrorIs(t, repo.Confirm(ctx, uuid.New()), domainerrors.ErrReservationNotFound)
+
+	expired := newReservation(product.ID, 2, time.Minute)
+	require.NoError(t, repo.Reserve(ctx, expired))
+	released, err := repo.ReleaseExpired(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+	assert.Equal(t, 2, stock())
+	found, err := repo.GetByID(ctx, expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ReservationStatusReleased, found.Status)
+}
+
+func TestMemoryReservationRepository_NeedsMemoryProducts(t *testing.T) {
+	_, err := NewMemoryReservationRepository(NewProductRepository(setupTestDB(t), nil, nil, nil, logger.NewLogger()))
+	assert.ErrorContains(t, err, "memory product repository")
+}
+
+func TestMemoryRepository_ValidatesAccess(t *testing.T) {
+	authorizer := actionAuthorizer{allowed: map[string]bool{"read": true}}
+	repo := NewMemoryProductRepository(authorizer, nil, nil, logger.NewLogger())
+	ctx := context.Background()
+	userID := uuid.New()
+
+	err := repo.Create(ctx, &entities.Product{Name: "Denied", Price: 10}, userID)
+	assert.ErrorIs(t, err, domainerrors.ErrInsufficientPermissions)
+	_, err = repo.List(ctx, 10, 0, userID)
+	assert.ErrorIs(t, err, domainerrors.ErrInsufficientPermissions)
+
+	// The system user bypasses the check, as with the database repositories
+	product := &entities.Product{Name: "Seeded", Price: 10}
+	require.NoError(t, repo.Create(ctx, product, constants.SystemUserID()))
+	_, err = repo.GetByID(ctx, product.ID, userID)
+	assert.NoError(t, err)
+}
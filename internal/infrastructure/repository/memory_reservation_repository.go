@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// stockAdjuster is implemented by the memory product repository, whose stock reservations must
+// change instead of the products table
+type stockAdjuster interface {
+	adjustStock(id uuid.UUID, delta int) bool
+}
+
+type memoryReservationRepository struct {
+	stock stockAdjuster
+	now   func() time.Time
+
+	mu           sync.Mutex
+	reservations map[uuid.UUID]*entities.Reservation
+}
+
+// NewMemoryReservationRepository returns a ReservationRepository that keeps reservations in
+// process memory and takes their units from products. It fails unless products came from
+// NewMemoryProductRepository.
+func NewMemoryReservationRepository(products repositories.ProductRepository) (repositories.ReservationRepository, error) {
+	stock, ok := products.(stockAdjuster)
+	if !ok {
+		return nil, fmt.Errorf("memory reservations need a memory product repository, got %T", products)
+	}
+	return &memoryReservationRepository{
+		stock:        stock,
+		now:          time.Now,
+		reservations: make(map[uuid.UUID]*entities.Reservation),
+	}, nil
+}
+
+// Reserve holds the lock across the stock change, so a reservation is stored exactly when its
+// units were taken
+func (r *memoryReservationRepository) Reserve(ctx context.Context, reservation *entities.Reservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.stock.adjustStock(reservation.ProductID, -reservation.Quantity) {
+		return domainerrors.ErrInsufficientStock
+	}
+	now := r.now().UTC()
+	reservation.CreatedAt, reservation.UpdatedAt = now, now
+	stored := *reservation
+	r.reservations[reservation.ID] = &stored
+	return nil
+}
+
+func (r *memoryReservationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, ok := r.reservations[id]
+	if !ok {
+		return nil, domainerrors.ErrReservationNotFound
+	}
+	found := *reservation
+	return &found, nil
+}
+
+func (r *memoryReservationRepository) Confirm(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, err := r.pendingLocked(id)
+	if err != nil {
+		return err
+	}
+	if !reservation.ExpiresAt.After(r.now().UTC()) {
+		return domainerrors.ErrReservationNotPending
+	}
+	r.setStatusLocked(reservation, constants.ReservationStatusConfirmed)
+	return nil
+}
+
+func (r *memoryReservationRepository) Release(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, err := r.pendingLocked(id)
+	if err != nil {
+		return err
+	}
+	r.releaseLocked(reservation)
+	return nil
+}
+
+func (r *memoryReservationRepository) ReleaseExpired(ctx context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, reservation := range r.reservations {
+		if reservation.Status == constants.ReservationStatusPending && !reservation.ExpiresAt.After(now.UTC()) {
+			r.releaseLocked(reservation)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryReservationRepository) pendingLocked(id uuid.UUID) (*entities.Reservation, error) {
+	reservation, ok := r.reservations[id]
+	if !ok {
+		return nil, domainerrors.ErrReservationNotFound
+	}
+	if reservation.Status != constants.ReservationStatusPending {
+		return nil, domainerrors.ErrReservationNotPending
+	}
+	return reservation, nil
+}
+
+// releaseLocked returns the units to stock like releaseReservation; a product deleted since is
+// left alone
+func (r *memoryReservationRepository) releaseLocked(reservation *entities.Reservation) {
+	r.setStatusLocked(reservation, constants.ReservationStatusReleased)
+	r.stock.adjustStock(reservation.ProductID, reservation.Quantity)
+}
+
+func (r *memoryReservationRepository) setStatusLocked(reservation *entities.Reservation, status string) {
+	reservation.Status = status
+	reservation.UpdatedAt = r.now().UTC()
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type memoryUserRepository struct {
+	*MemoryBaseRepository[entities.User]
+}
+
+// NewMemoryUserRepository returns a UserRepository that keeps users in process memory. Like the
+// database, it allows one live user per email.
+func NewMemoryUserRepository(
+	authService repositories.AuthorizationService,
+	auditLogger repositories.AuditLogger,
+	reads ReadAuditing,
+	logger logger.Logger,
+) repositories.UserRepository {
+	base := NewMemoryBaseRepository(func(u *entities.User) *entities.BaseEntity { return &u.BaseEntity },
+		auditLogger, logger, "user", authService).WithReadAuditing(reads.covers("user"))
+	base.sortColumns["email"] = func(a, b *entities.User) int { return strings.Compare(a.Email, b.Email) }
+	base.sortColumns["first_name"] = func(a, b *entities.User) int { return strings.Compare(a.FirstName, b.FirstName) }
+	base.sortColumns["last_name"] = func(a, b *entities.User) int { return strings.Compare(a.LastName, b.LastName) }
+	base.conflicts = func(a, b *entities.User) bool { return a.Email == b.Email }
+	base.listed = func(u *entities.User) bool { return !constants.IsSystemUser(u.ID) }
+	base.detach = func(u *entities.User) {
+		u.PendingEmail = clonePointer(u.PendingEmail)
+		u.EmailChangeExpiresAt = clonePointer(u.EmailChangeExpiresAt)
+		u.TokensValidAfter = clonePointer(u.TokensValidAfter)
+	}
+	return &memoryUserRepository{MemoryBaseRepository: base}
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *entities.User, userID uuid.UUID) error {
+	if err := user.Validate(); err != nil {
+		return err
+	}
+	err := r.MemoryBaseRepository.Create(ctx, user, userID)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domainerrors.ErrUserAlreadyExists
+	}
+	return err
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, user *entities.User, userID uuid.UUID) error {
+	if err := user.Validate(); err != nil {
+		return err
+	}
+	return r.MemoryBaseRepository.Update(ctx, user, userID)
+}
+
+func (r *memoryUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	users := r.filter(func(u *entities.User) bool { return u.Email == email })
+	if len(users) == 0 {
+		return nil, domainerrors.ErrUserNotFound
+	}
+	return users[0], nil
+}
+
+func (r *memoryUserRepository) GetByEmailIncludingDeleted(ctx context.Context, email string) ([]*entities.User, error) {
+	r.mu.RLock()
+	users := []*entities.User{}
+	for _, user := range r.rows {
+		if user.Email == email {
+			users = append(users, r.copyOf(user))
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(users, func(a, b *entities.User) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return users, nil
+}
+
+func (r *memoryUserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	return int64(len(r.filter(func(u *entities.User) bool {
+		return u.Role == role && u.IsActive && r.listed(u)
+	}))), nil
+}
+
+func (r *memoryUserRepository) ListPendingApproval(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	users := r.filter(func(u *entities.User) bool { return u.ApprovalPending })
+	slices.SortFunc(users, func(a, b *entities.User) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return compareIDs(a.ID, b.ID)
+	})
+	return page(users, limit, offset), nil
+}
+
+func (r *memoryUserRepository) Search(ctx context.Context, search repositories.UserSearch, limit, offset int) ([]*entities.User, int64, error) {
+	term := strings.ToLower(strings.TrimSpace(search.Query))
+	users := r.filter(func(u *entities.User) bool {
+		if !r.listed(u) {
+			return false
+		}
+		if term != "" && !strings.Contains(strings.ToLower(u.FirstName), term) &&
+			!strings.Contains(strings.ToLower(u.LastName), term) && !strings.Contains(strings.ToLower(u.Email), term) {
+			return false
+		}
+		if search.Role != "" && u.Role != search.Role {
+			return false
+		}
+		return search.Active == nil || u.IsActive == *search.Active
+	})
+	return page(r.sorted(ctx, users), limit, offset), int64(len(users)), nil
+}
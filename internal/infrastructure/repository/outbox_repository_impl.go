@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) repositories.OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// Add joins the transaction in ctx so the event commits or rolls back with the domain change
+func (r *outboxRepository) Add(ctx context.Context, event *entities.OutboxEvent) error {
+	return connFor(ctx, r.db).WithContext(ctx).Create(event).Error
+}
+
+func (r *outboxRepository) FetchPending(ctx context.Context, limit, maxAttempts int) ([]*entities.OutboxEvent, error) {
+	var events []*entities.OutboxEvent
+	err := connFor(ctx, r.db).WithContext(ctx).
+		Where("sent_at IS NULL AND attempts < ?", maxAttempts).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) MarkSent(ctx context.Context, id uuid.UUID) error {
+	now := time.Now().UTC()
+	return connFor(ctx, r.db).WithContext(ctx).Model(&entities.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"sent_at": now, "attempts": gorm.Expr("attempts + 1"), "last_error": ""}).Error
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	return connFor(ctx, r.db).WithContext(ctx).Model(&entities.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"attempts": gorm.Expr("attempts + 1"), "last_error": reason}).Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"sync"
+	"time"
+)
+
+// periodicRunner calls run every interval on its own goroutine from Start until Stop. The
+// sweepers embed it, so each only supplies the work of one pass.
+type periodicRunner struct {
+	interval time.Duration
+	run      func(ctx context.Context) error
+	// failure is the message logged when a pass returns an error
+	failure string
+	logger  logger.Logger
+
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+func newPeriodicRunner(
+	interval time.Duration,
+	run func(ctx context.Context) error,
+	failure string,
+	logger logger.Logger,
+) *periodicRunner {
+	return &periodicRunner{
+		interval: interval,
+		run:      run,
+		failure:  failure,
+		logger:   logger,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start runs a pass every interval until Stop is called
+func (r *periodicRunner) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				if err := r.run(context.Background()); err != nil {
+					r.logger.Error(r.failure, err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the loop and waits for a pass in progress; calling it again does nothing
+func (r *periodicRunner) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		r.wg.Wait()
+	})
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriodicRunner_RunsUntilStopped(t *testing.T) {
+	var passes atomic.Int32
+	runner := newPeriodicRunner(time.Millisecond, func(ctx context.Context) error {
+		passes.Add(1)
+		return nil
+	}, "pass failed", logger.NewLogger())
+
+	runner.Start()
+	assert.Eventually(t, func() bool { return passes.Load() >= 2 }, time.Second, time.Millisecond)
+	runner.Stop()
+	runner.Stop()
+
+	stopped := passes.Load()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, stopped, passes.Load(), "no pass runs after Stop returns")
+}
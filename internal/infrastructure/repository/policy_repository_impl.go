@@ -1,10 +1,14 @@
 package repository
 
 import (
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/entities"
+	domainerrors "clean-architecture-api/internal/domain/errors"
 	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/pkg/logger"
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -23,24 +27,11 @@ func NewPolicyRepository(db *gorm.DB, logger logger.Logger) repositories.PolicyR
 }
 
 func (r *policyRepository) Create(ctx context.Context, policy *entities.PolicyDocument) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(policy).Error; err != nil {
-			return err
-		}
-
-		for i := range policy.Statements {
-			policy.Statements[i].ID = uuid.New()
-			policy.Statements[i].PolicyID = policy.ID
-		}
-
-		if len(policy.Statements) > 0 {
-			if err := tx.Create(&policy.Statements).Error; err != nil {
-				return err
-			}
-		}
-
-		return nil
+	stampPolicyAuthor(ctx, policy, true)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.createInTx(tx, policy)
 	})
+	return policyConflict(err)
 }
 
 func (r *policyRepository) GetByRole(ctx context.Context, role string) ([]*entities.PolicyDocument, error) {
@@ -73,29 +64,169 @@ func (r *policyRepository) GetActive(ctx context.Context) ([]*entities.PolicyDoc
 	return policies, nil
 }
 
+// Update stores policy as a new active version; earlier versions with the same name are kept inactive
 func (r *policyRepository) Update(ctx context.Context, policy *entities.PolicyDocument) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Save(policy).Error; err != nil {
+	stampPolicyAuthor(ctx, policy, false)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.createVersionInTx(tx, policy)
+	})
+	return policyConflict(err)
+}
+
+// GetVersions returns every version sharing the name of the given policy, oldest first
+func (r *policyRepository) GetVersions(ctx context.Context, id uuid.UUID) ([]*entities.PolicyDocument, error) {
+	db := r.db.WithContext(ctx)
+
+	var policy entities.PolicyDocument
+	if err := db.Select("name").Where("id = ?", id).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrPolicyNotFound
+		}
+		return nil, err
+	}
+
+	var versions []*entities.PolicyDocument
+	err := db.Preload("Statements").Where("name = ?", policy.Name).Order("created_at ASC").Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// Rollback makes the given version of the policy the only active one
+func (r *policyRepository) Rollback(ctx context.Context, id uuid.UUID, version string) (*entities.PolicyDocument, error) {
+	var target entities.PolicyDocument
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var policy entities.PolicyDocument
+		if err := tx.Select("name").Where("id = ?", id).First(&policy).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domainerrors.ErrPolicyNotFound
+			}
 			return err
 		}
 
-		if err := tx.Where("policy_id = ?", policy.ID).Delete(&entities.PolicyStatement{}).Error; err != nil {
+		err := tx.Preload("Statements").Where("name = ? AND version = ?", policy.Name, version).First(&target).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domainerrors.ErrPolicyVersionNotFound
+			}
 			return err
 		}
 
-		for i := range policy.Statements {
-			policy.Statements[i].ID = uuid.New()
-			policy.Statements[i].PolicyID = policy.ID
+		if err := tx.Model(&entities.PolicyDocument{}).Where("name = ?", policy.Name).Update("is_active", false).Error; err != nil {
+			return err
 		}
 
-		if len(policy.Statements) > 0 {
-			if err := tx.Create(&policy.Statements).Error; err != nil {
+		target.IsActive = true
+		return tx.Model(&entities.PolicyDocument{}).Where("id = ?", target.ID).Update("is_active", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// UpsertByName creates or replaces each policy matched by name in a single transaction
+func (r *policyRepository) UpsertByName(ctx context.Context, policies []*entities.PolicyDocument) ([]entities.PolicyImportResult, error) {
+	var results []entities.PolicyImportResult
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		results = make([]entities.PolicyImportResult, 0, len(policies))
+		for _, policy := range policies {
+			var existing entities.PolicyDocument
+			err := tx.Where("name = ?", policy.Name).First(&existing).Error
+			switch {
+			case err == nil:
+				stampPolicyAuthor(ctx, policy, false)
+				if err := r.createVersionInTx(tx, policy); err != nil {
+					return err
+				}
+				results = append(results, entities.NewPolicyImportResult(policy, constants.PolicyImportUpdated))
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				policy.ID = uuid.New()
+				stampPolicyAuthor(ctx, policy, true)
+				if err := r.createInTx(tx, policy); err != nil {
+					return err
+				}
+				results = append(results, entities.NewPolicyImportResult(policy, constants.PolicyImportCreated))
+			default:
 				return err
 			}
 		}
-
 		return nil
 	})
+	if err != nil {
+		return nil, policyConflict(err)
+	}
+
+	return results, nil
+}
+
+func (r *policyRepository) createInTx(tx *gorm.DB, policy *entities.PolicyDocument) error {
+	statements := policy.Statements
+	policy.Statements = nil
+	err := tx.Create(policy).Error
+	policy.Statements = statements
+	if err != nil {
+		return err
+	}
+
+	return r.createStatementsInTx(tx, policy)
+}
+
+func (r *policyRepository) createVersionInTx(tx *gorm.DB, policy *entities.PolicyDocument) error {
+	var previous []entities.PolicyDocument
+	err := tx.Select("version", "created_by").Where("name = ?", policy.Name).Order("created_at ASC").Find(&previous).Error
+	if err != nil {
+		return err
+	}
+	versions := make([]string, len(previous))
+	for i, version := range previous {
+		versions[i] = version.Version
+	}
+	if len(previous) > 0 {
+		policy.CreatedBy = previous[0].CreatedBy
+	}
+
+	if err := tx.Model(&entities.PolicyDocument{}).Where("name = ?", policy.Name).Update("is_active", false).Error; err != nil {
+		return err
+	}
+
+	policy.ID = uuid.New()
+	policy.Version = entities.NextPolicyVersion(versions)
+	policy.IsActive = true
+	policy.CreatedAt = time.Time{}
+	policy.UpdatedAt = time.Time{}
+	return r.createInTx(tx, policy)
+}
+
+// stampPolicyAuthor records the caller in ctx as the author of policy: as creator and updater of
+// a new policy, as updater of a new version. Writes without a caller, such as the startup seed,
+// leave the fields as given.
+func stampPolicyAuthor(ctx context.Context, policy *entities.PolicyDocument, created bool) {
+	userID, ok := constants.UserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	if created {
+		policy.CreatedBy = userID
+	}
+	policy.UpdatedBy = userID
+}
+
+func (r *policyRepository) createStatementsInTx(tx *gorm.DB, policy *entities.PolicyDocument) error {
+	for i := range policy.Statements {
+		policy.Statements[i].ID = uuid.New()
+		policy.Statements[i].PolicyID = policy.ID
+	}
+
+	if len(policy.Statements) > 0 {
+		return tx.Create(&policy.Statements).Error
+	}
+	return nil
 }
 
 func (r *policyRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -125,3 +256,13 @@ func (r *policyRepository) deduplicatePolicies(policies []*entities.PolicyDocume
 
 	return result
 }
+
+// policyConflict reports a unique violation on the policy name and version, such as a second
+// policy created under an existing name or a concurrent write of the same version, as
+// ErrPolicyAlreadyExists
+func policyConflict(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domainerrors.ErrPolicyAlreadyExists
+	}
+	return err
+}
@@ -1,10 +1,14 @@
 package repository
 
 import (
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/entities"
+	domainerrors "clean-architecture-api/internal/domain/errors"
 	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/pkg/logger"
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -23,26 +27,13 @@ func NewPolicySQLiteRepository(db *gorm.DB, logger logger.Logger) repositories.P
 }
 
 func (r *policySQLiteRepository) Create(ctx context.Context, policy *entities.PolicyDocument) error {
+	stampPolicyAuthor(ctx, policy, true)
 	policySQLite := entities.FromPolicyDocument(policy)
 
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		policyToCreate := *policySQLite
-		policyToCreate.Statements = nil
-
-		if err := tx.Create(&policyToCreate).Error; err != nil {
-			return err
-		}
-
-		for _, stmt := range policySQLite.Statements {
-			stmt.ID = uuid.New().String()
-			stmt.PolicyID = policyToCreate.ID
-			if err := tx.Create(&stmt).Error; err != nil {
-				return err
-			}
-		}
-
-		return nil
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.createInTx(tx, policySQLite)
 	})
+	return policyConflict(err)
 }
 
 func (r *policySQLiteRepository) GetByRole(ctx context.Context, role string) ([]*entities.PolicyDocument, error) {
@@ -86,31 +77,155 @@ func (r *policySQLiteRepository) GetActive(ctx context.Context) ([]*entities.Pol
 	return policies, nil
 }
 
+// Update stores policy as a new active version; earlier versions with the same name are kept inactive
 func (r *policySQLiteRepository) Update(ctx context.Context, policy *entities.PolicyDocument) error {
-	policySQLite := entities.FromPolicyDocument(policy)
+	stampPolicyAuthor(ctx, policy, false)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.createVersionInTx(tx, policy)
+	})
+	return policyConflict(err)
+}
 
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Save(policySQLite).Error; err != nil {
+// GetVersions returns every version sharing the name of the given policy, oldest first
+func (r *policySQLiteRepository) GetVersions(ctx context.Context, id uuid.UUID) ([]*entities.PolicyDocument, error) {
+	db := r.db.WithContext(ctx)
+
+	var policy entities.PolicyDocumentSQLite
+	if err := db.Select("name").Where("id = ?", id.String()).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrPolicyNotFound
+		}
+		return nil, err
+	}
+
+	var versionsSQLite []*entities.PolicyDocumentSQLite
+	err := db.Preload("Statements").Where("name = ?", policy.Name).Order("created_at ASC").Find(&versionsSQLite).Error
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*entities.PolicyDocument, len(versionsSQLite))
+	for i, versionSQLite := range versionsSQLite {
+		versions[i] = versionSQLite.ToPolicyDocument()
+	}
+
+	return versions, nil
+}
+
+// Rollback makes the given version of the policy the only active one
+func (r *policySQLiteRepository) Rollback(ctx context.Context, id uuid.UUID, version string) (*entities.PolicyDocument, error) {
+	var target entities.PolicyDocumentSQLite
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var policy entities.PolicyDocumentSQLite
+		if err := tx.Select("name").Where("id = ?", id.String()).First(&policy).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domainerrors.ErrPolicyNotFound
+			}
 			return err
 		}
 
-		if err := tx.Where("policy_id = ?", policySQLite.ID).Delete(&entities.PolicyStatementSQLite{}).Error; err != nil {
+		err := tx.Preload("Statements").Where("name = ? AND version = ?", policy.Name, version).First(&target).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domainerrors.ErrPolicyVersionNotFound
+			}
 			return err
 		}
 
-		for i := range policySQLite.Statements {
-			policySQLite.Statements[i].ID = uuid.New().String()
-			policySQLite.Statements[i].PolicyID = policySQLite.ID
+		if err := tx.Model(&entities.PolicyDocumentSQLite{}).Where("name = ?", policy.Name).Update("is_active", false).Error; err != nil {
+			return err
 		}
 
-		if len(policySQLite.Statements) > 0 {
-			if err := tx.Create(&policySQLite.Statements).Error; err != nil {
+		target.IsActive = true
+		return tx.Model(&entities.PolicyDocumentSQLite{}).Where("id = ?", target.ID).Update("is_active", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return target.ToPolicyDocument(), nil
+}
+
+// UpsertByName creates or replaces each policy matched by name in a single transaction
+func (r *policySQLiteRepository) UpsertByName(ctx context.Context, policies []*entities.PolicyDocument) ([]entities.PolicyImportResult, error) {
+	var results []entities.PolicyImportResult
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		results = make([]entities.PolicyImportResult, 0, len(policies))
+		for _, policy := range policies {
+			var existing entities.PolicyDocumentSQLite
+			err := tx.Where("name = ?", policy.Name).First(&existing).Error
+			switch {
+			case err == nil:
+				stampPolicyAuthor(ctx, policy, false)
+				if err := r.createVersionInTx(tx, policy); err != nil {
+					return err
+				}
+				results = append(results, entities.NewPolicyImportResult(policy, constants.PolicyImportUpdated))
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				policy.ID = uuid.New()
+				stampPolicyAuthor(ctx, policy, true)
+				if err := r.createInTx(tx, entities.FromPolicyDocument(policy)); err != nil {
+					return err
+				}
+				results = append(results, entities.NewPolicyImportResult(policy, constants.PolicyImportCreated))
+			default:
 				return err
 			}
 		}
-
 		return nil
 	})
+	if err != nil {
+		return nil, policyConflict(err)
+	}
+
+	return results, nil
+}
+
+func (r *policySQLiteRepository) createInTx(tx *gorm.DB, policySQLite *entities.PolicyDocumentSQLite) error {
+	policyToCreate := *policySQLite
+	policyToCreate.Statements = nil
+
+	if err := tx.Create(&policyToCreate).Error; err != nil {
+		return err
+	}
+
+	for _, stmt := range policySQLite.Statements {
+		stmt.ID = uuid.New().String()
+		stmt.PolicyID = policyToCreate.ID
+		if err := tx.Create(&stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *policySQLiteRepository) createVersionInTx(tx *gorm.DB, policy *entities.PolicyDocument) error {
+	var previous []entities.PolicyDocumentSQLite
+	err := tx.Select("version", "created_by").Where("name = ?", policy.Name).Order("created_at ASC").Find(&previous).Error
+	if err != nil {
+		return err
+	}
+	versions := make([]string, len(previous))
+	for i, version := range previous {
+		versions[i] = version.Version
+	}
+	if len(previous) > 0 {
+		policy.CreatedBy, _ = uuid.Parse(previous[0].CreatedBy)
+	}
+
+	if err := tx.Model(&entities.PolicyDocumentSQLite{}).Where("name = ?", policy.Name).Update("is_active", false).Error; err != nil {
+		return err
+	}
+
+	policy.ID = uuid.New()
+	policy.Version = entities.NextPolicyVersion(versions)
+	policy.IsActive = true
+	policy.CreatedAt = time.Time{}
+	policy.UpdatedAt = time.Time{}
+	return r.createInTx(tx, entities.FromPolicyDocument(policy))
 }
 
 func (r *policySQLiteRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	domainerrors "clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPolicy(action string) *entities.PolicyDocument {
+	return &entities.PolicyDocument{
+		Name:     "user-reports",
+		Version:  "1.0",
+		IsActive: true,
+		Statements: []entities.PolicyStatement{{
+			Effect:    constants.PolicyEffectAllow,
+			Principal: "role:" + constants.RoleUser,
+			Action:    action,
+			Resource:  "report",
+		}},
+	}
+}
+
+func TestPolicySQLiteRepository_VersionsAndRollback(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&entities.PolicyDocumentSQLite{}, &entities.PolicyStatementSQLite{}))
+	repo := NewPolicySQLiteRepository(db, logger.NewLogger())
+	ctx := context.Background()
+
+	original := newTestPolicy(constants.ActionRead)
+	require.NoError(t, repo.Create(ctx, original))
+	require.NoError(t, repo.Update(ctx, newTestPolicy(constants.ActionList)))
+
+	versions, err := repo.GetVersions(ctx, original.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "1.0", versions[0].Version)
+	assert.False(t, versions[0].IsActive)
+	assert.Equal(t, "2.0", versions[1].Version)
+	assert.True(t, versions[1].IsActive)
+
+	rolledBack, err := repo.Rollback(ctx, original.ID, "1.0")
+	require.NoError(t, err)
+	assert.Equal(t, original.ID, rolledBack.ID)
+
+	active, err := repo.GetActive(ctx)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, "1.0", active[0].Version)
+	assert.Equal(t, constants.ActionRead, active[0].Statements[0].Action)
+
+	_, err = repo.Rollback(ctx, original.ID, "9.0")
+	assert.ErrorIs(t, err, domainerrors.ErrPolicyVersionNotFound)
+}
+
+func TestPolicySQLiteRepository_CreateDuplicateNameConflicts(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&entities.PolicyDocumentSQLite{}, &entities.PolicyStatementSQLite{}))
+	repo := NewPolicySQLiteRepository(db, logger.NewLogger())
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, newTestPolicy(constants.ActionRead)))
+	err := repo.Create(ctx, newTestPolicy(constants.ActionList))
+
+	require.ErrorIs(t, err, domainerrors.ErrPolicyAlreadyExists)
+	var appErr *domainerrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, domainerrors.CategoryConflict, appErr.Category)
+	assert.Equal(t, "POLICY_EXISTS", appErr.Code)
+
+	active, err := repo.GetActive(ctx)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, constants.ActionRead, active[0].Statements[0].Action, "the first policy is left as it was")
+}
+
+func TestPolicySQLiteRepository_RecordsAuthors(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&entities.PolicyDocumentSQLite{}, &entities.PolicyStatementSQLite{}))
+	repo := NewPolicySQLiteRepository(db, logger.NewLogger())
+	creatorID, editorID := uuid.New(), uuid.New()
+
+	original := newTestPolicy(constants.ActionRead)
+	require.NoError(t, repo.Create(constants.WithUserID(context.Background(), creatorID), original))
+	require.NoError(t, repo.Update(constants.WithUserID(context.Background(), editorID), newTestPolicy(constants.ActionList)))
+
+	versions, err := repo.GetVersions(context.Background(), original.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, creatorID, versions[0].CreatedBy)
+	assert.Equal(t, creatorID, versions[0].UpdatedBy)
+	assert.Equal(t, creatorID, versions[1].CreatedBy, "a new version keeps the policy's creator")
+	assert.Equal(t, editorID, versions[1].UpdatedBy)
+
+	// Seeding at startup has no caller
+	seeded := newTestPolicy(constants.ActionRead)
+	seeded.ID, seeded.Name = uuid.New(), "seeded"
+	require.NoError(t, repo.Create(context.Background(), seeded))
+	active, err := repo.GetActive(context.Background())
+	require.NoError(t, err)
+	for _, policy := range active {
+		if policy.Name == "seeded" {
+			assert.Equal(t, uuid.Nil, policy.CreatedBy)
+			assert.Equal(t, uuid.Nil, policy.UpdatedBy)
+		}
+	}
+}
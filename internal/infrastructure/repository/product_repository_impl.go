@@ -1,12 +1,17 @@
 package repository
 
 import (
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/entities"
 	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/pkg/logger"
 	"context"
 
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productRepository struct {
@@ -17,18 +22,88 @@ func NewProductRepository(
 	db *gorm.DB,
 	authService repositories.AuthorizationService,
 	auditLogger repositories.AuditLogger,
+	reads ReadAuditing,
 	logger logger.Logger,
 ) repositories.ProductRepository {
-	return &productRepository{
-		CleanBaseRepositoryImpl: NewCleanBaseRepository[entities.Product](db, auditLogger, logger, "product", authService),
-	}
+	base := NewCleanBaseRepository[entities.Product](db, auditLogger, logger, "product", authService)
+	return &productRepository{CleanBaseRepositoryImpl: base.WithReadAuditing(reads.covers("product"))}
 }
 
 func (r *productRepository) GetByCategory(ctx context.Context, category string, limit, offset int) ([]*entities.Product, error) {
 	var products []*entities.Product
-	err := r.GetDB().WithContext(ctx).Where("category = ?", category).Limit(limit).Offset(offset).Find(&products).Error
+	err := listOrder(ctx, r.readDB(ctx)).Where("LOWER(category) = LOWER(?)", category).Limit(limit).Offset(offset).Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *productRepository) CountInCategory(ctx context.Context, category string) (int64, error) {
+	var count int64
+	err := r.readDB(ctx).Model(&entities.Product{}).Where("LOWER(category) = LOWER(?)", category).Count(&count).Error
+	return count, err
+}
+
+func (r *productRepository) CountByCategory(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Category string
+		Count    int64
+	}
+	// NULL and empty categories group together, so the uncategorized bucket is never split
+	err := r.readDB(ctx).Model(&entities.Product{}).
+		Select("LOWER(COALESCE(category, '')) AS category, COUNT(*) AS count").
+		Group("LOWER(COALESCE(category, ''))").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Category] += row.Count
+	}
+	return counts, nil
+}
+
+func (r *productRepository) GetLowStock(ctx context.Context, threshold, limit, offset int) ([]*entities.Product, error) {
+	var products []*entities.Product
+	err := r.readDB(ctx).Where("stock <= ?", threshold).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "stock"}}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}}).
+		Limit(limit).Offset(offset).Find(&products).Error
 	if err != nil {
 		return nil, err
 	}
 	return products, nil
 }
+
+// Restore returns ErrProductNotFound unless a soft-deleted product has this ID, so restoring a
+// live product is reported the same way as restoring one that never existed
+func (r *productRepository) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.Product, error) {
+	if err := r.ValidateAccess(ctx, userID, "update"); err != nil {
+		return nil, err
+	}
+
+	var restored int64
+	err := r.retryWrite(ctx, func() error {
+		result := r.writeDB(ctx).Unscoped().Model(&entities.Product{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Updates(map[string]interface{}{"deleted_at": nil, "updated_by": userID})
+		restored = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		r.logger.Error("Database restore operation failed", err)
+		return nil, r.handleDatabaseError(err, "restore", r.resourceName)
+	}
+	if restored == 0 {
+		return nil, domainerrors.ErrProductNotFound
+	}
+
+	var product entities.Product
+	if err := r.writeDB(ctx).Where("id = ?", id).First(&product).Error; err != nil {
+		return nil, r.handleDatabaseError(err, "restore", r.resourceName)
+	}
+
+	return &product, r.AuditLog(ctx, userID, constants.ActionRestore, &product)
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"testing"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductRepository_GetLowStock(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductRepository(db, nil, nil, nil, logger.NewLogger())
+	ctx := context.Background()
+
+	for name, stock := range map[string]int{"plenty": 50, "edge": 5, "few": 2, "none": 0, "six": 6} {
+		require.NoError(t, db.Create(&entities.Product{Name: name, Price: 1, Stock: stock}).Error)
+	}
+
+	products, err := repo.GetLowStock(ctx, 5, 10, 0)
+	require.NoError(t, err)
+	names := make([]string, len(products))
+	for i, product := range products {
+		names[i] = product.Name
+	}
+	assert.Equal(t, []string{"none", "few", "edge"}, names, "stock <= threshold, ascending")
+
+	page, err := repo.GetLowStock(ctx, 5, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "few", page[0].Name)
+}
+
+func TestProductRepository_GetByCategoryWithoutProducts(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	for name, repo := range map[string]repositories.ProductRepository{
+		"database": NewProductRepository(db, nil, nil, nil, logger.NewLogger()),
+		"memory":   NewMemoryProductRepository(nil, nil, nil, logger.NewLogger()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, repo.Create(ctx, &entities.Product{Name: "novel", Price: 1, Category: "books"}, userID))
+			deleted := &entities.Product{Name: "lamp", Price: 1, Category: "home"}
+			require.NoError(t, repo.Create(ctx, deleted, userID))
+			require.NoError(t, repo.Delete(ctx, deleted.ID, userID))
+
+			// A category whose products were all deleted and one never used both list nothing
+			for _, category := range []string{"home", "garden"} {
+				products, err := repo.GetByCategory(ctx, category, 10, 0)
+				require.NoError(t, err, category)
+				assert.NotNil(t, products, category)
+				assert.Empty(t, products, category)
+			}
+
+			products, err := repo.GetByCategory(ctx, "books", 10, 0)
+			require.NoError(t, err)
+			assert.Len(t, products, 1)
+		})
+	}
+}
+
+func TestProductRepository_MatchesCategoryIgnoringCase(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	for name, repo := range map[string]repositories.ProductRepository{
+		"database": NewProductRepository(db, nil, nil, nil, logger.NewLogger()),
+		"memory":   NewMemoryProductRepository(nil, nil, nil, logger.NewLogger()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Rows stored before categories were normalized keep their original casing
+			for _, category := range []string{"Books", "books", "BOOKS"} {
+				require.NoError(t, repo.Create(ctx, &entities.Product{Name: category, Price: 1, Category: category}, userID))
+			}
+
+			products, err := repo.GetByCategory(ctx, "books", 10, 0)
+			require.NoError(t, err)
+			assert.Len(t, products, 3)
+			count, err := repo.CountInCategory(ctx, "Books")
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), count)
+			counts, err := repo.CountByCategory(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int64{"books": 3}, counts)
+		})
+	}
+}
+
+func TestProductRepository_CountByCategory(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductRepository(db, nil, nil, nil, logger.NewLogger())
+	ctx := context.Background()
+
+	for name, category := range map[string]string{
+		"novel": "books", "atlas": "books", "poem": "books", "lamp": "home", "mystery": "", "unknown": "",
+	} {
+		require.NoError(t, db.Create(&entities.Product{Name: name, Price: 1, Category: category}).Error)
+	}
+	deleted := &entities.Product{Name: "gone", Price: 1, Category: "home"}
+	require.NoError(t, db.Create(deleted).Error)
+	require.NoError(t, repo.Delete(ctx, deleted.ID, uuid.New()))
+
+	counts, err := repo.CountByCategory(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"books": 3, "home": 1, "": 2}, counts,
+		"deleted products are not counted")
+}
+
+func TestProductRepository_Restore(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductRepository(db, nil, nil, nil, logger.NewLogger())
+	ctx := context.Background()
+	userID := uuid.New()
+
+	product := &entities.Product{Name: "restorable", Price: 1, Stock: 3}
+	require.NoError(t, db.Create(product).Error)
+	require.NoError(t, repo.Delete(ctx, product.ID, userID))
+	_, err := repo.GetByID(ctx, product.ID, userID)
+	require.Error(t, err, "a deleted product must be hidden")
+
+	restored, err := repo.Restore(ctx, product.ID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "restorable", restored.Name)
+	assert.False(t, restored.DeletedAt.Valid)
+	assert.Equal(t, userID, restored.UpdatedBy)
+
+	found, err := repo.GetByID(ctx, product.ID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, product.ID, found.ID)
+}
+
+func TestProductRepository_RestoreLiveProduct(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewProductRepository(db, nil, nil, nil, logger.NewLogger())
+	ctx := context.Background()
+
+	product := &entities.Product{Name: "live", Price: 1}
+	require.NoError(t, db.Create(product).Error)
+
+	_, err := repo.Restore(ctx, product.ID, uuid.New())
+	assert.ErrorIs(t, err, domainerrors.ErrProductNotFound)
+
+	_, err = repo.Restore(ctx, uuid.New(), uuid.New())
+	assert.ErrorIs(t, err, domainerrors.ErrProductNotFound)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) repositories.RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *entities.RefreshToken) error {
+	return connFor(ctx, r.db).WithContext(ctx).Create(token).Error
+}
+
+func (r *refreshTokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.RefreshToken, error) {
+	var token entities.RefreshToken
+	if err := connFor(ctx, r.db).WithContext(ctx).Where("id = ?", id).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := connFor(ctx, r.db).WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", time.Now().UTC())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	return connFor(ctx, r.db).WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now().UTC()).Error
+}
+
+func (r *refreshTokenRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]*entities.RefreshToken, error) {
+	var tokens []*entities.RefreshToken
+	err := connFor(ctx, r.db).WithContext(ctx).
+		Where("user_id = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?", userID, time.Now().UTC()).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *refreshTokenRepository) RevokeUserFamily(ctx context.Context, userID, familyID uuid.UUID) (bool, error) {
+	result := connFor(ctx, r.db).WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("user_id = ? AND family_id = ? AND revoked_at IS NULL", userID, familyID).
+		Update("revoked_at", time.Now().UTC())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// repositoryHooks holds the access checks, audit trail and error mapping every repository applies
+// around its storage, so the GORM and in-memory implementations behave alike
+type repositoryHooks[T any] struct {
+	auditLogger  repositories.AuditLogger
+	logger       logger.Logger
+	resourceName string
+	authService  repositories.AuthorizationService
+	// auditReads records reads and lists as well as mutations
+	auditReads bool
+}
+
+func newRepositoryHooks[T any](
+	auditLogger repositories.AuditLogger,
+	logger logger.Logger,
+	resourceName string,
+	authService repositories.AuthorizationService,
+) repositoryHooks[T] {
+	return repositoryHooks[T]{
+		auditLogger:  auditLogger,
+		logger:       logger,
+		resourceName: resourceName,
+		authService:  authService,
+	}
+}
+
+// ReadAuditing names the resources whose reads and lists are audited, case-insensitively, or
+// holds * for all of them; mutations are always audited. config.Config.AuditReads supplies it.
+type ReadAuditing []string
+
+func (a ReadAuditing) covers(resource string) bool {
+	for _, name := range a {
+		if name == "*" || strings.EqualFold(name, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *repositoryHooks[T]) ValidateAccess(ctx context.Context, userID uuid.UUID, action string) error {
+	if r.authService == nil {
+		return nil
+	}
+
+	// System user bypass - allow system operations
+	if constants.IsSystemUser(userID) {
+		return nil
+	}
+
+	return r.authService.CheckPermission(ctx, userID, r.resourceName, action)
+}
+
+// AuditLog records action against entity, or against no single entity when entity is nil
+func (r *repositoryHooks[T]) AuditLog(ctx context.Context, userID uuid.UUID, action string, entity *T) error {
+	entityID := uuid.Nil
+	if entity != nil {
+		if identifiable, ok := any(entity).(entities.Identifiable); ok {
+			entityID = identifiable.GetID()
+		}
+	}
+	return r.auditEntity(ctx, userID, action, entityID)
+}
+
+// auditRead records a read or list through AuditLog when reads of this resource are audited
+func (r *repositoryHooks[T]) auditRead(ctx context.Context, userID uuid.UUID, action string, entity *T) error {
+	if !r.auditReads {
+		return nil
+	}
+	return r.AuditLog(ctx, userID, action, entity)
+}
+
+func (r *repositoryHooks[T]) auditEntity(ctx context.Context, userID uuid.UUID, action string, entityID uuid.UUID) error {
+	if r.auditLogger == nil {
+		return nil
+	}
+
+	resource := r.resourceName + ":" + action
+	return r.auditLogger.LogAccess(ctx, userID, action, resource, entityID)
+}
+
+func (r *repositoryHooks[T]) handleDatabaseError(err error, operation, resource string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domainerrors.NewNotFoundError(
+			fmt.Sprintf("%s_NOT_FOUND", resource),
+			fmt.Sprintf("%s not found", resource),
+		)
+	}
+
+	// The cause is kept so repositories can swap in their own conflict error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		conflict := domainerrors.NewConflictError(
+			fmt.Sprintf("%s_ALREADY_EXISTS", resource),
+			fmt.Sprintf("%s already exists", resource),
+		)
+		conflict.Cause = err
+		return conflict
+	}
+
+	return domainerrors.NewDatabaseError(
+		fmt.Sprintf("%s_%s_FAILED", operation, resource),
+		fmt.Sprintf("database %s operation failed for %s", operation, resource),
+		err,
+	)
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"context"
+	"errors"
+	"time"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type reservationRepository struct {
+	db *gorm.DB
+}
+
+func NewReservationRepository(db *gorm.DB) repositories.ReservationRepository {
+	return &reservationRepository{db: db}
+}
+
+// Reserve decrements stock with a conditional UPDATE, so two checkouts racing for the last units
+// cannot both succeed
+func (r *reservationRepository) Reserve(ctx context.Context, reservation *entities.Reservation) error {
+	return connFor(ctx, r.db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Product{}).
+			Where("id = ? AND stock >= ?", reservation.ProductID, reservation.Quantity).
+			Update("stock", gorm.Expr("stock - ?", reservation.Quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domainerrors.ErrInsufficientStock
+		}
+		return tx.Create(reservation).Error
+	})
+}
+
+func (r *reservationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Reservation, error) {
+	var reservation entities.Reservation
+	err := connFor(ctx, r.db).WithContext(ctx).Where("id = ?", id).First(&reservation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domainerrors.ErrReservationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *reservationRepository) Confirm(ctx context.Context, id uuid.UUID) error {
+	result := connFor(ctx, r.db).WithContext(ctx).Model(&entities.Reservation{}).
+		Where("id = ? AND status = ? AND expires_at > ?", id, constants.ReservationStatusPending, time.Now().UTC()).
+		Update("status", constants.ReservationStatusConfirmed)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.notPendingError(ctx, id)
+	}
+	return nil
+}
+
+func (r *reservationRepository) Release(ctx context.Context, id uuid.UUID) error {
+	var released bool
+	err := connFor(ctx, r.db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		released, err = releaseReservation(tx, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !released {
+		return r.notPendingError(ctx, id)
+	}
+	return nil
+}
+
+func (r *reservationRepository) ReleaseExpired(ctx context.Context, now time.Time) (int, error) {
+	var expired []uuid.UUID
+	err := connFor(ctx, r.db).WithContext(ctx).Model(&entities.Reservation{}).
+		Where("status = ? AND expires_at <= ?", constants.ReservationStatusPending, now.UTC()).
+		Pluck("id", &expired).Error
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, id := range expired {
+		err := connFor(ctx, r.db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			released, err := releaseReservation(tx, id)
+			if released {
+				count++
+			}
+			return err
+		})
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// releaseReservation flips a pending reservation to released and returns its quantity to stock.
+// It reports false when the reservation was no longer pending, e.g. a concurrent confirm won.
+func releaseReservation(tx *gorm.DB, id uuid.UUID) (bool, error) {
+	var reservation entities.Reservation
+	if err := tx.Where("id = ?", id).First(&reservation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	result := tx.Model(&entities.Reservation{}).
+		Where("id = ? AND status = ?", id, constants.ReservationStatusPending).
+		Update("status", constants.ReservationStatusReleased)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	err := tx.Model(&entities.Product{}).
+		Where("id = ?", reservation.ProductID).
+		Update("stock", gorm.Expr("stock + ?", reservation.Quantity)).Error
+	return err == nil, err
+}
+
+func (r *reservationRepository) notPendingError(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return domainerrors.ErrReservationNotPending
+}
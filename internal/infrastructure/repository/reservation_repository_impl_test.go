@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"testing"
+	"time"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupReservationTest(t *testing.T, stock int) (*gorm.DB, *reservationRepository, *entities.Product) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&entities.Reservation{}))
+
+	product := &entities.Product{Name: "Widget", Price: 10, Stock: stock}
+	require.NoError(t, db.Create(product).Error)
+	return db, NewReservationRepository(db).(*reservationRepository), product
+}
+
+func newReservation(productID uuid.UUID, quantity int, ttl time.Duration) *entities.Reservation {
+	return &entities.Reservation{
+		ID:        uuid.New(),
+		ProductID: productID,
+		Quantity:  quantity,
+		Status:    constants.ReservationStatusPending,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+	}
+}
+
+func stockOf(t *testing.T, db *gorm.DB, productID uuid.UUID) int {
+	var product entities.Product
+	require.NoError(t, db.First(&product, "id = ?", productID).Error)
+	return product.Stock
+}
+
+func TestReservationRepository_ReserveConfirmRelease(t *testing.T) {
+	db, repo, product := setupReservationTest(t, 5)
+	ctx := context.Background()
+
+	first := newReservation(product.ID, 3, time.Minute)
+	require.NoError(t, repo.Reserve(ctx, first))
+	assert.Equal(t, 2, stockOf(t, db, product.ID))
+
+	// Only two units are left, so this would oversell
+	assert.ErrorIs(t, repo.Reserve(ctx, newReservation(product.ID, 3, time.Minute)), domainerrors.ErrInsufficientStock)
+	assert.Equal(t, 2, stockOf(t, db, product.ID))
+
+	second := newReservation(product.ID, 2, time.Minute)
+	require.NoError(t, repo.Reserve(ctx, second))
+	assert.Equal(t, 0, stockOf(t, db, product.ID))
+
+	require.NoError(t, repo.Confirm(ctx, first.ID))
+	require.NoError(t, repo.Release(ctx, second.ID))
+	assert.Equal(t, 2, stockOf(t, db, product.ID), "confirmed units stay sold, released units return")
+
+	assert.ErrorIs(t, repo.Release(ctx, first.ID), domainerrors.ErrReservationNotPending)
+	assert.ErrorIs(t, repo.Confirm(ctx, second.ID), domainerrors.ErrReservationNotPending)
+	assert.ErrorIs(t, repo.Release(ctx, uuid.New()), domainerrors.ErrReservationNotFound)
+}
+
+func TestReservationSweeper_ReleasesExpired(t *testing.T) {
+	db, repo, product := setupReservationTest(t, 10)
+	ctx := context.Background()
+
+	expired := newReservation(product.ID, 4, time.Millisecond)
+	active := newReservation(product.ID, 3, time.Hour)
+	require.NoError(t, repo.Reserve(ctx, expired))
+	require.NoError(t, repo.Reserve(ctx, active))
+	assert.Equal(t, 3, stockOf(t, db, product.ID))
+	time.Sleep(5 * time.Millisecond)
+
+	// An expired reservation can no longer be confirmed
+	assert.ErrorIs(t, repo.Confirm(ctx, expired.ID), domainerrors.ErrReservationNotPending)
+
+	sweeper := NewReservationSweeper(repo, time.Minute, logger.NewLogger())
+	released, err := sweeper.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+	assert.Equal(t, 7, stockOf(t, db, product.ID))
+
+	stored, err := repo.GetByID(ctx, expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ReservationStatusReleased, stored.Status)
+
+	released, err = sweeper.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, released, "released reservations are not returned twice")
+	require.NoError(t, repo.Confirm(ctx, active.ID))
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+)
+
+// ReservationSweeper returns the stock of abandoned checkouts by releasing expired reservations
+type ReservationSweeper struct {
+	*periodicRunner
+	reservations repositories.ReservationRepository
+	logger       logger.Logger
+}
+
+func NewReservationSweeper(
+	reservations repositories.ReservationRepository,
+	interval time.Duration,
+	logger logger.Logger,
+) *ReservationSweeper {
+	s := &ReservationSweeper{
+		reservations: reservations,
+		logger:       logger,
+	}
+	s.periodicRunner = newPeriodicRunner(interval, func(ctx context.Context) error {
+		_, err := s.RunOnce(ctx)
+		return err
+	}, "Reservation sweep failed", logger)
+	return s
+}
+
+// RunOnce releases the reservations that expired by now and returns how many it released
+func (s *ReservationSweeper) RunOnce(ctx context.Context) (int, error) {
+	released, err := s.reservations.ReleaseExpired(ctx, time.Now())
+	if released > 0 {
+		s.logger.Info(fmt.Sprintf("Released %d expired reservations", released))
+	}
+	return released, err
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// RetryPolicy controls how repository writes are retried on transient database errors
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// SQLStates holds Postgres SQLSTATE codes or class prefixes (e.g. "08") considered transient
+	SQLStates []string
+}
+
+func NewRetryPolicyFromEnv() RetryPolicy {
+	policy := RetryPolicy{
+		MaxAttempts:    constants.DefaultDBRetryMaxAttempts,
+		InitialBackoff: constants.DefaultDBRetryInitialBackoff,
+		MaxBackoff:     constants.DefaultDBRetryMaxBackoff,
+		SQLStates:      splitSQLStates(constants.DefaultDBRetrySQLStates),
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("DB_RETRY_MAX_ATTEMPTS")); err == nil && value > 0 {
+		policy.MaxAttempts = value
+	}
+	if value, err := time.ParseDuration(os.Getenv("DB_RETRY_INITIAL_BACKOFF")); err == nil && value > 0 {
+		policy.InitialBackoff = value
+	}
+	if value, err := time.ParseDuration(os.Getenv("DB_RETRY_MAX_BACKOFF")); err == nil && value > 0 {
+		policy.MaxBackoff = value
+	}
+	if value := os.Getenv("DB_RETRY_SQLSTATES"); value != "" {
+		policy.SQLStates = splitSQLStates(value)
+	}
+
+	return policy
+}
+
+// IsTransient reports whether err is worth retrying under this policy
+func (p RetryPolicy) IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, gorm.ErrDuplicatedKey) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		for _, state := range p.SQLStates {
+			if strings.HasPrefix(pgErr.Code, state) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Do runs op until it succeeds, returns a non-transient error, runs out of attempts
+// or ctx is cancelled. The backoff doubles after each failed attempt up to MaxBackoff.
+func (p RetryPolicy) Do(ctx context.Context, op func() error) error {
+	attempts := p.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := p.InitialBackoff
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = op(); err == nil || !p.IsTransient(err) || attempt == attempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+
+	return err
+}
+
+func splitSQLStates(value string) []string {
+	var states []string
+	for _, state := range strings.Split(value, ",") {
+		if state = strings.TrimSpace(state); state != "" {
+			states = append(states, strings.ToUpper(state))
+		}
+	}
+	return states
+}
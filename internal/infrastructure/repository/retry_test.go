@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type flakyOperation struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyOperation) Run() error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		SQLStates:      []string{"08", "40001"},
+	}
+}
+
+func TestRetryPolicy_RetriesTransientErrors(t *testing.T) {
+	op := &flakyOperation{failures: 2, err: &pgconn.PgError{Code: "40001"}}
+
+	err := testRetryPolicy().Do(context.Background(), op.Run)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, op.calls)
+}
+
+func TestRetryPolicy_StopsAtMaxAttempts(t *testing.T) {
+	op := &flakyOperation{failures: 5, err: &pgconn.PgError{Code: "08006"}}
+
+	err := testRetryPolicy().Do(context.Background(), op.Run)
+
+	assert.Error(t, err)
+	assert.Equal(t, 3, op.calls)
+}
+
+func TestRetryPolicy_DoesNotRetryPermanentErrors(t *testing.T) {
+	for _, permanent := range []error{gorm.ErrRecordNotFound, gorm.ErrDuplicatedKey, &pgconn.PgError{Code: "23505"}} {
+		op := &flakyOperation{failures: 2, err: permanent}
+
+		err := testRetryPolicy().Do(context.Background(), op.Run)
+
+		assert.ErrorIs(t, err, permanent)
+		assert.Equal(t, 1, op.calls)
+	}
+}
+
+func TestRetryPolicy_RespectsContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	op := &flakyOperation{failures: 2, err: &pgconn.PgError{Code: "40001"}}
+
+	err := testRetryPolicy().Do(ctx, op.Run)
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, op.calls)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/repositories"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txContextKey struct{}
+
+type transactionManager struct {
+	db *gorm.DB
+}
+
+func NewTransactionManager(db *gorm.DB) repositories.TransactionManager {
+	return &transactionManager{db: db}
+}
+
+// WithinTransaction joins an already running transaction instead of nesting a new one
+func (m *transactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
+
+func txFromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(*gorm.DB)
+	return tx, ok
+}
+
+// connFor returns the transaction carried by ctx, or db when there is none
+func connFor(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
@@ -1,11 +1,17 @@
 package repository
 
 import (
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/entities"
 	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/pkg/logger"
 	"context"
+	"errors"
+	"strings"
 
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -17,18 +23,119 @@ func NewUserRepository(
 	db *gorm.DB,
 	authService repositories.AuthorizationService,
 	auditLogger repositories.AuditLogger,
+	reads ReadAuditing,
 	logger logger.Logger,
 ) repositories.UserRepository {
-	return &userRepository{
-		CleanBaseRepositoryImpl: NewCleanBaseRepository[entities.User](db, auditLogger, logger, "user", authService),
+	base := NewCleanBaseRepository[entities.User](db, auditLogger, logger, "user", authService)
+	base.WithReadAuditing(reads.covers("user"))
+	base.listScope = hideSystemUser
+	return &userRepository{CleanBaseRepositoryImpl: base}
+}
+
+// hideSystemUser leaves the seeded system user out of user lists and searches; it is an
+// internal identity, not an account
+func hideSystemUser(db *gorm.DB) *gorm.DB {
+	return db.Where("id <> ?", constants.SystemUserID())
+}
+
+// Create validates the user first so no creation path can store an invalid role. An email that
+// is already registered fails with ErrUserAlreadyExists, including when a concurrent registration
+// inserted it after the caller checked.
+func (r *userRepository) Create(ctx context.Context, user *entities.User, userID uuid.UUID) error {
+	if err := user.Validate(); err != nil {
+		return err
+	}
+	err := r.CleanBaseRepositoryImpl.Create(ctx, user, userID)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domainerrors.ErrUserAlreadyExists
+	}
+	return err
+}
+
+func (r *userRepository) Update(ctx context.Context, user *entities.User, userID uuid.UUID) error {
+	if err := user.Validate(); err != nil {
+		return err
 	}
+	return r.CleanBaseRepositoryImpl.Update(ctx, user, userID)
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
 	var user entities.User
-	err := r.GetDB().WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := r.readDB(ctx).Where("email = ? AND deleted_at IS NULL", email).First(&user).Error
 	if err != nil {
-		return nil, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrUserNotFound
+		}
+		return nil, r.handleDatabaseError(err, "read", r.resourceName)
 	}
 	return &user, nil
 }
+
+func (r *userRepository) GetByEmailIncludingDeleted(ctx context.Context, email string) ([]*entities.User, error) {
+	var users []*entities.User
+	err := r.readDB(ctx).Unscoped().
+		Where("email = ?", email).
+		Order("created_at DESC").
+		Find(&users).Error
+	if err != nil {
+		return nil, r.handleDatabaseError(err, "read", r.resourceName)
+	}
+	return users, nil
+}
+
+func (r *userRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	var count int64
+	err := hideSystemUser(r.readDB(ctx).Model(&entities.User{})).
+		Where("role = ? AND is_active = ?", role, true).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *userRepository) ListPendingApproval(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	var users []*entities.User
+	err := r.readDB(ctx).
+		Where("approval_pending = ?", true).
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, r.handleDatabaseError(err, "list", r.resourceName)
+	}
+	return users, nil
+}
+
+// likeEscaper escapes the LIKE wildcards in user input so they match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *userRepository) Search(ctx context.Context, search repositories.UserSearch, limit, offset int) ([]*entities.User, int64, error) {
+	query := hideSystemUser(r.readDB(ctx).Model(&entities.User{}))
+	if term := strings.ToLower(strings.TrimSpace(search.Query)); term != "" {
+		pattern := "%" + likeEscaper.Replace(term) + "%"
+		query = query.Where(
+			`(LOWER(first_name) LIKE ? ESCAPE '\' OR LOWER(last_name) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\')`,
+			pattern, pattern, pattern,
+		)
+	}
+	if search.Role != "" {
+		query = query.Where("role = ?", search.Role)
+	}
+	if search.Active != nil {
+		query = query.Where("is_active = ?", *search.Active)
+	}
+	// The count and the page share the filters but must not share statement state
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, r.handleDatabaseError(err, "list", r.resourceName)
+	}
+
+	var users []*entities.User
+	if err := listOrder(ctx, query).Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		return nil, 0, r.handleDatabaseError(err, "list", r.resourceName)
+	}
+	return users, total, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"testing"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_ReRegisterAfterSoftDelete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db, nil, nil, nil, logger.NewLogger())
+	ctx := context.Background()
+	systemID := uuid.Nil
+
+	newUser := func() *entities.User {
+		return &entities.User{Email: "reuse@example.com", Password: "hash", FirstName: "Re", LastName: "Use"}
+	}
+
+	original := newUser()
+	require.NoError(t, repo.Create(ctx, original, systemID))
+	assert.ErrorIs(t, repo.Create(ctx, newUser(), systemID), domainerrors.ErrUserAlreadyExists, "active emails stay unique")
+
+	require.NoError(t, repo.Delete(ctx, original.ID, systemID))
+	_, err := repo.GetByEmail(ctx, original.Email)
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
+
+	replacement := newUser()
+	require.NoError(t, repo.Create(ctx, replacement, systemID))
+
+	found, err := repo.GetByEmail(ctx, original.Email)
+	require.NoError(t, err)
+	assert.Equal(t, replacement.ID, found.ID)
+
+	all, err := repo.GetByEmailIncludingDeleted(ctx, original.Email)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	ids := []uuid.UUID{all[0].ID, all[1].ID}
+	assert.ElementsMatch(t, []uuid.UUID{original.ID, replacement.ID}, ids)
+	for _, user := range all {
+		assert.Equal(t, user.ID == original.ID, user.DeletedAt.Valid)
+	}
+}
+
+func TestUserRepository_Search(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db, nil, nil, nil, logger.NewLogger())
+	ctx := context.Background()
+
+	seed := []struct {
+		email, first, last, role string
+		active                   bool
+	}{
+		{"ada@example.com", "Ada", "Lovelace", constants.RoleAdmin, true},
+		{"alan@example.com", "Alan", "Turing", constants.RoleUser, true},
+		{"grace@example.com", "Grace", "Hopper", constants.RoleAdmin, false},
+		{"linus@example.com", "Linus", "Torvalds", constants.RoleUser, false},
+		{"percent@example.com", "100%", "Sure", constants.RoleUser, true},
+	}
+	for _, s := range seed {
+		user := &entities.User{Email: s.email, Password: "hash", FirstName: s.first, LastName: s.last, Role: s.role}
+		require.NoError(t, repo.Create(ctx, user, uuid.Nil))
+		// is_active has a database default of true, which a false field does not override on insert
+		require.NoError(t, db.Model(user).Update("is_active", s.active).Error)
+	}
+
+	emails := func(search repositories.UserSearch, limit, offset int) ([]string, int64) {
+		users, total, err := repo.Search(ctx, search, limit, offset)
+		require.NoError(t, err)
+		found := make([]string, len(users))
+		for i, user := range users {
+			found[i] = user.Email
+		}
+		return found, total
+	}
+	active, inactive := true, false
+
+	tests := []struct {
+		name   string
+		search repositories.UserSearch
+		want   []string
+	}{
+		{"name, case-insensitive", repositories.UserSearch{Query: "TUR"}, []string{"alan@example.com"}},
+		{"last name or email", repositories.UserSearch{Query: "to"}, []string{"linus@example.com"}},
+		{"role", repositories.UserSearch{Role: constants.RoleAdmin}, []string{"ada@example.com", "grace@example.com"}},
+		{"active", repositories.UserSearch{Active: &inactive}, []string{"grace@example.com", "linus@example.com"}},
+		{"query, role and active", repositories.UserSearch{Query: "a", Role: constants.RoleAdmin, Active: &active}, []string{"ada@example.com"}},
+		{"query and role match nobody", repositories.UserSearch{Query: "linus", Role: constants.RoleAdmin}, []string{}},
+		{"wildcards match literally", repositories.UserSearch{Query: "0%"}, []string{"percent@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, total := emails(tt.search, 10, 0)
+			assert.ElementsMatch(t, tt.want, found)
+			assert.Equal(t, int64(len(tt.want)), total)
+		})
+	}
+
+	// The total counts every match, not just the page
+	page, total := emails(repositories.UserSearch{Active: &active}, 1, 1)
+	assert.Len(t, page, 1)
+	assert.Equal(t, int64(3), total)
+}
+
+func TestUserRepository_SystemUserIsNoAccount(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	systemID := constants.SystemUserID()
+
+	for name, repo := range map[string]repositories.UserRepository{
+		"database": NewUserRepository(db, nil, nil, nil, logger.NewLogger()),
+		"memory":   NewMemoryUserRepository(nil, nil, nil, logger.NewLogger()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			create := func(user *entities.User) {
+				active := user.IsActive
+				require.NoError(t, repo.Create(ctx, user, systemID))
+				// is_active has a database default of true, which a false field does not override on insert
+				user.IsActive = active
+				require.NoError(t, repo.Update(ctx, user, systemID))
+			}
+			create(&entities.User{
+				BaseEntity: entities.BaseEntity{ID: systemID}, Email: constants.SystemUserEmail, Password: "!",
+				FirstName: "System", LastName: "User", Role: constants.RoleAdmin,
+			})
+			create(&entities.User{Email: "former@example.com", Password: "hash", FirstName: "Former", LastName: "Admin", Role: constants.RoleAdmin})
+			create(&entities.User{Email: "last@example.com", Password: "hash", FirstName: "Last", LastName: "Admin", Role: constants.RoleAdmin, IsActive: true})
+
+			// Neither the system user nor a deactivated admin can keep the last real admin from being deleted
+			admins, err := repo.CountByRole(ctx, constants.RoleAdmin)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), admins)
+
+			listed, err := repo.List(ctx, 10, 0, systemID)
+			require.NoError(t, err)
+			assert.Len(t, listed, 2)
+			for _, user := range listed {
+				assert.NotEqual(t, systemID, user.ID)
+			}
+			count, err := repo.Count(ctx, systemID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), count)
+			found, total, err := repo.Search(ctx, repositories.UserSearch{Query: "system"}, 10, 0)
+			require.NoError(t, err)
+			assert.Empty(t, found)
+			assert.Zero(t, total)
+
+			_, err = repo.GetByID(ctx, systemID, systemID)
+			assert.NoError(t, err, "the system user can still be looked up by ID")
+		})
+	}
+}
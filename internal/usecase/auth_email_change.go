@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/validators"
+	"clean-architecture-api/pkg/mail"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+var emailChangeTemplate = mail.MustTemplate("email-change", "Confirm your new email address",
+	"Use this code to confirm your new email address: {{.Token}}\n\nIt expires at {{.ExpiresAt}}. "+
+		"If you did not ask for this change, you can ignore this message.")
+
+// RequestEmailChange moves userID to newEmail. With EMAIL_VERIFICATION_ENABLED the address is held
+// as pending and a confirmation token is mailed to it; the current email keeps working until
+// ConfirmEmailChange. Otherwise the change applies immediately.
+func (uc *authUseCase) RequestEmailChange(ctx context.Context, userID uuid.UUID, newEmail string) (*entities.User, error) {
+	if err := validators.ValidateEmail(newEmail); err != nil {
+		return nil, err
+	}
+
+	systemUserID := constants.SystemUserID()
+	user, err := uc.userRepo.GetByID(ctx, userID, systemUserID)
+	if err != nil {
+		return nil, domainerrors.ErrUserNotFound
+	}
+	if user.Email == newEmail {
+		return nil, domainerrors.ErrEmailUnchanged
+	}
+	if err := uc.checkEmailAvailable(ctx, newEmail); err != nil {
+		return nil, err
+	}
+
+	if !uc.verifyEmailChanges || uc.mailer == nil {
+		user.Email = newEmail
+		clearPendingEmail(user)
+		if err := uc.saveEmailChange(ctx, user); err != nil {
+			return nil, err
+		}
+		uc.logger.Info("User changed email", user.ID.String())
+		return user, nil
+	}
+
+	token, err := newEmailChangeToken()
+	if err != nil {
+		return nil, domainerrors.ErrFailedToUpdateUser
+	}
+	expiresAt := time.Now().Add(constants.EmailChangeTokenLifetime)
+	user.PendingEmail = &newEmail
+	user.EmailChangeTokenHash = hashEmailChangeToken(token)
+	user.EmailChangeExpiresAt = &expiresAt
+	if err := uc.saveEmailChange(ctx, user); err != nil {
+		return nil, err
+	}
+
+	err = emailChangeTemplate.Send(ctx, uc.mailer, newEmail, map[string]string{
+		"Token":     token,
+		"ExpiresAt": expiresAt.UTC().Format(time.RFC1123),
+	})
+	if err != nil {
+		uc.logger.Error("Failed to send email change confirmation", err)
+		return nil, domainerrors.ErrFailedToSendEmail
+	}
+
+	uc.logger.Info("Email change requested", user.ID.String())
+	return user, nil
+}
+
+// ConfirmEmailChange commits the pending email once the token mailed to it is presented. The
+// address is checked for uniqueness again, since it may have been taken in the meantime.
+func (uc *authUseCase) ConfirmEmailChange(ctx context.Context, userID uuid.UUID, token string) (*entities.User, error) {
+	systemUserID := constants.SystemUserID()
+	user, err := uc.userRepo.GetByID(ctx, userID, systemUserID)
+	if err != nil {
+		return nil, domainerrors.ErrUserNotFound
+	}
+
+	if user.PendingEmail == nil || user.EmailChangeExpiresAt == nil || time.Now().After(*user.EmailChangeExpiresAt) ||
+		subtle.ConstantTimeCompare([]byte(hashEmailChangeToken(token)), []byte(user.EmailChangeTokenHash)) != 1 {
+		return nil, domainerrors.ErrInvalidEmailChange
+	}
+	if err := uc.checkEmailAvailable(ctx, *user.PendingEmail); err != nil {
+		return nil, err
+	}
+
+	user.Email = *user.PendingEmail
+	clearPendingEmail(user)
+	if err := uc.saveEmailChange(ctx, user); err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("User confirmed email change", user.ID.String())
+	return user, nil
+}
+
+func (uc *authUseCase) checkEmailAvailable(ctx context.Context, email string) error {
+	existingUser, err := uc.userRepo.GetByEmail(ctx, email)
+	if err == nil && existingUser != nil {
+		return domainerrors.ErrEmailAlreadyInUse
+	}
+	return nil
+}
+
+func (uc *authUseCase) saveEmailChange(ctx context.Context, user *entities.User) error {
+	systemUserID := constants.SystemUserID()
+	systemCtx := uc.systemContext(ctx)
+
+	err := uc.PersistAndPublish(systemCtx, constants.EventUserUpdated, user, func(ctx context.Context) error {
+		return uc.userRepo.Update(ctx, user, systemUserID)
+	})
+	if err != nil {
+		uc.logger.Error("Failed to save email change", err)
+		return domainerrors.ErrFailedToUpdateUser
+	}
+	return nil
+}
+
+func clearPendingEmail(user *entities.User) {
+	user.PendingEmail = nil
+	user.EmailChangeTokenHash = ""
+	user.EmailChangeExpiresAt = nil
+}
+
+func newEmailChangeToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hashEmailChangeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
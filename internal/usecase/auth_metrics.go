@@ -0,0 +1,63 @@
+package usecase
+
+import "clean-architecture-api/pkg/metrics"
+
+// Reasons used as the "reason" label on the auth failure counters
+const (
+	authReasonInvalidRequest     = "invalid_request"
+	authReasonInvalidCredentials = "invalid_credentials"
+	authReasonDeactivated        = "deactivated"
+	authReasonPendingApproval    = "pending_approval"
+	authReasonNotFound           = "not_found"
+	authReasonInvalidToken       = "invalid_token"
+	authReasonTokenReused        = "token_reused"
+	authReasonTokenRevoked       = "token_revoked"
+	authReasonInternalError      = "internal_error"
+	authReasonChallengeFailed    = "challenge_failed"
+)
+
+// authMetrics counts authentication outcomes for the security dashboard. A nil *authMetrics
+// records nothing, so use cases built without one keep working.
+type authMetrics struct {
+	logins             *metrics.CounterVec
+	loginFailures      *metrics.CounterVec
+	refreshes          *metrics.CounterVec
+	validationFailures *metrics.CounterVec
+}
+
+func newAuthMetrics(registry *metrics.Registry) *authMetrics {
+	return &authMetrics{
+		logins: registry.NewCounterVec("auth_logins_total",
+			"Successful logins."),
+		loginFailures: registry.NewCounterVec("auth_login_failures_total",
+			"Rejected logins by reason.", "reason"),
+		refreshes: registry.NewCounterVec("auth_token_refreshes_total",
+			"Refresh tokens exchanged for a new token pair."),
+		validationFailures: registry.NewCounterVec("auth_token_validation_failures_total",
+			"Access or refresh tokens rejected, by reason.", "reason"),
+	}
+}
+
+func (m *authMetrics) loginSucceeded() {
+	if m != nil {
+		m.logins.Inc()
+	}
+}
+
+func (m *authMetrics) loginFailed(reason string) {
+	if m != nil {
+		m.loginFailures.Inc(reason)
+	}
+}
+
+func (m *authMetrics) tokenRefreshed() {
+	if m != nil {
+		m.refreshes.Inc()
+	}
+}
+
+func (m *authMetrics) tokenRejected(reason string) {
+	if m != nil {
+		m.validationFailures.Inc(reason)
+	}
+}
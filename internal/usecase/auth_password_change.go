@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/validators"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"context"
+	"time"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ChangePassword replaces userID's password once the current one checks out. Every token issued
+// before the change is revoked, so sessions stolen before a compromise-driven change stop working;
+// the caller gets a fresh token pair to stay signed in.
+func (uc *authUseCase) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) (*auth.TokenPair, error) {
+	if err := validators.ValidatePassword(newPassword); err != nil {
+		return nil, err
+	}
+
+	systemUserID := constants.SystemUserID()
+	user, err := uc.userRepo.GetByID(ctx, userID, systemUserID)
+	if err != nil {
+		return nil, domainerrors.ErrUserNotFound
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return nil, domainerrors.ErrInvalidCredentials
+	}
+
+	hashedPassword, err := uc.hashPassword(newPassword)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = hashedPassword
+	user.RevokeTokens(time.Now())
+
+	systemCtx := uc.systemContext(ctx)
+	err = uc.PersistAndPublish(systemCtx, constants.EventUserUpdated, user, func(ctx context.Context) error {
+		return uc.userRepo.Update(ctx, user, systemUserID)
+	})
+	if err != nil {
+		uc.logger.Error("Failed to save password change", err)
+		return nil, domainerrors.ErrFailedToUpdateUser
+	}
+
+	tokenPair, err := uc.authService.GenerateTokenPair(user.ID, user.Email, user.Role, false)
+	if err != nil {
+		return nil, domainerrors.ErrFailedToGenerateTokens
+	}
+	if err := uc.storeRefreshToken(ctx, user.ID, tokenPair, nil); err != nil {
+		return nil, domainerrors.ErrFailedToGenerateTokens
+	}
+
+	uc.logger.Info("User changed password", user.ID.String())
+	return tokenPair, nil
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"context"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// ApproveUser activates a registration that is waiting for approval, after which the user can
+// log in. Approving a user that is not pending fails, so an approval never reactivates an account
+// an admin deactivated on purpose.
+func (uc *authUseCase) ApproveUser(ctx context.Context, targetID, adminID uuid.UUID) (*entities.User, error) {
+	systemUserID := constants.SystemUserID()
+	target, err := uc.userRepo.GetByID(ctx, targetID, systemUserID)
+	if err != nil {
+		return nil, domainerrors.ErrUserNotFound
+	}
+	if !target.ApprovalPending {
+		return nil, domainerrors.ErrUserNotPendingApproval
+	}
+	target.ApprovalPending = false
+	target.IsActive = true
+
+	systemCtx := uc.systemContext(ctx)
+	err = uc.PersistAndPublish(systemCtx, constants.EventUserApproved, target, func(ctx context.Context) error {
+		return uc.userRepo.Update(ctx, target, systemUserID)
+	})
+	if err != nil {
+		uc.logger.Error("Failed to approve user", err)
+		return nil, domainerrors.ErrFailedToUpdateUser
+	}
+
+	err = uc.auditLogger.LogDataAccess(ctx, adminID, constants.ActionApprove, constants.ResourceUser, map[string]interface{}{
+		"target_user_id": target.ID.String(),
+	})
+	if err != nil {
+		uc.logger.Error("Failed to audit user approval", err)
+	}
+	uc.logger.Info("Admin "+adminID.String()+" approved user", target.ID.String())
+
+	return target, nil
+}
+
+// ListPendingUsers returns the registrations waiting for approval, oldest first
+func (uc *authUseCase) ListPendingUsers(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	limit, offset = ClampPagination(limit, offset)
+	users, err := uc.userRepo.ListPendingApproval(ctx, limit, offset)
+	if err != nil {
+		uc.logger.Error("Failed to list pending users", err)
+		return nil, domainerrors.ErrFailedToListUsers
+	}
+	return users, nil
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/infrastructure/auth"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// maxSessionUserAgentLength matches the size of refresh_tokens.user_agent
+const maxSessionUserAgentLength = 512
+
+// Logout ends the session the refresh token belongs to. Access tokens already issued stay valid
+// until they expire. Logging out of a session that has already ended succeeds.
+func (uc *authUseCase) Logout(ctx context.Context, refreshToken string) error {
+	stored, _, err := uc.lookupRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+	if stored.RevokedAt != nil {
+		return nil
+	}
+
+	if err := uc.refreshTokens.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return uc.HandleDatabaseError(err, "UPDATE", "REFRESH_TOKEN")
+	}
+	uc.logger.Info("User logged out", stored.UserID.String())
+	return nil
+}
+
+// ListSessions returns the user's sessions that can still be refreshed, most recently refreshed
+// first. Sessions cut off by RevokeUserTokens or a password change are left out.
+func (uc *authUseCase) ListSessions(ctx context.Context, userID uuid.UUID) ([]*entities.Session, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID, constants.SystemUserID())
+	if err != nil {
+		return nil, domainerrors.ErrUserNotFound
+	}
+
+	tokens, err := uc.refreshTokens.ListActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, uc.HandleDatabaseError(err, "SELECT", "REFRESH_TOKEN")
+	}
+
+	sessions := make([]*entities.Session, 0, len(tokens))
+	for _, token := range tokens {
+		if user.TokenRevoked(token.CreatedAt) {
+			continue
+		}
+		sessions = append(sessions, token.Session())
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends one of the user's sessions and leaves the others signed in
+func (uc *authUseCase) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	revoked, err := uc.refreshTokens.RevokeUserFamily(ctx, userID, sessionID)
+	if err != nil {
+		return uc.HandleDatabaseError(err, "UPDATE", "REFRESH_TOKEN")
+	}
+	if !revoked {
+		return domainerrors.ErrSessionNotFound
+	}
+	uc.logger.Info("User revoked session "+sessionID.String(), userID.String())
+	return nil
+}
+
+// lookupRefreshToken verifies a refresh token and finds its stored record, whether or not it
+// has been used or revoked since
+func (uc *authUseCase) lookupRefreshToken(ctx context.Context, refreshToken string) (*entities.RefreshToken, *auth.Claims, error) {
+	claims, err := uc.authService.ValidateRefreshToken(refreshToken)
+	if err != nil || claims.ImpersonatedBy != nil {
+		return nil, nil, domainerrors.ErrInvalidToken
+	}
+
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, nil, domainerrors.ErrInvalidToken
+	}
+	stored, err := uc.refreshTokens.GetByID(ctx, tokenID)
+	if err != nil || stored.UserID != claims.UserID || !refreshTokenMatches(stored, refreshToken) {
+		return nil, nil, domainerrors.ErrInvalidToken
+	}
+	return stored, claims, nil
+}
+
+// refreshTokenMatches compares the presented token with the stored hash. Tokens stored before
+// hashes were recorded have none and are matched by their jti alone.
+func refreshTokenMatches(stored *entities.RefreshToken, token string) bool {
+	if stored.TokenHash == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(stored.TokenHash), []byte(hashRefreshToken(token))) == 1
+}
+
+// truncateUserAgent keeps user agents within the stored column size
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) > maxSessionUserAgentLength {
+		return strings.ToValidUTF8(userAgent[:maxSessionUserAgentLength], "")
+	}
+	return userAgent
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"context"
+	"time"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// RevokeUserTokens signs targetID out everywhere: every access and refresh token issued before now
+// is rejected, so the user has to log in again. The revocation stands even if the audit entry
+// cannot be written, since it is the safe side to fail on.
+func (uc *authUseCase) RevokeUserTokens(ctx context.Context, targetID, adminID uuid.UUID) error {
+	systemUserID := constants.SystemUserID()
+	target, err := uc.userRepo.GetByID(ctx, targetID, systemUserID)
+	if err != nil {
+		return domainerrors.ErrUserNotFound
+	}
+	target.RevokeTokens(time.Now())
+
+	systemCtx := uc.systemContext(ctx)
+	err = uc.PersistAndPublish(systemCtx, constants.EventUserUpdated, target, func(ctx context.Context) error {
+		return uc.userRepo.Update(ctx, target, systemUserID)
+	})
+	if err != nil {
+		uc.logger.Error("Failed to revoke user tokens", err)
+		return domainerrors.ErrFailedToUpdateUser
+	}
+
+	err = uc.auditLogger.LogDataAccess(ctx, adminID, constants.ActionRevokeTokens, constants.ResourceUser, map[string]interface{}{
+		"target_user_id":     target.ID.String(),
+		"tokens_valid_after": target.TokensValidAfter,
+	})
+	if err != nil {
+		uc.logger.Error("Failed to audit token revocation", err)
+	}
+	uc.logger.Warn("Admin "+adminID.String()+" revoked all tokens of user", target.ID.String())
+
+	return nil
+}
@@ -1,17 +1,33 @@
 package usecase
 
 import (
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/internal/infrastructure/auth"
+	"clean-architecture-api/internal/infrastructure/database"
+	"clean-architecture-api/internal/infrastructure/repository"
 	"clean-architecture-api/pkg/logger"
+	"clean-architecture-api/pkg/metrics"
 	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	domainerrors "clean-architecture-api/internal/domain/errors"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 type MockUserRepository struct {
@@ -39,6 +55,35 @@ func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*ent
 	return args.Get(0).(*entities.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByEmailIncludingDeleted(ctx context.Context, email string) ([]*entities.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	args := m.Called(ctx, role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) ListPendingApproval(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Search(ctx context.Context, search repositories.UserSearch, limit, offset int) ([]*entities.User, int64, error) {
+	args := m.Called(ctx, search, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*entities.User), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockUserRepository) GetAll(ctx context.Context) ([]*entities.User, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -47,6 +92,19 @@ func (m *MockUserRepository) GetAll(ctx context.Context) ([]*entities.User, erro
 	return args.Get(0).([]*entities.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByIDs(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) ([]*entities.User, error) {
+	args := m.Called(ctx, ids, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.User), args.Error(1)
+}
+
+func (m *MockUserRepository) HealthCheck(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Update(ctx context.Context, user *entities.User, userID uuid.UUID) error {
 	args := m.Called(ctx, user, userID)
 	return args.Error(0)
@@ -65,6 +123,11 @@ func (m *MockUserRepository) List(ctx context.Context, limit, offset int, userID
 	return args.Get(0).([]*entities.User), args.Error(1)
 }
 
+func (m *MockUserRepository) Count(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockUserRepository) ValidateAccess(ctx context.Context, userID uuid.UUID, action string) error {
 	args := m.Called(ctx, userID, action)
 	return args.Error(0)
@@ -79,8 +142,16 @@ type MockAuthService struct {
 	mock.Mock
 }
 
-func (m *MockAuthService) GenerateTokenPair(userID uuid.UUID, email, role string) (*auth.TokenPair, error) {
-	args := m.Called(userID, email, role)
+func (m *MockAuthService) GenerateTokenPair(userID uuid.UUID, email, role string, rememberMe bool) (*auth.TokenPair, error) {
+	args := m.Called(userID, email, role, rememberMe)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthService) GenerateImpersonationToken(userID uuid.UUID, email, role string, impersonatorID uuid.UUID) (*auth.TokenPair, error) {
+	args := m.Called(userID, email, role, impersonatorID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -95,6 +166,14 @@ func (m *MockAuthService) ValidateToken(tokenString string) (*auth.Claims, error
 	return args.Get(0).(*auth.Claims), args.Error(1)
 }
 
+func (m *MockAuthService) ValidateRefreshToken(tokenString string) (*auth.Claims, error) {
+	args := m.Called(tokenString)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth.Claims), args.Error(1)
+}
+
 func (m *MockAuthService) RefreshTokenPair(refreshToken string) (*auth.TokenPair, error) {
 	args := m.Called(refreshToken)
 	if args.Get(0) == nil {
@@ -103,6 +182,101 @@ func (m *MockAuthService) RefreshTokenPair(refreshToken string) (*auth.TokenPair
 	return args.Get(0).(*auth.TokenPair), args.Error(1)
 }
 
+type MockAuditLogger struct {
+	mock.Mock
+}
+
+func (m *MockAuditLogger) LogAccess(ctx context.Context, userID uuid.UUID, action, resource string, entityID uuid.UUID) error {
+	args := m.Called(ctx, userID, action, resource, entityID)
+	return args.Error(0)
+}
+
+func (m *MockAuditLogger) LogDataAccess(ctx context.Context, userID uuid.UUID, action, resource string, data interface{}) error {
+	args := m.Called(ctx, userID, action, resource, data)
+	return args.Error(0)
+}
+
+// fakeRefreshTokenRepository keeps refresh tokens in memory with the same used/revoked rules as the database
+type fakeRefreshTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[uuid.UUID]*entities.RefreshToken
+}
+
+func newFakeRefreshTokenRepository() *fakeRefreshTokenRepository {
+	return &fakeRefreshTokenRepository{tokens: make(map[uuid.UUID]*entities.RefreshToken)}
+}
+
+func (r *fakeRefreshTokenRepository) Create(ctx context.Context, token *entities.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *token
+	r.tokens[token.ID] = &stored
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[id]
+	if !ok {
+		return nil, domainerrors.ErrInvalidToken
+	}
+	stored := *token
+	return &stored, nil
+}
+
+func (r *fakeRefreshTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[id]
+	if !ok || token.UsedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	return true, nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]*entities.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var active []*entities.RefreshToken
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.UsedAt == nil && token.RevokedAt == nil && token.ExpiresAt.After(time.Now()) {
+			stored := *token
+			active = append(active, &stored)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].CreatedAt.After(active[j].CreatedAt) })
+	return active, nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeUserFamily(ctx context.Context, userID, familyID uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	revoked := false
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			revoked = true
+		}
+	}
+	return revoked, nil
+}
+
 type MockLogger struct {
 	mock.Mock
 }
@@ -150,9 +324,11 @@ func setupAuthUseCaseTest() (*authUseCase, *MockUserRepository, *MockAuthService
 	mockLogger := &MockLogger{}
 
 	authUC := &authUseCase{
-		BaseUseCase: *NewBaseUseCase(mockLogger),
-		userRepo:    mockUserRepo,
-		authService: mockAuthService,
+		BaseUseCase:   *NewBaseUseCase(mockLogger),
+		userRepo:      mockUserRepo,
+		refreshTokens: newFakeRefreshTokenRepository(),
+		authService:   mockAuthService,
+		bcryptCost:    bcrypt.MinCost,
 	}
 
 	return authUC, mockUserRepo, mockAuthService, mockLogger
@@ -235,6 +411,20 @@ func getRegisterValidationTestCases() []RegisterTestCase {
 			},
 			expectedError: domainerrors.ErrFailedToCreateUser,
 		},
+		{
+			name:      "Failure - Email registered concurrently after the existence check",
+			email:     "test@example.com",
+			password:  "password123",
+			firstName: "John",
+			lastName:  "Doe",
+			setupMocks: func(mockRepo *MockUserRepository, mockAuth *MockAuthService, mockLogger *MockLogger) {
+				mockRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(nil, domainerrors.ErrUserNotFound)
+				// What the user repository returns for a unique violation on email
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*entities.User"), mock.AnythingOfType("uuid.UUID")).Return(domainerrors.ErrUserAlreadyExists)
+				mockLogger.On("Warn", mock.Anything, mock.Anything).Return()
+			},
+			expectedError: domainerrors.ErrUserAlreadyExists,
+		},
 	}
 }
 
@@ -373,7 +563,7 @@ func getLoginSuccessTestCase(validUser *entities.User, validTokenPair *auth.Toke
 		password: "password123",
 		setupMocks: func(mockRepo *MockUserRepository, mockAuth *MockAuthService, mockLogger *MockLogger) {
 			mockRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(validUser, nil)
-			mockAuth.On("GenerateTokenPair", validUserID, "test@example.com", "user").Return(validTokenPair, nil)
+			mockAuth.On("GenerateTokenPair", validUserID, "test@example.com", "user", false).Return(validTokenPair, nil)
 			mockLogger.On("Info", mock.Anything, mock.Anything).Return()
 		},
 		expectedToken: validTokenPair,
@@ -439,7 +629,7 @@ func getTokenGenerationErrorTestCase(validUser *entities.User, validUserID uuid.
 		password: "password123",
 		setupMocks: func(mockRepo *MockUserRepository, mockAuth *MockAuthService, mockLogger *MockLogger) {
 			mockRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(validUser, nil)
-			mockAuth.On("GenerateTokenPair", validUserID, "test@example.com", "user").Return(nil, domainerrors.ErrFailedToGenerateTokens)
+			mockAuth.On("GenerateTokenPair", validUserID, "test@example.com", "user", false).Return(nil, domainerrors.ErrFailedToGenerateTokens)
 			mockLogger.On("Error", mock.Anything, mock.Anything).Return()
 		},
 		expectedToken: nil,
@@ -470,7 +660,7 @@ func runLoginTest(t *testing.T, tt LoginTestCase) {
 	tt.setupMocks(mockRepo, mockAuth, mockLogger)
 
 	ctx := context.Background()
-	tokenPair, err := authUC.Login(ctx, tt.email, tt.password)
+	tokenPair, err := authUC.Login(ctx, tt.email, tt.password, false)
 
 	if tt.expectedError != nil {
 		assert.Error(t, err)
@@ -497,3 +687,556 @@ func TestAuthUseCase_Login(t *testing.T) {
 		})
 	}
 }
+
+// setupRefreshTokenTest signs real tokens so each refresh token carries its own jti
+func setupRefreshTokenTest(t *testing.T) (*authUseCase, *fakeRefreshTokenRepository, *entities.User) {
+	keys, err := auth.LoadKeySet(auth.KeySetConfig{SecretKey: "test-secret"})
+	if err != nil {
+		t.Fatalf("Failed to create auth service: %v", err)
+	}
+	authService := auth.NewAuthServiceWithKeys(keys, time.Minute)
+
+	hashedPassword, err := NewTestHelper().HashPassword("password123")
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+	user := &entities.User{
+		BaseEntity: entities.BaseEntity{ID: uuid.New()},
+		Email:      "test@example.com",
+		Password:   hashedPassword,
+		Role:       "user",
+		IsActive:   true,
+	}
+
+	mockRepo := &MockUserRepository{}
+	mockRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	mockRepo.On("GetByID", mock.Anything, user.ID, mock.Anything).Return(user, nil)
+	mockLogger := &MockLogger{}
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warn", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return()
+
+	refreshTokens := newFakeRefreshTokenRepository()
+	authUC := &authUseCase{
+		BaseUseCase:   *NewBaseUseCase(mockLogger),
+		userRepo:      mockRepo,
+		refreshTokens: refreshTokens,
+		authService:   authService,
+		bcryptCost:    bcrypt.MinCost,
+	}
+	return authUC, refreshTokens, user
+}
+
+func TestAuthUseCase_RefreshToken_Rotates(t *testing.T) {
+	authUC, refreshTokens, user := setupRefreshTokenTest(t)
+	ctx := context.Background()
+
+	first, err := authUC.Login(ctx, user.Email, "password123", false)
+	assert.NoError(t, err)
+
+	second, err := authUC.RefreshToken(ctx, first.RefreshToken)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	parent, _ := refreshTokens.GetByID(ctx, first.RefreshTokenID)
+	child, _ := refreshTokens.GetByID(ctx, second.RefreshTokenID)
+	assert.NotNil(t, parent.UsedAt)
+	assert.Equal(t, parent.FamilyID, child.FamilyID)
+	assert.Equal(t, &parent.ID, child.ParentID)
+	assert.Nil(t, child.UsedAt)
+
+	third, err := authUC.RefreshToken(ctx, second.RefreshToken)
+	assert.NoError(t, err)
+	assert.NotNil(t, third)
+}
+
+func TestAuthUseCase_Login_RememberMeExtendsRefreshToken(t *testing.T) {
+	authUC, _, user := setupRefreshTokenTest(t)
+	ctx := context.Background()
+
+	short, err := authUC.Login(ctx, user.Email, "password123", false)
+	assert.NoError(t, err)
+	long, err := authUC.Login(ctx, user.Email, "password123", true)
+	assert.NoError(t, err)
+
+	day := 24 * time.Hour
+	assert.WithinDuration(t, time.Now().Add(constants.JWTRefreshTokenDuration*day), short.RefreshExpiresAt, time.Minute)
+	assert.WithinDuration(t, time.Now().Add(constants.JWTRememberMeRefreshTokenDuration*day), long.RefreshExpiresAt, time.Minute)
+	// The access token lifetime does not change
+	assert.InDelta(t, short.ExpiresIn, long.ExpiresIn, 60)
+
+	// Rotation keeps the lifetime the session started with
+	rotated, err := authUC.RefreshToken(ctx, long.RefreshToken)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, long.RefreshExpiresAt, rotated.RefreshExpiresAt, time.Minute)
+}
+
+func TestAuthUseCase_RefreshToken_ReuseRevokesFamily(t *testing.T) {
+	authUC, refreshTokens, user := setupRefreshTokenTest(t)
+	ctx := context.Background()
+
+	first, err := authUC.Login(ctx, user.Email, "password123", false)
+	assert.NoError(t, err)
+	second, err := authUC.RefreshToken(ctx, first.RefreshToken)
+	assert.NoError(t, err)
+
+	// Replaying the first token revokes every token in its family, including the current one
+	_, err = authUC.RefreshToken(ctx, first.RefreshToken)
+	assert.ErrorIs(t, err, domainerrors.ErrTokenReused)
+
+	child, _ := refreshTokens.GetByID(ctx, second.RefreshTokenID)
+	assert.NotNil(t, child.RevokedAt)
+
+	_, err = authUC.RefreshToken(ctx, second.RefreshToken)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+
+	// Other logins are separate families and keep working
+	other, err := authUC.Login(ctx, user.Email, "password123", false)
+	assert.NoError(t, err)
+	_, err = authUC.RefreshToken(ctx, other.RefreshToken)
+	assert.NoError(t, err)
+}
+
+func TestAuthUseCase_RevokeSession_KeepsOtherSessions(t *testing.T) {
+	authUC, refreshTokens, user := setupRefreshTokenTest(t)
+	ctx := context.Background()
+
+	laptop, err := authUC.Login(ctx, user.Email, "password123", false)
+	require.NoError(t, err)
+	phoneCtx := constants.WithClientInfo(ctx, constants.ClientInfo{IP: "198.51.100.7", UserAgent: "Phone/1.0"})
+	phone, err := authUC.Login(phoneCtx, user.Email, "password123", false)
+	require.NoError(t, err)
+	// Rotating keeps the session, so it is still listed once with the client it started on
+	phone, err = authUC.RefreshToken(ctx, phone.RefreshToken)
+	require.NoError(t, err)
+
+	sessions, err := authUC.ListSessions(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	var phoneSession *entities.Session
+	for _, session := range sessions {
+		if session.UserAgent == "Phone/1.0" {
+			phoneSession = session
+		}
+	}
+	require.NotNil(t, phoneSession)
+	assert.Equal(t, "198.51.100.7", phoneSession.IPAddress)
+	assert.False(t, phoneSession.CreatedAt.IsZero())
+	laptopToken, _ := refreshTokens.GetByID(ctx, laptop.RefreshTokenID)
+	assert.Equal(t, hashRefreshToken(laptop.RefreshToken), laptopToken.TokenHash)
+
+	require.NoError(t, authUC.RevokeSession(ctx, user.ID, laptopToken.FamilyID))
+	_, err = authUC.RefreshToken(ctx, laptop.RefreshToken)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+
+	sessions, err = authUC.ListSessions(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.NotEqual(t, laptopToken.FamilyID, sessions[0].ID)
+	_, err = authUC.RefreshToken(ctx, phone.RefreshToken)
+	assert.NoError(t, err)
+
+	// Revoking again, or revoking another user's session, finds nothing
+	assert.ErrorIs(t, authUC.RevokeSession(ctx, user.ID, laptopToken.FamilyID), domainerrors.ErrSessionNotFound)
+	assert.ErrorIs(t, authUC.RevokeSession(ctx, uuid.New(), sessions[0].ID), domainerrors.ErrSessionNotFound)
+}
+
+func TestAuthUseCase_Logout(t *testing.T) {
+	authUC, _, user := setupRefreshTokenTest(t)
+	ctx := context.Background()
+
+	pair, err := authUC.Login(ctx, user.Email, "password123", false)
+	require.NoError(t, err)
+	other, err := authUC.Login(ctx, user.Email, "password123", false)
+	require.NoError(t, err)
+
+	require.NoError(t, authUC.Logout(ctx, pair.RefreshToken))
+	_, err = authUC.RefreshToken(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+	assert.NoError(t, authUC.Logout(ctx, pair.RefreshToken), "logging out twice succeeds")
+
+	assert.ErrorIs(t, authUC.Logout(ctx, pair.AccessToken), domainerrors.ErrInvalidToken)
+
+	_, err = authUC.RefreshToken(ctx, other.RefreshToken)
+	assert.NoError(t, err)
+}
+
+func TestAuthUseCase_RefreshToken_RejectsAccessToken(t *testing.T) {
+	authUC, _, user := setupRefreshTokenTest(t)
+	ctx := context.Background()
+
+	pair, err := authUC.Login(ctx, user.Email, "password123", false)
+	assert.NoError(t, err)
+
+	_, err = authUC.RefreshToken(ctx, pair.AccessToken)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+}
+
+func TestAuthUseCase_CountsOutcomes(t *testing.T) {
+	authUC, _, user := setupRefreshTokenTest(t)
+	registry := metrics.NewRegistry()
+	authUC.metrics = newAuthMetrics(registry)
+	ctx := context.Background()
+
+	_, err := authUC.Login(ctx, user.Email, "wrong-password", false)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+	pair, err := authUC.Login(ctx, user.Email, "password123", false)
+	assert.NoError(t, err)
+	_, err = authUC.RefreshToken(ctx, pair.RefreshToken)
+	assert.NoError(t, err)
+	_, err = authUC.RefreshToken(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, domainerrors.ErrTokenReused)
+	_, err = authUC.ValidateToken(ctx, "not-a-token")
+	assert.Error(t, err)
+
+	m := authUC.metrics
+	assert.Equal(t, 1.0, m.logins.Value())
+	assert.Equal(t, 1.0, m.loginFailures.Value(authReasonInvalidCredentials))
+	assert.Equal(t, 0.0, m.loginFailures.Value(authReasonDeactivated))
+	assert.Equal(t, 1.0, m.refreshes.Value())
+	assert.Equal(t, 1.0, m.validationFailures.Value(authReasonTokenReused))
+	assert.Equal(t, 1.0, m.validationFailures.Value(authReasonInvalidToken))
+
+	var scrape strings.Builder
+	assert.NoError(t, registry.WriteText(&scrape))
+	assert.Contains(t, scrape.String(), "# TYPE auth_login_failures_total counter\n")
+	assert.Contains(t, scrape.String(), `auth_login_failures_total{reason="invalid_credentials"} 1`)
+}
+
+type fakeChallengeVerifier struct {
+	accepted string
+}
+
+func (v fakeChallengeVerifier) Verify(_ context.Context, response, _ string) error {
+	if response != v.accepted {
+		return errors.New("challenge rejected")
+	}
+	return nil
+}
+
+func TestAuthUseCase_ChallengeGatesLoginAndRegister(t *testing.T) {
+	authUC, _, user := setupRefreshTokenTest(t)
+	authUC.challenge = fakeChallengeVerifier{accepted: "human"}
+	ctx := context.Background()
+
+	// The user repository mock has no Create expectation, so reaching it would panic
+	_, err := authUC.Register(ctx, "new@example.com", "password123", "New", "User")
+	assert.ErrorIs(t, err, domainerrors.ErrChallengeFailed)
+
+	_, err = authUC.Login(constants.WithChallenge(ctx, constants.Challenge{Response: "bot"}), user.Email, "password123", false)
+	assert.ErrorIs(t, err, domainerrors.ErrChallengeFailed)
+
+	pair, err := authUC.Login(constants.WithChallenge(ctx, constants.Challenge{Response: "human"}), user.Email, "password123", false)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pair.AccessToken)
+}
+
+func setupImpersonationTest(t *testing.T) (*authUseCase, *MockUserRepository, *MockAuditLogger) {
+	keys, err := auth.LoadKeySet(auth.KeySetConfig{SecretKey: "test-secret"})
+	if err != nil {
+		t.Fatalf("Failed to create auth service: %v", err)
+	}
+	authService := auth.NewAuthServiceWithKeys(keys, time.Minute)
+
+	mockRepo := &MockUserRepository{}
+	mockAudit := &MockAuditLogger{}
+	mockLogger := &MockLogger{}
+	mockLogger.On("Warn", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return()
+
+	authUC := &authUseCase{
+		BaseUseCase:   *NewBaseUseCase(mockLogger),
+		userRepo:      mockRepo,
+		refreshTokens: newFakeRefreshTokenRepository(),
+		authService:   authService,
+		auditLogger:   mockAudit,
+		bcryptCost:    bcrypt.MinCost,
+	}
+	return authUC, mockRepo, mockAudit
+}
+
+func TestAuthUseCase_Impersonate(t *testing.T) {
+	authUC, mockRepo, mockAudit := setupImpersonationTest(t)
+	ctx := context.Background()
+	adminID := uuid.New()
+	target := &entities.User{
+		BaseEntity: entities.BaseEntity{ID: uuid.New()},
+		Email:      "target@example.com",
+		Role:       "user",
+		IsActive:   true,
+	}
+	mockRepo.On("GetByID", mock.Anything, target.ID, mock.Anything).Return(target, nil)
+	mockAudit.On("LogDataAccess", mock.Anything, adminID, "impersonate", "user", mock.MatchedBy(func(data map[string]interface{}) bool {
+		return data["target_user_id"] == target.ID.String()
+	})).Return(nil).Once()
+
+	tokenPair, err := authUC.Impersonate(ctx, target.ID, adminID)
+	assert.NoError(t, err)
+	assert.Empty(t, tokenPair.RefreshToken)
+	mockAudit.AssertExpectations(t)
+
+	claims, err := authUC.authService.ValidateToken(tokenPair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, target.ID, claims.UserID)
+	if assert.NotNil(t, claims.ImpersonatedBy) {
+		assert.Equal(t, adminID, *claims.ImpersonatedBy)
+	}
+	assert.WithinDuration(t, time.Now().Add(auth.ImpersonationTokenLifetime), claims.ExpiresAt.Time, 5*time.Second)
+
+	// The impersonation token cannot be exchanged for a longer session
+	_, err = authUC.RefreshToken(ctx, tokenPair.AccessToken)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+}
+
+func TestAuthUseCase_Impersonate_Refused(t *testing.T) {
+	authUC, mockRepo, mockAudit := setupImpersonationTest(t)
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	otherAdmin := &entities.User{BaseEntity: entities.BaseEntity{ID: uuid.New()}, Role: "admin", IsActive: true}
+	inactive := &entities.User{BaseEntity: entities.BaseEntity{ID: uuid.New()}, Role: "user", IsActive: false}
+	mockRepo.On("GetByID", mock.Anything, otherAdmin.ID, mock.Anything).Return(otherAdmin, nil)
+	mockRepo.On("GetByID", mock.Anything, inactive.ID, mock.Anything).Return(inactive, nil)
+
+	for name, targetID := range map[string]uuid.UUID{"self": adminID, "admin": otherAdmin.ID, "inactive": inactive.ID} {
+		t.Run(name, func(t *testing.T) {
+			_, err := authUC.Impersonate(ctx, targetID, adminID)
+			assert.ErrorIs(t, err, domainerrors.ErrCannotImpersonate)
+		})
+	}
+
+	// Without an audit entry no token is handed out
+	target := &entities.User{BaseEntity: entities.BaseEntity{ID: uuid.New()}, Role: "user", IsActive: true}
+	mockRepo.On("GetByID", mock.Anything, target.ID, mock.Anything).Return(target, nil)
+	mockAudit.On("LogDataAccess", mock.Anything, adminID, mock.Anything, mock.Anything, mock.Anything).Return(assert.AnError)
+	tokenPair, err := authUC.Impersonate(ctx, target.ID, adminID)
+	assert.Nil(t, tokenPair)
+	assert.ErrorIs(t, err, domainerrors.ErrFailedToGenerateTokens)
+}
+
+type fakeEmailSender struct {
+	sent map[string]string
+}
+
+func (s *fakeEmailSender) Send(_ context.Context, to, _, body string) error {
+	s.sent[to] = body
+	return nil
+}
+
+func TestAuthUseCase_EmailChange(t *testing.T) {
+	authUC, _, user := setupRefreshTokenTest(t)
+	mailer := &fakeEmailSender{sent: map[string]string{}}
+	authUC.mailer = mailer
+	authUC.verifyEmailChanges = true
+	repo := authUC.userRepo.(*MockUserRepository)
+	repo.On("GetByEmail", mock.Anything, "taken@example.com").Return(&entities.User{Email: "taken@example.com"}, nil)
+	repo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, domainerrors.ErrUserNotFound)
+	repo.On("Update", mock.Anything, user, mock.Anything).Return(nil)
+	ctx := context.Background()
+
+	_, err := authUC.RequestEmailChange(ctx, user.ID, "not-an-email")
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidEmail)
+	_, err = authUC.RequestEmailChange(ctx, user.ID, "taken@example.com")
+	assert.ErrorIs(t, err, domainerrors.ErrEmailAlreadyInUse)
+	_, err = authUC.RequestEmailChange(ctx, user.ID, user.Email)
+	assert.ErrorIs(t, err, domainerrors.ErrEmailUnchanged)
+
+	pending, err := authUC.RequestEmailChange(ctx, user.ID, "new@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", pending.Email)
+	assert.Equal(t, "new@example.com", *pending.PendingEmail)
+
+	// The old address keeps working until the change is confirmed
+	_, err = authUC.Login(ctx, "test@example.com", "password123", false)
+	assert.NoError(t, err)
+
+	token := regexp.MustCompile(`[0-9a-f]{64}`).FindString(mailer.sent["new@example.com"])
+	assert.NotEmpty(t, token)
+	assert.NotEqual(t, token, user.EmailChangeTokenHash)
+
+	_, err = authUC.ConfirmEmailChange(ctx, user.ID, "wrong-token")
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidEmailChange)
+
+	confirmed, err := authUC.ConfirmEmailChange(ctx, user.ID, token)
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", confirmed.Email)
+	assert.Nil(t, confirmed.PendingEmail)
+
+	// The token is single use
+	_, err = authUC.ConfirmEmailChange(ctx, user.ID, token)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidEmailChange)
+}
+
+func TestAuthUseCase_EmailChange_ExpiredTokenAndNoVerification(t *testing.T) {
+	authUC, _, user := setupRefreshTokenTest(t)
+	mailer := &fakeEmailSender{sent: map[string]string{}}
+	authUC.mailer = mailer
+	authUC.verifyEmailChanges = true
+	repo := authUC.userRepo.(*MockUserRepository)
+	repo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, domainerrors.ErrUserNotFound)
+	repo.On("Update", mock.Anything, user, mock.Anything).Return(nil)
+	ctx := context.Background()
+
+	_, err := authUC.RequestEmailChange(ctx, user.ID, "new@example.com")
+	assert.NoError(t, err)
+	expired := time.Now().Add(-time.Minute)
+	user.EmailChangeExpiresAt = &expired
+	token := regexp.MustCompile(`[0-9a-f]{64}`).FindString(mailer.sent["new@example.com"])
+	_, err = authUC.ConfirmEmailChange(ctx, user.ID, token)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidEmailChange)
+
+	// With verification off the change applies at once
+	authUC.verifyEmailChanges = false
+	changed, err := authUC.RequestEmailChange(ctx, user.ID, "new@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", changed.Email)
+	assert.Nil(t, changed.PendingEmail)
+}
+
+// waitForNextSecond moves past the current second, since JWT iat and TokensValidAfter have second
+// precision and tokens issued in the revocation's own second are kept
+func waitForNextSecond() {
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+}
+
+func TestAuthUseCase_DeactivationRevokesTokens(t *testing.T) {
+	authUC, _, user := setupRefreshTokenTest(t)
+	repo := authUC.userRepo.(*MockUserRepository)
+	repo.On("Update", mock.Anything, user, mock.Anything).Return(nil)
+	userUC := &userUseCase{BaseUseCase: authUC.BaseUseCase, userRepo: repo}
+	ctx := context.Background()
+	adminCtx := constants.WithUserRole(ctx, constants.RoleAdmin)
+
+	old, err := authUC.Login(ctx, user.Email, "password123", false)
+	assert.NoError(t, err)
+	waitForNextSecond()
+
+	update := func(active bool) {
+		err := userUC.Update(adminCtx, &entities.User{
+			BaseEntity: entities.BaseEntity{ID: user.ID}, FirstName: "Test", LastName: "User", Role: "user", IsActive: active,
+		}, uuid.New())
+		assert.NoError(t, err)
+	}
+	update(false)
+	assert.NotNil(t, user.TokensValidAfter)
+	_, err = authUC.ValidateToken(ctx, old.AccessToken)
+	assert.ErrorIs(t, err, domainerrors.ErrUserAccountIsDeactivated)
+	assert.ErrorIs(t, authUC.ValidateForwardedUser(ctx, user.ID, time.Now()), domainerrors.ErrUserAccountIsDeactivated)
+
+	// Reactivation does not revive tokens issued, or identities forwarded, before the deactivation
+	update(true)
+	_, err = authUC.ValidateToken(ctx, old.AccessToken)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+	assert.ErrorIs(t, authUC.ValidateForwardedUser(ctx, user.ID, time.Now().Add(-time.Minute)), domainerrors.ErrInvalidToken)
+	assert.NoError(t, authUC.ValidateForwardedUser(ctx, user.ID, time.Now()))
+	_, err = authUC.RefreshToken(ctx, old.RefreshToken)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+
+	fresh, err := authUC.Login(ctx, user.Email, "password123", false)
+	assert.NoError(t, err)
+	_, err = authUC.ValidateToken(ctx, fresh.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestAuthUseCase_ChangePasswordRevokesTokens(t *testing.T) {
+	authUC, _, user := setupRefreshTokenTest(t)
+	repo := authUC.userRepo.(*MockUserRepository)
+	repo.On("Update", mock.Anything, user, mock.Anything).Return(nil)
+	ctx := context.Background()
+
+	stolen, err := authUC.Login(ctx, user.Email, "password123", false)
+	assert.NoError(t, err)
+	waitForNextSecond()
+
+	_, err = authUC.ChangePassword(ctx, user.ID, "wrong-password", "new-password")
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+	_, err = authUC.ChangePassword(ctx, user.ID, "password123", "short")
+	assert.ErrorIs(t, err, domainerrors.ErrPasswordTooShort)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+
+	fresh, err := authUC.ChangePassword(ctx, user.ID, "password123", "new-password")
+	assert.NoError(t, err)
+
+	_, err = authUC.ValidateToken(ctx, stolen.AccessToken)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+	_, err = authUC.RefreshToken(ctx, stolen.RefreshToken)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+
+	_, err = authUC.ValidateToken(ctx, fresh.AccessToken)
+	assert.NoError(t, err)
+	_, err = authUC.Login(ctx, user.Email, "password123", false)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidCredentials)
+	_, err = authUC.Login(ctx, user.Email, "new-password", false)
+	assert.NoError(t, err)
+}
+
+func TestAuthUseCase_SystemOperationsAuditedAsConfiguredIdentity(t *testing.T) {
+	systemUserID := uuid.New()
+	previous := constants.SystemUserID()
+	constants.SetSystemUserID(systemUserID)
+	t.Cleanup(func() { constants.SetSystemUserID(previous) })
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entities.User{}, &entities.AuditLogEntry{}))
+	log := logger.NewLogger()
+	require.NoError(t, database.SeedSystemUser(db, log))
+
+	auditRepo := repository.NewAuditRepository(db)
+	userRepo := repository.NewUserRepository(db, nil, auth.NewPersistentAuditLogger(auditRepo, log), nil, log)
+	authUC := &authUseCase{
+		BaseUseCase: *NewBaseUseCase(log),
+		userRepo:    userRepo,
+		bcryptCost:  bcrypt.MinCost,
+	}
+
+	ctx := context.Background()
+	user, err := authUC.Register(ctx, "audited@example.com", "password123", "Audited", "User")
+	require.NoError(t, err)
+	assert.Equal(t, systemUserID, user.CreatedBy)
+
+	entries, err := auditRepo.ListByUser(ctx, systemUserID, 10, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Equal(t, "create", entries[0].Action)
+	nilEntries, err := auditRepo.ListByUser(ctx, uuid.Nil, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, nilEntries)
+
+	system, err := userRepo.GetByID(ctx, systemUserID, systemUserID)
+	require.NoError(t, err)
+	assert.False(t, system.IsActive, "the seeded system user must not be able to log in")
+	_, err = authUC.Login(ctx, constants.SystemUserEmail, "!", false)
+	assert.Error(t, err)
+}
+
+func TestAuthUseCase_RevokeUserTokens(t *testing.T) {
+	authUC, _, user := setupRefreshTokenTest(t)
+	repo := authUC.userRepo.(*MockUserRepository)
+	repo.On("Update", mock.Anything, user, mock.Anything).Return(nil)
+	adminID := uuid.New()
+	audit := &MockAuditLogger{}
+	audit.On("LogDataAccess", mock.Anything, adminID, constants.ActionRevokeTokens, constants.ResourceUser, mock.Anything).Return(nil)
+	authUC.auditLogger = audit
+	ctx := context.Background()
+
+	old, err := authUC.Login(ctx, user.Email, "password123", false)
+	require.NoError(t, err)
+	waitForNextSecond()
+
+	require.NoError(t, authUC.RevokeUserTokens(ctx, user.ID, adminID))
+	audit.AssertExpectations(t)
+
+	_, err = authUC.ValidateToken(ctx, old.AccessToken)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+	_, err = authUC.RefreshToken(ctx, old.RefreshToken)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+
+	fresh, err := authUC.Login(ctx, user.Email, "password123", false)
+	require.NoError(t, err)
+	_, err = authUC.ValidateToken(ctx, fresh.AccessToken)
+	assert.NoError(t, err)
+
+	repo.On("GetByID", mock.Anything, mock.Anything, mock.Anything).Return(nil, domainerrors.ErrUserNotFound)
+	assert.ErrorIs(t, authUC.RevokeUserTokens(ctx, uuid.New(), adminID), domainerrors.ErrUserNotFound)
+}
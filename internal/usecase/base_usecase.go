@@ -1,8 +1,12 @@
 package usecase
 
 import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/pkg/logger"
 	"context"
+	"errors"
 	"fmt"
 
 	domainerrors "clean-architecture-api/internal/domain/errors"
@@ -10,12 +14,93 @@ import (
 
 type BaseUseCase struct {
 	logger logger.Logger
+	events repositories.EventPublisher
+	tx     repositories.TransactionManager
 }
 
 func NewBaseUseCase(logger logger.Logger) *BaseUseCase {
 	return &BaseUseCase{logger: logger}
 }
 
+// WithEvents sets the publisher used by PublishEvent
+func (uc *BaseUseCase) WithEvents(events repositories.EventPublisher) *BaseUseCase {
+	uc.events = events
+	return uc
+}
+
+// WithTransactions makes PersistAndPublish record events in the same transaction as the
+// change. Only set it when the publisher writes to the database (the outbox).
+func (uc *BaseUseCase) WithTransactions(tx repositories.TransactionManager) *BaseUseCase {
+	uc.tx = tx
+	return uc
+}
+
+// ClampPagination bounds a page request the way every list use case applies it: a missing limit
+// means constants.DefaultLimit, no page is larger than constants.MaxLimit, and a negative offset
+// starts at the beginning. Delivery layers may clamp tighter first, but never looser.
+func ClampPagination(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = constants.DefaultLimit
+	}
+	if limit > constants.MaxLimit {
+		limit = constants.MaxLimit
+	}
+	if offset < 0 {
+		offset = constants.DefaultOffset
+	}
+	return limit, offset
+}
+
+// concealDenied answers a read the caller may not perform with notFound, the error a missing
+// record gets, so callers cannot probe which IDs exist. Other errors pass through.
+func concealDenied(err error, notFound error) error {
+	var permissionErr *domainerrors.PermissionError
+	if errors.As(err, &permissionErr) {
+		return notFound
+	}
+	var appErr *domainerrors.AppError
+	if errors.As(err, &appErr) &&
+		(appErr.Category == domainerrors.CategoryForbidden || appErr.Category == domainerrors.CategoryNotFound) {
+		return notFound
+	}
+	return err
+}
+
+// PersistAndPublish runs persist and emits the event once it succeeded. With a transaction
+// manager both happen atomically, so failing to record the event also undoes persist.
+func (uc *BaseUseCase) PersistAndPublish(
+	ctx context.Context,
+	eventType string,
+	data interface{},
+	persist func(ctx context.Context) error,
+) error {
+	if uc.tx == nil || uc.events == nil {
+		if err := persist(ctx); err != nil {
+			return err
+		}
+		uc.PublishEvent(ctx, eventType, data)
+		return nil
+	}
+
+	return uc.tx.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := persist(txCtx); err != nil {
+			return err
+		}
+		return uc.events.Publish(txCtx, entities.NewDomainEvent(eventType, data))
+	})
+}
+
+// PublishEvent emits a domain event after a successful write. Delivery problems are
+// logged and never fail the operation that produced the event.
+func (uc *BaseUseCase) PublishEvent(ctx context.Context, eventType string, data interface{}) {
+	if uc.events == nil {
+		return
+	}
+	if err := uc.events.Publish(ctx, entities.NewDomainEvent(eventType, data)); err != nil {
+		uc.logger.Error("Failed to publish event "+eventType, err)
+	}
+}
+
 func (uc *BaseUseCase) HandleError(err error, message string) error {
 	uc.logger.Error(message, err)
 	return fmt.Errorf("%s: %w", message, err)
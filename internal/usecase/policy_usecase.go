@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"clean-architecture-api/internal/domain/errors"
+	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PolicyUseCase interface {
+	Export(ctx context.Context) (*entities.PolicySet, error)
+	Import(ctx context.Context, set *entities.PolicySet) ([]entities.PolicyImportResult, error)
+	ListVersions(ctx context.Context, policyID uuid.UUID) ([]*entities.PolicyDocument, error)
+	Rollback(ctx context.Context, policyID uuid.UUID, version string) (*entities.PolicyDocument, error)
+	PoliciesForUser(ctx context.Context, targetID, requesterID uuid.UUID) (*entities.UserPolicies, error)
+	Simulate(ctx context.Context, req *entities.PermissionRequest, requesterID uuid.UUID) (*entities.PermissionResponse, error)
+	Reload(ctx context.Context) (int, error)
+}
+
+type policyUseCase struct {
+	BaseUseCase
+	policyRepo   repositories.PolicyRepository
+	policyEngine repositories.PolicyEngine
+	userRepo     repositories.UserRepository
+}
+
+func NewPolicyUseCase(
+	policyRepo repositories.PolicyRepository,
+	policyEngine repositories.PolicyEngine,
+	userRepo repositories.UserRepository,
+	logger logger.Logger,
+) PolicyUseCase {
+	return &policyUseCase{
+		BaseUseCase:  *NewBaseUseCase(logger),
+		policyRepo:   policyRepo,
+		policyEngine: policyEngine,
+		userRepo:     userRepo,
+	}
+}
+
+func (uc *policyUseCase) Export(ctx context.Context) (*entities.PolicySet, error) {
+	policies, err := uc.policyRepo.GetActive(ctx)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to export policies")
+	}
+
+	if policies == nil {
+		policies = []*entities.PolicyDocument{}
+	}
+
+	return &entities.PolicySet{
+		ExportedAt: time.Now().UTC(),
+		Policies:   policies,
+	}, nil
+}
+
+// Import validates every policy in the set before upserting any of them. When validation
+// fails, the per-policy results are returned together with ErrInvalidPolicyDoc.
+func (uc *policyUseCase) Import(ctx context.Context, set *entities.PolicySet) ([]entities.PolicyImportResult, error) {
+	if set == nil || len(set.Policies) == 0 {
+		return nil, errors.ErrInvalidPolicyDoc
+	}
+
+	if results, ok := uc.validatePolicySet(set); !ok {
+		return results, errors.ErrInvalidPolicyDoc
+	}
+
+	for _, policy := range set.Policies {
+		policy.IsActive = true
+		if policy.Version == "" {
+			policy.Version = "1.0"
+		}
+	}
+
+	results, err := uc.policyEngine.ImportPolicies(ctx, set.Policies)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to import policies")
+	}
+
+	return results, nil
+}
+
+func (uc *policyUseCase) ListVersions(ctx context.Context, policyID uuid.UUID) ([]*entities.PolicyDocument, error) {
+	versions, err := uc.policyRepo.GetVersions(ctx, policyID)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to list policy versions")
+	}
+	return versions, nil
+}
+
+// PoliciesForUser resolves the target's role and returns the full policy documents attached to
+// it, for troubleshooting what GET /auth/permissions reports. Roles do not inherit from each
+// other, so these are all the policies that apply.
+func (uc *policyUseCase) PoliciesForUser(ctx context.Context, targetID, requesterID uuid.UUID) (*entities.UserPolicies, error) {
+	user, err := uc.userRepo.GetByID(ctx, targetID, requesterID)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to get user")
+	}
+
+	policies, err := uc.policyEngine.GetPoliciesForRole(ctx, user.Role)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to get policies for role")
+	}
+	if policies == nil {
+		policies = []*entities.PolicyDocument{}
+	}
+
+	return &entities.UserPolicies{UserID: user.ID, Role: user.Role, Policies: policies}, nil
+}
+
+// Simulate evaluates req without enforcing it. When req names a user but no role, the user's
+// current role is used. Mark ctx with constants.WithPolicyExplain to get a per-statement trace.
+func (uc *policyUseCase) Simulate(ctx context.Context, req *entities.PermissionRequest, requesterID uuid.UUID) (*entities.PermissionResponse, error) {
+	if req == nil || strings.TrimSpace(req.Resource) == "" || strings.TrimSpace(req.Action) == "" {
+		return nil, errors.ErrInvalidRequest
+	}
+
+	if req.Role == "" {
+		if req.UserID == uuid.Nil {
+			return nil, errors.ErrInvalidRequest
+		}
+		user, err := uc.userRepo.GetByID(ctx, req.UserID, requesterID)
+		if err != nil {
+			return nil, uc.HandleError(err, "failed to get user")
+		}
+		req.Role = user.Role
+	}
+
+	response, err := uc.policyEngine.Evaluate(ctx, req)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to evaluate policies")
+	}
+	return response, nil
+}
+
+func (uc *policyUseCase) Rollback(ctx context.Context, policyID uuid.UUID, version string) (*entities.PolicyDocument, error) {
+	if strings.TrimSpace(version) == "" {
+		return nil, errors.ErrInvalidRequest
+	}
+
+	policy, err := uc.policyEngine.RollbackPolicy(ctx, policyID, version)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to roll back policy")
+	}
+	return policy, nil
+}
+
+// Reload refreshes the policy cache on every instance, for policies edited directly in the database
+func (uc *policyUseCase) Reload(ctx context.Context) (int, error) {
+	count, err := uc.policyEngine.ReloadPolicies(ctx)
+	if err != nil {
+		return 0, uc.HandleError(err, "failed to reload policies")
+	}
+	return count, nil
+}
+
+func (uc *policyUseCase) validatePolicySet(set *entities.PolicySet) ([]entities.PolicyImportResult, bool) {
+	valid := true
+	seen := make(map[string]bool, len(set.Policies))
+	results := make([]entities.PolicyImportResult, 0, len(set.Policies))
+
+	for _, policy := range set.Policies {
+		if policy == nil {
+			valid = false
+			results = append(results, entities.PolicyImportResult{Status: constants.PolicyImportInvalid, Error: "policy is empty"})
+			continue
+		}
+
+		err := validatePolicyDocument(policy)
+		if err == nil && seen[policy.Name] {
+			err = fmt.Errorf("duplicate policy name %q", policy.Name)
+		}
+		seen[policy.Name] = true
+
+		result := entities.PolicyImportResult{Name: policy.Name, Status: "valid"}
+		if err != nil {
+			valid = false
+			result.Status = constants.PolicyImportInvalid
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results, valid
+}
+
+func validatePolicyDocument(policy *entities.PolicyDocument) error {
+	if strings.TrimSpace(policy.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if len(policy.Statements) == 0 {
+		return fmt.Errorf("at least one statement is required")
+	}
+
+	for i, statement := range policy.Statements {
+		switch {
+		case !statement.IsValid():
+			return fmt.Errorf("statement %d: effect must be %q or %q", i, constants.PolicyEffectAllow, constants.PolicyEffectDeny)
+		case statement.Principal != "*" && !strings.HasPrefix(statement.Principal, "role:"):
+			return fmt.Errorf("statement %d: principal must be \"*\" or \"role:<name>\"", i)
+		case statement.Action == "":
+			return fmt.Errorf("statement %d: action is required", i)
+		case statement.Resource == "":
+			return fmt.Errorf("statement %d: resource is required", i)
+		}
+	}
+
+	return nil
+}
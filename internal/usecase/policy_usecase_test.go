@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	domainerrors "clean-architecture-api/internal/domain/errors"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPolicyEngine struct {
+	mock.Mock
+}
+
+func (m *MockPolicyEngine) Evaluate(ctx context.Context, req *entities.PermissionRequest) (*entities.PermissionResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*entities.PermissionResponse), args.Error(1)
+}
+
+func (m *MockPolicyEngine) LoadPolicies(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockPolicyEngine) ReloadPolicies(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPolicyEngine) AddPolicy(ctx context.Context, policy *entities.PolicyDocument) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *MockPolicyEngine) RemovePolicy(ctx context.Context, policyID uuid.UUID) error {
+	args := m.Called(ctx, policyID)
+	return args.Error(0)
+}
+
+func (m *MockPolicyEngine) GetPoliciesForRole(ctx context.Context, role string) ([]*entities.PolicyDocument, error) {
+	args := m.Called(ctx, role)
+	return args.Get(0).([]*entities.PolicyDocument), args.Error(1)
+}
+
+func (m *MockPolicyEngine) ImportPolicies(ctx context.Context, policies []*entities.PolicyDocument) ([]entities.PolicyImportResult, error) {
+	args := m.Called(ctx, policies)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.PolicyImportResult), args.Error(1)
+}
+
+func (m *MockPolicyEngine) RollbackPolicy(ctx context.Context, policyID uuid.UUID, version string) (*entities.PolicyDocument, error) {
+	args := m.Called(ctx, policyID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.PolicyDocument), args.Error(1)
+}
+
+func (m *MockPolicyEngine) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func setupPolicyUseCaseTest() (*policyUseCase, *MockPolicyEngine) {
+	mockEngine := &MockPolicyEngine{}
+	uc := &policyUseCase{
+		BaseUseCase:  *NewBaseUseCase(&MockLogger{}),
+		policyEngine: mockEngine,
+	}
+	return uc, mockEngine
+}
+
+func validPolicy(name string) *entities.PolicyDocument {
+	return &entities.PolicyDocument{
+		Name: name,
+		Statements: []entities.PolicyStatement{{
+			Effect:    constants.PolicyEffectAllow,
+			Principal: "role:" + constants.RoleUser,
+			Action:    constants.ActionRead,
+			Resource:  constants.PermissionProductRead,
+		}},
+	}
+}
+
+func TestPolicyUseCase_ImportRejectsWholeDocumentOnInvalidPolicy(t *testing.T) {
+	uc, mockEngine := setupPolicyUseCaseTest()
+
+	invalid := validPolicy("broken")
+	invalid.Statements[0].Effect = "maybe"
+	set := &entities.PolicySet{Policies: []*entities.PolicyDocument{validPolicy("good"), invalid, validPolicy("good")}}
+
+	results, err := uc.Import(context.Background(), set)
+
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidPolicyDoc)
+	assert.Len(t, results, 3)
+	assert.Equal(t, "valid", results[0].Status)
+	assert.Equal(t, constants.PolicyImportInvalid, results[1].Status)
+	assert.Equal(t, constants.PolicyImportInvalid, results[2].Status)
+	mockEngine.AssertNotCalled(t, "ImportPolicies", mock.Anything, mock.Anything)
+}
+
+func TestPolicyUseCase_ImportAppliesValidDocument(t *testing.T) {
+	uc, mockEngine := setupPolicyUseCaseTest()
+
+	set := &entities.PolicySet{Policies: []*entities.PolicyDocument{validPolicy("readers")}}
+	expected := []entities.PolicyImportResult{{Name: "readers", Status: constants.PolicyImportCreated}}
+	mockEngine.On("ImportPolicies", mock.Anything, set.Policies).Return(expected, nil)
+
+	results, err := uc.Import(context.Background(), set)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, results)
+	assert.True(t, set.Policies[0].IsActive)
+	mockEngine.AssertExpectations(t)
+}
+
+func TestPolicyUseCase_PoliciesForUser(t *testing.T) {
+	uc, mockEngine := setupPolicyUseCaseTest()
+	mockUsers := &MockUserRepository{}
+	mockLogger := &MockLogger{}
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return()
+	uc.userRepo = mockUsers
+	uc.logger = mockLogger
+
+	adminID := uuid.New()
+	user := &entities.User{BaseEntity: entities.BaseEntity{ID: uuid.New()}, Role: constants.RoleUser}
+	policies := []*entities.PolicyDocument{validPolicy("readers")}
+	mockUsers.On("GetByID", mock.Anything, user.ID, adminID).Return(user, nil)
+	mockEngine.On("GetPoliciesForRole", mock.Anything, constants.RoleUser).Return(policies, nil)
+
+	result, err := uc.PoliciesForUser(context.Background(), user.ID, adminID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, result.UserID)
+	assert.Equal(t, constants.RoleUser, result.Role)
+	assert.Equal(t, policies, result.Policies)
+
+	missingID := uuid.New()
+	mockUsers.On("GetByID", mock.Anything, missingID, adminID).Return(nil, domainerrors.ErrUserNotFound)
+	_, err = uc.PoliciesForUser(context.Background(), missingID, adminID)
+	assert.ErrorIs(t, err, domainerrors.ErrUserNotFound)
+}
+
+func TestPolicyUseCase_SimulateUsesUserRole(t *testing.T) {
+	uc, mockEngine := setupPolicyUseCaseTest()
+	mockUsers := &MockUserRepository{}
+	uc.userRepo = mockUsers
+
+	adminID := uuid.New()
+	user := &entities.User{BaseEntity: entities.BaseEntity{ID: uuid.New()}, Role: constants.RoleUser}
+	mockUsers.On("GetByID", mock.Anything, user.ID, adminID).Return(user, nil)
+	mockEngine.On("Evaluate", mock.Anything, mock.MatchedBy(func(req *entities.PermissionRequest) bool {
+		return req.Role == constants.RoleUser
+	})).Return(&entities.PermissionResponse{Allowed: true, Reason: constants.PolicyReasonExplicitAllow}, nil)
+
+	req := &entities.PermissionRequest{UserID: user.ID, Resource: constants.ResourceProduct, Action: constants.ActionRead}
+	response, err := uc.Simulate(context.Background(), req, adminID)
+
+	assert.NoError(t, err)
+	assert.True(t, response.Allowed)
+	mockEngine.AssertExpectations(t)
+
+	_, err = uc.Simulate(context.Background(), &entities.PermissionRequest{Resource: "product", Action: "read"}, adminID)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidRequest, "a role or a user is required")
+}
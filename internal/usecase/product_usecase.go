@@ -1,10 +1,16 @@
 package usecase
 
 import (
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/entities"
 	"clean-architecture-api/internal/domain/repositories"
 	"clean-architecture-api/pkg/logger"
 	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
 
 	"github.com/google/uuid"
 )
@@ -12,28 +18,85 @@ import (
 type ProductUseCase interface {
 	Create(ctx context.Context, product *entities.Product, userID uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error)
 	Update(ctx context.Context, product *entities.Product) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) (*entities.Product, error)
+	History(ctx context.Context, id uuid.UUID, limit, offset int) ([]*entities.ChangeEntry, error)
 	List(ctx context.Context, limit, offset int) ([]*entities.Product, error)
 	GetByCategory(ctx context.Context, category string, limit, offset int) ([]*entities.Product, error)
+	Count(ctx context.Context) (int64, error)
+	CountInCategory(ctx context.Context, category string) (int64, error)
+	CountByCategory(ctx context.Context) (map[string]int64, error)
+	LowStock(ctx context.Context, threshold, limit, offset int) ([]*entities.Product, error)
+	Reserve(ctx context.Context, productID uuid.UUID, quantity int, ttl time.Duration) (uuid.UUID, error)
+	Confirm(ctx context.Context, reservationID uuid.UUID) error
+	Release(ctx context.Context, reservationID uuid.UUID) error
 }
 
 type productUseCase struct {
 	BaseUseCase
-	productRepo repositories.ProductRepository
+	productRepo  repositories.ProductRepository
+	reservations repositories.ReservationRepository
+	auditRepo    repositories.AuditRepository
+	categoryCase string
+	publicReads  bool
+}
+
+// ProductSettings holds the configured product behaviour NewProductUseCase needs
+type ProductSettings struct {
+	// CategoryCase is constants.CategoryCaseLower or constants.CategoryCaseTitle
+	CategoryCase string
+	// PublicReads lets anonymous callers read products as the system user
+	PublicReads bool
 }
 
-func NewProductUseCase(productRepo repositories.ProductRepository, logger logger.Logger) ProductUseCase {
+func NewProductUseCase(
+	productRepo repositories.ProductRepository,
+	reservations repositories.ReservationRepository,
+	auditRepo repositories.AuditRepository,
+	events repositories.EventPublisher,
+	tx repositories.TransactionManager,
+	settings ProductSettings,
+	logger logger.Logger,
+) ProductUseCase {
 	return &productUseCase{
-		BaseUseCase: *NewBaseUseCase(logger),
-		productRepo: productRepo,
+		BaseUseCase:  *NewBaseUseCase(logger).WithEvents(events).WithTransactions(tx),
+		productRepo:  productRepo,
+		reservations: reservations,
+		auditRepo:    auditRepo,
+		categoryCase: settings.CategoryCase,
+		publicReads:  settings.PublicReads,
 	}
 }
 
+// normalizeCategory trims category, collapses inner whitespace and applies the configured casing,
+// so " Books " and "books" land in the same bucket on write and on lookup. The repositories
+// match categories case-insensitively, so rows written before normalization are found as well.
+func (uc *productUseCase) normalizeCategory(category string) string {
+	words := strings.Fields(category)
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if uc.categoryCase == constants.CategoryCaseTitle {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			word = string(runes)
+		}
+		words[i] = word
+	}
+	return strings.Join(words, " ")
+}
+
 func (uc *productUseCase) Create(ctx context.Context, product *entities.Product, userID uuid.UUID) error {
-	product.CreatedBy = userID
+	product.Category = uc.normalizeCategory(product.Category)
+	if err := product.Validate(); err != nil {
+		return err
+	}
 
-	if err := uc.productRepo.Create(ctx, product, userID); err != nil {
+	err := uc.PersistAndPublish(ctx, constants.EventProductCreated, product, func(ctx context.Context) error {
+		return uc.productRepo.Create(ctx, product, userID)
+	})
+	if err != nil {
 		return uc.HandleError(err, "failed to create product")
 	}
 
@@ -41,19 +104,43 @@ func (uc *productUseCase) Create(ctx context.Context, product *entities.Product,
 }
 
 func (uc *productUseCase) GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
-	// For GetByID operations, we need userID from context or parameter
-	// For now, we'll extract from context or use a default approach
-	userID := uc.getUserIDFromContext(ctx)
+	userID, err := uc.readerID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
+	// The repository checks access before it looks the product up; a denied read is reported
+	// like a missing product
 	product, err := uc.productRepo.GetByID(ctx, id, userID)
 	if err != nil {
-		return nil, uc.HandleError(err, "product not found")
+		return nil, concealDenied(uc.HandleError(err, "product not found"), domainerrors.ErrProductNotFound)
 	}
 	return product, nil
 }
 
+// GetByIDs loads the products in ids with one query instead of one per ID. IDs that match no
+// product are skipped; more than constants.MaxLimit IDs are rejected.
+func (uc *productUseCase) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Product, error) {
+	if len(ids) > constants.MaxLimit {
+		return nil, domainerrors.ErrInvalidRequest
+	}
+	userID, err := uc.readerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := uc.productRepo.GetByIDs(ctx, ids, userID)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to get products")
+	}
+	return products, nil
+}
+
 func (uc *productUseCase) Update(ctx context.Context, product *entities.Product) error {
-	userID := uc.getUserIDFromContext(ctx)
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
 
 	existingProduct, err := uc.productRepo.GetByID(ctx, product.ID, userID)
 	if err != nil {
@@ -62,7 +149,14 @@ func (uc *productUseCase) Update(ctx context.Context, product *entities.Product)
 
 	uc.updateProductFields(existingProduct, product)
 
-	if err := uc.productRepo.Update(ctx, existingProduct, userID); err != nil {
+	if err := existingProduct.Validate(); err != nil {
+		return err
+	}
+
+	err = uc.PersistAndPublish(ctx, constants.EventProductUpdated, existingProduct, func(ctx context.Context) error {
+		return uc.productRepo.Update(ctx, existingProduct, userID)
+	})
+	if err != nil {
 		return uc.HandleError(err, "failed to update product")
 	}
 
@@ -74,11 +168,14 @@ func (uc *productUseCase) updateProductFields(existingProduct, product *entities
 	existingProduct.Description = product.Description
 	existingProduct.Price = product.Price
 	existingProduct.Stock = product.Stock
-	existingProduct.Category = product.Category
+	existingProduct.Category = uc.normalizeCategory(product.Category)
 }
 
 func (uc *productUseCase) Delete(ctx context.Context, id uuid.UUID) error {
-	userID := uc.getUserIDFromContext(ctx)
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
 
 	if err := uc.ValidateEntityExists(ctx, func() error {
 		_, err := uc.productRepo.GetByID(ctx, id, userID)
@@ -87,15 +184,72 @@ func (uc *productUseCase) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	if err := uc.productRepo.Delete(ctx, id, userID); err != nil {
+	err = uc.PersistAndPublish(ctx, constants.EventProductDeleted, map[string]uuid.UUID{"id": id}, func(ctx context.Context) error {
+		return uc.productRepo.Delete(ctx, id, userID)
+	})
+	if err != nil {
 		return uc.HandleError(err, "failed to delete product")
 	}
 
 	return nil
 }
 
+// Restore undoes a soft delete. It fails with ErrProductNotFound when the product is not deleted.
+func (uc *productUseCase) Restore(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var product *entities.Product
+	err = uc.PersistAndPublish(ctx, constants.EventProductRestored, map[string]uuid.UUID{"id": id}, func(ctx context.Context) error {
+		var err error
+		product, err = uc.productRepo.Restore(ctx, id, userID)
+		return err
+	})
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to restore product")
+	}
+
+	return product, nil
+}
+
+// productChangeResources are the audit resources that record a change to a product; reads are
+// audited too but do not belong in its history
+var productChangeResources = []string{
+	constants.ResourceProduct + ":" + constants.ActionCreate,
+	constants.ResourceProduct + ":" + constants.ActionUpdate,
+	constants.ResourceProduct + ":" + constants.ActionDelete,
+	constants.ResourceProduct + ":" + constants.ActionRestore,
+}
+
+// History returns who changed product id and when, newest first. Entries outlive the product, so
+// a deleted product still has a history.
+func (uc *productUseCase) History(ctx context.Context, id uuid.UUID, limit, offset int) ([]*entities.ChangeEntry, error) {
+	limit, offset = ClampPagination(limit, offset)
+	entries, err := uc.auditRepo.ListByEntity(ctx, id, productChangeResources, limit, offset)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to list product history")
+	}
+
+	history := make([]*entities.ChangeEntry, 0, len(entries))
+	for _, entry := range entries {
+		history = append(history, &entities.ChangeEntry{
+			ID:        entry.ID,
+			UserID:    entry.UserID,
+			Action:    entry.Action,
+			Timestamp: entry.Timestamp,
+		})
+	}
+	return history, nil
+}
+
 func (uc *productUseCase) List(ctx context.Context, limit, offset int) ([]*entities.Product, error) {
-	userID := uc.getUserIDFromContext(ctx)
+	limit, offset = ClampPagination(limit, offset)
+	userID, err := uc.readerID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	products, err := uc.productRepo.List(ctx, limit, offset, userID)
 	if err != nil {
@@ -107,18 +261,155 @@ func (uc *productUseCase) List(ctx context.Context, limit, offset int) ([]*entit
 func (uc *productUseCase) GetByCategory(ctx context.Context, category string, limit, offset int) ([]*entities.Product,
 	error,
 ) {
-	products, err := uc.productRepo.GetByCategory(ctx, category, limit, offset)
+	if err := uc.checkListAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	limit, offset = ClampPagination(limit, offset)
+	products, err := uc.productRepo.GetByCategory(ctx, uc.normalizeCategory(category), limit, offset)
 	if err != nil {
 		return nil, uc.HandleError(err, "failed to get products by category")
 	}
 	return products, nil
 }
 
-// getUserIDFromContext extracts user ID from context
-func (uc *productUseCase) getUserIDFromContext(ctx context.Context) uuid.UUID {
-	if userID, exists := ctx.Value("user_id").(uuid.UUID); exists {
-		return userID
+func (uc *productUseCase) Count(ctx context.Context) (int64, error) {
+	userID, err := uc.readerID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := uc.productRepo.Count(ctx, userID)
+	if err != nil {
+		return 0, uc.HandleError(err, "failed to count products")
+	}
+	return count, nil
+}
+
+func (uc *productUseCase) CountInCategory(ctx context.Context, category string) (int64, error) {
+	if err := uc.checkListAccess(ctx); err != nil {
+		return 0, err
+	}
+
+	count, err := uc.productRepo.CountInCategory(ctx, uc.normalizeCategory(category))
+	if err != nil {
+		return 0, uc.HandleError(err, "failed to count products by category")
+	}
+	return count, nil
+}
+
+// CountByCategory returns the number of products in each category for the dashboard stats.
+// Products without a category are counted under the empty key.
+func (uc *productUseCase) CountByCategory(ctx context.Context) (map[string]int64, error) {
+	if err := uc.checkListAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	counts, err := uc.productRepo.CountByCategory(ctx)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to count products per category")
+	}
+	// The repositories key counts in lower case; present them in the configured casing
+	normalized := make(map[string]int64, len(counts))
+	for category, count := range counts {
+		normalized[uc.normalizeCategory(category)] += count
+	}
+	return normalized, nil
+}
+
+// LowStock lists products with stock at or below threshold for the inventory report
+func (uc *productUseCase) LowStock(ctx context.Context, threshold, limit, offset int) ([]*entities.Product, error) {
+	if threshold < 0 {
+		return nil, domainerrors.ErrInvalidThreshold
+	}
+
+	limit, offset = ClampPagination(limit, offset)
+	products, err := uc.productRepo.GetLowStock(ctx, threshold, limit, offset)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to get low stock products")
+	}
+	return products, nil
+}
+
+// Reserve holds quantity units of a product for ttl and returns the reservation ID. The stock
+// drops immediately so concurrent checkouts cannot oversell; Confirm keeps it that way, while
+// Release or expiry puts the units back.
+func (uc *productUseCase) Reserve(ctx context.Context, productID uuid.UUID, quantity int, ttl time.Duration) (uuid.UUID, error) {
+	if quantity <= 0 {
+		return uuid.Nil, domainerrors.ErrInvalidQuantity
+	}
+	if ttl <= 0 || ttl > constants.MaxReservationTTL {
+		return uuid.Nil, domainerrors.ErrInvalidReservationTTL
+	}
+
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if _, err := uc.productRepo.GetByID(ctx, productID, userID); err != nil {
+		return uuid.Nil, uc.HandleError(err, "product not found")
+	}
+
+	reservation := &entities.Reservation{
+		ID:        uuid.New(),
+		ProductID: productID,
+		Quantity:  quantity,
+		Status:    constants.ReservationStatusPending,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+	}
+	if err := uc.reservations.Reserve(ctx, reservation); err != nil {
+		return uuid.Nil, uc.HandleError(err, "failed to reserve product")
+	}
+
+	return reservation.ID, nil
+}
+
+// Confirm finalizes a reservation at checkout; it fails once the reservation has expired
+func (uc *productUseCase) Confirm(ctx context.Context, reservationID uuid.UUID) error {
+	if err := uc.reservations.Confirm(ctx, reservationID); err != nil {
+		return uc.HandleError(err, "failed to confirm reservation")
+	}
+	return nil
+}
+
+// Release cancels a reservation and returns its units to stock
+func (uc *productUseCase) Release(ctx context.Context, reservationID uuid.UUID) error {
+	if err := uc.reservations.Release(ctx, reservationID); err != nil {
+		return uc.HandleError(err, "failed to release reservation")
+	}
+	return nil
+}
+
+// readerID is the identity product reads run as. Anonymous callers read as the system user while
+// PRODUCTS_PUBLIC allows it and are refused otherwise; authenticated callers are checked as themselves.
+func (uc *productUseCase) readerID(ctx context.Context) (uuid.UUID, error) {
+	if userID, exists := constants.UserIDFromContext(ctx); exists {
+		return userID, nil
+	}
+	if uc.publicReads {
+		return constants.SystemUserID(), nil
+	}
+	return uuid.Nil, domainerrors.ErrAuthorizationHeaderRequired
+}
+
+// checkListAccess applies the list permission check for queries the repository does not guard itself
+func (uc *productUseCase) checkListAccess(ctx context.Context) error {
+	userID, err := uc.readerID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := uc.productRepo.ValidateAccess(ctx, userID, constants.ActionList); err != nil {
+		return uc.HandleError(err, "access denied")
+	}
+	return nil
+}
+
+// currentUserID returns the caller that product writes run as. Unlike reads, writes never fall back
+// to the system user, so a caller without an identity gets ErrUserIDNotFound.
+func currentUserID(ctx context.Context) (uuid.UUID, error) {
+	userID, exists := constants.UserIDFromContext(ctx)
+	if !exists {
+		return uuid.Nil, domainerrors.ErrUserIDNotFound
 	}
-	// Return a nil UUID if not found - this should be handled at middleware level
-	return uuid.Nil
+	return userID, nil
 }
@@ -0,0 +1,430 @@
+package usecase
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"context"
+	"testing"
+	"time"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProductRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductRepository) Create(ctx context.Context, product *entities.Product, userID uuid.UUID) error {
+	args := m.Called(ctx, product, userID)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.Product, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) ([]*entities.Product, error) {
+	args := m.Called(ctx, ids, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) HealthCheck(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) Update(ctx context.Context, product *entities.Product, userID uuid.UUID) error {
+	args := m.Called(ctx, product, userID)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) List(ctx context.Context, limit, offset int, userID uuid.UUID) ([]*entities.Product, error) {
+	args := m.Called(ctx, limit, offset, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) ValidateAccess(ctx context.Context, userID uuid.UUID, action string) error {
+	args := m.Called(ctx, userID, action)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) AuditLog(ctx context.Context, userID uuid.UUID, action string, entity *entities.Product) error {
+	args := m.Called(ctx, userID, action, entity)
+	return args.Error(0)
+}
+
+func (m *MockProductRepository) GetByCategory(ctx context.Context, category string, limit, offset int) ([]*entities.Product, error) {
+	args := m.Called(ctx, category, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) Count(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockProductRepository) CountInCategory(ctx context.Context, category string) (int64, error) {
+	args := m.Called(ctx, category)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockProductRepository) CountByCategory(ctx context.Context) (map[string]int64, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *MockProductRepository) GetLowStock(ctx context.Context, threshold, limit, offset int) ([]*entities.Product, error) {
+	args := m.Called(ctx, threshold, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*entities.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.Product, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Product), args.Error(1)
+}
+
+func setupProductUseCaseTest() (*productUseCase, *MockProductRepository, *MockLogger) {
+	mockProductRepo := &MockProductRepository{}
+	mockLogger := &MockLogger{}
+
+	productUC := &productUseCase{
+		BaseUseCase: *NewBaseUseCase(mockLogger),
+		productRepo: mockProductRepo,
+	}
+
+	return productUC, mockProductRepo, mockLogger
+}
+
+func TestProductUseCase_UsesAuthenticatedUserID(t *testing.T) {
+	productUC, mockRepo, mockLogger := setupProductUseCaseTest()
+
+	userID := uuid.New()
+	productID := uuid.New()
+	product := &entities.Product{BaseEntity: entities.BaseEntity{ID: productID}, Name: "Widget"}
+
+	ctx := constants.WithUserID(context.Background(), userID)
+
+	mockRepo.On("GetByID", ctx, productID, userID).Return(product, nil).Once()
+	mockRepo.On("List", ctx, constants.DefaultLimit, constants.DefaultOffset, userID).Return([]*entities.Product{product}, nil).Once()
+
+	got, err := productUC.GetByID(ctx, productID)
+	assert.NoError(t, err)
+	assert.Equal(t, product, got)
+
+	products, err := productUC.List(ctx, constants.DefaultLimit, constants.DefaultOffset)
+	assert.NoError(t, err)
+	assert.Len(t, products, 1)
+
+	mockRepo.AssertExpectations(t)
+	mockLogger.AssertExpectations(t)
+}
+
+func TestProductUseCase_GetByIDConcealsDenied(t *testing.T) {
+	userID := uuid.New()
+	ctx := constants.WithUserID(context.Background(), userID)
+
+	for name, repoErr := range map[string]error{
+		"denied":  domainerrors.NewPermissionError(constants.RoleUser, constants.ResourceProduct, constants.ActionRead, "no statement allows"),
+		"missing": domainerrors.ErrProductNotFound,
+	} {
+		t.Run(name, func(t *testing.T) {
+			productUC, mockRepo, mockLogger := setupProductUseCaseTest()
+			productID := uuid.New()
+			mockRepo.On("GetByID", ctx, productID, userID).Return(nil, repoErr).Once()
+			mockLogger.On("Error", mock.Anything, mock.Anything).Return()
+
+			_, err := productUC.GetByID(ctx, productID)
+			assert.Equal(t, domainerrors.ErrProductNotFound, err)
+		})
+	}
+}
+
+// Internal callers skip gin binding, so the use case must reject invalid products itself
+func TestProductUseCase_RejectsInvalidProducts(t *testing.T) {
+	userID := uuid.New()
+	ctx := constants.WithUserID(context.Background(), userID)
+
+	tests := []struct {
+		name     string
+		product  entities.Product
+		expected error
+	}{
+		{"zero price", entities.Product{Name: "Widget", Price: 0}, domainerrors.ErrInvalidPrice},
+		{"negative price", entities.Product{Name: "Widget", Price: -5}, domainerrors.ErrInvalidPrice},
+		{"too many decimals", entities.Product{Name: "Widget", Price: 1.999}, domainerrors.ErrPriceTooPrecise},
+		{"missing name", entities.Product{Price: 10}, domainerrors.ErrNameIsRequired},
+		{"negative stock", entities.Product{Name: "Widget", Price: 10, Stock: -1}, domainerrors.ErrInvalidStock},
+	}
+
+	for _, tt := range tests {
+		t.Run("create/"+tt.name, func(t *testing.T) {
+			productUC, mockRepo, _ := setupProductUseCaseTest()
+			product := tt.product
+
+			err := productUC.Create(ctx, &product, userID)
+			assert.ErrorIs(t, err, tt.expected)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("update/"+tt.name, func(t *testing.T) {
+			productUC, mockRepo, _ := setupProductUseCaseTest()
+			productID := uuid.New()
+			existing := &entities.Product{BaseEntity: entities.BaseEntity{ID: productID}, Name: "Widget", Price: 10}
+			mockRepo.On("GetByID", ctx, productID, userID).Return(existing, nil).Once()
+
+			product := tt.product
+			product.ID = productID
+			err := productUC.Update(ctx, &product)
+			assert.ErrorIs(t, err, tt.expected)
+			mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestProductUseCase_WritesRequireCaller(t *testing.T) {
+	productUC, mockRepo, _ := setupProductUseCaseTest()
+	anonymous := context.Background()
+	productID := uuid.New()
+
+	err := productUC.Update(anonymous, &entities.Product{BaseEntity: entities.BaseEntity{ID: productID}, Name: "Widget", Price: 10})
+	assert.ErrorIs(t, err, domainerrors.ErrUserIDNotFound)
+	assert.ErrorIs(t, productUC.Delete(anonymous, productID), domainerrors.ErrUserIDNotFound)
+	_, err = productUC.Restore(anonymous, productID)
+	assert.ErrorIs(t, err, domainerrors.ErrUserIDNotFound)
+	_, err = productUC.Reserve(anonymous, productID, 1, time.Minute)
+	assert.ErrorIs(t, err, domainerrors.ErrUserIDNotFound)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProductUseCase_PublicReadsUseConfiguredSystemUser(t *testing.T) {
+	systemID := uuid.New()
+	constants.SetSystemUserID(systemID)
+	t.Cleanup(func() { constants.SetSystemUserID(uuid.MustParse(constants.DefaultSystemUserID)) })
+
+	productUC, mockRepo, _ := setupProductUseCaseTest()
+	productUC.publicReads = true
+	anonymous := context.Background()
+	productID := uuid.New()
+	mockRepo.On("GetByID", anonymous, productID, systemID).Return(&entities.Product{}, nil).Once()
+	mockRepo.On("List", anonymous, 10, 0, systemID).Return([]*entities.Product{}, nil).Once()
+
+	_, err := productUC.GetByID(anonymous, productID)
+	assert.NoError(t, err)
+	_, err = productUC.List(anonymous, 10, 0)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProductUseCase_ReserveValidatesInput(t *testing.T) {
+	productUC, _, _ := setupProductUseCaseTest()
+	ctx := context.Background()
+
+	_, err := productUC.Reserve(ctx, uuid.New(), 0, time.Minute)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidQuantity)
+	_, err = productUC.Reserve(ctx, uuid.New(), 1, 0)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidReservationTTL)
+	_, err = productUC.Reserve(ctx, uuid.New(), 1, constants.MaxReservationTTL+time.Second)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidReservationTTL)
+}
+
+func TestProductUseCase_LowStock(t *testing.T) {
+	productUC, mockRepo, _ := setupProductUseCaseTest()
+	ctx := context.Background()
+	low := []*entities.Product{{Name: "Empty", Stock: 0}, {Name: "Few", Stock: 3}}
+	mockRepo.On("GetLowStock", ctx, 5, constants.DefaultLimit, 0).Return(low, nil).Once()
+
+	products, err := productUC.LowStock(ctx, 5, constants.DefaultLimit, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, low, products)
+
+	_, err = productUC.LowStock(ctx, -1, constants.DefaultLimit, 0)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidThreshold)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProductUseCase_CountByCategory(t *testing.T) {
+	productUC, mockRepo, _ := setupProductUseCaseTest()
+	userID := uuid.New()
+	ctx := constants.WithUserID(context.Background(), userID)
+	mockRepo.On("ValidateAccess", ctx, userID, constants.ActionList).Return(nil).Once()
+	mockRepo.On("CountByCategory", ctx).Return(map[string]int64{"books": 3, "home": 1}, nil).Once()
+
+	counts, err := productUC.CountByCategory(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"books": 3, "home": 1}, counts)
+	mockRepo.AssertExpectations(t)
+}
+
+// Callers that skip the handlers, such as gRPC or jobs, still get a bounded page
+func TestProductUseCase_ListClampsPagination(t *testing.T) {
+	productUC, mockRepo, _ := setupProductUseCaseTest()
+	userID := uuid.New()
+	ctx := constants.WithUserID(context.Background(), userID)
+	mockRepo.On("List", ctx, constants.MaxLimit, constants.DefaultOffset, userID).Return([]*entities.Product{}, nil).Once()
+	mockRepo.On("GetLowStock", ctx, 5, constants.DefaultLimit, constants.DefaultOffset).Return([]*entities.Product{}, nil).Once()
+
+	_, err := productUC.List(ctx, 5000, -3)
+	assert.NoError(t, err)
+	_, err = productUC.LowStock(ctx, 5, 0, -1)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClampPagination(t *testing.T) {
+	tests := []struct {
+		limit, offset         int
+		wantLimit, wantOffset int
+	}{
+		{0, 0, constants.DefaultLimit, 0},
+		{-1, -1, constants.DefaultLimit, constants.DefaultOffset},
+		{constants.MaxLimit + 1, 20, constants.MaxLimit, 20},
+		{25, 50, 25, 50},
+	}
+	for _, tt := range tests {
+		limit, offset := ClampPagination(tt.limit, tt.offset)
+		assert.Equal(t, tt.wantLimit, limit)
+		assert.Equal(t, tt.wantOffset, offset)
+	}
+}
+
+func TestProductUseCase_NormalizesCategory(t *testing.T) {
+	userID := uuid.New()
+	ctx := constants.WithUserID(context.Background(), userID)
+
+	tests := []struct {
+		categoryCase string
+		inputs       []string
+		expected     string
+	}{
+		{constants.CategoryCaseLower, []string{"books", " Books ", "BOOKS"}, "books"},
+		{constants.CategoryCaseTitle, []string{"books", " Books ", "BOOKS"}, "Books"},
+		{constants.CategoryCaseTitle, []string{"home  garden", "Home Garden "}, "Home Garden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.categoryCase+"/"+tt.expected, func(t *testing.T) {
+			productUC, mockRepo, _ := setupProductUseCaseTest()
+			productUC.categoryCase = tt.categoryCase
+			mockRepo.On("ValidateAccess", ctx, userID, constants.ActionList).Return(nil)
+
+			for _, input := range tt.inputs {
+				product := &entities.Product{Name: "Widget", Price: 10, Category: input}
+				mockRepo.On("Create", ctx, product, userID).Return(nil).Once()
+				assert.NoError(t, productUC.Create(ctx, product, userID))
+				assert.Equal(t, tt.expected, product.Category)
+
+				mockRepo.On("GetByCategory", ctx, tt.expected, constants.DefaultLimit, 0).Return([]*entities.Product{product}, nil).Once()
+				products, err := productUC.GetByCategory(ctx, input, constants.DefaultLimit, 0)
+				assert.NoError(t, err)
+				assert.Len(t, products, 1)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductUseCase_CountByCategoryAppliesCasing(t *testing.T) {
+	userID := uuid.New()
+	ctx := constants.WithUserID(context.Background(), userID)
+	productUC, mockRepo, _ := setupProductUseCaseTest()
+	productUC.categoryCase = constants.CategoryCaseTitle
+	mockRepo.On("ValidateAccess", ctx, userID, constants.ActionList).Return(nil)
+	mockRepo.On("CountByCategory", ctx).Return(map[string]int64{"home garden": 2, "": 1}, nil)
+
+	counts, err := productUC.CountByCategory(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"Home Garden": 2, "": 1}, counts)
+}
+
+func TestProductUseCase_ReadAccess(t *testing.T) {
+	anonymous := context.Background()
+	userID := uuid.New()
+	authenticated := constants.WithUserID(anonymous, userID)
+
+	t.Run("public lets anonymous callers read as the system user", func(t *testing.T) {
+		productUC, mockRepo, _ := setupProductUseCaseTest()
+		productUC.publicReads = true
+		mockRepo.On("List", anonymous, 10, 0, constants.SystemUserID()).Return([]*entities.Product{}, nil)
+		mockRepo.On("ValidateAccess", anonymous, constants.SystemUserID(), constants.ActionList).Return(nil)
+		mockRepo.On("GetByCategory", anonymous, "books", 10, 0).Return([]*entities.Product{}, nil)
+
+		_, err := productUC.List(anonymous, 10, 0)
+		assert.NoError(t, err)
+		_, err = productUC.GetByCategory(anonymous, "books", 10, 0)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("protected refuses anonymous callers", func(t *testing.T) {
+		productUC, mockRepo, _ := setupProductUseCaseTest()
+
+		_, err := productUC.List(anonymous, 10, 0)
+		assert.ErrorIs(t, err, domainerrors.ErrAuthorizationHeaderRequired)
+		_, err = productUC.GetByCategory(anonymous, "books", 10, 0)
+		assert.ErrorIs(t, err, domainerrors.ErrAuthorizationHeaderRequired)
+		_, err = productUC.GetByID(anonymous, uuid.New())
+		assert.ErrorIs(t, err, domainerrors.ErrAuthorizationHeaderRequired)
+		mockRepo.AssertNotCalled(t, "GetByCategory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("category lookups check the caller's list permission", func(t *testing.T) {
+		productUC, mockRepo, mockLogger := setupProductUseCaseTest()
+		mockLogger.On("Error", mock.Anything, mock.Anything).Return()
+		mockRepo.On("ValidateAccess", authenticated, userID, constants.ActionList).Return(domainerrors.ErrInsufficientPermissions)
+
+		_, err := productUC.GetByCategory(authenticated, "books", 10, 0)
+		assert.ErrorIs(t, err, domainerrors.ErrInsufficientPermissions)
+		_, err = productUC.CountInCategory(authenticated, "books")
+		assert.ErrorIs(t, err, domainerrors.ErrInsufficientPermissions)
+		mockRepo.AssertNotCalled(t, "GetByCategory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductUseCase_GetByIDs(t *testing.T) {
+	userID := uuid.New()
+	ctx := constants.WithUserID(context.Background(), userID)
+	productUC, mockRepo, _ := setupProductUseCaseTest()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	found := []*entities.Product{{BaseEntity: entities.BaseEntity{ID: ids[0]}, Name: "Widget"}}
+	mockRepo.On("GetByIDs", ctx, ids, userID).Return(found, nil).Once()
+
+	products, err := productUC.GetByIDs(ctx, ids)
+	assert.NoError(t, err)
+	assert.Equal(t, found, products)
+
+	_, err = productUC.GetByIDs(ctx, make([]uuid.UUID, constants.MaxLimit+1))
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidRequest)
+	mockRepo.AssertExpectations(t)
+}
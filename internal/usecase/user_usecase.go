@@ -1,10 +1,17 @@
 package usecase
 
 import (
+	"clean-architecture-api/internal/domain/constants"
 	"clean-architecture-api/internal/domain/entities"
 	"clean-architecture-api/internal/domain/repositories"
+	"clean-architecture-api/internal/domain/validators"
 	"clean-architecture-api/pkg/logger"
 	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	domainerrors "clean-architecture-api/internal/domain/errors"
 
@@ -12,47 +19,129 @@ import (
 )
 
 type UserUseCase interface {
+	Create(ctx context.Context, user *entities.User, userID uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.User, error)
+	GetByEmail(ctx context.Context, email string) (*entities.User, error)
 	Update(ctx context.Context, user *entities.User, userID uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 	List(ctx context.Context, limit, offset int, userID uuid.UUID) ([]*entities.User, error)
+	Count(ctx context.Context, userID uuid.UUID) (int64, error)
+	Search(ctx context.Context, search repositories.UserSearch, limit, offset int) ([]*entities.User, int64, error)
+	Activity(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.ActivityEntry, error)
 }
 
 type userUseCase struct {
 	BaseUseCase
-	userRepo repositories.UserRepository
+	userRepo        repositories.UserRepository
+	auditRepo       repositories.AuditRepository
+	allowSelfDelete bool
 }
 
-func NewUserUseCase(userRepo repositories.UserRepository, logger logger.Logger) UserUseCase {
+func NewUserUseCase(
+	userRepo repositories.UserRepository,
+	auditRepo repositories.AuditRepository,
+	events repositories.EventPublisher,
+	tx repositories.TransactionManager,
+	logger logger.Logger,
+) UserUseCase {
+	allowSelfDelete, _ := strconv.ParseBool(os.Getenv("ALLOW_SELF_DELETE"))
 	return &userUseCase{
-		BaseUseCase: *NewBaseUseCase(logger),
-		userRepo:    userRepo,
+		BaseUseCase:     *NewBaseUseCase(logger).WithEvents(events).WithTransactions(tx),
+		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		allowSelfDelete: allowSelfDelete,
 	}
 }
 
+// Create stores a user built by an internal caller such as an admin import. The password must
+// already be hashed; Validate defaults an empty role to user and rejects unknown roles.
+func (uc *userUseCase) Create(ctx context.Context, user *entities.User, userID uuid.UUID) error {
+	if err := validators.ValidateEmail(user.Email); err != nil {
+		return err
+	}
+	if err := user.Validate(); err != nil {
+		return err
+	}
+
+	err := uc.PersistAndPublish(ctx, constants.EventUserCreated, user, func(ctx context.Context) error {
+		return uc.userRepo.Create(ctx, user, userID)
+	})
+	if err != nil {
+		return uc.HandleError(err, "failed to create user")
+	}
+
+	return nil
+}
+
 func (uc *userUseCase) GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.User, error) {
-	user, err := uc.userRepo.GetByID(ctx, id, userID)
+	user, err := uc.userRepo.GetByID(withUserOwner(ctx, id), id, userID)
 	if err != nil {
 		return nil, domainerrors.ErrUserNotFound
 	}
 	return user, nil
 }
 
+func (uc *userUseCase) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	if err := validators.ValidateEmail(email); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrUserNotFound) {
+			return nil, domainerrors.ErrUserNotFound
+		}
+		return nil, uc.HandleError(err, "failed to get user by email")
+	}
+	return user, nil
+}
+
 func (uc *userUseCase) Update(ctx context.Context, user *entities.User, userID uuid.UUID) error {
+	ctx = withUserOwner(ctx, user.ID)
 	existingUser, err := uc.userRepo.GetByID(ctx, user.ID, userID)
 	if err != nil {
 		return domainerrors.ErrUserNotFound
 	}
 
+	// Self-access policies let users edit their profile, not grant themselves more access
+	if user.ID == userID && !isAdminContext(ctx) &&
+		(user.Role != existingUser.Role || user.IsActive != existingUser.IsActive) {
+		return domainerrors.ErrCannotChangeOwnAccess
+	}
+
+	// Deactivation revokes the user's tokens, so reactivating them later does not revive old sessions
+	eventType := constants.EventUserUpdated
+	if existingUser.IsActive && !user.IsActive {
+		existingUser.RevokeTokens(time.Now())
+		eventType = constants.EventUserDeactivated
+	}
+
 	uc.updateUserFields(existingUser, user)
+	if err := existingUser.Validate(); err != nil {
+		return err
+	}
 
-	if err := uc.userRepo.Update(ctx, existingUser, userID); err != nil {
+	err = uc.PersistAndPublish(ctx, eventType, existingUser, func(ctx context.Context) error {
+		return uc.userRepo.Update(ctx, existingUser, userID)
+	})
+	if err != nil {
 		return uc.HandleError(err, "failed to update user")
 	}
 
 	return nil
 }
 
+// withUserOwner marks a user record as owned by that user, which is what resource_owner
+// policy conditions compare the caller against
+func withUserOwner(ctx context.Context, id uuid.UUID) context.Context {
+	return constants.WithResourceOwner(ctx, id)
+}
+
+func isAdminContext(ctx context.Context) bool {
+	role, ok := constants.UserRoleFromContext(ctx)
+	return ok && role == constants.RoleAdmin
+}
+
 func (uc *userUseCase) updateUserFields(existingUser, user *entities.User) {
 	existingUser.FirstName = user.FirstName
 	existingUser.LastName = user.LastName
@@ -61,24 +150,95 @@ func (uc *userUseCase) updateUserFields(existingUser, user *entities.User) {
 }
 
 func (uc *userUseCase) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
-	if err := uc.ValidateEntityExists(ctx, func() error {
-		_, err := uc.userRepo.GetByID(ctx, id, userID)
-		return err
-	}, "user"); err != nil {
-		return err
+	if id == userID && !uc.allowSelfDelete {
+		return domainerrors.ErrCannotDeleteSelf
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return uc.HandleError(err, "user not found")
+	}
+
+	if user.IsAdmin() {
+		if err := uc.ensureNotLastAdmin(ctx); err != nil {
+			return err
+		}
 	}
 
-	if err := uc.userRepo.Delete(ctx, id, userID); err != nil {
+	err = uc.PersistAndPublish(ctx, constants.EventUserDeleted, map[string]uuid.UUID{"id": id}, func(ctx context.Context) error {
+		return uc.userRepo.Delete(ctx, id, userID)
+	})
+	if err != nil {
 		return domainerrors.ErrDeleteUser
 	}
 
 	return nil
 }
 
+func (uc *userUseCase) ensureNotLastAdmin(ctx context.Context) error {
+	adminCount, err := uc.userRepo.CountByRole(ctx, constants.RoleAdmin)
+	if err != nil {
+		return uc.HandleError(err, "failed to count admins")
+	}
+	if adminCount <= 1 {
+		return domainerrors.ErrCannotDeleteLastAdmin
+	}
+	return nil
+}
+
 func (uc *userUseCase) List(ctx context.Context, limit, offset int, userID uuid.UUID) ([]*entities.User, error) {
+	limit, offset = ClampPagination(limit, offset)
 	users, err := uc.userRepo.List(ctx, limit, offset, userID)
 	if err != nil {
 		return nil, uc.HandleError(err, "failed to list users")
 	}
 	return users, nil
 }
+
+func (uc *userUseCase) Count(ctx context.Context, userID uuid.UUID) (int64, error) {
+	count, err := uc.userRepo.Count(ctx, userID)
+	if err != nil {
+		return 0, uc.HandleError(err, "failed to count users")
+	}
+	return count, nil
+}
+
+// Search finds users for the admin search, returning a page and the total number of matches
+func (uc *userUseCase) Search(ctx context.Context, search repositories.UserSearch, limit, offset int) ([]*entities.User, int64, error) {
+	if search.Role != "" && search.Role != constants.RoleUser && search.Role != constants.RoleAdmin {
+		return nil, 0, domainerrors.ErrInvalidRole
+	}
+
+	limit, offset = ClampPagination(limit, offset)
+	users, total, err := uc.userRepo.Search(ctx, search, limit, offset)
+	if err != nil {
+		return nil, 0, uc.HandleError(err, "failed to search users")
+	}
+	return users, total, nil
+}
+
+// Activity returns userID's own audit trail, newest first. Resources are reduced to their public
+// name, e.g. "user:read" becomes "user", since the suffix only repeats the action.
+func (uc *userUseCase) Activity(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.ActivityEntry, error) {
+	limit, offset = ClampPagination(limit, offset)
+	entries, err := uc.auditRepo.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, uc.HandleError(err, "failed to list activity")
+	}
+
+	activity := make([]*entities.ActivityEntry, 0, len(entries))
+	for _, entry := range entries {
+		item := &entities.ActivityEntry{
+			ID:        entry.ID,
+			Action:    entry.Action,
+			Resource:  strings.SplitN(entry.Resource, ":", 2)[0],
+			Timestamp: entry.Timestamp,
+		}
+		if entry.EntityID != uuid.Nil {
+			entityID := entry.EntityID
+			item.EntityID = &entityID
+		}
+		activity = append(activity, item)
+	}
+	return activity, nil
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"clean-architecture-api/internal/domain/constants"
+	"clean-architecture-api/internal/domain/entities"
+	"context"
+	"testing"
+	"time"
+
+	domainerrors "clean-architecture-api/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupUserUseCaseTest() (*userUseCase, *MockUserRepository, *MockLogger) {
+	mockUserRepo := &MockUserRepository{}
+	mockLogger := &MockLogger{}
+
+	userUC := &userUseCase{
+		BaseUseCase: *NewBaseUseCase(mockLogger),
+		userRepo:    mockUserRepo,
+	}
+
+	return userUC, mockUserRepo, mockLogger
+}
+
+func TestUserUseCase_Delete(t *testing.T) {
+	adminID := uuid.New()
+	otherAdminID := uuid.New()
+	regularUserID := uuid.New()
+
+	tests := []struct {
+		name          string
+		targetID      uuid.UUID
+		setupMocks    func(*MockUserRepository)
+		expectedError error
+	}{
+		{
+			name:          "Failure - Cannot delete self",
+			targetID:      adminID,
+			setupMocks:    func(mockRepo *MockUserRepository) {},
+			expectedError: domainerrors.ErrCannotDeleteSelf,
+		},
+		{
+			name:     "Failure - Cannot delete last admin",
+			targetID: otherAdminID,
+			setupMocks: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetByID", mock.Anything, otherAdminID, adminID).
+					Return(&entities.User{BaseEntity: entities.BaseEntity{ID: otherAdminID}, Role: constants.RoleAdmin}, nil)
+				mockRepo.On("CountByRole", mock.Anything, constants.RoleAdmin).Return(int64(1), nil)
+			},
+			expectedError: domainerrors.ErrCannotDeleteLastAdmin,
+		},
+		{
+			name:     "Success - Delete admin when others remain",
+			targetID: otherAdminID,
+			setupMocks: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetByID", mock.Anything, otherAdminID, adminID).
+					Return(&entities.User{BaseEntity: entities.BaseEntity{ID: otherAdminID}, Role: constants.RoleAdmin}, nil)
+				mockRepo.On("CountByRole", mock.Anything, constants.RoleAdmin).Return(int64(2), nil)
+				mockRepo.On("Delete", mock.Anything, otherAdminID, adminID).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "Success - Delete regular user",
+			targetID: regularUserID,
+			setupMocks: func(mockRepo *MockUserRepository) {
+				mockRepo.On("GetByID", mock.Anything, regularUserID, adminID).
+					Return(&entities.User{BaseEntity: entities.BaseEntity{ID: regularUserID}, Role: constants.RoleUser}, nil)
+				mockRepo.On("Delete", mock.Anything, regularUserID, adminID).Return(nil)
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userUC, mockRepo, _ := setupUserUseCaseTest()
+			tt.setupMocks(mockRepo)
+
+			err := userUC.Delete(context.Background(), tt.targetID, adminID)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserUseCase_DeleteSelfWhenAllowed(t *testing.T) {
+	userUC, mockRepo, _ := setupUserUseCaseTest()
+	userUC.allowSelfDelete = true
+	userID := uuid.New()
+
+	mockRepo.On("GetByID", mock.Anything, userID, userID).
+		Return(&entities.User{BaseEntity: entities.BaseEntity{ID: userID}, Role: constants.RoleUser}, nil)
+	mockRepo.On("Delete", mock.Anything, userID, userID).Return(nil)
+
+	assert.NoError(t, userUC.Delete(context.Background(), userID, userID))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_Create(t *testing.T) {
+	adminID := uuid.New()
+
+	t.Run("Failure - Invalid role", func(t *testing.T) {
+		userUC, mockRepo, _ := setupUserUseCaseTest()
+		user := &entities.User{Email: "new@example.com", FirstName: "New", LastName: "User", Role: "superuser"}
+
+		err := userUC.Create(context.Background(), user, adminID)
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidRole)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Empty role defaults to user", func(t *testing.T) {
+		userUC, mockRepo, _ := setupUserUseCaseTest()
+		user := &entities.User{Email: "new@example.com", FirstName: "New", LastName: "User"}
+		mockRepo.On("Create", mock.Anything, user, adminID).Return(nil).Once()
+
+		err := userUC.Create(context.Background(), user, adminID)
+		assert.NoError(t, err)
+		assert.Equal(t, constants.RoleUser, user.Role)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserUseCase_UpdateRejectsInvalidRole(t *testing.T) {
+	userUC, mockRepo, _ := setupUserUseCaseTest()
+	adminID := uuid.New()
+	targetID := uuid.New()
+	ctx := constants.WithUserRole(context.Background(), constants.RoleAdmin)
+	mockRepo.On("GetByID", mock.Anything, targetID, adminID).
+		Return(&entities.User{BaseEntity: entities.BaseEntity{ID: targetID}, Role: constants.RoleUser}, nil)
+
+	err := userUC.Update(ctx, &entities.User{BaseEntity: entities.BaseEntity{ID: targetID}, Role: "owner"}, adminID)
+	assert.ErrorIs(t, err, domainerrors.ErrInvalidRole)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+type fakeAuditRepository struct {
+	entries []*entities.AuditLogEntry
+}
+
+func (r *fakeAuditRepository) Add(_ context.Context, entry *entities.AuditLogEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *fakeAuditRepository) ListByUser(_ context.Context, userID uuid.UUID, _, _ int) ([]*entities.AuditLogEntry, error) {
+	var entries []*entities.AuditLogEntry
+	for _, entry := range r.entries {
+		if entry.UserID == userID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeAuditRepository) ListByEntity(_ context.Context, entityID uuid.UUID, resources []string, _, _ int) ([]*entities.AuditLogEntry, error) {
+	var entries []*entities.AuditLogEntry
+	for _, entry := range r.entries {
+		for _, resource := range resources {
+			if entry.EntityID == entityID && entry.Resource == resource {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeAuditRepository) DeleteBefore(context.Context, time.Time, int) (int, error) {
+	return 0, nil
+}
+
+func TestUserUseCase_ActivityMasksResources(t *testing.T) {
+	userUC, _, _ := setupUserUseCaseTest()
+	userID := uuid.New()
+	productID := uuid.New()
+	userUC.auditRepo = &fakeAuditRepository{entries: []*entities.AuditLogEntry{
+		{ID: uuid.New(), UserID: userID, Action: "read", Resource: "product:read", EntityID: productID},
+		{ID: uuid.New(), UserID: userID, Action: "list", Resource: "user:list"},
+		{ID: uuid.New(), UserID: uuid.New(), Action: "delete", Resource: "user:delete"},
+	}}
+
+	activity, err := userUC.Activity(context.Background(), userID, constants.DefaultLimit, 0)
+	assert.NoError(t, err)
+	assert.Len(t, activity, 2)
+	assert.Equal(t, "product", activity[0].Resource)
+	assert.Equal(t, &productID, activity[0].EntityID)
+	assert.Equal(t, "user", activity[1].Resource)
+	assert.Nil(t, activity[1].EntityID)
+}
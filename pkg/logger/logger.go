@@ -18,20 +18,24 @@ type Logger interface {
 }
 
 type logger struct {
-	logrus *logrus.Logger
+	logrus *logrus.Entry
 }
 
-// NewLogger creates a new logger instance with structured JSON output
+// NewLogger creates a new logger instance with structured JSON output at the level named by
+// LOG_LEVEL, defaulting to info
 func NewLogger() Logger {
 	log := logrus.New()
 	log.SetOutput(os.Stdout)
 	log.SetLevel(logrus.InfoLevel)
+	if level, err := logrus.ParseLevel(os.Getenv("LOG_LEVEL")); err == nil {
+		log.SetLevel(level)
+	}
 
 	log.SetFormatter(&logrus.JSONFormatter{
 		TimestampFormat: "2006-01-02 15:04:05",
 	})
 
-	return &logger{logrus: log}
+	return &logger{logrus: logrus.NewEntry(log)}
 }
 
 func (l *logger) Info(args ...any) {
@@ -55,9 +59,9 @@ func (l *logger) Debug(args ...any) {
 }
 
 func (l *logger) WithField(key string, value any) Logger {
-	return &logger{logrus: l.logrus.WithField(key, value).Logger}
+	return &logger{logrus: l.logrus.WithField(key, value)}
 }
 
 func (l *logger) WithError(err error) Logger {
-	return &logger{logrus: l.logrus.WithError(err).Logger}
+	return &logger{logrus: l.logrus.WithError(err)}
 }
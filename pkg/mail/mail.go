@@ -0,0 +1,61 @@
+// Package mail sends transactional email, such as email-change confirmations. Mailer is the seam
+// the use cases depend on; SMTPMailer delivers through a relay and LogMailer writes messages to the
+// log for local development.
+package mail
+
+import (
+	"clean-architecture-api/pkg/logger"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverLog  = "log"
+)
+
+// Mailer delivers a plain-text message to a single recipient
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// LogMailer logs each message instead of sending it. Bodies are logged in full so confirmation
+// codes can be read during development; do not use it where mail carries secrets to real users.
+type LogMailer struct {
+	logger logger.Logger
+}
+
+func NewLogMailer(logger logger.Logger) *LogMailer {
+	return &LogMailer{logger: logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.logger.WithField("to", to).WithField("subject", subject).WithField("body", body).Info("Mail not sent (log mailer)")
+	return nil
+}
+
+// NewMailerFromEnv picks the implementation named by MAIL_DRIVER. Without MAIL_DRIVER it uses SMTP
+// when SMTP_ADDR is set and the log mailer otherwise.
+func NewMailerFromEnv(logger logger.Logger) (Mailer, error) {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("MAIL_DRIVER")))
+	if driver == "" {
+		driver = DriverLog
+		if os.Getenv("SMTP_ADDR") != "" {
+			driver = DriverSMTP
+		}
+	}
+
+	switch driver {
+	case DriverSMTP:
+		return NewSMTPMailerFromEnv()
+	case DriverLog:
+		return NewLogMailer(logger), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q, expected %q or %q", driver, DriverSMTP, DriverLog)
+	}
+}
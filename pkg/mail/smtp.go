@@ -0,0 +1,74 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+type SMTPConfig struct {
+	// Addr is host:port of the relay
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer sends plain-text mail through an SMTP relay, authenticating with PLAIN auth when a
+// username is configured
+type SMTPMailer struct {
+	config SMTPConfig
+	send   func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: config, send: smtp.SendMail}
+}
+
+// NewSMTPMailerFromEnv reads SMTP_ADDR, SMTP_FROM, SMTP_USERNAME and SMTP_PASSWORD. The address and
+// sender are required.
+func NewSMTPMailerFromEnv() (*SMTPMailer, error) {
+	config := SMTPConfig{
+		Addr:     os.Getenv("SMTP_ADDR"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if config.Addr == "" || config.From == "" {
+		return nil, errors.New("SMTP_ADDR and SMTP_FROM are required for the smtp mail driver")
+	}
+
+	return NewSMTPMailer(config), nil
+}
+
+func (s *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		host, _, err := net.SplitHostPort(s.config.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, host)
+	}
+
+	return s.send(s.config.Addr, auth, s.config.From, []string{to}, buildMessage(s.config.From, to, subject, body))
+}
+
+func buildMessage(from, to, subject, body string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
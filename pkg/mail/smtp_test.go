@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"context"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPMailer_Send(t *testing.T) {
+	mailer := NewSMTPMailer(SMTPConfig{Addr: "smtp.example.com:587", Username: "api", Password: "pw", From: "no-reply@example.com"})
+
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	var gotAuth smtp.Auth
+	mailer.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotAuth, gotFrom, gotTo, gotMsg = addr, a, from, to, msg
+		return nil
+	}
+
+	require.NoError(t, mailer.Send(context.Background(), "new@example.com", "Confirm", "line one\nline two"))
+	assert.Equal(t, "smtp.example.com:587", gotAddr)
+	assert.NotNil(t, gotAuth)
+	assert.Equal(t, "no-reply@example.com", gotFrom)
+	assert.Equal(t, []string{"new@example.com"}, gotTo)
+	assert.Contains(t, string(gotMsg), "To: new@example.com\r\n")
+	assert.Contains(t, string(gotMsg), "Subject: Confirm\r\n")
+	assert.Contains(t, string(gotMsg), "\r\n\r\nline one\r\nline two")
+}
+
+func TestNewMailerFromEnv(t *testing.T) {
+	mailer, err := NewMailerFromEnv(nil)
+	require.NoError(t, err)
+	assert.IsType(t, &LogMailer{}, mailer)
+
+	t.Setenv("MAIL_DRIVER", "smtp")
+	_, err = NewMailerFromEnv(nil)
+	assert.Error(t, err)
+
+	t.Setenv("SMTP_ADDR", "localhost:25")
+	t.Setenv("SMTP_FROM", "no-reply@example.com")
+	mailer, err = NewMailerFromEnv(nil)
+	require.NoError(t, err)
+	assert.IsType(t, &SMTPMailer{}, mailer)
+
+	// SMTP_ADDR alone selects SMTP
+	t.Setenv("MAIL_DRIVER", "")
+	mailer, err = NewMailerFromEnv(nil)
+	require.NoError(t, err)
+	assert.IsType(t, &SMTPMailer{}, mailer)
+
+	t.Setenv("MAIL_DRIVER", "pigeon")
+	_, err = NewMailerFromEnv(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"context"
+	"strings"
+	"text/template"
+)
+
+// Template renders a subject and plain-text body from text/template sources. The subject is
+// collapsed to a single line so data cannot inject extra headers.
+type Template struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func NewTemplate(name, subject, body string) (*Template, error) {
+	subjectTmpl, err := template.New(name + ".subject").Option("missingkey=error").Parse(subject)
+	if err != nil {
+		return nil, err
+	}
+	bodyTmpl, err := template.New(name + ".body").Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{subject: subjectTmpl, body: bodyTmpl}, nil
+}
+
+// MustTemplate is NewTemplate for package-level templates, panicking on a parse error
+func MustTemplate(name, subject, body string) *Template {
+	tmpl, err := NewTemplate(name, subject, body)
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
+
+func (t *Template) Render(data any) (subject, body string, err error) {
+	var b strings.Builder
+	if err := t.subject.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	subject = strings.Join(strings.Fields(b.String()), " ")
+
+	b.Reset()
+	if err := t.body.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	return subject, b.String(), nil
+}
+
+// Send renders the template with data and sends the result to a single recipient
+func (t *Template) Send(ctx context.Context, mailer Mailer, to string, data any) error {
+	subject, body, err := t.Render(data)
+	if err != nil {
+		return err
+	}
+	return mailer.Send(ctx, to, subject, body)
+}
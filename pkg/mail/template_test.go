@@ -0,0 +1,36 @@
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingMailer struct {
+	to, subject, body string
+}
+
+func (m *recordingMailer) Send(_ context.Context, to, subject, body string) error {
+	m.to, m.subject, m.body = to, subject, body
+	return nil
+}
+
+func TestTemplate_Send(t *testing.T) {
+	tmpl := MustTemplate("welcome", "Welcome, {{.Name}}", "Hi {{.Name}},\nyour code is {{.Code}}.")
+	mailer := &recordingMailer{}
+
+	require.NoError(t, tmpl.Send(context.Background(), mailer, "a@example.com", map[string]string{
+		"Name": "Ann\r\nBcc: evil@example.com", "Code": "1234",
+	}))
+	assert.Equal(t, "a@example.com", mailer.to)
+	assert.Equal(t, "Welcome, Ann Bcc: evil@example.com", mailer.subject, "subject must stay on one line")
+	assert.Contains(t, mailer.body, "your code is 1234.")
+
+	_, _, err := tmpl.Render(map[string]string{"Name": "Ann"})
+	assert.Error(t, err, "missing keys are an error rather than <no value>")
+
+	_, err = NewTemplate("broken", "{{.Name", "")
+	assert.Error(t, err)
+}
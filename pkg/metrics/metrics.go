@@ -0,0 +1,173 @@
+// Package metrics provides labelled counters and gauges exposed in the Prometheus text format. It covers the
+// small part of the Prometheus client this service needs, so scrapers work without the dependency.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Registry holds metric families by name and renders them for a scrape
+type Registry struct {
+	mutex    sync.Mutex
+	counters map[string]*CounterVec
+}
+
+func NewRegistry() *Registry {
+	return &Registry{counters: make(map[string]*CounterVec)}
+}
+
+// Default is the registry served at /metrics
+var Default = NewRegistry()
+
+// CounterVec is a family of counters sharing a name and label names
+type CounterVec struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mutex  sync.Mutex
+	values map[string]*sample
+}
+
+type sample struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec registers a counter family, or returns the one already registered under name
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return r.register(name, help, "counter", labels)
+}
+
+// GaugeVec is a family of gauges. Unlike a counter, a gauge can be set to any value.
+type GaugeVec struct {
+	*CounterVec
+}
+
+// NewGaugeVec registers a gauge family, or returns the one already registered under name
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{CounterVec: r.register(name, help, "gauge", labels)}
+}
+
+// Set replaces the gauge value for the given label values
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.update(labelValues, func(s *sample) { s.value = value })
+}
+
+func (r *Registry) register(name, help, kind string, labels []string) *CounterVec {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if existing, ok := r.counters[name]; ok {
+		return existing
+	}
+	family := &CounterVec{name: name, help: help, kind: kind, labels: labels, values: make(map[string]*sample)}
+	r.counters[name] = family
+	return family
+}
+
+// Inc adds one to the counter with the given label values, in the order the labels were declared
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	c.update(labelValues, func(s *sample) { s.value += delta })
+}
+
+func (c *CounterVec) update(labelValues []string, apply func(*sample)) {
+	if len(labelValues) != len(c.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", c.name, len(c.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, ok := c.values[key]
+	if !ok {
+		s = &sample{labelValues: append([]string(nil), labelValues...)}
+		c.values[key] = s
+	}
+	apply(s)
+}
+
+// Value returns the current count for the given label values
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if s, ok := c.values[strings.Join(labelValues, "\xff")]; ok {
+		return s.value
+	}
+	return 0
+}
+
+// WriteText renders every metric family in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mutex.Lock()
+	names := make([]string, 0, len(r.counters))
+	for name := range r.counters {
+		names = append(names, name)
+	}
+	r.mutex.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.mutex.Lock()
+		counter := r.counters[name]
+		r.mutex.Unlock()
+		if err := counter.writeText(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CounterVec) writeText(w io.Writer) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", c.name, c.help, c.name, c.kind); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := c.values[key]
+		if _, err := fmt.Fprintf(w, "%s%s %g\n", c.name, c.formatLabels(s.labelValues), s.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CounterVec) formatLabels(values []string) string {
+	if len(c.labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(c.labels))
+	for i, label := range c.labels {
+		pairs[i] = fmt.Sprintf("%s=%q", label, values[i])
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Handler serves the registry for Prometheus to scrape
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_ = r.WriteText(w)
+	})
+}
@@ -2,6 +2,9 @@ package newrelic
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/newrelic/go-agent/v3/newrelic"
@@ -23,54 +26,19 @@ func NewGormLogger(baseLogger logger.Interface, app *newrelic.Application) *Gorm
 	}
 }
 
-// Trace adds New Relic database segment to GORM queries.
+// Trace forwards to the wrapped logger and records rows affected on the transaction. It does not
+// start a datastore segment: the callbacks installed by InstrumentGorm own segments, so each query
+// is counted once however the two are combined.
 func (l *GormNewRelicLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
-	// Always call the original trace method first
 	l.Interface.Trace(ctx, begin, fc, err)
 
-	// Add New Relic monitoring if available
-	l.addNewRelicTrace(ctx, fc)
-}
-
-// addNewRelicTrace adds New Relic monitoring to the trace.
-func (l *GormNewRelicLogger) addNewRelicTrace(ctx context.Context, fc func() (sql string, rowsAffected int64)) {
-	if l.app == nil {
-		return
-	}
-
-	txn := newrelic.FromContext(ctx)
-	if txn == nil {
+	if l.app == nil || fc == nil {
 		return
 	}
-
-	sql, rowsAffected := l.getSQLInfo(fc)
-	segment := l.createDatastoreSegment(txn, sql)
-	l.addRowsAffectedAttribute(txn, rowsAffected)
-	segment.End()
-}
-
-// getSQLInfo safely extracts SQL and rows affected information.
-func (l *GormNewRelicLogger) getSQLInfo(fc func() (sql string, rowsAffected int64)) (string, int64) {
-	if fc == nil {
-		return "", 0
+	if txn := newrelic.FromContext(ctx); txn != nil {
+		_, rowsAffected := fc()
+		l.addRowsAffectedAttribute(txn, rowsAffected)
 	}
-	return fc()
-}
-
-// createDatastoreSegment creates and configures a New Relic datastore segment.
-func (l *GormNewRelicLogger) createDatastoreSegment(txn *newrelic.Transaction, sql string) *newrelic.DatastoreSegment {
-	segment := newrelic.DatastoreSegment{
-		Product:    newrelic.DatastorePostgres,
-		Collection: "query",
-		Operation:  "exec",
-	}
-	segment.StartTime = txn.StartSegmentNow()
-
-	if sql != "" {
-		segment.ParameterizedQuery = sql
-	}
-
-	return &segment
 }
 
 // addRowsAffectedAttribute adds rows affected as a New Relic attribute if available.
@@ -82,86 +50,77 @@ func (l *GormNewRelicLogger) addRowsAffectedAttribute(txn *newrelic.Transaction,
 	}
 }
 
-// AddNewRelicToGorm adds New Relic callbacks to GORM instance.
-func AddNewRelicToGorm(db *gorm.DB, app *newrelic.Application) error {
+// GormSegmentsEnabled reports whether GORM queries should be recorded as datastore segments. It is on
+// unless NEW_RELIC_GORM_SEGMENTS is set to false.
+func GormSegmentsEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("NEW_RELIC_GORM_SEGMENTS"))
+	return err != nil || enabled
+}
+
+// InstrumentGorm wraps db's logger and, when GormSegmentsEnabled, registers the callbacks that
+// record one datastore segment per create, query, update and delete. It is a no-op without an app.
+func InstrumentGorm(db *gorm.DB, app *newrelic.Application) error {
 	if app == nil {
 		return nil
 	}
 
-	// Add New Relic callback for create operations
-	if err := db.Callback().Create().Before("gorm:create").Register("newrelic:before_create", beforeCreate(app)); err != nil {
-		return err
-	}
-	if err := db.Callback().Create().After("gorm:create").Register("newrelic:after_create", afterCreate); err != nil {
-		return err
+	db.Logger = NewGormLogger(db.Logger, app)
+	if !GormSegmentsEnabled() {
+		return nil
 	}
+	return AddNewRelicToGorm(db, app)
+}
 
-	// Add New Relic callback for query operations
-	if err := db.Callback().Query().Before("gorm:query").Register("newrelic:before_query", beforeQuery(app)); err != nil {
-		return err
-	}
-	if err := db.Callback().Query().After("gorm:query").Register("newrelic:after_query", afterQuery); err != nil {
-		return err
+// AddNewRelicToGorm wraps GORM's create, query, update and delete processors so each runs inside a
+// datastore segment. The segment is ended by a defer, so it closes even when the query fails or panics.
+func AddNewRelicToGorm(db *gorm.DB, app *newrelic.Application) error {
+	if app == nil {
+		return nil
 	}
 
-	// Add New Relic callback for update operations
-	if err := db.Callback().Update().Before("gorm:update").Register("newrelic:before_update", beforeUpdate(app)); err != nil {
+	callbacks := db.Callback()
+	if err := wrapWithSegment(callbacks.Create(), "gorm:create", "CREATE"); err != nil {
 		return err
 	}
-	if err := db.Callback().Update().After("gorm:update").Register("newrelic:after_update", afterUpdate); err != nil {
+	if err := wrapWithSegment(callbacks.Query(), "gorm:query", "SELECT"); err != nil {
 		return err
 	}
-
-	// Add New Relic callback for delete operations
-	if err := db.Callback().Delete().Before("gorm:delete").Register("newrelic:before_delete", beforeDelete(app)); err != nil {
+	if err := wrapWithSegment(callbacks.Update(), "gorm:update", "UPDATE"); err != nil {
 		return err
 	}
-	if err := db.Callback().Delete().After("gorm:delete").Register("newrelic:after_delete", afterDelete); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func beforeCreate(app *newrelic.Application) func(*gorm.DB) {
-	return func(db *gorm.DB) {
-		startDatastoreSegment(db, "CREATE")
-	}
+	return wrapWithSegment(callbacks.Delete(), "gorm:delete", "DELETE")
 }
 
-func afterCreate(db *gorm.DB) {
-	endDatastoreSegment(db)
+// callbackProcessor is the part of GORM's unexported callback processor that wrapWithSegment needs
+type callbackProcessor interface {
+	Get(name string) func(*gorm.DB)
+	Replace(name string, fn func(*gorm.DB)) error
 }
 
-func beforeQuery(app *newrelic.Application) func(*gorm.DB) {
-	return func(db *gorm.DB) {
-		startDatastoreSegment(db, "SELECT")
+func wrapWithSegment(processor callbackProcessor, name, operation string) error {
+	original := processor.Get(name)
+	if original == nil {
+		return fmt.Errorf("gorm callback %q is not registered", name)
 	}
-}
 
-func afterQuery(db *gorm.DB) {
-	endDatastoreSegment(db)
+	return processor.Replace(name, func(db *gorm.DB) {
+		startDatastoreSegment(db, operation)
+		defer endDatastoreSegment(db)
+		original(db)
+	})
 }
 
-func beforeUpdate(app *newrelic.Application) func(*gorm.DB) {
-	return func(db *gorm.DB) {
-		startDatastoreSegment(db, "UPDATE")
+// startSegmentNow and endSegment are swapped in tests to count the segments a query starts and ends
+var (
+	startSegmentNow = func(txn *newrelic.Transaction) newrelic.SegmentStartTime {
+		return txn.StartSegmentNow()
 	}
-}
-
-func afterUpdate(db *gorm.DB) {
-	endDatastoreSegment(db)
-}
-
-func beforeDelete(app *newrelic.Application) func(*gorm.DB) {
-	return func(db *gorm.DB) {
-		startDatastoreSegment(db, "DELETE")
+	endSegment = func(segment *newrelic.DatastoreSegment) {
+		segment.End()
 	}
-}
+)
 
-func afterDelete(db *gorm.DB) {
-	endDatastoreSegment(db)
-}
+const segmentKey = "newrelic:segment"
 
 func startDatastoreSegment(db *gorm.DB, operation string) {
 	if txn := newrelic.FromContext(db.Statement.Context); txn != nil {
@@ -169,18 +128,22 @@ func startDatastoreSegment(db *gorm.DB, operation string) {
 			Product:   newrelic.DatastorePostgres, // Change based on your database
 			Operation: operation,
 		}
-		segment.StartTime = txn.StartSegmentNow()
-		db.Set("newrelic:segment", segment)
+		segment.StartTime = startSegmentNow(txn)
+		db.Statement.Settings.Store(segmentKey, segment)
 	}
 }
 
+// endDatastoreSegment ends the statement's segment, if any. Removing it first makes a second call a
+// no-op, so a segment is never ended twice.
 func endDatastoreSegment(db *gorm.DB) {
-	if segment, exists := db.Get("newrelic:segment"); exists {
-		if datastoreSegment, ok := segment.(*newrelic.DatastoreSegment); ok {
-			if db.Statement.Table != "" {
-				datastoreSegment.Collection = db.Statement.Table
-			}
-			datastoreSegment.End()
+	value, exists := db.Statement.Settings.LoadAndDelete(segmentKey)
+	if !exists {
+		return
+	}
+	if segment, ok := value.(*newrelic.DatastoreSegment); ok {
+		if db.Statement.Table != "" {
+			segment.Collection = db.Statement.Table
 		}
+		endSegment(segment)
 	}
 }
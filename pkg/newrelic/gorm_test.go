@@ -0,0 +1,101 @@
+package newrelic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+type segmentCounts struct {
+	started, ended int
+}
+
+// setupInstrumentedDB returns a database instrumented against a disabled app, a context carrying a
+// transaction and the number of segments started and ended so far
+func setupInstrumentedDB(t *testing.T) (*gorm.DB, context.Context, *segmentCounts) {
+	app, err := newrelic.NewApplication(newrelic.ConfigAppName("test"), newrelic.ConfigEnabled(false))
+	require.NoError(t, err)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&widget{}))
+	require.NoError(t, InstrumentGorm(db, app))
+
+	counts := &segmentCounts{}
+	originalStart, originalEnd := startSegmentNow, endSegment
+	startSegmentNow = func(txn *newrelic.Transaction) newrelic.SegmentStartTime {
+		counts.started++
+		return originalStart(txn)
+	}
+	endSegment = func(segment *newrelic.DatastoreSegment) {
+		counts.ended++
+		originalEnd(segment)
+	}
+	t.Cleanup(func() { startSegmentNow, endSegment = originalStart, originalEnd })
+
+	ctx := newrelic.NewContext(context.Background(), app.StartTransaction("test"))
+	return db, ctx, counts
+}
+
+func TestInstrumentGorm_OneSegmentPerQuery(t *testing.T) {
+	db, ctx, counts := setupInstrumentedDB(t)
+	assert.IsType(t, &GormNewRelicLogger{}, db.Logger)
+
+	require.NoError(t, db.WithContext(ctx).Create(&widget{Name: "a"}).Error)
+	assert.Equal(t, segmentCounts{started: 1, ended: 1}, *counts)
+
+	var found widget
+	require.NoError(t, db.WithContext(ctx).First(&found).Error)
+	assert.Equal(t, segmentCounts{started: 2, ended: 2}, *counts)
+
+	// Queries outside a transaction start no segment
+	require.NoError(t, db.First(&found).Error)
+	assert.Equal(t, segmentCounts{started: 2, ended: 2}, *counts)
+}
+
+func TestInstrumentGorm_EndsSegmentOnError(t *testing.T) {
+	db, ctx, counts := setupInstrumentedDB(t)
+
+	err := db.WithContext(ctx).Table("missing").First(&widget{}).Error
+	assert.Error(t, err)
+	assert.Equal(t, segmentCounts{started: 1, ended: 1}, *counts)
+}
+
+func TestWrapWithSegment_EndsSegmentOnPanic(t *testing.T) {
+	db, ctx, counts := setupInstrumentedDB(t)
+	processor := db.Callback().Raw()
+	require.NoError(t, processor.Register("test:panic", func(*gorm.DB) { panic("driver exploded") }))
+	require.NoError(t, wrapWithSegment(processor, "test:panic", "RAW"))
+
+	assert.Panics(t, func() { db.WithContext(ctx).Exec("SELECT 1") })
+	assert.Equal(t, segmentCounts{started: 1, ended: 1}, *counts)
+}
+
+func TestEndDatastoreSegment_EndsOnce(t *testing.T) {
+	db, ctx, counts := setupInstrumentedDB(t)
+	tx := db.WithContext(ctx).Session(&gorm.Session{})
+
+	startDatastoreSegment(tx, "SELECT")
+	endDatastoreSegment(tx)
+	endDatastoreSegment(tx)
+	assert.Equal(t, segmentCounts{started: 1, ended: 1}, *counts)
+}
+
+func TestInstrumentGorm_SegmentsDisabled(t *testing.T) {
+	t.Setenv("NEW_RELIC_GORM_SEGMENTS", "false")
+	db, ctx, counts := setupInstrumentedDB(t)
+
+	require.NoError(t, db.WithContext(ctx).Create(&widget{Name: "a"}).Error)
+	assert.Zero(t, counts.started)
+}